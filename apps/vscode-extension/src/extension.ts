@@ -41,12 +41,16 @@ export function activate(context: vscode.ExtensionContext) {
     const previewCmd = vscode.commands.registerCommand("sruja.previewArchitecture", () =>
       previewArchitecture(context)
     );
+    const elementDiagramCmd = vscode.commands.registerCommand(
+      "sruja.showElementDiagram",
+      (uri: string, fqn: string) => showElementDiagram(context, uri, fqn)
+    );
     const debugCmd = vscode.commands.registerCommand("sruja.debugWasmLsp", () => debugWasmLsp());
     const updateStatusCmd = vscode.commands.registerCommand("sruja.updateStatusBar", () =>
       updateStatusBarWithDiagnostics()
     );
 
-    context.subscriptions.push(previewCmd, debugCmd, updateStatusCmd);
+    context.subscriptions.push(previewCmd, elementDiagramCmd, debugCmd, updateStatusCmd);
 
     // Listen for active editor changes to update status bar
     vscode.window.onDidChangeActiveTextEditor(() => {
@@ -141,7 +145,7 @@ function getPreviewUri(uri: vscode.Uri): vscode.Uri {
   return vscode.Uri.parse(`${SrujaPreviewProvider.scheme}:${uri.path}.md?${query}`);
 }
 
-async function previewArchitecture(_context: vscode.ExtensionContext) {
+async function previewArchitecture(_context: vscode.ExtensionContext, focus?: string) {
   const editor = vscode.window.activeTextEditor;
   if (!editor) {
     vscode.window.showErrorMessage("No active editor");
@@ -179,7 +183,10 @@ async function previewArchitecture(_context: vscode.ExtensionContext) {
     return;
   }
 
-  const previewUri = getPreviewUri(doc.uri);
+  // The preview scrolls to the section of a focused element
+  const previewUri = focus
+    ? getPreviewUri(doc.uri).with({ fragment: focus.toLowerCase() })
+    : getPreviewUri(doc.uri);
 
   try {
     // Trigger preview update to refresh content
@@ -194,6 +201,13 @@ async function previewArchitecture(_context: vscode.ExtensionContext) {
   }
 }
 
+// Opens the architecture preview of a document at one of its elements, for the
+// code lens shown above each element.
+async function showElementDiagram(context: vscode.ExtensionContext, uri: string, fqn: string) {
+  await vscode.window.showTextDocument(vscode.Uri.parse(uri), { preview: false });
+  await previewArchitecture(context, fqn);
+}
+
 async function showWelcomeMessage(_context: vscode.ExtensionContext) {
   const action = await vscode.window.showInformationMessage(
     "Welcome to Sruja! 🎉",
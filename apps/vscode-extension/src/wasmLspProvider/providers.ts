@@ -526,25 +526,21 @@ export function registerLspProviders(context: vscode.ExtensionContext): void {
       if (!wasmApi || document.languageId !== "sruja") return [];
 
       try {
-        const text = document.getText();
-        const hints: vscode.InlayHint[] = [];
-
-        // Extract symbols to show type hints
-        const symbols = await wasmApi.getSymbols(text);
-        for (const sym of symbols) {
-          const line = Math.max(0, sym.line - 1);
-          if (line >= range.start.line && line <= range.end.line) {
-            // Add type hint for systems, containers, etc.
-            if (sym.kind === "system" || sym.kind === "container" || sym.kind === "component") {
-              const position = new vscode.Position(line, 1000); // End of line
-              const hint = new vscode.InlayHint(position, ` : ${sym.kind}`);
-              hint.paddingLeft = true;
-              hints.push(hint);
-            }
-          }
-        }
-
-        return hints;
+        // Resolved IDs of short relation endpoints and inherited technology
+        const hints = await wasmApi.inlayHints(document.getText());
+        return hints
+          .map((h) => {
+            const position = new vscode.Position(h.position.line, h.position.character);
+            const hint = new vscode.InlayHint(
+              position,
+              h.label,
+              h.kind === 2 ? vscode.InlayHintKind.Parameter : vscode.InlayHintKind.Type
+            );
+            hint.tooltip = h.tooltip;
+            hint.paddingLeft = h.paddingLeft;
+            return hint;
+          })
+          .filter((hint) => range.contains(hint.position));
       } catch (error) {
         const errMsg = error instanceof Error ? error.message : String(error);
         log(`WASM inlayHints failed: ${errMsg}`, "error");
@@ -564,29 +560,24 @@ export function registerLspProviders(context: vscode.ExtensionContext): void {
       if (!wasmApi || document.languageId !== "sruja") return [];
 
       try {
-        const text = document.getText();
-        const symbols = await wasmApi.getSymbols(text);
-        const lenses: vscode.CodeLens[] = [];
-
-        for (const sym of symbols) {
-          const line = Math.max(0, sym.line - 1);
-          const range = new vscode.Range(line, 0, line, 1000);
-
-          // Find references count
-          try {
-            const refs = await wasmApi.findReferences(text, sym.line, 1);
-            const lens = new vscode.CodeLens(range, {
-              title: `${refs.length} reference${refs.length !== 1 ? "s" : ""}`,
-              command: "editor.action.showReferences",
-              arguments: [document.uri, new vscode.Position(line, 0)],
+        // Relations, ADRs and scenarios of each element, opening its diagram
+        const lenses = await wasmApi.codeLenses(document.getText());
+        return lenses
+          .filter((l) => l.command)
+          .map((l) => {
+            const range = new vscode.Range(
+              l.range.start.line,
+              l.range.start.character,
+              l.range.end.line,
+              l.range.end.character
+            );
+            const [, fqn] = l.command?.arguments ?? [];
+            return new vscode.CodeLens(range, {
+              title: l.command?.title ?? "",
+              command: l.command?.command ?? "",
+              arguments: [document.uri.toString(), fqn],
             });
-            lenses.push(lens);
-          } catch {
-            // If findReferences fails, skip this lens
-          }
-        }
-
-        return lenses;
+          });
       } catch (error) {
         const errMsg = error instanceof Error ? error.message : String(error);
         log(`WASM codeLenses failed: ${errMsg}`, "error");
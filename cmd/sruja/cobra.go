@@ -30,6 +30,8 @@ func init() {
 	rootCmd.AddCommand(cmdList)
 	rootCmd.AddCommand(cmdTree)
 	rootCmd.AddCommand(cmdDiff)
+	rootCmd.AddCommand(cmdMod)
+	rootCmd.AddCommand(cmdRules)
 
 	rootCmd.AddCommand(cmdCompletion)
 	rootCmd.AddCommand(cmdLSP)
@@ -89,7 +91,7 @@ var cmdFmt = &cobra.Command{
 var cmdExport = &cobra.Command{
 	Use:                "export",
 	Short:              "Export to a format",
-	Long:               "Export a .sruja file to various formats (json)",
+	Long:               "Export a .sruja file to various formats (json, mermaid, dot, plantuml, structurizr, markdown, context)",
 	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if runExport(args, cmd.OutOrStdout(), cmd.ErrOrStderr()) != 0 {
@@ -102,7 +104,7 @@ var cmdExport = &cobra.Command{
 var cmdImport = &cobra.Command{
 	Use:                "import",
 	Short:              "Import from a format",
-	Long:               "Import a .sruja file from various formats (json)",
+	Long:               "Import a .sruja file from various formats (json, structurizr)",
 	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if runImport(args, cmd.OutOrStdout(), cmd.ErrOrStderr()) != 0 {
@@ -163,6 +165,30 @@ var cmdDiff = &cobra.Command{
 	},
 }
 
+var cmdMod = &cobra.Command{
+	Use:                "mod",
+	Short:              "Vendor or tidy required modules",
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if runMod(args, cmd.OutOrStdout(), cmd.ErrOrStderr()) != 0 {
+			return fmt.Errorf("mod failed")
+		}
+		return nil
+	},
+}
+
+var cmdRules = &cobra.Command{
+	Use:                "rules",
+	Short:              "List validation rules or write their documentation",
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if runRules(args, cmd.OutOrStdout(), cmd.ErrOrStderr()) != 0 {
+			return fmt.Errorf("rules failed")
+		}
+		return nil
+	},
+}
+
 var cmdCompletion = &cobra.Command{
 	Use:   "completion [bash|zsh|fish]",
 	Short: "Generate shell completions",
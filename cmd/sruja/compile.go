@@ -17,6 +17,7 @@ import (
 func runCompile(args []string, stdout, stderr io.Writer) int {
 	compileCmd := flag.NewFlagSet("compile", flag.ContinueOnError)
 	compileCmd.SetOutput(stderr)
+	formatFlag := compileCmd.String("format", formatText, "Output format: "+outputFormats())
 
 	if err := compileCmd.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing compile flags: %v", err)))
@@ -24,7 +25,12 @@ func runCompile(args []string, stdout, stderr io.Writer) int {
 	}
 
 	if compileCmd.NArg() < 1 {
-		_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja compile <file>"))
+		_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja compile [--format <format>] <file>"))
+		return 1
+	}
+	format, err := parseOutputFormat(*formatFlag)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(err.Error()))
 		return 1
 	}
 
@@ -70,13 +76,17 @@ func runCompile(args []string, stdout, stderr io.Writer) int {
 		engine.RunResolution(program)
 	}
 
-	// Validation
-	validator := engine.NewValidator()
-	validator.RegisterDefaultRules()
+	// Validation (rules, severities and strictness come from sruja.config.json)
+	validator, err := projectValidator(filePath, ruleSelection{})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Config Error: %v", err)))
+		return 1
+	}
 
 	diags := validator.Validate(program)
 
 	// Filter out informational cycle messages (cycles are valid in many architectures)
+	var reported []diagnostics.Diagnostic
 	var blockingErrors []diagnostics.Diagnostic
 	//nolint:gocritic // copying small structs is fine
 	for _, d := range diags {
@@ -84,6 +94,7 @@ func runCompile(args []string, stdout, stderr io.Writer) int {
 		if d.Code == diagnostics.CodeCycleDetected && d.Severity == diagnostics.SeverityInfo {
 			continue // Cycles are valid - skip informational messages
 		}
+		reported = append(reported, d)
 		// Only consider Errors as blocking, unless we want to fail on Warnings too
 		// For now, let's treat Errors as blocking
 		if d.Severity == diagnostics.SeverityError {
@@ -91,6 +102,18 @@ func runCompile(args []string, stdout, stderr io.Writer) int {
 		}
 	}
 
+	// Machine-readable output replaces the human-formatted one
+	if format != formatText {
+		if err := writeReport(stdout, format, filePath, validator, reported); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error writing report: %v", err)))
+			return 1
+		}
+		if len(blockingErrors) > 0 {
+			return 1
+		}
+		return 0
+	}
+
 	if len(blockingErrors) > 0 {
 		// Enhance errors with suggestions and context
 		enhancer := dx.NewErrorEnhancer(filePath, strings.Split(string(content), "\n"), program)
@@ -2,14 +2,21 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 
+	"github.com/sruja-ai/sruja/pkg/diff"
 	"github.com/sruja-ai/sruja/pkg/dx"
+	"github.com/sruja-ai/sruja/pkg/engine"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -17,60 +24,88 @@ func runDiff(args []string, stdout, stderr io.Writer) int {
 	diffCmd := flag.NewFlagSet("diff", flag.ContinueOnError)
 	diffCmd.SetOutput(stderr)
 	diffJSON := diffCmd.Bool("json", false, "output as JSON")
-	diffFormat := diffCmd.String("format", "text", "output format: text, json")
+	diffFormat := diffCmd.String("format", "text", "output format: text, json, markdown")
+	gitMode := diffCmd.Bool("git", false, "compare git refs: sruja diff --git <base> [<head>]")
+	gitPath := diffCmd.String("path", ".", "file or directory to compare in --git mode")
 
 	if err := diffCmd.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing diff flags: %v", err)))
 		return 1
 	}
 
-	if diffCmd.NArg() < 2 {
-		_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja diff <file1> <file2>"))
-		_, _ = fmt.Fprintln(stderr, dx.Info("Example: sruja diff architecture-v1.sruja architecture-v2.sruja"))
-		return 1
+	var base, head *language.Program
+	var baseName, headName string
+	var err error
+	if *gitMode {
+		if diffCmd.NArg() < 1 || diffCmd.NArg() > 2 {
+			_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja diff --git [--path <dir>] <base-ref> [<head-ref>]"))
+			_, _ = fmt.Fprintln(stderr, dx.Info("Example: sruja diff --git --path architecture main HEAD"))
+			return 1
+		}
+		baseName = diffCmd.Arg(0)
+		if base, err = loadGitRef(baseName, *gitPath); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error loading %s: %v", baseName, err)))
+			return 1
+		}
+		if diffCmd.NArg() == 2 {
+			headName = diffCmd.Arg(1)
+			head, err = loadGitRef(headName, *gitPath)
+		} else {
+			headName = "working tree"
+			head, err = loadArchitecture(*gitPath)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error loading %s: %v", headName, err)))
+			return 1
+		}
+	} else {
+		if diffCmd.NArg() < 2 {
+			_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja diff <old> <new>"))
+			_, _ = fmt.Fprintln(stderr, dx.Info("Example: sruja diff architecture-v1.sruja architecture-v2.sruja"))
+			_, _ = fmt.Fprintln(stderr, dx.Info("Files, workspace directories and git refs (--git) can be compared"))
+			return 1
+		}
+		baseName, headName = diffCmd.Arg(0), diffCmd.Arg(1)
+		if base, err = loadArchitecture(baseName); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing %s: %v", baseName, err)))
+			return 1
+		}
+		if head, err = loadArchitecture(headName); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing %s: %v", headName, err)))
+			return 1
+		}
 	}
 
-	file1 := diffCmd.Arg(0)
-	file2 := diffCmd.Arg(1)
+	result := diff.ComparePrograms(base, head)
+	result.Base, result.Head = baseName, headName
 
-	// Parse both files
-	program1, err := parseFile(file1)
-	if err != nil {
-		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing %s: %v", file1, err)))
-		return 1
+	format := *diffFormat
+	if *diffJSON {
+		format = "json"
 	}
-
-	program2, err := parseFile(file2)
-	if err != nil {
-		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing %s: %v", file2, err)))
+	switch format {
+	case "json":
+		data, err := result.JSON()
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error encoding diff: %v", err)))
+			return 1
+		}
+		_, _ = fmt.Fprintln(stdout, string(data))
+	case "markdown", "md":
+		result.WriteMarkdown(stdout)
+	case "text":
+		_, _ = fmt.Fprintln(stdout, dx.Header("Architecture Diff"))
+		result.WriteText(stdout, dx.SupportsColor())
+	default:
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Unsupported diff format: %s (expected text, json or markdown)", format)))
 		return 1
 	}
-
-	// Generate diff
-	diff := computeDiff(program1, program2, file1, file2)
-
-	// Output diff
-	const outputFormatJSON = "json"
-	if *diffJSON || *diffFormat == outputFormatJSON {
-		outputDiffJSON(diff, stdout)
-	} else {
-		outputDiffText(diff, stdout)
-	}
 	return 0
 }
 
-type Diff struct {
-	AddedSystems      []string
-	RemovedSystems    []string
-	AddedContainers   map[string][]string // system -> containers
-	RemovedContainers map[string][]string
-	AddedComponents   map[string][]string // system.container -> components
-	RemovedComponents map[string][]string
-	ModifiedSystems   []string
-}
-
-func parseFile(filePath string) (*language.Program, error) {
-	content, err := os.ReadFile(filepath.Clean(filePath))
+// loadArchitecture parses and resolves a .sruja file or a workspace directory.
+func loadArchitecture(path string) (*language.Program, error) {
+	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
@@ -80,307 +115,169 @@ func parseFile(filePath string) (*language.Program, error) {
 		return nil, err
 	}
 
-	program, _, err := p.Parse(filePath, string(content))
-	return program, err
-}
-
-//nolint:funlen,gocyclo,gocritic,goconst // Diff logic is inherently complex and centralized
-func computeDiff(program1, program2 *language.Program, _, _ string) *Diff {
-	diff := &Diff{
-		AddedContainers:   make(map[string][]string),
-		RemovedContainers: make(map[string][]string),
-		AddedComponents:   make(map[string][]string),
-		RemovedComponents: make(map[string][]string),
+	if info.IsDir() {
+		ws, err := p.ParseWorkspace(path)
+		if err != nil {
+			return nil, err
+		}
+		engine.RunWorkspaceResolution(ws)
+		return ws.MergedProgram(), nil
 	}
 
-	if program1 == nil || program1.Model == nil || program2 == nil || program2.Model == nil {
-		return diff
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
 	}
+	program, _, err := p.Parse(path, string(content))
+	if err != nil {
+		return nil, err
+	}
+	engine.RunResolution(program)
+	return program, nil
+}
 
-	// Collect systems from Model
-	collectSystems := func(model *language.Model) map[string]map[string]interface{} {
-		systems := make(map[string]map[string]interface{})
-		var collectFromElement func(elem *language.ElementDef, parentFQN string)
-		collectFromElement = func(elem *language.ElementDef, parentFQN string) {
-			if elem == nil {
-				return
-			}
-
-			id := elem.GetID()
-			if id == "" {
-				return
-			}
-
-			fqn := id
-			if parentFQN != "" {
-				fqn = parentFQN + "." + id
-			}
-
-			kind := elem.GetKind()
-			switch kind {
-			case "system":
-				if systems[id] == nil {
-					systems[id] = make(map[string]interface{})
-				}
-				systems[id]["fqn"] = fqn
-				systems[id]["containers"] = make(map[string]map[string]interface{})
-				systems[id]["components"] = make(map[string]map[string]interface{})
-			case "container":
-				// Find parent system
-				if parentFQN != "" {
-					// Extract system ID from parent FQN
-					parts := strings.Split(parentFQN, ".")
-					if len(parts) > 0 {
-						sysID := parts[0]
-						if systems[sysID] == nil {
-							systems[sysID] = make(map[string]interface{})
-							systems[sysID]["containers"] = make(map[string]map[string]interface{})
-							systems[sysID]["components"] = make(map[string]map[string]interface{})
-						}
-						containers := systems[sysID]["containers"].(map[string]map[string]interface{})
-						containers[id] = map[string]interface{}{"fqn": fqn, "components": make(map[string]interface{})}
-					}
-				}
-			case "component":
-				// Find parent system and container
-				if parentFQN != "" {
-					parts := strings.Split(parentFQN, ".")
-					if len(parts) >= 2 {
-						sysID := parts[0]
-						contID := parts[1]
-						if systems[sysID] == nil {
-							systems[sysID] = make(map[string]interface{})
-							systems[sysID]["containers"] = make(map[string]map[string]interface{})
-							systems[sysID]["components"] = make(map[string]map[string]interface{})
-						}
-						containers := systems[sysID]["containers"].(map[string]map[string]interface{})
-						if containers[contID] == nil {
-							containers[contID] = map[string]interface{}{"components": make(map[string]interface{})}
-						}
-						components := containers[contID]["components"].(map[string]interface{})
-						components[id] = true
-					} else if len(parts) == 1 {
-						// Component directly under system
-						sysID := parts[0]
-						if systems[sysID] == nil {
-							systems[sysID] = make(map[string]interface{})
-							systems[sysID]["containers"] = make(map[string]map[string]interface{})
-							systems[sysID]["components"] = make(map[string]map[string]interface{})
-						}
-						components := systems[sysID]["components"].(map[string]map[string]interface{})
-						components[id] = map[string]interface{}{"fqn": fqn}
-					}
-				}
-			}
-
-			// Recurse into nested elements
-			body := elem.GetBody()
-			if body != nil {
-				for _, bodyItem := range body.Items {
-					if bodyItem.Element != nil {
-						collectFromElement(bodyItem.Element, fqn)
-					}
-				}
-			}
-		}
+// loadGitRef loads path (a file or directory in the working tree) as of a git ref,
+// by extracting its .sruja files and the modules they import into a temporary
+// directory.
+func loadGitRef(ref, path string) (*language.Program, error) {
+	// git would take such a ref for one of its options
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := abs
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		dir = filepath.Dir(abs)
+	}
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root = strings.TrimSpace(root)
+	// Symlinked temp dirs make the toplevel differ from the absolute path
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("%s is not inside the git repository at %s", path, root)
+	}
 
-		for _, item := range model.Items {
-			if item.ElementDef != nil {
-				collectFromElement(item.ElementDef, "")
-			}
-		}
+	// Imports of modules resolve with the sruja.mod, sruja.sum and vendored
+	// modules of the directories above path
+	modules, err := moduleFiles(root, ref, filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"archive", "--format=tar", ref, "--", filepath.ToSlash(rel)}, modules...)
+	archive, err := git(root, args...)
+	if err != nil {
+		return nil, err
+	}
 
-		return systems
+	tmp, err := os.MkdirTemp("", "sruja-diff-")
+	if err != nil {
+		return nil, err
 	}
+	defer func() { _ = os.RemoveAll(tmp) }()
 
-	systems1 := collectSystems(program1.Model)
-	systems2 := collectSystems(program2.Model)
+	if err := extractSrujaFiles(strings.NewReader(archive), tmp); err != nil {
+		return nil, err
+	}
+	target := filepath.Join(tmp, rel)
+	if _, err := os.Stat(target); err != nil {
+		return nil, fmt.Errorf("%s does not exist at %s", rel, ref)
+	}
+	return loadArchitecture(target)
+}
 
-	// Find added and removed systems
-	for id := range systems2 {
-		if _, exists := systems1[id]; !exists {
-			diff.AddedSystems = append(diff.AddedSystems, id)
-		}
+// moduleFiles returns the sruja.mod, sruja.sum and vendor directories of the
+// parent directories of rel that exist at ref, relative to the repository root.
+func moduleFiles(root, ref, rel string) ([]string, error) {
+	if rel == "." {
+		return nil, nil
 	}
-	for id := range systems1 {
-		if _, exists := systems2[id]; !exists {
-			diff.RemovedSystems = append(diff.RemovedSystems, id)
+	var candidates []string
+	for dir := path.Dir(rel); ; dir = path.Dir(dir) {
+		for _, name := range []string{language.ModFileName, language.SumFileName, language.VendorDir} {
+			candidates = append(candidates, path.Join(dir, name))
+		}
+		if dir == "." {
+			break
 		}
 	}
+	out, err := git(root, append([]string{"ls-tree", "--name-only", ref, "--"}, candidates...)...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
 
-	// Find modified systems (existing in both but changed)
-	for id, sys2 := range systems2 {
-		sys1, exists := systems1[id]
-		if !exists {
-			continue
+// extractSrujaFiles writes the .sruja files of a tar archive under dir, with
+// the module files and vendored modules their imports need.
+func extractSrujaFiles(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
 		}
-
-		// Compare containers
-		containers1 := make(map[string]bool)
-		containers2 := make(map[string]bool)
-
-		if sys1["containers"] != nil {
-			for contID := range sys1["containers"].(map[string]map[string]interface{}) {
-				containers1[contID] = true
-			}
+		if err != nil {
+			return err
 		}
-		if sys2["containers"] != nil {
-			for contID := range sys2["containers"].(map[string]map[string]interface{}) {
-				containers2[contID] = true
-			}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if hdr.Typeflag != tar.TypeReg || !isArchitectureFile(name) ||
+			filepath.IsAbs(name) || strings.HasPrefix(name, "..") {
+			continue
 		}
-
-		// Added containers
-		for contID := range containers2 {
-			if !containers1[contID] {
-				diff.AddedContainers[id] = append(diff.AddedContainers[id], contID)
-				diff.ModifiedSystems = appendIfNotExists(diff.ModifiedSystems, id)
-			}
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return err
 		}
-
-		// Removed containers
-		for contID := range containers1 {
-			if !containers2[contID] {
-				diff.RemovedContainers[id] = append(diff.RemovedContainers[id], contID)
-				diff.ModifiedSystems = appendIfNotExists(diff.ModifiedSystems, id)
-			}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
 		}
-
-		// Compare components
-		if sys1["containers"] != nil && sys2["containers"] != nil {
-			conts1 := sys1["containers"].(map[string]map[string]interface{})
-			conts2 := sys2["containers"].(map[string]map[string]interface{})
-
-			for contID, cont2 := range conts2 {
-				cont1, exists := conts1[contID]
-				if !exists {
-					continue
-				}
-
-				comps1 := make(map[string]bool)
-				comps2 := make(map[string]bool)
-
-				if cont1["components"] != nil {
-					for compID := range cont1["components"].(map[string]interface{}) {
-						comps1[compID] = true
-					}
-				}
-				if cont2["components"] != nil {
-					for compID := range cont2["components"].(map[string]interface{}) {
-						comps2[compID] = true
-					}
-				}
-
-				for compID := range comps2 {
-					if !comps1[compID] {
-						key := fmt.Sprintf("%s.%s", id, contID)
-						diff.AddedComponents[key] = append(diff.AddedComponents[key], compID)
-						diff.ModifiedSystems = appendIfNotExists(diff.ModifiedSystems, id)
-					}
-				}
-				for compID := range comps1 {
-					if !comps2[compID] {
-						key := fmt.Sprintf("%s.%s", id, contID)
-						diff.RemovedComponents[key] = append(diff.RemovedComponents[key], compID)
-						diff.ModifiedSystems = appendIfNotExists(diff.ModifiedSystems, id)
-					}
-				}
-			}
+		if err := os.WriteFile(target, content, 0o600); err != nil {
+			return err
 		}
 	}
-
-	return diff
 }
 
-func appendIfNotExists(slice []string, item string) []string {
-	for _, s := range slice {
-		if s == item {
-			return slice
-		}
+// isArchitectureFile reports whether a file of an architecture is needed to load
+// it: a .sruja file, a module file or a file of a vendored module, whose
+// checksum covers all its files.
+func isArchitectureFile(name string) bool {
+	switch filepath.Base(name) {
+	case language.ModFileName, language.SumFileName:
+		return true
 	}
-	return append(slice, item)
-}
-
-func outputDiffText(diff *Diff, w io.Writer) {
-	_, _ = fmt.Fprintln(w, dx.Header("Architecture Diff"))
-
-	if len(diff.AddedSystems) == 0 && len(diff.RemovedSystems) == 0 &&
-		len(diff.AddedContainers) == 0 && len(diff.RemovedContainers) == 0 &&
-		len(diff.AddedComponents) == 0 && len(diff.RemovedComponents) == 0 {
-		_, _ = fmt.Fprintln(w, dx.Success("No differences found. Architectures are identical."))
-		return
+	if filepath.Ext(name) == srujaFileExt {
+		return true
 	}
-
-	useColor := dx.SupportsColor()
-
-	// Added systems
-	printDiffList(w, "Added Systems", diff.AddedSystems, "+", dx.ColorGreen)
-
-	// Removed systems
-	printDiffList(w, "Removed Systems", diff.RemovedSystems, "-", dx.ColorRed)
-
-	// Modified systems
-	if len(diff.ModifiedSystems) > 0 {
-		_, _ = fmt.Fprintln(w, dx.Section("Modified Systems"))
-		for _, sys := range diff.ModifiedSystems {
-			_, _ = fmt.Fprintf(w, "  %s %s\n", dx.Colorize(dx.ColorYellow, "~", useColor), dx.Colorize(dx.ColorBold, sys, useColor))
-
-			// Added/Removed containers
-			printContainerDiffs(w, sys, diff.AddedContainers, "+", dx.ColorGreen)
-			printContainerDiffs(w, sys, diff.RemovedContainers, "-", dx.ColorRed)
-
-			// Added components
-			for key, components := range diff.AddedComponents {
-				if strings.HasPrefix(key, sys+".") {
-					cont := strings.TrimPrefix(key, sys+".")
-					for _, comp := range components {
-						_, _ = fmt.Fprintf(w, "    %s component %s.%s\n", dx.Colorize(dx.ColorGreen, "+", useColor), cont, comp)
-					}
-				}
-			}
-
-			// Removed components
-			for key, components := range diff.RemovedComponents {
-				if strings.HasPrefix(key, sys+".") {
-					cont := strings.TrimPrefix(key, sys+".")
-					for _, comp := range components {
-						_, _ = fmt.Fprintf(w, "    %s component %s.%s\n", dx.Colorize(dx.ColorRed, "-", useColor), cont, comp)
-					}
-				}
-			}
+	for _, part := range strings.Split(filepath.Dir(name), string(filepath.Separator)) {
+		if part == language.VendorDir {
+			return true
 		}
-		_, _ = fmt.Fprintln(w)
 	}
+	return false
 }
 
-func outputDiffJSON(diff *Diff, w io.Writer) {
-	_, _ = fmt.Fprintln(w, "{")
-	_, _ = fmt.Fprintln(w, "  \"added_systems\": [")
-	for i, sys := range diff.AddedSystems {
-		_, _ = fmt.Fprintf(w, "    \"%s\"", sys)
-		if i < len(diff.AddedSystems)-1 {
-			_, _ = fmt.Fprint(w, ",")
-		}
-		_, _ = fmt.Fprintln(w)
-	}
-	_, _ = fmt.Fprintln(w, "  ],")
-	_, _ = fmt.Fprintln(w, "  \"removed_systems\": [")
-	for i, sys := range diff.RemovedSystems {
-		_, _ = fmt.Fprintf(w, "    \"%s\"", sys)
-		if i < len(diff.RemovedSystems)-1 {
-			_, _ = fmt.Fprint(w, ",")
-		}
-		_, _ = fmt.Fprintln(w)
-	}
-	_, _ = fmt.Fprintln(w, "  ],")
-	_, _ = fmt.Fprintln(w, "  \"modified_systems\": [")
-	for i, sys := range diff.ModifiedSystems {
-		_, _ = fmt.Fprintf(w, "    \"%s\"", sys)
-		if i < len(diff.ModifiedSystems)-1 {
-			_, _ = fmt.Fprint(w, ",")
+// git runs a git command in dir and returns its output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
 		}
-		_, _ = fmt.Fprintln(w)
+		return "", fmt.Errorf("git %s: %w", args[0], err)
 	}
-	_, _ = fmt.Fprintln(w, "  ]")
-	_, _ = fmt.Fprintln(w, "}")
+	return stdout.String(), nil
 }
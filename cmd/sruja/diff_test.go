@@ -2,12 +2,14 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
-	"github.com/sruja-ai/sruja/pkg/language"
+	"github.com/sruja-ai/sruja/pkg/diff"
 )
 
 func TestRunDiff(t *testing.T) {
@@ -37,15 +39,15 @@ func TestRunDiff(t *testing.T) {
 	}
 
 	output := stdout.String()
-	// Should show modified system S1 and added container C1
-	if !strings.Contains(output, "Modified Systems") {
-		t.Error("Expected output to contain 'Modified Systems'")
+	// Should show the added container S1.C1
+	if !strings.Contains(output, "Elements") {
+		t.Error("Expected output to contain 'Elements'")
 	}
-	if !strings.Contains(output, "S1") {
-		t.Error("Expected output to contain 'S1'")
+	if !strings.Contains(output, `+ container S1.C1 "Container 1"`) {
+		t.Errorf("Expected output to contain the added container S1.C1, got: %s", output)
 	}
-	if !strings.Contains(output, "container C1") {
-		t.Error("Expected output to contain 'container C1'")
+	if !strings.Contains(output, "1 added, 0 removed, 0 modified") {
+		t.Errorf("Expected a summary line, got: %s", output)
 	}
 }
 
@@ -71,18 +73,25 @@ func TestRunDiff_JSON(t *testing.T) {
 		t.Errorf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
 	}
 
-	output := stdout.String()
-	if !strings.Contains(output, "\"added_systems\": [") {
-		t.Error("Expected JSON output with added_systems")
+	var result diff.Result
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v. Output: %s", err, stdout.String())
 	}
-	if !strings.Contains(output, "\"S2\"") {
-		t.Error("Expected S2 in output")
+	if result.Summary.Added != 1 || result.Summary.Removed != 1 {
+		t.Errorf("Expected 1 added and 1 removed, got %+v", result.Summary)
 	}
-	if !strings.Contains(output, "\"removed_systems\": [") {
-		t.Error("Expected JSON output with removed_systems")
+	want := []diff.Change{
+		{Type: diff.Added, Category: diff.CategoryElement, ID: "S2", Kind: "system", New: "System 2"},
+		{Type: diff.Removed, Category: diff.CategoryElement, ID: "S1", Kind: "system", Old: "System 1"},
 	}
-	if !strings.Contains(output, "\"S1\"") {
-		t.Error("Expected S1 in output")
+	for _, c := range want {
+		found := false
+		for _, got := range result.Changes {
+			found = found || got == c
+		}
+		if !found {
+			t.Errorf("Expected change %+v, got %+v", c, result.Changes)
+		}
 	}
 }
 
@@ -131,94 +140,222 @@ func TestRunDiff_Complex(t *testing.T) {
 	runDiff([]string{file1, file2}, &stdout, &stderr)
 	output := stdout.String()
 
-	if !strings.Contains(output, "- container C2") {
+	if !strings.Contains(output, "- container S1.C2") {
 		t.Error("Expected removed container C2")
 	}
-	if !strings.Contains(output, "+ container C3") {
+	if !strings.Contains(output, "+ container S1.C3") {
 		t.Error("Expected added container C3")
 	}
-	if !strings.Contains(output, "- component C1.Comp1") {
+	if !strings.Contains(output, "- component S1.C1.Comp1") {
 		t.Error("Expected removed component Comp1")
 	}
-	if !strings.Contains(output, "+ component C1.Comp2") {
+	if !strings.Contains(output, "+ component S1.C1.Comp2") {
 		t.Error("Expected added component Comp2")
 	}
 }
 
-func TestComputeDiff_SrujaSyntax(t *testing.T) {
-	parser, err := language.NewParser()
-	if err != nil {
-		t.Fatalf("Failed to create parser: %v", err)
-	}
+func TestRunDiff_Markdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	file1 := filepath.Join(tmpDir, "v1.sruja")
+	file2 := filepath.Join(tmpDir, "v2.sruja")
 
-	v1 := `Backend = system "Backend"
-		Frontend = system "Frontend"`
-	v2 := `Backend = system "Backend" {
-			API = container "API"
+	v1 := `S1 = system "S1" {
+			API = container "API" {
+				technology "Go"
+			}
+		}
+		Decision = adr "Use Go" {
+			status "proposed"
+		}`
+	v2 := `S1 = system "S1" {
+			API = container "API" {
+				technology "Rust"
+			}
 		}
-		Frontend = system "Frontend"
-		Database = system "Database"`
+		Decision = adr "Use Go" {
+			status "accepted"
+		}`
 
-	program1, _, err := parser.Parse("v1.sruja", v1)
-	if err != nil {
-		t.Fatalf("Failed to parse v1: %v", err)
+	if err := os.WriteFile(file1, []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
 	}
-
-	program2, _, err := parser.Parse("v2.sruja", v2)
-	if err != nil {
-		t.Fatalf("Failed to parse v2: %v", err)
+	if err := os.WriteFile(file2, []byte(v2), 0o644); err != nil {
+		t.Fatal(err)
 	}
 
-	diff := computeDiff(program1, program2, "v1.sruja", "v2.sruja")
-
-	// Check added systems
-	if len(diff.AddedSystems) != 1 || diff.AddedSystems[0] != "Database" {
-		t.Errorf("Expected 1 added system 'Database', got %v", diff.AddedSystems)
+	var stdout, stderr bytes.Buffer
+	if exitCode := runDiff([]string{"--format", "markdown", file1, file2}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"### Architecture diff",
+		"**0 added, 0 removed, 2 modified**",
+		"| Modified | `S1.API` | technology: `Go` -> `Rust` |",
+		"| Modified | `Decision` | status: `proposed` -> `accepted` |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in markdown output, got: %s", want, output)
+		}
 	}
+}
 
-	// Check modified systems (Backend has new container)
-	if len(diff.ModifiedSystems) != 1 || diff.ModifiedSystems[0] != "Backend" {
-		t.Errorf("Expected 1 modified system 'Backend', got %v", diff.ModifiedSystems)
+func TestRunDiff_Directories(t *testing.T) {
+	dir1 := filepath.Join(t.TempDir(), "v1")
+	dir2 := filepath.Join(t.TempDir(), "v2")
+	files := map[string]string{
+		filepath.Join(dir1, "systems.sruja"): `Shop = system "Shop"`,
+		filepath.Join(dir2, "systems.sruja"): `Shop = system "Shop"`,
+		filepath.Join(dir2, "people.sruja"):  `User = person "User"`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	// Check added containers
-	if len(diff.AddedContainers["Backend"]) != 1 || diff.AddedContainers["Backend"][0] != "API" {
-		t.Errorf("Expected added container 'API' in Backend, got %v", diff.AddedContainers["Backend"])
+	var stdout, stderr bytes.Buffer
+	if exitCode := runDiff([]string{dir1, dir2}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	if output := stdout.String(); !strings.Contains(output, `+ person User "User"`) || strings.Contains(output, "Shop") {
+		t.Errorf("Expected only the added person, got: %s", output)
 	}
 }
 
-func TestComputeDiff_EmptyModels(t *testing.T) {
-	parser, err := language.NewParser()
-	if err != nil {
-		t.Fatalf("Failed to create parser: %v", err)
+func TestRunDiff_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	archDir := filepath.Join(repo, "arch")
+	if err := os.MkdirAll(archDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(archDir, "main.sruja")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	empty := ``
-	program1, _, err := parser.Parse("empty.sruja", empty)
-	if err != nil {
-		t.Fatalf("Failed to parse: %v", err)
+	gitRun("init", "-q")
+	write(`A = system "A"
+		B = system "B"
+		A -> B "calls"`)
+	gitRun("add", "-A")
+	gitRun("commit", "-q", "-m", "v1")
+	gitRun("tag", "v1")
+	write(`A = system "A"
+		B = system "B"
+		A -> B "publishes to"`)
+	gitRun("commit", "-q", "-am", "v2")
+	write(`A = system "A"`)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := runDiff([]string{"--git", "--path", archDir, "v1", "HEAD"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	if output := stdout.String(); !strings.Contains(output, `label: "calls" -> "publishes to"`) {
+		t.Errorf("Expected relabelled relation between refs, got: %s", output)
 	}
 
-	diff := computeDiff(program1, program1, "empty.sruja", "empty.sruja")
+	// Without a head ref the working tree is compared
+	stdout.Reset()
+	if exitCode := runDiff([]string{"--git", "--path", file, "HEAD"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	if output := stdout.String(); !strings.Contains(output, `- system B "B"`) {
+		t.Errorf("Expected B removed in the working tree, got: %s", output)
+	}
 
-	if len(diff.AddedSystems) != 0 {
-		t.Errorf("Expected no added systems, got %v", diff.AddedSystems)
+	if exitCode := runDiff([]string{"--git", "--path", archDir, "no-such-ref"}, &stdout, &stderr); exitCode == 0 {
+		t.Error("Expected non-zero exit code for an unknown ref")
 	}
-	if len(diff.RemovedSystems) != 0 {
-		t.Errorf("Expected no removed systems, got %v", diff.RemovedSystems)
+
+	// Refs are never passed to git as options
+	output := filepath.Join(t.TempDir(), "out.tar")
+	if exitCode := runDiff([]string{"--git", "--path", archDir, "HEAD", "--output=" + output}, &stdout, &stderr); exitCode == 0 {
+		t.Error("Expected non-zero exit code for a ref starting with '-'")
 	}
-	if len(diff.ModifiedSystems) != 0 {
-		t.Errorf("Expected no modified systems, got %v", diff.ModifiedSystems)
+	if _, err := os.Stat(output); err == nil {
+		t.Error("Expected git not to write the archive for a ref starting with '-'")
 	}
 }
 
-func TestComputeDiff_NilPrograms(t *testing.T) {
-	diff := computeDiff(nil, nil, "file1", "file2")
-
-	if len(diff.AddedSystems) != 0 {
-		t.Errorf("Expected no added systems, got %v", diff.AddedSystems)
+func TestRunDiff_GitModules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("SRUJA_MODCACHE", t.TempDir())
+	repo := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The module root holds the architecture in a subdirectory
+	platform := filepath.Join(t.TempDir(), "platform")
+	write(filepath.Join(platform, "sruja.mod"), "module acme.com/platform\n")
+	write(filepath.Join(platform, "kinds.sruja"), "service = kind \"Service\"\nStripe = system \"Stripe\"\n")
+	write(filepath.Join(repo, "sruja.mod"), "module acme.com/shop\nrequire acme.com/platform v1.0.0 => "+filepath.ToSlash(platform)+"\n")
+	var stdout, stderr bytes.Buffer
+	if code := runMod([]string{"vendor", repo}, &stdout, &stderr); code != 0 {
+		t.Fatalf("vendor exit code %d: %s", code, stderr.String())
+	}
+	// Only the vendored copy is left to resolve the import
+	write(filepath.Join(repo, "sruja.mod"), "module acme.com/shop\nrequire acme.com/platform v1.0.0\n")
+	if err := os.RemoveAll(platform); err != nil {
+		t.Fatal(err)
 	}
-	if len(diff.RemovedSystems) != 0 {
-		t.Errorf("Expected no removed systems, got %v", diff.RemovedSystems)
+	archDir := filepath.Join(repo, "arch")
+	file := filepath.Join(archDir, "main.sruja")
+	write(file, `import { service, Stripe } from 'acme.com/platform'
+Shop = service "Shop"
+Shop -> Stripe "pays"`)
+
+	gitRun("init", "-q")
+	gitRun("add", "-A")
+	gitRun("commit", "-q", "-m", "v1")
+	write(file, `import { service, Stripe } from 'acme.com/platform'
+Shop = service "Shop"
+Shop -> Stripe "pays"
+Billing = service "Billing"`)
+
+	stdout.Reset()
+	if exitCode := runDiff([]string{"--git", "--json", "--path", archDir, "HEAD"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	var res diff.Result
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	// The imported module is loaded at both versions
+	if len(res.Changes) != 1 || res.Changes[0].ID != "Billing" || res.Changes[0].Kind != "service" {
+		t.Errorf("Expected only Billing added, got %+v", res.Changes)
 	}
 }
@@ -6,14 +6,25 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sruja-ai/sruja/pkg/engine"
 	ctxexport "github.com/sruja-ai/sruja/pkg/export/context"
 	jexport "github.com/sruja-ai/sruja/pkg/export/json"
 	"github.com/sruja-ai/sruja/pkg/export/markdown"
+	"github.com/sruja-ai/sruja/pkg/export/plantuml"
+	"github.com/sruja-ai/sruja/pkg/export/structurizr"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
+// rankDirections maps -direction values to Structurizr automatic layout directions.
+var rankDirections = map[string]string{
+	"TB": "TopBottom",
+	"BT": "BottomTop",
+	"LR": "LeftRight",
+	"RL": "RightLeft",
+}
+
 //nolint:funlen,gocyclo,goconst // Export logic is complex, distinct strings needed
 func runExport(args []string, stdout, stderr io.Writer) int {
 	exportCmd := flag.NewFlagSet("export", flag.ContinueOnError)
@@ -21,7 +32,7 @@ func runExport(args []string, stdout, stderr io.Writer) int {
 
 	// Define flags
 	_ = exportCmd.Bool("single-file", false, "(deprecated) Generate single file (legacy)")
-	_ = exportCmd.String("out", "", "(deprecated) Output directory")
+	outDir := exportCmd.String("out", "", "Output directory for mermaid/dot/plantuml diagrams (one file per view)")
 	extended := exportCmd.Bool("extended", false, "Include pre-computed views in JSON output (for viewer apps)")
 	_ = exportCmd.Bool("local", false, "Use local assets")
 
@@ -30,6 +41,12 @@ func runExport(args []string, stdout, stderr io.Writer) int {
 	tokenLimit := exportCmd.Int("token-limit", 0, "Limit output to approximately N tokens (0 = no limit)")
 	context := exportCmd.String("context", "default", "Context type: default, code_generation, review, analysis")
 
+	// Diagram options for 'mermaid', 'dot' and 'plantuml' formats
+	level := exportCmd.Int("level", 1, "C4 view level for mermaid/dot/plantuml: 1 (context), 2 (container), 3 (component), 4 (deployment, plantuml only)")
+	focus := exportCmd.String("focus", "", "Element to focus on for level 2/3 diagrams (e.g., Shop or Shop.API)")
+	viewName := exportCmd.String("view", "", "Declared view to render for mermaid/dot (overrides -level)")
+	direction := exportCmd.String("direction", "", "Layout direction for mermaid/dot/plantuml/structurizr: TB, BT, LR, RL")
+
 	// New dedicated flags for 'context' format (reusing scope above)
 	template := exportCmd.String("template", "proposal", "Instruction template for context export (proposal, security, general)")
 
@@ -40,12 +57,32 @@ func runExport(args []string, stdout, stderr io.Writer) int {
 
 	if exportCmd.NArg() < 2 {
 		_, _ = fmt.Fprintln(stderr, "Usage: sruja export <format> <file>")
-		_, _ = fmt.Fprintln(stderr, "Formats: json, mermaid, markdown, context")
+		_, _ = fmt.Fprintln(stderr, "Formats: json, mermaid, dot, plantuml, structurizr, markdown, context")
 		return 1
 	}
 
 	format := exportCmd.Arg(0)
 	filePath := exportCmd.Arg(1)
+
+	maxLevel := 3
+	if format == "plantuml" {
+		maxLevel = plantuml.LevelDeployment
+	}
+	if *level < 1 || *level > maxLevel {
+		_, _ = fmt.Fprintf(stderr, "Invalid level: %d (expected 1 to %d)\n", *level, maxLevel)
+		return 1
+	}
+	if format == "plantuml" && *viewName != "" {
+		_, _ = fmt.Fprintln(stderr, "Error: -view is not supported for plantuml export")
+		return 1
+	}
+	switch strings.ToUpper(*direction) {
+	case "", "TB", "BT", "LR", "RL":
+	default:
+		_, _ = fmt.Fprintf(stderr, "Invalid direction: %s (expected TB, BT, LR or RL)\n", *direction)
+		return 1
+	}
+
 	info, err := os.Stat(filePath)
 	if err != nil {
 		_, _ = fmt.Fprintf(stderr, "Error accessing path: %v\n", err)
@@ -94,6 +131,13 @@ func runExport(args []string, stdout, stderr io.Writer) int {
 		exporter := jexport.NewExporter()
 		exporter.Extended = *extended
 		output, err = exporter.Export(program)
+	case "structurizr":
+		config := structurizr.DefaultConfig()
+		config.Name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		if dir, ok := rankDirections[strings.ToUpper(*direction)]; ok {
+			config.RankDirection = dir
+		}
+		output, err = structurizr.NewExporter(config).Export(program)
 	case "markdown":
 		// Parse scope if provided
 		var scopeObj *markdown.Scope
@@ -127,10 +171,17 @@ func runExport(args []string, stdout, stderr io.Writer) int {
 
 		exporter := markdown.NewExporter(options)
 		output = exporter.Export(program)
-	case "mermaid":
-		// TODO: Update mermaid exporter to work with Sruja AST
-		_, _ = fmt.Fprintf(stderr, "Error: mermaid export not yet updated for Sruja syntax\n")
-		return 1
+	case "mermaid", "dot", "plantuml":
+		opts := diagramOptions{
+			Level:     *level,
+			Focus:     *focus,
+			View:      *viewName,
+			Direction: strings.ToUpper(*direction),
+		}
+		if *outDir != "" {
+			return exportDiagramsToDir(format, program, opts, *outDir, stdout, stderr)
+		}
+		return exportDiagram(format, program, opts, stdout, stderr)
 	case "context":
 		opts := ctxexport.Options{
 			Scope:    *scope,
@@ -140,7 +191,7 @@ func runExport(args []string, stdout, stderr io.Writer) int {
 		exporter := ctxexport.NewExporter(opts)
 		output = exporter.Export(program)
 	default:
-		_, _ = fmt.Fprintf(stderr, "Unsupported export format: %s. Supported formats: json, mermaid, markdown, context\n", format)
+		_, _ = fmt.Fprintf(stderr, "Unsupported export format: %s. Supported formats: json, mermaid, dot, plantuml, structurizr, markdown, context\n", format)
 		return 1
 	}
 
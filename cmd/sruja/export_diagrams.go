@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/export/dot"
+	"github.com/sruja-ai/sruja/pkg/export/mermaid"
+	"github.com/sruja-ai/sruja/pkg/export/plantuml"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// diagramOptions holds the flags shared by the mermaid, dot and plantuml export formats.
+type diagramOptions struct {
+	Level     int
+	Focus     string
+	View      string
+	Direction string
+}
+
+// diagramSpec identifies a single diagram: a C4 level with an optional focus, or a declared view.
+type diagramSpec struct {
+	Name  string
+	Level int
+	Focus string
+	View  string
+}
+
+// renderDiagram renders one diagram in the given format ("mermaid", "dot" or "plantuml").
+func renderDiagram(format string, program *language.Program, spec diagramSpec, direction string) string {
+	switch format {
+	case "plantuml":
+		config := plantuml.DefaultConfig()
+		config.ViewLevel = spec.Level
+		config.FocusID = spec.Focus
+		if direction != "" {
+			config.Direction = direction
+		}
+		return plantuml.NewExporter(config).Export(program)
+	case "dot":
+		config := dot.DefaultConfig()
+		config.ViewLevel = spec.Level
+		config.FocusNodeID = spec.Focus
+		config.ViewName = spec.View
+		if direction != "" {
+			config.RankDir = direction
+		}
+		return dot.NewExporter(config).Export(program).DOT
+	}
+
+	config := mermaid.DefaultConfig()
+	config.ViewLevel = spec.Level
+	config.TargetID = spec.Focus
+	config.ViewName = spec.View
+	if direction != "" {
+		config.Direction = direction
+	}
+	return mermaid.NewExporter(config).Export(program)
+}
+
+// exportDiagram writes a single diagram selected by the options to stdout.
+func exportDiagram(format string, program *language.Program, opts diagramOptions, stdout, stderr io.Writer) int {
+	spec := diagramSpec{Level: opts.Level, Focus: opts.Focus, View: opts.View}
+	output := renderDiagram(format, program, spec, opts.Direction)
+	if output == "" {
+		_, _ = fmt.Fprintf(stderr, "Error: %s\n", emptyDiagramMessage(spec))
+		return 1
+	}
+	_, _ = fmt.Fprint(stdout, output)
+	return 0
+}
+
+// exportDiagramsToDir writes one file per diagram to outDir. With a named view only that
+// view is written; otherwise every declared view plus the L1, L2 (per system) and L3
+// (per container) diagrams are written.
+func exportDiagramsToDir(format string, program *language.Program, opts diagramOptions, outDir string, stdout, stderr io.Writer) int {
+	specs := diagramSpecs(format, program, opts)
+	if err := os.MkdirAll(outDir, 0o750); err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error creating output directory: %v\n", err)
+		return 1
+	}
+
+	ext := ".mmd"
+	switch format {
+	case "dot":
+		ext = ".dot"
+	case "plantuml":
+		ext = ".puml"
+	}
+
+	written := 0
+	for _, spec := range specs {
+		output := renderDiagram(format, program, spec, opts.Direction)
+		if output == "" {
+			if spec.View != "" {
+				_, _ = fmt.Fprintf(stderr, "Error: %s\n", emptyDiagramMessage(spec))
+				return 1
+			}
+			continue
+		}
+		path := filepath.Join(outDir, spec.Name+ext)
+		if err := os.WriteFile(path, []byte(output), 0o644); err != nil { //nolint:gosec // diagrams are meant to be shared
+			_, _ = fmt.Fprintf(stderr, "Error writing %s: %v\n", path, err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(stdout, "Wrote %s\n", path)
+		written++
+	}
+
+	if written == 0 {
+		_, _ = fmt.Fprintf(stderr, "Error: no diagrams to export\n")
+		return 1
+	}
+	return 0
+}
+
+// diagramSpecs lists the diagrams written by exportDiagramsToDir.
+func diagramSpecs(format string, program *language.Program, opts diagramOptions) []diagramSpec {
+	if opts.View != "" {
+		return []diagramSpec{{Name: opts.View, View: opts.View}}
+	}
+
+	var specs []diagramSpec
+	if program.Views != nil {
+		for _, item := range program.Views.Items {
+			if item != nil && item.View != nil && item.View.Name != nil && *item.View.Name != "" {
+				specs = append(specs, diagramSpec{Name: *item.View.Name, View: *item.View.Name})
+			}
+		}
+	}
+
+	specs = append(specs, diagramSpec{Name: "L1", Level: 1})
+	for _, item := range program.Model.Items {
+		if item.ElementDef == nil || !strings.EqualFold(item.ElementDef.GetKind(), "system") {
+			continue
+		}
+		sysID := item.ElementDef.GetID()
+		specs = append(specs, diagramSpec{Name: "L2-" + sysID, Level: 2, Focus: sysID})
+		body := item.ElementDef.GetBody()
+		if body == nil {
+			continue
+		}
+		for _, bodyItem := range body.Items {
+			if bodyItem.Element != nil && strings.EqualFold(bodyItem.Element.GetKind(), "container") {
+				contID := sysID + "." + bodyItem.Element.GetID()
+				specs = append(specs, diagramSpec{Name: "L3-" + contID, Level: 3, Focus: contID})
+			}
+		}
+	}
+	if format == "plantuml" {
+		specs = append(specs, diagramSpec{Name: "Deployment", Level: plantuml.LevelDeployment})
+	}
+	return specs
+}
+
+// emptyDiagramMessage explains why a diagram came out empty.
+func emptyDiagramMessage(spec diagramSpec) string {
+	switch {
+	case spec.View != "":
+		return fmt.Sprintf("view %q not found or shows no elements", spec.View)
+	case spec.Level == plantuml.LevelDeployment:
+		return "no deployment nodes found"
+	case spec.Focus != "":
+		return fmt.Sprintf("no elements found for level %d focused on %q", spec.Level, spec.Focus)
+	default:
+		return fmt.Sprintf("no elements found for level %d", spec.Level)
+	}
+}
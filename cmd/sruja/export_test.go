@@ -86,22 +86,128 @@ func TestRunExport(t *testing.T) {
 func TestRunExport_Mermaid(t *testing.T) {
 	tmpDir := t.TempDir()
 	file := filepath.Join(tmpDir, "mermaid.sruja")
-	err := os.WriteFile(file, []byte(`system = kind "System"
-		container = kind "Container"
-        S = system "Sys" { C = container "Cont" }`), 0o644)
+	err := os.WriteFile(file, []byte(`User = person "User"
+		S = system "Sys" {
+			C = container "Cont"
+			D = database "DB"
+		}
+		User -> S.C "Uses"
+		S.C -> S.D "Reads"
+		view data of S {
+			include S.C ->
+		}`), 0o644)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	var stdout, stderr bytes.Buffer
-	// Mermaid export is still disabled (not updated for Sruja syntax)
-	exitCode := runExport([]string{"mermaid", file}, &stdout, &stderr)
-	if exitCode == 0 {
-		t.Skip("Mermaid export not yet updated for Sruja syntax - skipping test")
+	exitCode := runExport([]string{"-direction", "TB", "mermaid", file}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "graph TB") || !strings.Contains(stdout.String(), "User -->") {
+		t.Errorf("Expected L1 mermaid diagram, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	exitCode = runExport([]string{"-view", "data", "mermaid", file}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0 for named view, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "S_C -->|\"Reads\"| S_D") || strings.Contains(stdout.String(), "User") {
+		t.Errorf("Expected only the view's elements, got: %s", stdout.String())
+	}
+
+	stderr.Reset()
+	if runExport([]string{"-view", "missing", "mermaid", file}, &stdout, &stderr) == 0 {
+		t.Error("Expected non-zero exit code for unknown view")
+	}
+	if runExport([]string{"-level", "4", "mermaid", file}, &stdout, &stderr) == 0 {
+		t.Error("Expected non-zero exit code for invalid level")
+	}
+}
+
+func TestRunExport_DotOutDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "dot.sruja")
+	err := os.WriteFile(file, []byte(`S = system "Sys" {
+			C = container "Cont"
+		}
+		view all {
+			include *
+		}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(tmpDir, "diagrams")
+	var stdout, stderr bytes.Buffer
+	exitCode := runExport([]string{"-out", outDir, "dot", file}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	for _, name := range []string{"all.dot", "L1.dot", "L2-S.dot", "L3-S.C.dot"} {
+		content, err := os.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+			continue
+		}
+		if !strings.Contains(string(content), "digraph G {") {
+			t.Errorf("Expected DOT content in %s", name)
+		}
+	}
+}
+
+func TestRunExport_PlantUML(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "plantuml.sruja")
+	err := os.WriteFile(file, []byte(`User = person "User"
+		S = system "Sys" {
+			C = container "Cont"
+		}
+		User -> S.C "Uses"`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runExport([]string{"-level", "2", "-focus", "S", "plantuml", file}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	for _, want := range []string{"!include <C4/C4_Container>", `System_Boundary(S, "Sys")`, `Rel(User, S_C, "Uses")`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in output, got: %s", want, stdout.String())
+		}
 	}
-	// Expected to fail with error message
-	if !strings.Contains(stderr.String(), "mermaid export not yet updated") {
-		t.Logf("Expected mermaid error message, got: %s", stderr.String())
+
+	// No deployment nodes in the model
+	if runExport([]string{"-level", "4", "plantuml", file}, &stdout, &stderr) == 0 {
+		t.Error("Expected non-zero exit code for empty deployment diagram")
+	}
+}
+
+func TestRunExport_Structurizr(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "shop.sruja")
+	err := os.WriteFile(file, []byte(`User = person "User"
+		S = system "Sys" {
+			C = container "Cont"
+		}
+		User -> S.C "Uses"`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runExport([]string{"-direction", "LR", "structurizr", file}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d. Stderr: %s", exitCode, stderr.String())
+	}
+	for _, want := range []string{`"name": "shop"`, `"softwareSystems"`, `"description": "Uses"`, `"rankDirection": "LeftRight"`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in output, got: %s", want, stdout.String())
+		}
 	}
 }
 
@@ -7,8 +7,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/sruja-ai/sruja/pkg/export/dsl"
 	jsonexport "github.com/sruja-ai/sruja/pkg/export/json"
+	"github.com/sruja-ai/sruja/pkg/export/structurizr"
+	"github.com/sruja-ai/sruja/pkg/language"
 )
 
 func runImport(args []string, stdout, stderr io.Writer) int {
@@ -50,35 +54,44 @@ func runImport(args []string, stdout, stderr io.Writer) int {
 	case "json":
 		// Try Sruja's internal JSON format first (ArchitectureJSON)
 		var arch jsonexport.ArchitectureJSON
-		if err := json.Unmarshal(content, &arch); err == nil && len(arch.Architecture.Systems) > 0 {
-			for _, sys := range arch.Architecture.Systems {
-				_, _ = fmt.Fprintf(stdout, "system %s \"%s\"\n", sys.ID, sys.Label)
-			}
+		if err := json.Unmarshal(content, &arch); err == nil && len(arch.Architecture.Systems)+len(arch.Architecture.Persons) > 0 {
+			_, _ = fmt.Fprint(stdout, dsl.Print(jsonexport.ArchitectureToModelDump(&arch)))
 			return 0
 		}
 
 		// Fallback to SrujaModelDump format
 		var dump jsonexport.SrujaModelDump
-		if err := json.Unmarshal(content, &dump); err == nil && len(dump.Elements) > 0 {
-			for _, elem := range dump.Elements {
-				if elem.Kind == "system" {
-					_, _ = fmt.Fprintf(stdout, "system %s \"%s\"\n", elem.ID, elem.Title)
-				}
-			}
+		if err := json.Unmarshal(content, &dump); err == nil && (len(dump.Elements) > 0 || dump.Sruja != nil) {
+			_, _ = fmt.Fprint(stdout, dsl.Print(&dump))
 			return 0
 		}
 
 		// Final fallback to direct SystemJSON
 		var sysJSON jsonexport.SystemJSON
 		if err := json.Unmarshal(content, &sysJSON); err == nil && sysJSON.ID != "" {
-			_, _ = fmt.Fprintf(stdout, "system %s \"%s\"\n", sysJSON.ID, sysJSON.Label)
+			arch := jsonexport.ArchitectureJSON{Architecture: jsonexport.ArchitectureBody{Systems: []jsonexport.SystemJSON{sysJSON}}}
+			_, _ = fmt.Fprint(stdout, dsl.Print(jsonexport.ArchitectureToModelDump(&arch)))
 			return 0
 		}
 
 		_, _ = fmt.Fprintln(stderr, "Error: Could not identify architecture in JSON")
 		return 1
+	case "structurizr":
+		// workspace.json or workspace.dsl
+		var program *language.Program
+		if strings.EqualFold(filepath.Ext(filePath), ".json") || strings.HasPrefix(strings.TrimSpace(string(content)), "{") {
+			program, err = structurizr.ImportJSON(content)
+		} else {
+			program, err = structurizr.ImportDSL(string(content))
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Error importing Structurizr workspace: %v\n", err)
+			return 1
+		}
+		_, _ = fmt.Fprint(stdout, language.NewPrinter().Print(program))
+		return 0
 	default:
-		_, _ = fmt.Fprintf(stderr, "Unsupported import format: %s. Supported formats: json\n", format)
+		_, _ = fmt.Fprintf(stderr, "Unsupported import format: %s. Supported formats: json, structurizr\n", format)
 		return 1
 	}
 }
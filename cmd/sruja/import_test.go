@@ -2,10 +2,15 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+
+	jsonexport "github.com/sruja-ai/sruja/pkg/export/json"
 )
 
 func TestRunImport(t *testing.T) {
@@ -30,8 +35,8 @@ func TestRunImport(t *testing.T) {
 		t.Fatalf("runImport failed: %s", stderr.String())
 	}
 
-	if !bytes.Contains(stdout.Bytes(), []byte("system sys1")) {
-		t.Errorf("Expected stdout to contain 'system sys1', got %s", stdout.String())
+	if !bytes.Contains(stdout.Bytes(), []byte(`sys1 = system "System 1"`)) {
+		t.Errorf("Expected stdout to contain 'sys1 = system \"System 1\"', got %s", stdout.String())
 	}
 
 	// Test unsupported format
@@ -42,6 +47,309 @@ func TestRunImport(t *testing.T) {
 	}
 }
 
+const roundTripSource = `import { Shared } from "lib"
+
+User = person "User" {
+  description "A customer"
+}
+Shop = system "Shop" {
+  description "Online shop"
+  tags ["core", "pci"]
+  metadata {
+    owner "team-shop"
+  }
+  slo {
+    availability {
+      target "99.9%"
+      window "30d"
+    }
+    latency {
+      p95 "200ms"
+      p99 "500ms"
+    }
+  }
+  API = container "API" {
+    technology "Go"
+    Handler = component "Handler"
+  }
+  DB = database "DB" {
+    technology "PostgreSQL"
+  }
+  API -> DB "reads" "SQL" [sync]
+}
+Stripe = system "Stripe"
+User -> Shop.API "uses"
+Shop.API <-> Shop.DB "syncs"
+Shop.API <-> Stripe "charges"
+
+R1 = requirement functional "Must be fast" {
+  description "p95 under 200ms"
+  status "accepted"
+  metadata {
+    priority "high"
+  }
+}
+ADR1 = adr "Use Go" {
+  status "accepted"
+  context "Need speed"
+  decision "Go"
+  consequences "Hiring"
+  metadata {
+    date "2024-01-01"
+  }
+}
+P1 = policy security "Encrypt" {
+  description "Encrypt data"
+  enforcement "required"
+}
+S1 = scenario "Checkout" {
+  description "User buys"
+  step User -> Shop.API "Places order"
+}
+F1 = flow "Sync" {
+  step Shop.API -> Shop.DB "Writes"
+}
+
+deployment Prod "Production" {
+  node AWS "AWS" {
+    infrastructure LB "Load Balancer"
+    containerInstance API
+  }
+}
+
+constraints {
+  latency "Under 200ms"
+}
+conventions {
+  naming "camelCase"
+}
+
+view main of Shop {
+  title "Main"
+  include *
+  exclude Shop.DB
+  layout {
+    element Shop.API {
+      position { x: 100, y: 50 }
+    }
+  }
+}
+`
+
+// exportJSON exports a .sruja file to a model dump through the CLI.
+func exportJSON(t *testing.T, file string) (string, *jsonexport.SrujaModelDump) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if runExport([]string{"json", file}, &stdout, &stderr) != 0 {
+		t.Fatalf("runExport failed: %s", stderr.String())
+	}
+	var dump jsonexport.SrujaModelDump
+	if err := json.Unmarshal(stdout.Bytes(), &dump); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	dump.Metadata.Generated = ""
+	return stdout.String(), &dump
+}
+
+// roundTripJSON exports a .sruja file to JSON, imports the JSON back to DSL and
+// exports that, returning the imported source and both model dumps.
+func roundTripJSON(t *testing.T, source string) (string, *jsonexport.SrujaModelDump, *jsonexport.SrujaModelDump) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	exported, want := exportJSON(t, source)
+
+	jsonFile := filepath.Join(tmpDir, "model.json")
+	if err := os.WriteFile(jsonFile, []byte(exported), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if runImport([]string{"json", jsonFile}, &stdout, &stderr) != 0 {
+		t.Fatalf("runImport failed: %s", stderr.String())
+	}
+
+	imported := filepath.Join(tmpDir, "imported.sruja")
+	if err := os.WriteFile(imported, stdout.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, got := exportJSON(t, imported)
+	return stdout.String(), want, got
+}
+
+func TestRunImport_JSONRoundTrip(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "source.sruja")
+	if err := os.WriteFile(source, []byte(roundTripSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	imported, want, got := roundTripJSON(t, source)
+
+	// Implied relations are not declared, so relations keep their count and arrows
+	if len(got.Relations) != len(want.Relations) {
+		t.Errorf("Expected %d relations, got %d", len(want.Relations), len(got.Relations))
+	}
+	arrows := func(dump *jsonexport.SrujaModelDump) map[string]bool {
+		out := map[string]bool{}
+		for _, rel := range dump.Relations {
+			out[rel.Source.Model+" "+rel.Target.Model] = rel.Bidirectional
+		}
+		return out
+	}
+	if !reflect.DeepEqual(arrows(want), arrows(got)) {
+		t.Errorf("Expected bidirectional relations %v, got %v", arrows(want), arrows(got))
+	}
+	if strings.Contains(imported, "Shop -> Stripe") {
+		t.Errorf("Expected implied Shop -> Stripe not to be declared, got:\n%s", imported)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		wantJSON, _ := json.MarshalIndent(want, "", "  ")
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("Round trip changed the model.\nImported source:\n%s\nWant:\n%s\nGot:\n%s", imported, wantJSON, gotJSON)
+	}
+}
+
+// declaredRelations returns the relations of a dump that are not implied,
+// without their generated IDs, in a stable order.
+func declaredRelations(dump *jsonexport.SrujaModelDump) []jsonexport.RelationDump {
+	var rels []jsonexport.RelationDump
+	for _, rel := range dump.Relations {
+		if !rel.Implied {
+			rel.ID = ""
+			rels = append(rels, rel)
+		}
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		a, b := rels[i], rels[j]
+		if a.Source.Model != b.Source.Model {
+			return a.Source.Model < b.Source.Model
+		}
+		if a.Target.Model != b.Target.Model {
+			return a.Target.Model < b.Target.Model
+		}
+		return a.Title < b.Title
+	})
+	return rels
+}
+
+// The examples are printed in another order than they are written in, so their
+// elements and declared relations are compared instead of the whole dump.
+func TestRunImport_JSONRoundTripExamples(t *testing.T) {
+	examples, err := filepath.Glob(filepath.Join("..", "..", "examples", "*.sruja"))
+	if err != nil || len(examples) == 0 {
+		t.Fatalf("no examples found: %v", err)
+	}
+	for _, example := range examples {
+		t.Run(filepath.Base(example), func(t *testing.T) {
+			imported, want, got := roundTripJSON(t, example)
+			for id, el := range want.Elements {
+				if !reflect.DeepEqual(el, got.Elements[id]) {
+					t.Errorf("Round trip changed element %s: want %+v, got %+v", id, el, got.Elements[id])
+				}
+			}
+			if len(got.Elements) != len(want.Elements) {
+				t.Errorf("Expected %d elements, got %d", len(want.Elements), len(got.Elements))
+			}
+			if w, g := declaredRelations(want), declaredRelations(got); !reflect.DeepEqual(w, g) {
+				t.Errorf("Round trip changed the relations.\nImported source:\n%s\nWant: %+v\nGot: %+v", imported, w, g)
+			}
+		})
+	}
+}
+
+func TestRunImport_ArchitectureJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "arch.json")
+	err := os.WriteFile(jsonFile, []byte(`{
+		"metadata": {"name": "Shop", "layout": {"Shop": {"x": 10, "y": 20}}},
+		"architecture": {
+			"persons": [{"id": "User", "label": "User"}],
+			"systems": [{
+				"id": "Shop",
+				"label": "Shop",
+				"containers": [{"id": "API", "label": "API", "technology": "Go", "tags": ["core"]}],
+				"datastores": [{"id": "DB", "label": "DB"}],
+				"relations": [{"from": "API", "to": "DB", "verb": "reads", "label": "SQL"}]
+			}],
+			"relations": [{"from": "User", "to": "Shop.API", "verb": "uses"}],
+			"adrs": [{"id": "ADR1", "title": "Use Go", "status": "accepted"}]
+		}
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if runImport([]string{"json", jsonFile}, &stdout, &stderr) != 0 {
+		t.Fatalf("runImport failed: %s", stderr.String())
+	}
+	for _, want := range []string{
+		`API = container "API" {`,
+		`tags ["core"]`,
+		`DB = database "DB"`,
+		`Shop.API -> Shop.DB "reads" "SQL"`,
+		`User -> Shop.API "uses"`,
+		`ADR1 = adr "Use Go" {`,
+		`position { x: 10, y: 20 }`,
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in output, got: %s", want, stdout.String())
+		}
+	}
+}
+
+func TestRunImport_Structurizr(t *testing.T) {
+	tmpDir := t.TempDir()
+	dslFile := filepath.Join(tmpDir, "workspace.dsl")
+	err := os.WriteFile(dslFile, []byte(`workspace {
+		model {
+			user = person "User"
+			shop = softwareSystem "Shop"
+			user -> shop "Uses"
+		}
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runImport([]string{"structurizr", dslFile}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("runImport failed: %s", stderr.String())
+	}
+	for _, want := range []string{`user = person "User"`, `shop = system "Shop"`, `user -> shop "Uses"`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in output, got: %s", want, stdout.String())
+		}
+	}
+
+	// Workspace JSON is detected by its content
+	jsonFile := filepath.Join(tmpDir, "workspace.txt")
+	err = os.WriteFile(jsonFile, []byte(`{"name": "Shop", "model": {"people": [{"id": "1", "name": "User"}]}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	exitCode = runImport([]string{"structurizr", jsonFile}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("runImport failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `User = person "User"`) {
+		t.Errorf("Expected imported person, got: %s", stdout.String())
+	}
+
+	// Invalid DSL
+	badFile := filepath.Join(tmpDir, "bad.dsl")
+	if err := os.WriteFile(badFile, []byte(`model {}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if runImport([]string{"structurizr", badFile}, &stdout, &stderr) == 0 {
+		t.Error("Expected failure for invalid workspace")
+	}
+	if !strings.Contains(stderr.String(), "Error importing Structurizr workspace") {
+		t.Errorf("Expected import error, got: %s", stderr.String())
+	}
+}
+
 func TestRunImport_Errors(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 
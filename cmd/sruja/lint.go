@@ -17,6 +17,12 @@ import (
 func runLint(args []string, stdout, stderr io.Writer) int {
 	lintCmd := flag.NewFlagSet("lint", flag.ContinueOnError)
 	lintCmd.SetOutput(stderr)
+	formatFlag := lintCmd.String("format", formatText, "Output format: "+outputFormats())
+	baselineMode := lintCmd.String("baseline", "", "Record known diagnostics (write) or report only new ones (use)")
+	baselineFile := lintCmd.String("baseline-file", "", "Baseline file (default: "+engine.BaselineFileName+" next to the architecture)")
+	var rules ruleSelection
+	lintCmd.Var(&rules.enable, "enable", "Run a rule in addition to the configured ones (repeatable, comma-separated; see sruja rules list)")
+	lintCmd.Var(&rules.disable, "disable", "Do not run a rule (repeatable, comma-separated)")
 
 	if err := lintCmd.Parse(args); err != nil {
 		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing lint flags: %v", err)))
@@ -24,7 +30,16 @@ func runLint(args []string, stdout, stderr io.Writer) int {
 	}
 
 	if lintCmd.NArg() < 1 {
-		_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja lint <file>"))
+		_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja lint [--format <format>] [--baseline write|use] [--enable <rule>] [--disable <rule>] <file>"))
+		return 1
+	}
+	format, err := parseOutputFormat(*formatFlag)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(err.Error()))
+		return 1
+	}
+	if *baselineMode != "" && *baselineMode != "write" && *baselineMode != "use" {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Invalid --baseline %q (expected write or use)", *baselineMode)))
 		return 1
 	}
 
@@ -43,6 +58,7 @@ func runLint(args []string, stdout, stderr io.Writer) int {
 
 	var program *language.Program
 	var content string // For error context
+	var importDiags []diagnostics.Diagnostic
 
 	if info.IsDir() {
 		ws, err := p.ParseWorkspace(filePath)
@@ -50,6 +66,12 @@ func runLint(args []string, stdout, stderr io.Writer) int {
 			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Workspace Parser Error: %v", err)))
 			return 1
 		}
+		// Imports of modules that cannot be loaded or that conflict
+		for _, d := range ws.Diags {
+			if strings.HasPrefix(d.Code, "E5") {
+				importDiags = append(importDiags, d)
+			}
+		}
 		// Resolve references across the workspace
 		engine.RunWorkspaceResolution(ws)
 		program = ws.MergedProgram()
@@ -70,13 +92,45 @@ func runLint(args []string, stdout, stderr io.Writer) int {
 		engine.RunResolution(program)
 	}
 
-	// Validation
-	validator := engine.NewValidator()
-	validator.RegisterDefaultRules()
+	// Validation (rules, severities and strictness come from sruja.config.json
+	// and --enable/--disable), reporting suppressions that are no longer needed
+	validator, err := projectValidator(filePath, rules, engine.WithUnusedSuppressions())
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Config Error: %v", err)))
+		return 1
+	}
 
-	diags := validator.Validate(program)
+	diags := append(importDiags, validator.Validate(program)...)
+
+	// Known diagnostics are recorded in, or left out through, a baseline
+	if *baselineMode != "" {
+		path := *baselineFile
+		if path == "" {
+			path = filepath.Join(targetDir(filePath), engine.BaselineFileName)
+		}
+		if *baselineMode == "write" {
+			baseline := engine.NewBaseline(program, diags)
+			if err := baseline.Save(path); err != nil {
+				_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error writing baseline: %v", err)))
+				return 1
+			}
+			_, _ = fmt.Fprintln(stdout, dx.Success(fmt.Sprintf("Baseline of %d diagnostic(s) written to %s", len(diags), path)))
+			return 0
+		}
+		baseline, err := engine.LoadBaseline(path)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Baseline Error: %v", err)))
+			return 1
+		}
+		var fixed int
+		diags, fixed = baseline.Filter(program, diags)
+		if fixed > 0 {
+			_, _ = fmt.Fprintln(stderr, dx.Info(fmt.Sprintf("%d diagnostic(s) in the baseline are no longer reported; run with --baseline write to update it", fixed)))
+		}
+	}
 
 	// Filter diagnostics
+	var reported []diagnostics.Diagnostic
 	var blockingErrors []diagnostics.Diagnostic
 	var warnings []diagnostics.Diagnostic
 
@@ -86,6 +140,7 @@ func runLint(args []string, stdout, stderr io.Writer) int {
 		if d.Code == diagnostics.CodeCycleDetected && d.Severity == diagnostics.SeverityInfo {
 			continue // Cycles are valid - skip informational messages
 		}
+		reported = append(reported, d)
 
 		switch d.Severity {
 		case diagnostics.SeverityError:
@@ -95,6 +150,18 @@ func runLint(args []string, stdout, stderr io.Writer) int {
 		}
 	}
 
+	// Machine-readable output replaces the human-formatted one
+	if format != formatText {
+		if err := writeReport(stdout, format, filePath, validator, reported); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error writing report: %v", err)))
+			return 1
+		}
+		if len(blockingErrors) > 0 {
+			return 1
+		}
+		return 0
+	}
+
 	// Print warnings first (non-blocking)
 	if len(warnings) > 0 {
 		enhancer := dx.NewErrorEnhancer(filePath, strings.Split(string(content), "\n"), program)
@@ -72,3 +72,171 @@ func TestRunLint_FlagsError(t *testing.T) {
 		t.Errorf("Expected flag parse error, got: %s", stderr.String())
 	}
 }
+
+func TestRunLint_ProjectConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	archDir := filepath.Join(tmpDir, "arch")
+	if err := os.MkdirAll(archDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(archDir, "model.sruja")
+	content := `system = kind "System"
+		S1 = system "System 1"
+		S2 = system "System 2"
+		S3 = system "Unused"
+		S1 -> S2 "uses"`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := runLint([]string{file}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected warnings only without config, got exit %d: %s", exitCode, stderr.String())
+	}
+
+	// Strict mode in a parent directory's config promotes the orphan warning to a failure
+	configPath := filepath.Join(tmpDir, "sruja.config.json")
+	if err := os.WriteFile(configPath, []byte(`{"validation": {"strict": true}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{file}, &stdout, &stderr); exitCode == 0 {
+		t.Error("Expected strict config to fail lint on warnings")
+	}
+
+	// Turning the rule off silences it even in strict mode
+	if err := os.WriteFile(configPath, []byte(`{"validation": {"strict": true, "severity": {"orphan-detection": "off", "simplicity": "off", "public-interface-documentation": "off"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{file}, &stdout, &stderr); exitCode != 0 {
+		t.Errorf("Expected lint to pass with orphan rule off, got exit %d: %s", exitCode, stderr.String())
+	}
+
+	// Unknown rules are reported
+	if err := os.WriteFile(configPath, []byte(`{"validation": {"rules": ["bogus"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{file}, &stdout, &stderr); exitCode == 0 || !strings.Contains(stderr.String(), "bogus") {
+		t.Errorf("Expected config error for unknown rule, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestRunLint_Format(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "invalid.sruja")
+	content := `system = kind "System"
+		S1 = system "System 1"
+		S1 = system "Duplicate System"`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := runLint([]string{"--format", "sarif", file}, &stdout, &stderr); exitCode == 0 {
+		t.Error("Expected non-zero exit code for invalid file")
+	}
+	if !strings.Contains(stdout.String(), `"version": "2.1.0"`) || !strings.Contains(stdout.String(), `"ruleId": "E201"`) {
+		t.Errorf("Expected SARIF log on stdout, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	runLint([]string{"--format", "github", file}, &stdout, &stderr)
+	if !strings.Contains(stdout.String(), "::error file=") || !strings.Contains(stdout.String(), "title=E201 Unique IDs::") {
+		t.Errorf("Expected GitHub annotations, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{"--format", "yaml", file}, &stdout, &stderr); exitCode == 0 || !strings.Contains(stderr.String(), "unknown format") {
+		t.Errorf("Expected unknown format error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestRunLint_Baseline(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "legacy.sruja")
+	content := `system = kind "System"
+		// sruja:ignore E205 reason="kept for the migration"
+		Old = system "Old"
+		Unused = system "Unused"`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Strict mode fails lint on the orphan warning
+	if err := os.WriteFile(filepath.Join(tmpDir, "sruja.config.json"), []byte(`{"validation": {"strict": true}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := runLint([]string{file}, &stdout, &stderr); exitCode == 0 {
+		t.Fatal("Expected the unsuppressed orphan to fail lint")
+	}
+	if strings.Contains(stderr.String(), "'Old'") {
+		t.Errorf("Expected the suppressed orphan to be silenced, got: %s", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{"--baseline", "write", file}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected baseline write to succeed, got exit %d: %s", exitCode, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "sruja.baseline.json")); err != nil {
+		t.Fatalf("Expected baseline file: %v", err)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{"--baseline", "use", file}, &stdout, &stderr); exitCode != 0 {
+		t.Errorf("Expected baselined diagnostics to pass, got exit %d: %s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := runLint([]string{"--baseline", "bogus", file}, &stdout, &stderr); exitCode == 0 || !strings.Contains(stderr.String(), "expected write or use") {
+		t.Errorf("Expected invalid baseline mode error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestRunLint_EnableDisable(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "tags.sruja")
+	content := `system = kind "System"
+		A = system "A"
+		B = system "B"
+		A -> B "streams" [broadcasts]`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := runLint([]string{file}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected lint to pass without relation-tags, got exit %d: %s", exitCode, stderr.String())
+	}
+	if strings.Contains(stderr.String(), "broadcasts") {
+		t.Errorf("relation-tags is not a default rule, got: %s", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	runLint([]string{"--enable", "relation-tags", file}, &stdout, &stderr)
+	if !strings.Contains(stderr.String(), "Invalid relation tag 'broadcasts'") {
+		t.Errorf("Expected --enable to run relation-tags, got: %s", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	runLint([]string{"--enable", "relation-tags", "--disable", "relation-tags,orphan-detection", file}, &stdout, &stderr)
+	if strings.Contains(stderr.String(), "broadcasts") {
+		t.Errorf("Expected --disable to win over --enable, got: %s", stderr.String())
+	}
+
+	stderr.Reset()
+	if exitCode := runLint([]string{"--enable", "no-such-rule", file}, &stdout, &stderr); exitCode == 0 || !strings.Contains(stderr.String(), "unknown rule") {
+		t.Errorf("Expected unknown rule error, got exit %d: %s", exitCode, stderr.String())
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/sruja-ai/sruja/pkg/dx"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const modUsage = "Usage: sruja mod <vendor|tidy> [dir]"
+
+func runMod(args []string, stdout, stderr io.Writer) int {
+	modCmd := flag.NewFlagSet("mod", flag.ContinueOnError)
+	modCmd.SetOutput(stderr)
+
+	if err := modCmd.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing mod flags: %v", err)))
+		return 1
+	}
+	if modCmd.NArg() < 1 || modCmd.NArg() > 2 {
+		_, _ = fmt.Fprintln(stderr, dx.Error(modUsage))
+		return 1
+	}
+
+	var run func(string) error
+	switch modCmd.Arg(0) {
+	case "vendor":
+		run = language.VendorModules
+	case "tidy":
+		run = language.TidyModules
+	default:
+		_, _ = fmt.Fprintln(stderr, dx.Error(modUsage))
+		return 1
+	}
+
+	dir := "."
+	if modCmd.NArg() == 2 {
+		dir = modCmd.Arg(1)
+	}
+	modPath := language.FindModFile(dir)
+	if modPath == "" {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("No %s found in %s or its parents", language.ModFileName, dir)))
+		return 1
+	}
+	if err := run(modPath); err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Module Error: %v", err)))
+		return 1
+	}
+
+	_, _ = fmt.Fprintln(stdout, dx.Success(fmt.Sprintf("Updated modules of %s", modPath)))
+	return 0
+}
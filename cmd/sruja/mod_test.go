@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunMod(t *testing.T) {
+	t.Setenv("SRUJA_MODCACHE", t.TempDir())
+	tmpDir := t.TempDir()
+	app := filepath.Join(tmpDir, "shop")
+	files := map[string]string{
+		filepath.Join(tmpDir, "platform", "sruja.mod"):   "module acme.com/platform\n",
+		filepath.Join(tmpDir, "platform", "kinds.sruja"): "service = kind \"Service\"\n",
+		filepath.Join(app, "sruja.mod"):                  "module acme.com/shop\nrequire acme.com/platform v1.0.0 => ../platform\n",
+		filepath.Join(app, "main.sruja"):                 "import { service } from 'acme.com/platform'\nShop = service \"Shop\"\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runMod([]string{"vendor", app}, &stdout, &stderr); code != 0 {
+		t.Fatalf("vendor exit code %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(app, "vendor", "acme.com", "platform", "kinds.sruja")); err != nil {
+		t.Fatalf("module not vendored: %v", err)
+	}
+	if code := runMod([]string{"tidy", filepath.Join(app, "vendor")}, &stdout, &stderr); code != 0 {
+		t.Fatalf("tidy exit code %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := runLint([]string{app}, &stdout, &stderr); code != 0 {
+		t.Fatalf("lint exit code %d: %s", code, stderr.String())
+	}
+
+	// Lint reports vendored files that no longer match sruja.sum
+	if err := os.WriteFile(filepath.Join(app, "vendor", "acme.com", "platform", "kinds.sruja"), []byte("service = kind \"Microservice\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if code := runLint([]string{app}, &stdout, &stderr); code == 0 || !strings.Contains(stderr.String(), "checksum mismatch") {
+		t.Errorf("expected a checksum error, got exit code %d: %s", code, stderr.String())
+	}
+
+	for _, args := range [][]string{{}, {"download"}, {"vendor", t.TempDir()}} {
+		stderr.Reset()
+		if code := runMod(args, &stdout, &stderr); code == 0 {
+			t.Errorf("runMod(%v) succeeded", args)
+		}
+	}
+}
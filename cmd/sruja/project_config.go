@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/engine"
+)
+
+// loadProjectConfig loads the sruja.config.json that applies to target (a file or directory),
+// found by walking up from the target's directory.
+func loadProjectConfig(target string) (*config.Config, error) {
+	return config.LoadConfigFrom(targetDir(target))
+}
+
+// targetDir returns target if it is a directory, or the directory of the file.
+func targetDir(target string) string {
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		return filepath.Dir(target)
+	}
+	return target
+}
+
+// projectValidator builds the validator configured for target by the project config,
+// with the rules of sel enabled or disabled on top of it.
+func projectValidator(target string, sel ruleSelection, opts ...engine.ValidatorOption) (*engine.Validator, error) {
+	cfg, err := loadProjectConfig(target)
+	if err != nil {
+		return nil, err
+	}
+	return engine.NewValidatorFromConfig(sel.apply(cfg.Validation), nil, opts...)
+}
+
+// ruleList collects rule IDs from a repeatable flag taking comma-separated IDs.
+type ruleList []string
+
+func (l *ruleList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *ruleList) Set(value string) error {
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := engine.LookupRule(id); !ok {
+			return fmt.Errorf("unknown rule %q (see sruja rules list)", id)
+		}
+		*l = append(*l, id)
+	}
+	return nil
+}
+
+// ruleSelection holds the rules enabled and disabled on the command line.
+type ruleSelection struct {
+	enable, disable ruleList
+}
+
+// apply returns a copy of cfg that also runs the enabled rules and turns the
+// disabled ones off.
+func (s ruleSelection) apply(cfg *config.ValidationConfig) *config.ValidationConfig {
+	if len(s.enable) == 0 && len(s.disable) == 0 {
+		return cfg
+	}
+	out := &config.ValidationConfig{}
+	if cfg != nil {
+		*out = *cfg
+	}
+
+	ids := out.Rules
+	if len(ids) == 0 {
+		ids = engine.DefaultRuleIDs
+	}
+	out.Rules = append([]string(nil), ids...)
+	out.Severity = make(map[string]string, len(out.Severity)+len(s.disable))
+	if cfg != nil {
+		for id, sev := range cfg.Severity {
+			out.Severity[id] = sev
+		}
+	}
+
+	for _, id := range s.enable {
+		found := false
+		for _, existing := range out.Rules {
+			if existing == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out.Rules = append(out.Rules, id)
+		}
+		if strings.EqualFold(out.Severity[id], config.SeverityOff) {
+			delete(out.Severity, id)
+		}
+	}
+	for _, id := range s.disable {
+		out.Severity[id] = config.SeverityOff
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/report"
+)
+
+// formatText is the human-formatted output of lint and compile.
+const formatText = "text"
+
+// outputFormats lists the values accepted by --format.
+func outputFormats() string {
+	names := []string{formatText}
+	for _, f := range report.Formats {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseOutputFormat validates a --format value.
+func parseOutputFormat(s string) (report.Format, error) {
+	if strings.EqualFold(s, formatText) {
+		return formatText, nil
+	}
+	return report.ParseFormat(s)
+}
+
+// writeReport writes the diagnostics of target in a machine-readable format,
+// listing the rules the validator ran.
+func writeReport(w io.Writer, format report.Format, target string, validator *engine.Validator, diags []diagnostics.Diagnostic) error {
+	rules := make([]string, 0, len(validator.Rules))
+	for _, rule := range validator.Rules {
+		rules = append(rules, rule.Name())
+	}
+	return report.Write(w, format, &report.Report{
+		Tool:        "sruja",
+		Version:     version,
+		Target:      target,
+		Rules:       rules,
+		Diagnostics: diags,
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sruja-ai/sruja/pkg/dx"
+	"github.com/sruja-ai/sruja/pkg/engine"
+)
+
+const rulesUsage = "Usage: sruja rules list [--json] | sruja rules docs [dir]"
+
+// defaultRuleDocsDir is where sruja rules docs writes the rule pages.
+const defaultRuleDocsDir = "docs/rules"
+
+func runRules(args []string, stdout, stderr io.Writer) int {
+	rulesCmd := flag.NewFlagSet("rules", flag.ContinueOnError)
+	rulesCmd.SetOutput(stderr)
+	rulesJSON := rulesCmd.Bool("json", false, "output as JSON")
+
+	if len(args) < 1 {
+		_, _ = fmt.Fprintln(stderr, dx.Error(rulesUsage))
+		return 1
+	}
+	if err := rulesCmd.Parse(args[1:]); err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing rules flags: %v", err)))
+		return 1
+	}
+
+	switch args[0] {
+	case "list":
+		if rulesCmd.NArg() > 0 {
+			_, _ = fmt.Fprintln(stderr, dx.Error(rulesUsage))
+			return 1
+		}
+		if err := listRules(stdout, *rulesJSON); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error listing rules: %v", err)))
+			return 1
+		}
+		return 0
+	case "docs":
+		if rulesCmd.NArg() > 1 {
+			_, _ = fmt.Fprintln(stderr, dx.Error(rulesUsage))
+			return 1
+		}
+		dir := defaultRuleDocsDir
+		if rulesCmd.NArg() == 1 {
+			dir = rulesCmd.Arg(0)
+		}
+		paths, err := engine.WriteRuleDocs(dir)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error writing rule docs: %v", err)))
+			return 1
+		}
+		_, _ = fmt.Fprintln(stdout, dx.Success(fmt.Sprintf("Wrote %d page(s) to %s", len(paths), dir)))
+		return 0
+	default:
+		_, _ = fmt.Fprintln(stderr, dx.Error(rulesUsage))
+		return 1
+	}
+}
+
+// ruleListing is the JSON form of a registered rule.
+type ruleListing struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Severity    string   `json:"severity"`
+	Codes       []string `json:"codes,omitempty"`
+	Default     bool     `json:"default"`
+}
+
+// listRules prints the rule registry.
+func listRules(w io.Writer, jsonOutput bool) error {
+	rules := engine.Rules()
+	if jsonOutput {
+		listing := make([]ruleListing, 0, len(rules))
+		for _, info := range rules {
+			listing = append(listing, ruleListing{
+				ID:          info.ID,
+				Name:        info.Name(),
+				Description: info.Description,
+				Severity:    strings.ToLower(string(info.Severity)),
+				Codes:       info.Codes,
+				Default:     info.Default,
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(listing)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RULE\tSEVERITY\tDEFAULT\tDESCRIPTION")
+	for _, info := range rules {
+		enabled := "no"
+		if info.Default {
+			enabled = "yes"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.ID, strings.ToLower(string(info.Severity)), enabled, info.Description)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunRules(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if exitCode := runRules([]string{"list"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected rules list to succeed, got exit %d: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "relation-tags") || !strings.Contains(stdout.String(), "orphan-detection") {
+		t.Errorf("Expected the rule catalogue, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if exitCode := runRules([]string{"list", "--json"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected rules list --json to succeed, got exit %d: %s", exitCode, stderr.String())
+	}
+	var listing []ruleListing
+	if err := json.Unmarshal(stdout.Bytes(), &listing); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	for _, r := range listing {
+		if r.ID == "orphan-detection" && (r.Severity != "warning" || !r.Default) {
+			t.Errorf("Unexpected listing of orphan-detection: %+v", r)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "rules")
+	stdout.Reset()
+	if exitCode := runRules([]string{"docs", dir}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("Expected rules docs to succeed, got exit %d: %s", exitCode, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "completeness.md")); err != nil {
+		t.Errorf("Expected a page per rule: %v", err)
+	}
+
+	stderr.Reset()
+	if exitCode := runRules([]string{"show"}, &stdout, &stderr); exitCode == 0 || !strings.Contains(stderr.String(), "Usage") {
+		t.Errorf("Expected usage error for unknown subcommand, got exit %d: %s", exitCode, stderr.String())
+	}
+}
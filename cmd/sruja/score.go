@@ -1,8 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/sruja-ai/sruja/pkg/dx"
@@ -10,25 +14,43 @@ import (
 )
 
 var cmdScore = &cobra.Command{
-	Use:   "score [file]",
+	Use:   "score [--profile <name>] [--fail-under <score>] [--history] [file]",
 	Short: "Calculate architecture health score",
-	Long:  `Analyze the architecture and calculate a health score (0-100) based on best practices and validation rules.`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Analyze the architecture and calculate a health score (0-100) based on best practices and validation rules.
+
+Weights, penalties and grade thresholds come from the scoring profile selected in
+sruja.config.json or with --profile. --fail-under fails the command when the score
+is below a minimum, for CI. --history appends the result to a JSON file so the
+score can be plotted across commits.`,
+	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		filePath := ""
-		if len(args) > 0 {
-			filePath = args[0]
-		}
-		if runScore(filePath, cmd.OutOrStdout(), cmd.ErrOrStderr()) != 0 {
+		if runScore(args, cmd.OutOrStdout(), cmd.ErrOrStderr()) != 0 {
 			return fmt.Errorf("score failed")
 		}
 		return nil
 	},
 }
 
-func runScore(filePath string, stdout, stderr io.Writer) int {
+//nolint:gocyclo // Sequential command flow
+func runScore(args []string, stdout, stderr io.Writer) int {
+	scoreCmd := flag.NewFlagSet("score", flag.ContinueOnError)
+	scoreCmd.SetOutput(stderr)
+	profileName := scoreCmd.String("profile", "", "Scoring profile from sruja.config.json (default: the configured profile)")
+	failUnder := scoreCmd.Int("fail-under", -1, "Fail when the score is below this value (default: scoring.failUnder from sruja.config.json)")
+	history := scoreCmd.Bool("history", false, "Append the result to the score history file")
+	historyFile := scoreCmd.String("history-file", "", "Score history file (default: scoring.history, or "+engine.ScoreHistoryFileName+" next to the architecture)")
+
+	if err := scoreCmd.Parse(args); err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error parsing score flags: %v", err)))
+		return 1
+	}
+	if scoreCmd.NArg() > 1 {
+		_, _ = fmt.Fprintln(stderr, dx.Error("Usage: sruja score [--profile <name>] [--fail-under <score>] [--history] [file]"))
+		return 1
+	}
+
 	// 1. Find file
-	targetFile := findSrujaFile(filePath)
+	targetFile := findSrujaFile(scoreCmd.Arg(0))
 	if targetFile == "" {
 		_, _ = fmt.Fprintln(stderr, dx.Error("No .sruja file found. Please specify a file or run in a directory with a .sruja file."))
 		return 1
@@ -41,7 +63,21 @@ func runScore(filePath string, stdout, stderr io.Writer) int {
 	}
 
 	// 3. Score
-	scorer := engine.NewScorer()
+	cfg, err := loadProjectConfig(targetFile)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Config Error: %v", err)))
+		return 1
+	}
+	profile, err := engine.ScoringProfileFromConfig(cfg.Scoring, *profileName)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Config Error: %v", err)))
+		return 1
+	}
+	scorer, err := engine.NewScorerFromConfig(cfg.Validation, engine.WithScoringProfile(profile))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Config Error: %v", err)))
+		return 1
+	}
 	card := scorer.CalculateScore(program)
 
 	// 4. Report
@@ -77,5 +113,60 @@ func runScore(filePath string, stdout, stderr io.Writer) int {
 		_, _ = fmt.Fprintln(stdout, dx.Success("Perfect Score! No deductions."))
 	}
 
+	// 5. Record the result for trend tracking
+	if *history {
+		path := *historyFile
+		if path == "" && cfg.Scoring != nil {
+			path = cfg.Scoring.History
+		}
+		if path == "" {
+			path = filepath.Join(targetDir(targetFile), engine.ScoreHistoryFileName)
+		}
+		if err := recordScore(path, targetFile, card, profile.Name, stdout); err != nil {
+			_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Error recording score history: %v", err)))
+			return 1
+		}
+	}
+
+	// 6. Gate on a minimum score
+	minimum := *failUnder
+	if minimum < 0 {
+		minimum = 0
+		if cfg.Scoring != nil {
+			minimum = cfg.Scoring.FailUnder
+		}
+	}
+	if card.Score < minimum {
+		_, _ = fmt.Fprintln(stderr, dx.Error(fmt.Sprintf("Score %d is below the minimum of %d", card.Score, minimum)))
+		return 1
+	}
+
 	return 0
 }
+
+// recordScore appends a score card to the history file at path, reporting the
+// change since the previous entry.
+func recordScore(path, targetFile string, card engine.ScoreCard, profile string, stdout io.Writer) error {
+	h, err := engine.LoadScoreHistory(path)
+	if err != nil {
+		return err
+	}
+	entry := engine.NewScoreHistoryEntry(card, profile, time.Now())
+	if out, err := git(targetDir(targetFile), "rev-parse", "--short", "HEAD"); err == nil {
+		entry.Commit = strings.TrimSpace(out)
+	}
+
+	if prev := h.Last(); prev != nil {
+		since := prev.Time.Local().Format(time.DateTime)
+		if prev.Commit != "" {
+			since = prev.Commit
+		}
+		_, _ = fmt.Fprintln(stdout, dx.Info(fmt.Sprintf("Score %+d since %s (%d, %s)", card.Score-prev.Score, since, prev.Score, prev.Grade)))
+	}
+	h.Append(entry)
+	if err := h.Save(path); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(stdout, dx.Dim("Score recorded in "+path))
+	return nil
+}
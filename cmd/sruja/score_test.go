@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/sruja-ai/sruja/pkg/engine"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -86,3 +87,43 @@ func TestScoreCommand_FileNotFound(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestRunScore_FailUnderAndHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "arch.sruja")
+	content := `system = kind "System"
+	A = system "A"
+	B = system "B"
+	A -> B "calls"`
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	config := `{"scoring": {
+		"failUnder": 99,
+		"history": "health.json",
+		"profiles": {"lenient": {"grades": {"A": 10, "B": 5, "C": 2, "D": 1}}}
+	}}`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "sruja.config.json"), []byte(config), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, runScore([]string{file}, &stdout, &stderr), "configured failUnder gates the score")
+	assert.Contains(t, stderr.String(), "below the minimum of 99")
+
+	stdout.Reset()
+	stderr.Reset()
+	assert.Equal(t, 0, runScore([]string{"--fail-under", "0", "--profile", "lenient", "--history", file}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "(A)")
+
+	stdout.Reset()
+	assert.Equal(t, 0, runScore([]string{"--fail-under", "0", "--history", file}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "since")
+
+	history, err := engine.LoadScoreHistory(filepath.Join(tempDir, "health.json"))
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	assert.Equal(t, "lenient", history.Entries[0].Profile)
+	assert.Equal(t, "default", history.Entries[1].Profile)
+	assert.Equal(t, history.Entries[0].Score, history.Entries[1].Score)
+
+	stderr.Reset()
+	assert.Equal(t, 1, runScore([]string{"--profile", "missing", file}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unknown scoring profile")
+}
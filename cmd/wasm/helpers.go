@@ -33,6 +33,28 @@ func parseToWorkspace(input, filename string) (*language.Workspace, *language.Pr
 	return ws, ws.MergedProgram(), nil
 }
 
+// parsePartialToWorkspace is parseToWorkspace for editor features, which keep
+// working while the text has syntax errors: the program holds the parts that
+// parse and the workspace diagnostics report every syntax error.
+func parsePartialToWorkspace(input, filename string) (*language.Workspace, *language.Program, error) {
+	p, err := language.DefaultParser()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ws := language.NewWorkspace()
+	prog, diags, err := p.ParsePartial(filename, input)
+	if prog == nil {
+		return nil, nil, err
+	}
+
+	ws.AddProgram(filename, prog, diags)
+	_ = p.ResolveImports(ws, prog, filename)
+	engine.RunWorkspaceResolution(ws)
+
+	return ws, ws.MergedProgram(), nil
+}
+
 // Symbol represents a symbol extracted from the program AST.
 type Symbol struct {
 	Name string `json:"name"`
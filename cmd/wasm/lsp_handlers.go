@@ -5,6 +5,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/sruja-ai/sruja/pkg/diagnostics"
 	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/report"
 )
 
 // getDiagnostics returns diagnostics (errors/warnings) for the DSL text
@@ -27,7 +29,7 @@ func getDiagnostics(this js.Value, args []js.Value) (ret interface{}) {
 	}
 	input := args[0].String()
 
-	ws, program, err := parseToWorkspace(input, "input.sruja")
+	ws, program, err := parsePartialToWorkspace(input, "input.sruja")
 	var diags []diagnostics.Diagnostic
 	if ws != nil {
 		diags = ws.Diags
@@ -42,12 +44,7 @@ func getDiagnostics(this js.Value, args []js.Value) (ret interface{}) {
 
 	// Validator now works directly with Sruja AST (no conversion needed)
 	if program != nil {
-		validator := engine.NewValidator()
-		validator.RegisterRule(&engine.UniqueIDRule{})
-		validator.RegisterRule(&engine.ValidReferenceRule{})
-		validator.RegisterRule(&engine.RelationTagRule{})
-		validator.RegisterRule(&engine.CompletenessRule{})
-		errs := validator.Validate(program)
+		errs := editorValidator().Validate(program)
 		for _, e := range errs {
 			diags = append(diags, diagnostics.Diagnostic{
 				Code:     e.Code,
@@ -79,6 +76,65 @@ func getDiagnostics(this js.Value, args []js.Value) (ret interface{}) {
 	return lspResult(true, string(jsonBytes), "")
 }
 
+// editorValidator returns the validator whose diagnostics the editor shows.
+func editorValidator() *engine.Validator {
+	validator := engine.NewValidator()
+	validator.RegisterRule(&engine.UniqueIDRule{})
+	validator.RegisterRule(&engine.ValidReferenceRule{})
+	validator.RegisterRule(&engine.RelationTagRule{})
+	validator.RegisterRule(&engine.CompletenessRule{})
+	return validator
+}
+
+// lintReport returns the diagnostics for the DSL text serialized in a
+// machine-readable format (sarif, junit, checkstyle or github), as written by
+// `sruja lint --format`.
+func lintReport(this js.Value, args []js.Value) (ret interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			ret = lspResult(false, nil, fmt.Sprint(r))
+		}
+	}()
+
+	if len(args) < 2 {
+		return lspResult(false, nil, "invalid arguments")
+	}
+	input := args[0].String()
+	format, err := report.ParseFormat(args[1].String())
+	if err != nil {
+		return lspResult(false, nil, err.Error())
+	}
+
+	ws, program, err := parsePartialToWorkspace(input, defaultFilename)
+	var diags []diagnostics.Diagnostic
+	if ws != nil {
+		diags = ws.Diags
+	}
+	if err != nil && len(diags) == 0 {
+		return lspResult(false, nil, err.Error())
+	}
+
+	validator := editorValidator()
+	if program != nil {
+		diags = append(diags, validator.Validate(program)...)
+	}
+	rules := make([]string, 0, len(validator.Rules))
+	for _, rule := range validator.Rules {
+		rules = append(rules, rule.Name())
+	}
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, format, &report.Report{
+		Tool:        "sruja",
+		Target:      defaultFilename,
+		Rules:       rules,
+		Diagnostics: diags,
+	}); err != nil {
+		return lspResult(false, nil, fmt.Sprintf("failed to write report: %v", err))
+	}
+	return lspResult(true, buf.String(), "")
+}
+
 // getSymbols returns all symbols (identifiers) in the DSL
 func getSymbols(this js.Value, args []js.Value) (ret interface{}) {
 	defer func() {
@@ -92,7 +148,7 @@ func getSymbols(this js.Value, args []js.Value) (ret interface{}) {
 	}
 	input := args[0].String()
 
-	_, program, err := parseToWorkspace(input, "input.sruja")
+	_, program, err := parsePartialToWorkspace(input, "input.sruja")
 	if err != nil || program == nil {
 		return lspResult(true, "[]", "")
 	}
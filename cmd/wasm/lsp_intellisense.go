@@ -23,7 +23,7 @@ func hover(this js.Value, args []js.Value) (ret interface{}) {
 	line := args[1].Int()
 	column := args[2].Int()
 
-	_, program, err := parseToWorkspace(input, "input.sruja")
+	_, program, err := parsePartialToWorkspace(input, "input.sruja")
 	if err != nil || program == nil {
 		return lspResult(true, "null", "")
 	}
@@ -55,7 +55,7 @@ func completion(this js.Value, args []js.Value) (ret interface{}) {
 
 	keywords := []string{"specification", "model", "views", "system", "container", "component", "database", "queue", "person", "relation", "requirement", "adr", "library", "import", "metadata", "description"}
 
-	_, program, _ := parseToWorkspace(input, "input.sruja")
+	_, program, _ := parsePartialToWorkspace(input, "input.sruja")
 	if program != nil {
 		// Extract symbols from Model block
 		symbols := extractSymbolsFromProgram(program)
@@ -0,0 +1,67 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/sourcegraph/go-lsp"
+	srujalsp "github.com/sruja-ai/sruja/pkg/lsp"
+)
+
+// openInServer opens DSL text in a language server of its own, for the editor
+// features the server implements in full.
+func openInServer(input string) (*srujalsp.Server, lsp.TextDocumentIdentifier) {
+	srv := srujalsp.NewServer()
+	doc := lsp.TextDocumentItem{URI: lsp.DocumentURI(defaultFilename), LanguageID: "sruja", Version: 1, Text: input}
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: doc})
+	return srv, lsp.TextDocumentIdentifier{URI: doc.URI}
+}
+
+// inlayHints returns the resolved IDs of short relation endpoints and the
+// inherited technology of elements, with LSP positions (0-based).
+func inlayHints(this js.Value, args []js.Value) (ret interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			ret = lspResult(false, nil, fmt.Sprint(r))
+		}
+	}()
+
+	if len(args) < 1 {
+		return lspResult(false, nil, "invalid arguments")
+	}
+	srv, doc := openInServer(args[0].String())
+	hints, err := srv.InlayHints(context.Background(), srujalsp.InlayHintParams{TextDocument: doc})
+	if err != nil {
+		return lspResult(false, nil, err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(hints)
+	return lspResult(true, string(jsonBytes), "")
+}
+
+// codeLenses returns a lens per element summarizing its relations, ADRs and
+// scenarios, with LSP ranges (0-based). The lens command's first argument is
+// the document URI, which callers replace with their own.
+func codeLenses(this js.Value, args []js.Value) (ret interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			ret = lspResult(false, nil, fmt.Sprint(r))
+		}
+	}()
+
+	if len(args) < 1 {
+		return lspResult(false, nil, "invalid arguments")
+	}
+	srv, doc := openInServer(args[0].String())
+	lenses, err := srv.CodeLenses(context.Background(), lsp.CodeLensParams{TextDocument: doc})
+	if err != nil {
+		return lspResult(false, nil, err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(lenses)
+	return lspResult(true, string(jsonBytes), "")
+}
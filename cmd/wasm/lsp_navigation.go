@@ -24,7 +24,7 @@ func goToDefinition(this js.Value, args []js.Value) (ret interface{}) {
 	line := args[1].Int()
 	column := args[2].Int()
 
-	_, program, err := parseToWorkspace(input, "input.sruja")
+	_, program, err := parsePartialToWorkspace(input, "input.sruja")
 	if err != nil || program == nil {
 		return lspResult(true, "null", "")
 	}
@@ -54,7 +54,7 @@ func findReferences(this js.Value, args []js.Value) (ret interface{}) {
 	line := args[1].Int()
 	column := args[2].Int()
 
-	_, program, err := parseToWorkspace(input, "input.sruja")
+	_, program, err := parsePartialToWorkspace(input, "input.sruja")
 	if err != nil || program == nil {
 		return lspResult(true, "[]", "")
 	}
@@ -43,6 +43,7 @@ func main() {
 	// js.Global().Set("sruja_json_to_dsl", jsonToDslFn) // Removed
 
 	js.Global().Set("sruja_get_diagnostics", js.FuncOf(getDiagnostics))
+	js.Global().Set("sruja_lint_report", js.FuncOf(lintReport))
 	js.Global().Set("sruja_get_symbols", js.FuncOf(getSymbols))
 	js.Global().Set("sruja_hover", js.FuncOf(hover))
 	js.Global().Set("sruja_completion", js.FuncOf(completion))
@@ -54,6 +55,8 @@ func main() {
 	js.Global().Set("sruja_semantic_tokens", js.FuncOf(semanticTokens))
 	js.Global().Set("sruja_document_links", js.FuncOf(documentLinks))
 	js.Global().Set("sruja_folding_ranges", js.FuncOf(foldingRanges))
+	js.Global().Set("sruja_inlay_hints", js.FuncOf(inlayHints))
+	js.Global().Set("sruja_code_lenses", js.FuncOf(codeLenses))
 	js.Global().Set("sruja_analyze_governance", js.FuncOf(score))
 	js.Global().Set("sruja_dsl_to_mermaid", js.FuncOf(dslToMermaid))
 	js.Global().Set("sruja_dsl_to_markdown", js.FuncOf(dslToMarkdown))
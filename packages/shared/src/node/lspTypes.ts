@@ -96,3 +96,35 @@ export interface FoldingRange {
   kind?: string;
 }
 
+
+/**
+ * Inline hint, such as the resolved ID of a relation endpoint.
+ * Positions are 0-based.
+ * 
+ * @public
+ */
+export interface InlayHint {
+  position: { line: number; character: number };
+  label: string;
+  kind?: number;
+  tooltip?: string;
+  paddingLeft?: boolean;
+}
+
+/**
+ * Code lens summarizing an element, with the command to open its diagram.
+ * Ranges are 0-based.
+ * 
+ * @public
+ */
+export interface CodeLens {
+  range: {
+    start: { line: number; character: number };
+    end: { line: number; character: number };
+  };
+  command?: {
+    title: string;
+    command: string;
+    arguments?: unknown[];
+  };
+}
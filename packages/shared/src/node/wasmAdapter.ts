@@ -16,6 +16,8 @@ import type {
   CodeAction,
   DocumentLink,
   FoldingRange,
+  InlayHint,
+  CodeLens,
 } from "./lspTypes";
 
 // Re-export LSP types for convenience
@@ -28,6 +30,8 @@ export type {
   CodeAction,
   DocumentLink,
   FoldingRange,
+  InlayHint,
+  CodeLens,
 } from "./lspTypes";
 
 /**
@@ -38,6 +42,7 @@ interface WasmGlobalFunctions {
   sruja_parse_dsl?: (dsl: string, filename?: string) => string;
   sruja_json_to_dsl?: (json: string) => string;
   sruja_get_diagnostics?: (text: string) => string;
+  sruja_lint_report?: (text: string, format: string) => string;
   sruja_get_symbols?: (text: string) => string;
   sruja_hover?: (text: string, line: number, column: number) => string;
   sruja_completion?: (text: string, line: number, column: number) => string;
@@ -49,6 +54,8 @@ interface WasmGlobalFunctions {
   sruja_semantic_tokens?: (text: string) => string;
   sruja_document_links?: (text: string) => string;
   sruja_folding_ranges?: (text: string) => string;
+  sruja_inlay_hints?: (text: string) => string;
+  sruja_code_lenses?: (text: string) => string;
   sruja_dsl_to_mermaid?: (dsl: string) => string;
   sruja_dsl_to_markdown?: (dsl: string) => string;
   sruja_dsl_to_model?: (dsl: string, filename?: string) => string;
@@ -67,6 +74,7 @@ export type NodeWasmApi = {
   dslToModel: (dsl: string, filename?: string) => Promise<string>;
   // LSP functions
   getDiagnostics: (text: string) => Promise<Diagnostic[]>;
+  lintReport: (text: string, format: LintReportFormat) => Promise<string>;
   getSymbols: (text: string) => Promise<Symbol[]>;
   hover: (text: string, line: number, column: number) => Promise<HoverInfo | null>;
   completion: (text: string, line: number, column: number) => Promise<CompletionItem[]>;
@@ -78,8 +86,17 @@ export type NodeWasmApi = {
   semanticTokens: (text: string) => Promise<number[]>;
   documentLinks: (text: string) => Promise<DocumentLink[]>;
   foldingRanges: (text: string) => Promise<FoldingRange[]>;
+  inlayHints: (text: string) => Promise<InlayHint[]>;
+  codeLenses: (text: string) => Promise<CodeLens[]>;
 };
 
+/**
+ * Machine-readable lint report formats, as written by `sruja lint --format`.
+ *
+ * @public
+ */
+export type LintReportFormat = "sarif" | "junit" | "checkstyle" | "github";
+
 interface ParseResult {
   ok: boolean;
   json?: string;
@@ -172,6 +189,7 @@ export async function initWasmNode(options?: {
   const parseFn = wasmGlobals.sruja_parse_dsl;
   const jsonToDslFn = wasmGlobals.sruja_json_to_dsl;
   const diagnosticsFn = wasmGlobals.sruja_get_diagnostics;
+  const lintReportFn = wasmGlobals.sruja_lint_report;
   const symbolsFn = wasmGlobals.sruja_get_symbols;
   const hoverFn = wasmGlobals.sruja_hover;
   const completionFn = wasmGlobals.sruja_completion;
@@ -183,6 +201,8 @@ export async function initWasmNode(options?: {
   const semanticTokensFn = wasmGlobals.sruja_semantic_tokens;
   const documentLinksFn = wasmGlobals.sruja_document_links;
   const foldingRangesFn = wasmGlobals.sruja_folding_ranges;
+  const inlayHintsFn = wasmGlobals.sruja_inlay_hints;
+  const codeLensesFn = wasmGlobals.sruja_code_lenses;
   const mermaidFn = wasmGlobals.sruja_dsl_to_mermaid;
   const markdownFn = wasmGlobals.sruja_dsl_to_markdown;
   const modelFn = wasmGlobals.sruja_dsl_to_model;
@@ -197,6 +217,7 @@ export async function initWasmNode(options?: {
   // Log available LSP functions for debugging
   const availableLspFunctions = [];
   if (diagnosticsFn) availableLspFunctions.push("diagnostics");
+  if (lintReportFn) availableLspFunctions.push("lintReport");
   if (symbolsFn) availableLspFunctions.push("symbols");
   if (hoverFn) availableLspFunctions.push("hover");
   if (completionFn) availableLspFunctions.push("completion");
@@ -208,6 +229,8 @@ export async function initWasmNode(options?: {
   if (semanticTokensFn) availableLspFunctions.push("semanticTokens");
   if (documentLinksFn) availableLspFunctions.push("documentLinks");
   if (foldingRangesFn) availableLspFunctions.push("foldingRanges");
+  if (inlayHintsFn) availableLspFunctions.push("inlayHints");
+  if (codeLensesFn) availableLspFunctions.push("codeLenses");
 
   if (availableLspFunctions.length > 0) {
     logger.debug("WASM LSP functions available", {
@@ -298,6 +321,20 @@ export async function initWasmNode(options?: {
         );
       }
     },
+    lintReport: async (text: string, format: LintReportFormat): Promise<string> => {
+      if (!lintReportFn) {
+        throw new Error("lintReport function not available");
+      }
+      const r = lintReportFn(text, format) as unknown as {
+        ok: boolean;
+        data?: string;
+        error?: string;
+      };
+      if (!r || !r.ok) {
+        throw new Error(r?.error || "lint report failed");
+      }
+      return r.data ?? "";
+    },
     printJsonToDsl: async (json: string): Promise<string> => {
       if (!jsonToDslFn) {
         throw new Error("jsonToDsl function not available");
@@ -637,6 +674,22 @@ export async function initWasmNode(options?: {
         "foldingRanges",
         text
       ),
+    inlayHints: (text: string) =>
+      callWasmFunction<InlayHint[]>(
+        inlayHintsFn as unknown as
+          | ((...args: unknown[]) => { ok: boolean; data?: string; error?: string })
+          | undefined,
+        "inlayHints",
+        text
+      ),
+    codeLenses: (text: string) =>
+      callWasmFunction<CodeLens[]>(
+        codeLensesFn as unknown as
+          | ((...args: unknown[]) => { ok: boolean; data?: string; error?: string })
+          | undefined,
+        "codeLenses",
+        text
+      ),
   };
 }
 
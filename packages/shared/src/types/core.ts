@@ -39,9 +39,11 @@ export interface Relationship {
   title?: string | null;
   description?: string | null;
   technology?: string | null;
-  kind?: string | null;
+  kind?: string | null; // declared relationship kind
   tags?: string[] | null;
   metadata?: Record<string, string> | null;
+  bidirectional?: boolean;
+  implied?: boolean;
   color?: string;
   line?: string; // "solid", "dashed", "dotted"
   head?: string;
@@ -245,7 +247,17 @@ export interface ElementKindDump {
 
 export interface RelationshipKindDump {
   title?: string;
+  description?: string;
   technology?: string;
+  async?: boolean;
+  color?: string;
+  line?: string; // "solid", "dashed", "dotted"
+  head?: string;
+  tail?: string;
+  /** Element kinds relations of this kind may start from; empty allows any */
+  from?: string[];
+  /** Element kinds relations of this kind may point to; empty allows any */
+  to?: string[];
 }
 
 export interface TagDump {
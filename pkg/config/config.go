@@ -9,12 +9,24 @@ import (
 	"strings"
 )
 
+// ConfigFileName is the name of the project configuration file.
+const ConfigFileName = "sruja.config.json"
+
+// Rule severity values accepted in ValidationConfig.Severity.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
 // Config represents the Sruja configuration file structure.
 type Config struct {
 	Diagrams   *DiagramsConfig   `json:"diagrams,omitempty"`
 	Plugins    []string          `json:"plugins,omitempty"`
 	Validation *ValidationConfig `json:"validation,omitempty"`
 	LSP        *LSPConfig        `json:"lsp,omitempty"`
+	Scoring    *ScoringConfig    `json:"scoring,omitempty"`
 }
 
 // DiagramsConfig configures diagram generation.
@@ -27,8 +39,61 @@ type DiagramsConfig struct {
 
 // ValidationConfig configures validation rules.
 type ValidationConfig struct {
-	Strict bool     `json:"strict,omitempty"`
-	Rules  []string `json:"rules,omitempty"`
+	// Strict promotes warnings to errors so they fail lint and compile.
+	Strict bool `json:"strict,omitempty"`
+	// Rules selects the rule IDs to run. Empty means the command's default rule set.
+	Rules []string `json:"rules,omitempty"`
+	// Severity overrides the severity of a rule's diagnostics by rule ID
+	// (error, warning, info, or off to disable the rule).
+	Severity map[string]string `json:"severity,omitempty"`
+	// Layers declares the layer model for the layer-violation rule. A layers block
+	// in the DSL takes precedence over this setting.
+	Layers *LayersConfig `json:"layers,omitempty"`
+	// RuleFiles lists .sruja files with rule declarations to evaluate in addition to
+	// the selected rules. Relative paths are resolved against the config file's directory.
+	RuleFiles []string `json:"ruleFiles,omitempty"`
+}
+
+// LayersConfig declares the ordered layers enforced by the layer-violation rule.
+type LayersConfig struct {
+	// Order lists layer names from top to bottom.
+	Order []string `json:"order"`
+	// Members assigns element IDs to layers, keyed by layer name.
+	Members map[string][]string `json:"members,omitempty"`
+	// AllowSkip permits dependencies that skip intermediate layers (default true).
+	AllowSkip *bool `json:"allowSkip,omitempty"`
+	// Allow lists explicitly permitted layer dependencies as "from -> to".
+	Allow []string `json:"allow,omitempty"`
+	// Exceptions lists relations exempt from layer checks as "a.b -> c.d".
+	Exceptions []string `json:"exceptions,omitempty"`
+}
+
+// ScoringConfig configures the architecture health score.
+type ScoringConfig struct {
+	// Profile selects the scoring profile by name. Empty means "default".
+	Profile string `json:"profile,omitempty"`
+	// Profiles declares named scoring profiles. A profile named "default"
+	// adjusts the built-in one.
+	Profiles map[string]*ScoringProfileConfig `json:"profiles,omitempty"`
+	// FailUnder makes sruja score fail when the score is below it (0 disables the gate).
+	FailUnder int `json:"failUnder,omitempty"`
+	// History is the file sruja score --history appends results to. Relative
+	// paths are resolved against the config file's directory.
+	History string `json:"history,omitempty"`
+}
+
+// ScoringProfileConfig overrides parts of the built-in scoring profile.
+// Settings that are left out keep their built-in values.
+type ScoringProfileConfig struct {
+	// Weights sets the relative weight of a category (structural, documentation,
+	// traceability, complexity, standardization).
+	Weights map[string]float64 `json:"weights,omitempty"`
+	// Penalties sets the points deducted per finding, keyed by rule ID or by
+	// scorer check (missing-description, missing-technology, missing-metadata,
+	// low-traceability, validation-error).
+	Penalties map[string]int `json:"penalties,omitempty"`
+	// Grades sets the minimum score of the grades A, B, C and D.
+	Grades map[string]int `json:"grades,omitempty"`
 }
 
 // LSPConfig configures LSP behavior.
@@ -41,29 +106,13 @@ type LSPConfig struct {
 //
 //nolint:gocyclo // Config loading is complex
 func LoadConfig(configPath string) (*Config, error) {
-	// Try to find config file
+	// Look for sruja.config.json in current directory and parent directories
 	if configPath == "" {
-		// Look for sruja.config.json in current directory and parent directories
 		dir, err := os.Getwd()
 		if err != nil {
 			return DefaultConfig(), nil
 		}
-
-		for {
-			candidate := filepath.Join(dir, "sruja.config.json")
-			if fi, err := os.Lstat(candidate); err == nil {
-				if fi.Mode().IsRegular() && (fi.Mode()&os.ModeSymlink) == 0 {
-					configPath = candidate
-					break
-				}
-			}
-
-			parent := filepath.Dir(dir)
-			if parent == dir {
-				break // Reached root
-			}
-			dir = parent
-		}
+		configPath = FindConfigFile(dir)
 	}
 
 	if configPath == "" {
@@ -99,14 +148,59 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("error parsing config file: %w", err)
 	}
+	if config.Validation != nil {
+		for i, path := range config.Validation.RuleFiles {
+			if !filepath.IsAbs(path) {
+				config.Validation.RuleFiles[i] = filepath.Join(filepath.Dir(cleanPath), path)
+			}
+		}
+	}
+	if config.Scoring != nil && config.Scoring.History != "" && !filepath.IsAbs(config.Scoring.History) {
+		config.Scoring.History = filepath.Join(filepath.Dir(cleanPath), config.Scoring.History)
+	}
 
 	return &config, nil
 }
 
+// LoadConfigFrom loads the configuration that applies to startDir, discovered by
+// walking up from startDir to the filesystem root. Returns the default config
+// when no config file is found.
+func LoadConfigFrom(startDir string) (*Config, error) {
+	configPath := FindConfigFile(startDir)
+	if configPath == "" {
+		return DefaultConfig(), nil
+	}
+	return LoadConfig(configPath)
+}
+
+// FindConfigFile returns the path of the nearest sruja.config.json in startDir
+// or one of its parents, or "" if there is none.
+func FindConfigFile(startDir string) string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		candidate := filepath.Join(dir, ConfigFileName)
+		if fi, err := os.Lstat(candidate); err == nil {
+			if fi.Mode().IsRegular() && (fi.Mode()&os.ModeSymlink) == 0 {
+				return candidate
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "" // Reached root
+		}
+		dir = parent
+	}
+}
+
 // SaveConfig saves configuration to a file.
 func SaveConfig(config *Config, configPath string) error {
 	if configPath == "" {
-		configPath = "sruja.config.json"
+		configPath = ConfigFileName
 	}
 
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -147,7 +241,6 @@ func DefaultConfig() *Config {
 		Plugins: []string{},
 		Validation: &ValidationConfig{
 			Strict: false,
-			Rules:  []string{"unique-ids", "valid-references", "cycle-detection"},
 		},
 		LSP: &LSPConfig{
 			MetadataSuggestions: true,
@@ -188,6 +281,20 @@ func (c *Config) Merge(other *Config) {
 		if len(other.Validation.Rules) > 0 {
 			c.Validation.Rules = other.Validation.Rules
 		}
+		if other.Validation.Layers != nil {
+			c.Validation.Layers = other.Validation.Layers
+		}
+		if len(other.Validation.RuleFiles) > 0 {
+			c.Validation.RuleFiles = other.Validation.RuleFiles
+		}
+		if len(other.Validation.Severity) > 0 {
+			if c.Validation.Severity == nil {
+				c.Validation.Severity = make(map[string]string, len(other.Validation.Severity))
+			}
+			for id, sev := range other.Validation.Severity {
+				c.Validation.Severity[id] = sev
+			}
+		}
 	}
 
 	if other.LSP != nil {
@@ -198,6 +305,29 @@ func (c *Config) Merge(other *Config) {
 		c.LSP.QuickActions = other.LSP.QuickActions
 	}
 
+	if other.Scoring != nil {
+		if c.Scoring == nil {
+			c.Scoring = &ScoringConfig{}
+		}
+		if other.Scoring.Profile != "" {
+			c.Scoring.Profile = other.Scoring.Profile
+		}
+		if other.Scoring.FailUnder != 0 {
+			c.Scoring.FailUnder = other.Scoring.FailUnder
+		}
+		if other.Scoring.History != "" {
+			c.Scoring.History = other.Scoring.History
+		}
+		if len(other.Scoring.Profiles) > 0 {
+			if c.Scoring.Profiles == nil {
+				c.Scoring.Profiles = make(map[string]*ScoringProfileConfig, len(other.Scoring.Profiles))
+			}
+			for name, profile := range other.Scoring.Profiles {
+				c.Scoring.Profiles[name] = profile
+			}
+		}
+	}
+
 	if len(other.Plugins) > 0 {
 		c.Plugins = other.Plugins
 	}
@@ -435,3 +435,96 @@ func TestMerge_Layout(t *testing.T) {
 		t.Errorf("Expected layout 'dagre', got '%s'", cfg1.Diagrams.Layout)
 	}
 }
+
+func TestLoadConfigFrom_WalksUp(t *testing.T) {
+	tmpDir := t.TempDir()
+	subDir := filepath.Join(tmpDir, "arch", "billing")
+	if err := os.MkdirAll(subDir, 0o755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	configJSON := `{"validation": {"strict": true, "rules": ["unique-ids"], "severity": {"unique-ids": "warning"}}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, ConfigFileName), []byte(configJSON), 0o644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	if got := FindConfigFile(subDir); got != filepath.Join(tmpDir, ConfigFileName) {
+		t.Errorf("FindConfigFile = %q, want config in %s", got, tmpDir)
+	}
+
+	cfg, err := LoadConfigFrom(subDir)
+	if err != nil {
+		t.Fatalf("LoadConfigFrom failed: %v", err)
+	}
+	if !cfg.Validation.Strict {
+		t.Error("Expected strict validation to be true")
+	}
+	if len(cfg.Validation.Rules) != 1 || cfg.Validation.Rules[0] != "unique-ids" {
+		t.Errorf("Unexpected rules: %v", cfg.Validation.Rules)
+	}
+	if cfg.Validation.Severity["unique-ids"] != SeverityWarning {
+		t.Errorf("Expected severity override 'warning', got %q", cfg.Validation.Severity["unique-ids"])
+	}
+}
+
+func TestLoadConfig_ResolvesRuleFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configJSON := `{"validation": {"ruleFiles": ["rules/fitness.sruja", "/abs/rules.sruja"]}}`
+	configPath := filepath.Join(tmpDir, ConfigFileName)
+	if err := os.WriteFile(configPath, []byte(configJSON), 0o644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	want := []string{filepath.Join(tmpDir, "rules", "fitness.sruja"), "/abs/rules.sruja"}
+	if len(cfg.Validation.RuleFiles) != 2 || cfg.Validation.RuleFiles[0] != want[0] || cfg.Validation.RuleFiles[1] != want[1] {
+		t.Errorf("RuleFiles = %v, want %v", cfg.Validation.RuleFiles, want)
+	}
+}
+
+func TestLoadConfig_Scoring(t *testing.T) {
+	tmpDir := t.TempDir()
+	configJSON := `{"scoring": {"profile": "ci", "failUnder": 80, "history": "metrics/score.json",
+		"profiles": {"ci": {"weights": {"structural": 1}, "penalties": {"orphan-detection": 0}}}}}`
+	configPath := filepath.Join(tmpDir, ConfigFileName)
+	if err := os.WriteFile(configPath, []byte(configJSON), 0o644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Scoring == nil || cfg.Scoring.Profile != "ci" || cfg.Scoring.FailUnder != 80 {
+		t.Fatalf("Unexpected scoring config: %+v", cfg.Scoring)
+	}
+	if want := filepath.Join(tmpDir, "metrics", "score.json"); cfg.Scoring.History != want {
+		t.Errorf("History = %q, want %q", cfg.Scoring.History, want)
+	}
+	ci := cfg.Scoring.Profiles["ci"]
+	if ci == nil || ci.Weights["structural"] != 1 || ci.Penalties["orphan-detection"] != 0 {
+		t.Errorf("Unexpected ci profile: %+v", ci)
+	}
+}
+
+func TestLoadConfigFrom_NoFile(t *testing.T) {
+	cfg, err := LoadConfigFrom(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfigFrom should not error without a config file: %v", err)
+	}
+	if len(cfg.Validation.Rules) != 0 {
+		t.Errorf("Default config should not restrict rules, got %v", cfg.Validation.Rules)
+	}
+}
+
+func TestMerge_SeverityOverrides(t *testing.T) {
+	cfg1 := &Config{Validation: &ValidationConfig{Severity: map[string]string{"orphan-detection": "off"}}}
+	cfg2 := &Config{Validation: &ValidationConfig{Severity: map[string]string{"layer-violation": "warning"}}}
+
+	cfg1.Merge(cfg2)
+	if len(cfg1.Validation.Severity) != 2 {
+		t.Errorf("Expected 2 severity overrides, got %v", cfg1.Validation.Severity)
+	}
+}
@@ -25,4 +25,49 @@ const (
 	CodeDuplicateIdentifier = "E201" // Alias for CodeDuplicateID
 	CodeReferenceNotFound   = "E202" // Alias for CodeUndefinedRef
 	CodeBestPractice        = "W001" // Best practice warning
+	CodeUnusedSuppression   = "W002" // sruja:ignore comment that silences nothing
+
+	// User-defined Rules (E4xx)
+	CodeCustomRule        = "E401" // User-defined rule violation (default code)
+	CodeInvalidCustomRule = "E402" // User-defined rule cannot be compiled
+
+	// Module Errors (E5xx)
+	CodeModuleNotFound = "E501" // Imported module cannot be resolved
+	CodeModuleChecksum = "E502" // Module files do not match sruja.sum
+	CodeImportConflict = "E503" // Imports define the same kind or tag differently
 )
+
+// codeTitles describes the standard error codes, for tools listing them.
+var codeTitles = map[string]string{
+	CodeSyntaxError:         "Syntax error",
+	CodeUnexpectedToken:     "Unexpected token",
+	CodeMissingBrace:        "Missing closing brace",
+	CodeInvalidString:       "Invalid string literal",
+	CodeDuplicateID:         "Duplicate identifier",
+	CodeUndefinedRef:        "Undefined reference",
+	CodeInvalidRelation:     "Invalid relation",
+	CodeCycleDetected:       "Cycle detected",
+	CodeOrphanElement:       "Orphan element",
+	CodeLayerViolation:      "Layer violation",
+	CodeInvalidProperty:     "Invalid property",
+	CodeMissingField:        "Missing required field",
+	CodeValidationRuleError: "Validation rule error",
+	CodeValidationTimeout:   "Validation rule timed out",
+	CodeValidationPanic:     "Validation rule panicked",
+	CodeBestPractice:        "Best practice",
+	CodeUnusedSuppression:   "Unused suppression",
+	CodeCustomRule:          "User-defined rule violation",
+	CodeInvalidCustomRule:   "Invalid user-defined rule",
+	CodeModuleNotFound:      "Module not found",
+	CodeModuleChecksum:      "Module checksum mismatch",
+	CodeImportConflict:      "Conflicting imports",
+}
+
+// CodeTitle returns a short description of a standard error code, or the code
+// itself when it is not a standard one.
+func CodeTitle(code string) string {
+	if title, ok := codeTitles[code]; ok {
+		return title
+	}
+	return code
+}
@@ -35,6 +35,7 @@ type Diagnostic struct {
 	Location    SourceLocation // Where the error occurred
 	Context     []string       // Surrounding lines of code
 	Suggestions []string       // Actionable suggestions (e.g., "Did you mean 'system'?")
+	Rule        string         // Name of the validation rule that reported it, if any
 }
 
 func (d Diagnostic) String() string {
@@ -0,0 +1,234 @@
+// Package diff computes semantic differences between two Sruja architectures.
+//
+// Both sides are compared as JSON model dumps, so every change is keyed by the
+// fully-qualified ID of the element, relation, governance item, deployment node
+// or view it applies to.
+package diff
+
+import (
+	"sort"
+
+	jsonexport "github.com/sruja-ai/sruja/pkg/export/json"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// ChangeType is the kind of change made to an item.
+type ChangeType string
+
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Modified ChangeType = "modified"
+)
+
+// Category groups changes by the part of the model they apply to.
+type Category string
+
+const (
+	CategoryElement     Category = "element"
+	CategoryRelation    Category = "relation"
+	CategoryRequirement Category = "requirement"
+	CategoryADR         Category = "adr"
+	CategoryPolicy      Category = "policy"
+	CategoryScenario    Category = "scenario"
+	CategoryFlow        Category = "flow"
+	CategoryDeployment  Category = "deployment"
+	CategoryView        Category = "view"
+	CategoryConstraint  Category = "constraint"
+	CategoryConvention  Category = "convention"
+)
+
+// categoryOrder is the order categories are reported in.
+var categoryOrder = []Category{
+	CategoryElement,
+	CategoryRelation,
+	CategoryRequirement,
+	CategoryADR,
+	CategoryPolicy,
+	CategoryScenario,
+	CategoryFlow,
+	CategoryDeployment,
+	CategoryView,
+	CategoryConstraint,
+	CategoryConvention,
+}
+
+// categoryTitles are the section titles used by the text and markdown outputs.
+var categoryTitles = map[Category]string{
+	CategoryElement:     "Elements",
+	CategoryRelation:    "Relations",
+	CategoryRequirement: "Requirements",
+	CategoryADR:         "ADRs",
+	CategoryPolicy:      "Policies",
+	CategoryScenario:    "Scenarios",
+	CategoryFlow:        "Flows",
+	CategoryDeployment:  "Deployment Nodes",
+	CategoryView:        "Views",
+	CategoryConstraint:  "Constraints",
+	CategoryConvention:  "Conventions",
+}
+
+// Change is a single difference between two architectures. Added and removed
+// items carry their title in New and Old; modified items carry one change per
+// field, e.g. "technology", "tags", "metadata.owner" or "slo.availability.target".
+type Change struct {
+	Type     ChangeType `json:"type"`
+	Category Category   `json:"category"`
+	ID       string     `json:"id"`
+	Kind     string     `json:"kind,omitempty"`
+	Field    string     `json:"field,omitempty"`
+	Old      string     `json:"old,omitempty"`
+	New      string     `json:"new,omitempty"`
+}
+
+// Summary counts the added, removed and modified items of a diff.
+type Summary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+}
+
+// Result is the diff between a base and a head architecture.
+type Result struct {
+	Base    string   `json:"base,omitempty"`
+	Head    string   `json:"head,omitempty"`
+	Summary Summary  `json:"summary"`
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the architectures are identical.
+func (r *Result) Empty() bool {
+	return len(r.Changes) == 0
+}
+
+// ByCategory returns the changes of a category, in report order.
+func (r *Result) ByCategory(category Category) []Change {
+	var out []Change
+	for _, c := range r.Changes {
+		if c.Category == category {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ComparePrograms diffs two parsed programs. Programs should be resolved first so
+// relation endpoints are fully qualified. A nil program compares as empty.
+func ComparePrograms(base, head *language.Program) *Result {
+	exporter := jsonexport.NewExporter()
+	return Compare(exporter.ToModelDump(base), exporter.ToModelDump(head))
+}
+
+// Compare diffs two model dumps.
+func Compare(base, head *jsonexport.SrujaModelDump) *Result {
+	d := &differ{}
+	d.compareRecords(CategoryElement, elementRecords(base), elementRecords(head))
+	d.compareRelations(relations(base), relations(head))
+
+	baseExt, headExt := extensions(base), extensions(head)
+	d.compareRecords(CategoryRequirement, requirementRecords(baseExt), requirementRecords(headExt))
+	d.compareRecords(CategoryADR, adrRecords(baseExt), adrRecords(headExt))
+	d.compareRecords(CategoryPolicy, policyRecords(baseExt), policyRecords(headExt))
+	d.compareRecords(CategoryScenario, scenarioRecords(baseExt.Scenarios), scenarioRecords(headExt.Scenarios))
+	d.compareRecords(CategoryFlow, flowRecords(baseExt), flowRecords(headExt))
+	d.compareRecords(CategoryDeployment, deploymentRecords(baseExt), deploymentRecords(headExt))
+	d.compareRecords(CategoryView, viewRecords(base), viewRecords(head))
+	d.compareRecords(CategoryConstraint, constraintRecords(baseExt), constraintRecords(headExt))
+	d.compareRecords(CategoryConvention, conventionRecords(baseExt), conventionRecords(headExt))
+
+	return d.result()
+}
+
+// record is the comparable form of a model item: its kind, its title and the
+// fields compared when it exists on both sides.
+type record struct {
+	kind   string
+	title  string
+	fields map[string]string
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(c Change) {
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) compareRecords(category Category, base, head map[string]record) {
+	for id, h := range head {
+		b, ok := base[id]
+		if !ok {
+			d.add(Change{Type: Added, Category: category, ID: id, Kind: h.kind, New: h.title})
+			continue
+		}
+		d.compareFields(category, id, h.kind, b, h)
+	}
+	for id, b := range base {
+		if _, ok := head[id]; !ok {
+			d.add(Change{Type: Removed, Category: category, ID: id, Kind: b.kind, Old: b.title})
+		}
+	}
+}
+
+func (d *differ) compareFields(category Category, id, kind string, base, head record) {
+	if base.kind != head.kind {
+		d.add(Change{Type: Modified, Category: category, ID: id, Kind: kind, Field: "kind", Old: base.kind, New: head.kind})
+	}
+	if base.title != head.title {
+		d.add(Change{Type: Modified, Category: category, ID: id, Kind: kind, Field: "title", Old: base.title, New: head.title})
+	}
+	for field, h := range head.fields {
+		if b := base.fields[field]; b != h {
+			d.add(Change{Type: Modified, Category: category, ID: id, Kind: kind, Field: field, Old: b, New: h})
+		}
+	}
+	for field, b := range base.fields {
+		if _, ok := head.fields[field]; !ok && b != "" {
+			d.add(Change{Type: Modified, Category: category, ID: id, Kind: kind, Field: field, Old: b})
+		}
+	}
+}
+
+// result sorts the changes by category, ID and field and counts them. Modified
+// items are counted once however many of their fields changed.
+func (d *differ) result() *Result {
+	rank := make(map[Category]int, len(categoryOrder))
+	for i, c := range categoryOrder {
+		rank[c] = i
+	}
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		a, b := d.changes[i], d.changes[j]
+		if a.Category != b.Category {
+			return rank[a.Category] < rank[b.Category]
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Field < b.Field
+	})
+
+	res := &Result{Changes: d.changes}
+	if res.Changes == nil {
+		res.Changes = []Change{}
+	}
+	modified := make(map[string]bool)
+	for _, c := range res.Changes {
+		switch c.Type {
+		case Added:
+			res.Summary.Added++
+		case Removed:
+			res.Summary.Removed++
+		case Modified:
+			key := string(c.Category) + ":" + c.ID
+			if !modified[key] {
+				modified[key] = true
+				res.Summary.Modified++
+			}
+		}
+	}
+	return res
+}
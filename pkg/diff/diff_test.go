@@ -0,0 +1,346 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+func parse(t *testing.T, source string) *language.Program {
+	t.Helper()
+	p, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := p.Parse("test.sruja", source)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	engine.RunResolution(prog)
+	return prog
+}
+
+func compare(t *testing.T, base, head string) *Result {
+	t.Helper()
+	return ComparePrograms(parse(t, base), parse(t, head))
+}
+
+func hasChange(changes []Change, want Change) bool {
+	for _, c := range changes {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCompare_Identical(t *testing.T) {
+	source := `
+		User = person "User"
+		Shop = system "Shop" {
+			API = container "API"
+		}
+		User -> Shop.API "uses"
+		view main {
+			include *
+		}`
+	if res := compare(t, source, source); !res.Empty() {
+		t.Errorf("Expected no changes, got %+v", res.Changes)
+	}
+}
+
+func TestCompare_Elements(t *testing.T) {
+	res := compare(t, `
+		Shop = system "Shop" {
+			API = container "API" {
+				description "Serves requests"
+				technology "Go"
+				tags ["web"]
+				metadata {
+					owner "team-a"
+				}
+				slo {
+					availability {
+						target "99.9%"
+						window "30d"
+					}
+				}
+			}
+			Legacy = container "Legacy"
+		}`, `
+		Shop = system "Online Shop" {
+			API = container "API" {
+				description "Serves requests"
+				technology "Rust"
+				tags ["web", "public"]
+				metadata {
+					owner "team-b"
+				}
+				slo {
+					availability {
+						target "99.95%"
+						window "30d"
+					}
+				}
+			}
+			Cache = database "Cache"
+		}`)
+
+	for _, want := range []Change{
+		{Type: Added, Category: CategoryElement, ID: "Shop.Cache", Kind: "database", New: "Cache"},
+		{Type: Removed, Category: CategoryElement, ID: "Shop.Legacy", Kind: "container", Old: "Legacy"},
+		{Type: Modified, Category: CategoryElement, ID: "Shop", Kind: "system", Field: "title", Old: "Shop", New: "Online Shop"},
+		{Type: Modified, Category: CategoryElement, ID: "Shop.API", Kind: "container", Field: "technology", Old: "Go", New: "Rust"},
+		{Type: Modified, Category: CategoryElement, ID: "Shop.API", Kind: "container", Field: "tags", Old: "web", New: "public, web"},
+		{Type: Modified, Category: CategoryElement, ID: "Shop.API", Kind: "container", Field: "metadata.owner", Old: "team-a", New: "team-b"},
+		{Type: Modified, Category: CategoryElement, ID: "Shop.API", Kind: "container", Field: "slo.availability.target", Old: "99.9%", New: "99.95%"},
+	} {
+		if !hasChange(res.Changes, want) {
+			t.Errorf("Expected change %+v, got %+v", want, res.Changes)
+		}
+	}
+	if len(res.Changes) != 7 {
+		t.Errorf("Expected 7 changes, got %d: %+v", len(res.Changes), res.Changes)
+	}
+	if res.Summary != (Summary{Added: 1, Removed: 1, Modified: 2}) {
+		t.Errorf("Unexpected summary: %+v", res.Summary)
+	}
+}
+
+func TestCompare_Relations(t *testing.T) {
+	res := compare(t, `
+		User = person "User"
+		Shop = system "Shop" {
+			API = container "API"
+			DB = database "DB"
+			Queue = queue "Queue"
+		}
+		User -> Shop.API "uses"
+		Shop.API -> Shop.DB "reads"
+		Shop.API -> Shop.Queue "publishes"`, `
+		User = person "User"
+		Shop = system "Shop" {
+			API = container "API"
+			DB = database "DB"
+			Queue = queue "Queue"
+			Worker = container "Worker"
+		}
+		User -> Shop.API "browses" [public]
+		Shop.API -> Shop.DB "reads"
+		Shop.Worker -> Shop.Queue "consumes"`)
+
+	rels := res.ByCategory(CategoryRelation)
+	for _, want := range []Change{
+		{Type: Modified, Category: CategoryRelation, ID: "User -> Shop.API", Field: "label", Old: `"uses"`, New: `"browses"`},
+		{Type: Modified, Category: CategoryRelation, ID: "User -> Shop.API", Field: "tags", New: "public"},
+		{Type: Added, Category: CategoryRelation, ID: "Shop.Worker -> Shop.Queue", New: `"consumes"`},
+		{Type: Removed, Category: CategoryRelation, ID: "Shop.API -> Shop.Queue", Old: `"publishes"`},
+	} {
+		if !hasChange(rels, want) {
+			t.Errorf("Expected change %+v, got %+v", want, rels)
+		}
+	}
+	// The implied User -> Shop relation changes with User -> Shop.API
+	for _, c := range rels {
+		if c.ID == "User -> Shop" {
+			t.Errorf("Did not expect implied relation change %+v", c)
+		}
+	}
+	if len(rels) != 4 {
+		t.Errorf("Expected 4 relation changes, got %+v", rels)
+	}
+}
+
+func TestCompare_ExplicitParentRelationsAndArrows(t *testing.T) {
+	res := compare(t, `
+		User = person "User"
+		Shop = system "Shop" {
+			API = container "API"
+		}
+		Cache = database "Cache"
+		User -> Shop.API "uses"
+		Shop.API -> Cache "syncs"`, `
+		User = person "User"
+		Shop = system "Shop" {
+			API = container "API"
+		}
+		Cache = database "Cache"
+		User -> Shop.API "uses"
+		User -> Shop "uses"
+		Shop.API <-> Cache "syncs"`)
+
+	rels := res.ByCategory(CategoryRelation)
+	for _, want := range []Change{
+		// Declared parent relations are compared even when they repeat a child relation
+		{Type: Added, Category: CategoryRelation, ID: "User -> Shop", New: `"uses"`},
+		{Type: Modified, Category: CategoryRelation, ID: "Shop.API <-> Cache", Field: "bidirectional", Old: "false", New: "true"},
+	} {
+		if !hasChange(rels, want) {
+			t.Errorf("Expected change %+v, got %+v", want, rels)
+		}
+	}
+	if len(rels) != 2 {
+		t.Errorf("Expected 2 relation changes, got %+v", rels)
+	}
+}
+
+func TestCompare_GovernanceDeploymentAndViews(t *testing.T) {
+	res := compare(t, `
+		Shop = system "Shop" {
+			API = container "API"
+		}
+		R1 = requirement functional "Checkout" {
+			status "draft"
+		}
+		D1 = adr "Use Go" {
+			status "proposed"
+		}
+		deployment Prod "Production" {
+			node AWS "AWS" {
+				containerInstance API
+			}
+		}
+		view main {
+			title "Main"
+			include *
+		}`, `
+		Shop = system "Shop" {
+			API = container "API"
+		}
+		R1 = requirement functional "Checkout" {
+			status "approved"
+		}
+		R2 = requirement performance "Fast"
+		D1 = adr "Use Go" {
+			status "accepted"
+		}
+		deployment Prod "Production" {
+			node AWS "AWS" {
+				containerInstance API
+				node EKS "EKS"
+			}
+		}
+		view main {
+			title "Main"
+			include Shop.API
+		}`)
+
+	for _, want := range []Change{
+		{Type: Modified, Category: CategoryRequirement, ID: "R1", Kind: "functional", Field: "status", Old: "draft", New: "approved"},
+		{Type: Added, Category: CategoryRequirement, ID: "R2", Kind: "performance", New: "Fast"},
+		{Type: Modified, Category: CategoryADR, ID: "D1", Field: "status", Old: "proposed", New: "accepted"},
+		{Type: Added, Category: CategoryDeployment, ID: "Prod.AWS.EKS", Kind: "node", New: "EKS"},
+		{Type: Modified, Category: CategoryView, ID: "main", Field: "include", Old: "*", New: "Shop.API"},
+	} {
+		if !hasChange(res.Changes, want) {
+			t.Errorf("Expected change %+v, got %+v", want, res.Changes)
+		}
+	}
+	// Governance items are not reported as elements
+	if els := res.ByCategory(CategoryElement); len(els) != 0 {
+		t.Errorf("Expected no element changes, got %+v", els)
+	}
+}
+
+func TestCompare_ViewRuleGrouping(t *testing.T) {
+	res := compare(t, `
+		Shop = system "Shop" {
+			API = container "API"
+			DB = database "DB"
+			Cache = database "Cache"
+		}
+		view main {
+			include Shop.API, Shop.DB
+			include Shop.Cache
+			exclude Shop.DB
+		}`, `
+		Shop = system "Shop" {
+			API = container "API"
+			DB = database "DB"
+			Cache = database "Cache"
+		}
+		view main {
+			include Shop.Cache
+			include Shop.DB
+			include Shop.API, Shop.Cache
+			exclude Shop.DB
+		}`)
+
+	if views := res.ByCategory(CategoryView); len(views) != 0 {
+		t.Errorf("Expected regrouped view rules to be unchanged, got %+v", views)
+	}
+}
+
+func TestComparePrograms_Nil(t *testing.T) {
+	res := ComparePrograms(nil, parse(t, `Shop = system "Shop"`))
+	if !hasChange(res.Changes, Change{Type: Added, Category: CategoryElement, ID: "Shop", Kind: "system", New: "Shop"}) {
+		t.Errorf("Expected Shop added, got %+v", res.Changes)
+	}
+	if res := ComparePrograms(nil, nil); !res.Empty() {
+		t.Errorf("Expected no changes, got %+v", res.Changes)
+	}
+}
+
+func TestResult_Output(t *testing.T) {
+	res := compare(t, `
+		Shop = system "Shop" {
+			API = container "API" {
+				technology "Go"
+			}
+		}`, `
+		Shop = system "Shop" {
+			API = container "API" {
+				technology "Rust | Tokio"
+			}
+			Cache = container "Cache"
+		}`)
+	res.Base, res.Head = "main", "feature"
+
+	var text bytes.Buffer
+	res.WriteText(&text, false)
+	for _, want := range []string{
+		"Elements\n",
+		"  ~ container Shop.API\n      technology: \"Go\" -> \"Rust | Tokio\"\n",
+		"  + container Shop.Cache \"Cache\"\n",
+		"1 added, 0 removed, 1 modified",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected %q in text output, got:\n%s", want, text.String())
+		}
+	}
+
+	var md bytes.Buffer
+	res.WriteMarkdown(&md)
+	for _, want := range []string{
+		"### Architecture diff: `main` → `feature`",
+		"#### Elements",
+		"| Modified | `Shop.API` | technology: `Go` -> `Rust \\| Tokio` |",
+		"| Added | `Shop.Cache` | container Cache |",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Expected %q in markdown output, got:\n%s", want, md.String())
+		}
+	}
+
+	data, err := res.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if decoded.Base != "main" || decoded.Summary != res.Summary || len(decoded.Changes) != len(res.Changes) {
+		t.Errorf("Unexpected decoded result: %+v", decoded)
+	}
+
+	var empty bytes.Buffer
+	(&Result{}).WriteMarkdown(&empty)
+	if !strings.Contains(empty.String(), "No architecture changes.") {
+		t.Errorf("Expected no-changes message, got: %s", empty.String())
+	}
+}
@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/dx"
+)
+
+// JSON returns the diff as indented JSON.
+func (r *Result) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// String summarizes the counts, e.g. "2 added, 1 removed, 3 modified".
+func (s Summary) String() string {
+	return fmt.Sprintf("%d added, %d removed, %d modified", s.Added, s.Removed, s.Modified)
+}
+
+// WriteText writes the diff grouped by category, one line per added or removed
+// item and one line per changed field of a modified item.
+func (r *Result) WriteText(w io.Writer, color bool) {
+	if r.Empty() {
+		_, _ = fmt.Fprintln(w, "No differences found. Architectures are identical.")
+		return
+	}
+	for _, category := range categoryOrder {
+		changes := r.ByCategory(category)
+		if len(changes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintln(w, dx.Colorize(dx.ColorBold, categoryTitles[category], color))
+		lastModified := ""
+		for _, c := range changes {
+			switch c.Type {
+			case Added:
+				_, _ = fmt.Fprintf(w, "  %s %s\n", dx.Colorize(dx.ColorGreen, "+", color), subject(c, c.New))
+			case Removed:
+				_, _ = fmt.Fprintf(w, "  %s %s\n", dx.Colorize(dx.ColorRed, "-", color), subject(c, c.Old))
+			case Modified:
+				if c.ID != lastModified {
+					_, _ = fmt.Fprintf(w, "  %s %s\n", dx.Colorize(dx.ColorYellow, "~", color), subject(c, ""))
+				}
+				_, _ = fmt.Fprintf(w, "      %s\n", fieldChange(c, strconv.Quote))
+			}
+			lastModified = ""
+			if c.Type == Modified {
+				lastModified = c.ID
+			}
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+	_, _ = fmt.Fprintln(w, r.Summary.String())
+}
+
+// WriteMarkdown writes the diff as markdown suitable for a pull request comment.
+func (r *Result) WriteMarkdown(w io.Writer) {
+	title := "Architecture diff"
+	if r.Base != "" && r.Head != "" {
+		title += fmt.Sprintf(": `%s` → `%s`", r.Base, r.Head)
+	}
+	_, _ = fmt.Fprintf(w, "### %s\n\n", title)
+	if r.Empty() {
+		_, _ = fmt.Fprintln(w, "No architecture changes.")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "**%s**\n", r.Summary.String())
+
+	for _, category := range categoryOrder {
+		changes := r.ByCategory(category)
+		if len(changes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n#### %s\n\n", categoryTitles[category])
+		_, _ = fmt.Fprintln(w, "| Change | ID | Details |")
+		_, _ = fmt.Fprintln(w, "| --- | --- | --- |")
+		for _, c := range changes {
+			var details string
+			switch c.Type {
+			case Added:
+				details = describe(c.Kind, c.New)
+			case Removed:
+				details = describe(c.Kind, c.Old)
+			case Modified:
+				details = fieldChange(c, markdownCode)
+			}
+			_, _ = fmt.Fprintf(w, "| %s | `%s` | %s |\n", changeTitle(c.Type), escapeMarkdown(c.ID), escapeMarkdown(details))
+		}
+	}
+}
+
+// subject renders the kind, ID and title of the item a change applies to.
+func subject(c Change, title string) string {
+	parts := make([]string, 0, 3)
+	if c.Kind != "" {
+		parts = append(parts, c.Kind)
+	}
+	parts = append(parts, c.ID)
+	if title != "" && c.Category != CategoryRelation {
+		title = strconv.Quote(title)
+	}
+	if title != "" {
+		parts = append(parts, title)
+	}
+	return strings.Join(parts, " ")
+}
+
+func describe(kind, title string) string {
+	switch {
+	case kind == "":
+		return title
+	case title == "":
+		return kind
+	default:
+		return kind + " " + title
+	}
+}
+
+// fieldChange renders a modified field as "field: old -> new", quoting values with
+// quote. Relation labels are already quoted.
+func fieldChange(c Change, quote func(string) string) string {
+	value := func(v string) string {
+		switch {
+		case v == "":
+			return "(none)"
+		case c.Category == CategoryRelation && c.Field == "label":
+			return v
+		default:
+			return quote(v)
+		}
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Field, value(c.Old), value(c.New))
+}
+
+func changeTitle(t ChangeType) string {
+	switch t {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	default:
+		return "Modified"
+	}
+}
+
+func markdownCode(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
+
+// escapeMarkdown keeps a value on a single table row.
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,250 @@
+package diff
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonexport "github.com/sruja-ai/sruja/pkg/export/json"
+)
+
+// governanceKinds are element kinds reported in their own categories rather than
+// as elements.
+var governanceKinds = map[string]bool{
+	"requirement": true,
+	"adr":         true,
+	"policy":      true,
+	"scenario":    true,
+	"story":       true,
+	"flow":        true,
+}
+
+func extensions(dump *jsonexport.SrujaModelDump) *jsonexport.SrujaExtensions {
+	if dump == nil || dump.Sruja == nil {
+		return &jsonexport.SrujaExtensions{}
+	}
+	return dump.Sruja
+}
+
+func elementRecords(dump *jsonexport.SrujaModelDump) map[string]record {
+	records := make(map[string]record)
+	if dump == nil {
+		return records
+	}
+	for id, el := range dump.Elements {
+		if governanceKinds[el.Kind] {
+			continue
+		}
+		fields := map[string]string{
+			"description": el.Description,
+			"technology":  el.Technology,
+			"tags":        joinSorted(el.Tags),
+		}
+		for key, value := range el.Metadata {
+			fields["metadata."+key] = value
+		}
+		addSLOFields(fields, el.SLO)
+		records[id] = record{kind: el.Kind, title: el.Title, fields: fields}
+	}
+	return records
+}
+
+// addSLOFields adds the targets and windows of an SLO. Current values are
+// observations rather than design, so they are not compared.
+func addSLOFields(fields map[string]string, slo *jsonexport.SLOJSON) {
+	if slo == nil {
+		return
+	}
+	if a := slo.Availability; a != nil {
+		fields["slo.availability.target"] = a.Target
+		fields["slo.availability.window"] = a.Window
+	}
+	if l := slo.Latency; l != nil {
+		fields["slo.latency.p95"] = l.P95
+		fields["slo.latency.p99"] = l.P99
+		fields["slo.latency.window"] = l.Window
+	}
+	if e := slo.ErrorRate; e != nil {
+		fields["slo.errorRate.target"] = e.Target
+		fields["slo.errorRate.window"] = e.Window
+	}
+	if t := slo.Throughput; t != nil {
+		fields["slo.throughput.target"] = t.Target
+		fields["slo.throughput.window"] = t.Window
+	}
+	if c := slo.Cost; c != nil {
+		fields["slo.cost.target"] = c.Target
+		fields["slo.cost.window"] = c.Window
+	}
+}
+
+func requirementRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	records := make(map[string]record, len(ext.Requirements))
+	for _, r := range ext.Requirements {
+		records[r.ID] = record{kind: r.Type, title: r.Title, fields: map[string]string{
+			"description": r.Description,
+			"priority":    r.Priority,
+			"status":      r.Status,
+			"elements":    joinSorted(r.Elements),
+		}}
+	}
+	return records
+}
+
+func adrRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	records := make(map[string]record, len(ext.ADRs))
+	for _, a := range ext.ADRs {
+		records[a.ID] = record{title: a.Title, fields: map[string]string{
+			"status":       a.Status,
+			"context":      a.Context,
+			"decision":     a.Decision,
+			"consequences": a.Consequences,
+			"date":         a.Date,
+			"author":       a.Author,
+		}}
+	}
+	return records
+}
+
+func policyRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	records := make(map[string]record, len(ext.Policies))
+	for _, p := range ext.Policies {
+		records[p.ID] = record{kind: p.Category, title: p.Title, fields: map[string]string{
+			"description": p.Description,
+			"enforcement": p.Enforcement,
+			"elements":    joinSorted(p.Elements),
+		}}
+	}
+	return records
+}
+
+func scenarioRecords(scenarios []jsonexport.ScenarioDump) map[string]record {
+	records := make(map[string]record, len(scenarios))
+	for _, s := range scenarios {
+		records[s.ID] = record{title: s.Title, fields: map[string]string{
+			"description": s.Description,
+			"steps":       joinSteps(s.Steps),
+		}}
+	}
+	return records
+}
+
+func flowRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	scenarios := make([]jsonexport.ScenarioDump, 0, len(ext.Flows))
+	for _, f := range ext.Flows {
+		scenarios = append(scenarios, jsonexport.ScenarioDump(f))
+	}
+	return scenarioRecords(scenarios)
+}
+
+// deploymentRecords flattens the deployment tree, keying each node by the IDs of
+// its ancestors and itself, e.g. "Prod.AWS.LB".
+func deploymentRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	records := make(map[string]record)
+	var walk func(nodes []jsonexport.DeploymentDump, parent string)
+	walk = func(nodes []jsonexport.DeploymentDump, parent string) {
+		for _, n := range nodes {
+			id := n.ID
+			if parent != "" {
+				id = parent + "." + n.ID
+			}
+			records[id] = record{kind: n.Kind, title: n.Title, fields: map[string]string{
+				"description": n.Description,
+				"technology":  n.Technology,
+				"instances":   joinSorted(n.Instances),
+			}}
+			walk(n.Children, id)
+		}
+	}
+	walk(ext.Deployments, "")
+	return records
+}
+
+// viewRecords compares the definition of each view. The include and exclude
+// rules are compared as sets, so regrouping them into other statements is not a
+// change. Computed nodes, edges and layout positions are not compared.
+func viewRecords(dump *jsonexport.SrujaModelDump) map[string]record {
+	records := make(map[string]record)
+	if dump == nil {
+		return records
+	}
+	for id, v := range dump.Views {
+		include, exclude := map[string]bool{}, map[string]bool{}
+		for _, rule := range v.Rules {
+			addRuleItems(include, rule.Include)
+			addRuleItems(exclude, rule.Exclude)
+		}
+		records[id] = record{title: v.Title, fields: map[string]string{
+			"description": v.Description,
+			"of":          v.ViewOf,
+			"tags":        joinSorted(v.Tags),
+			"include":     joinSet(include),
+			"exclude":     joinSet(exclude),
+		}}
+	}
+	return records
+}
+
+// addRuleItems adds the wildcards, elements and expressions of a view rule to items.
+func addRuleItems(items map[string]bool, expr *jsonexport.ViewRuleExpr) {
+	if expr == nil {
+		return
+	}
+	switch {
+	case expr.Recursive:
+		items["**"] = true
+	case expr.Wildcard:
+		items["*"] = true
+	}
+	for _, e := range expr.Elements {
+		items[e] = true
+	}
+	for _, e := range expr.Expressions {
+		items[e] = true
+	}
+}
+
+func joinSet(set map[string]bool) string {
+	values := make([]string, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	return joinSorted(values)
+}
+
+func constraintRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	records := make(map[string]record, len(ext.Constraints))
+	for _, c := range ext.Constraints {
+		records[c.ID] = record{kind: c.Type, title: c.Description}
+	}
+	return records
+}
+
+func conventionRecords(ext *jsonexport.SrujaExtensions) map[string]record {
+	records := make(map[string]record, len(ext.Conventions))
+	for _, c := range ext.Conventions {
+		records[c.ID] = record{title: c.Description}
+	}
+	return records
+}
+
+func joinSorted(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+func joinSteps(steps []jsonexport.StepDump) string {
+	parts := make([]string, 0, len(steps))
+	for _, s := range steps {
+		step := s.From + " -> " + s.To
+		if s.Description != "" {
+			step += " " + strconv.Quote(s.Description)
+		}
+		parts = append(parts, step)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonexport "github.com/sruja-ai/sruja/pkg/export/json"
+)
+
+// relation is the comparable form of a relation. Relations have no stable ID, so
+// they are keyed by their endpoints.
+type relation struct {
+	key           string // "Source -> Target"
+	label         string
+	technology    string
+	tags          string
+	bidirectional bool
+}
+
+// id renders the endpoints of the relation with its arrow.
+func (r relation) id() string {
+	if r.bidirectional {
+		return strings.Replace(r.key, " -> ", " <-> ", 1)
+	}
+	return r.key
+}
+
+// relations returns the declared relations of a dump. Relations implied by a
+// relation between children are dropped, since they change with it.
+func relations(dump *jsonexport.SrujaModelDump) []relation {
+	if dump == nil {
+		return nil
+	}
+	var out []relation
+	for _, r := range dump.Relations {
+		if r.Implied {
+			continue
+		}
+		out = append(out, relation{
+			key:           r.Source.Model + " -> " + r.Target.Model,
+			label:         relationLabel(r),
+			technology:    r.Technology,
+			tags:          joinSorted(r.Tags),
+			bidirectional: r.Bidirectional,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].key != out[j].key {
+			return out[i].key < out[j].key
+		}
+		return out[i].label < out[j].label
+	})
+	return out
+}
+
+// relationLabel renders the verb and label of a relation as written in the DSL.
+func relationLabel(r jsonexport.RelationDump) string {
+	verb, label := r.Description, r.Title
+	if verb == "" {
+		verb, label = label, ""
+	}
+	if label == verb {
+		label = ""
+	}
+	switch {
+	case verb == "":
+		return ""
+	case label == "":
+		return strconv.Quote(verb)
+	default:
+		return strconv.Quote(verb) + " " + strconv.Quote(label)
+	}
+}
+
+// compareRelations matches relations between the same endpoints. Identical labels
+// match first; the remaining relations of an endpoint pair are matched in order and
+// reported as relabelled, and any left over are added or removed.
+func (d *differ) compareRelations(base, head []relation) {
+	baseByKey, headByKey := groupRelations(base), groupRelations(head)
+	keys := make([]string, 0, len(baseByKey)+len(headByKey))
+	for key := range baseByKey {
+		keys = append(keys, key)
+	}
+	for key := range headByKey {
+		if _, ok := baseByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		olds, news := baseByKey[key], headByKey[key]
+		var unmatched []relation
+		for _, n := range news {
+			if i := indexOfLabel(olds, n.label); i >= 0 {
+				d.compareRelation(olds[i], n)
+				olds = append(olds[:i:i], olds[i+1:]...)
+				continue
+			}
+			unmatched = append(unmatched, n)
+		}
+		for i, n := range unmatched {
+			if i >= len(olds) {
+				d.add(Change{Type: Added, Category: CategoryRelation, ID: n.id(), New: n.label})
+				continue
+			}
+			d.add(Change{Type: Modified, Category: CategoryRelation, ID: n.id(), Field: "label", Old: olds[i].label, New: n.label})
+			d.compareRelation(olds[i], n)
+		}
+		for i := len(unmatched); i < len(olds); i++ {
+			d.add(Change{Type: Removed, Category: CategoryRelation, ID: olds[i].id(), Old: olds[i].label})
+		}
+	}
+}
+
+func (d *differ) compareRelation(base, head relation) {
+	if base.technology != head.technology {
+		d.add(Change{Type: Modified, Category: CategoryRelation, ID: head.id(), Field: "technology", Old: base.technology, New: head.technology})
+	}
+	if base.tags != head.tags {
+		d.add(Change{Type: Modified, Category: CategoryRelation, ID: head.id(), Field: "tags", Old: base.tags, New: head.tags})
+	}
+	if base.bidirectional != head.bidirectional {
+		d.add(Change{Type: Modified, Category: CategoryRelation, ID: head.id(), Field: "bidirectional",
+			Old: strconv.FormatBool(base.bidirectional), New: strconv.FormatBool(head.bidirectional)})
+	}
+}
+
+func groupRelations(rels []relation) map[string][]relation {
+	grouped := make(map[string][]relation)
+	for _, r := range rels {
+		grouped[r.key] = append(grouped[r.key], r)
+	}
+	return grouped
+}
+
+func indexOfLabel(rels []relation, label string) int {
+	for i, r := range rels {
+		if r.label == label {
+			return i
+		}
+	}
+	return -1
+}
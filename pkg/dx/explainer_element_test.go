@@ -225,3 +225,32 @@ func TestFindElement_ComponentInSystem(t *testing.T) {
 		t.Errorf("Expected ID 'Sys.Comp', got '%s'", explanation.ID)
 	}
 }
+
+func TestExplainElement_ScenarioSteps(t *testing.T) {
+	dsl := `
+		User = person "User"
+		API = system "API Service"
+		Checkout = scenario "Checkout" {
+			step User -> API "Places an order"
+		}
+		Browse = scenario "Browse the catalog" {
+			step User -> User "Looks around"
+		}
+	`
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	explanation, err := NewExplainer(prog).ExplainElement("API")
+	if err != nil {
+		t.Fatalf("ExplainElement failed: %v", err)
+	}
+	if len(explanation.Scenarios) != 1 || explanation.Scenarios[0].ID != "Checkout" || explanation.Scenarios[0].Role != "step" {
+		t.Errorf("Expected API to take part in Checkout as a step, got %+v", explanation.Scenarios)
+	}
+}
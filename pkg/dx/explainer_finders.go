@@ -212,7 +212,8 @@ func (e *Explainer) findRelatedADRs(elementID string) []string {
 	return related
 }
 
-// findRelatedScenarios finds scenarios that involve the element.
+// findRelatedScenarios finds scenarios that involve the element, as a step or
+// by mention in their title.
 func (e *Explainer) findRelatedScenarios(elementID string) []*ScenarioInfo {
 	var related []*ScenarioInfo
 	if e.program == nil || e.program.Model == nil {
@@ -224,18 +225,24 @@ func (e *Explainer) findRelatedScenarios(elementID string) []*ScenarioInfo {
 		if item.ElementDef != nil && item.ElementDef.Assignment != nil {
 			a := item.ElementDef.Assignment
 			if a.Kind == "scenario" || a.Kind == "story" {
-				// For now, check if title mentions the element
-				if a.Title != nil && strings.Contains(*a.Title, elementID) {
-					title := ""
-					if a.Title != nil {
-						title = *a.Title
-					}
-					related = append(related, &ScenarioInfo{
-						ID:    a.Name,
-						Label: title,
-						Role:  "participant",
-					})
+				title := ""
+				if a.Title != nil {
+					title = *a.Title
+				}
+				role := ""
+				switch {
+				case hasStepWith(item.ElementDef.GetBody(), elementID):
+					role = "step"
+				case strings.Contains(title, elementID):
+					role = "participant"
+				default:
+					continue
 				}
+				related = append(related, &ScenarioInfo{
+					ID:    a.Name,
+					Label: title,
+					Role:  role,
+				})
 			}
 		}
 	}
@@ -243,6 +250,32 @@ func (e *Explainer) findRelatedScenarios(elementID string) []*ScenarioInfo {
 	return related
 }
 
+// hasStepWith reports whether a scenario body has a step from or to the element.
+// Steps are not resolved, so a step may name the element by a suffix of its ID.
+func hasStepWith(body *language.ElementDefBody, elementID string) bool {
+	if body == nil {
+		return false
+	}
+	for _, item := range body.Items {
+		switch {
+		case item.Step != nil:
+			from, to := strings.Join(item.Step.FromParts, "."), strings.Join(item.Step.ToParts, ".")
+			if refersTo(from, elementID) || refersTo(to, elementID) {
+				return true
+			}
+		case item.Relation != nil:
+			if refersTo(item.Relation.From.String(), elementID) || refersTo(item.Relation.To.String(), elementID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func refersTo(ref, elementID string) bool {
+	return ref != "" && (ref == elementID || strings.HasSuffix(elementID, "."+ref))
+}
+
 // findDependencies finds all dependencies of an element.
 func (e *Explainer) findDependencies(elementID string) []string {
 	// Estimate capacity: typically few dependencies per element
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// BaselineFileName is the default name of a baseline file, in the directory
+// of the architecture it was recorded for.
+const BaselineFileName = "sruja.baseline.json"
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// Baseline records the diagnostics an architecture is known to have, so that
+// only new ones are reported when stricter rules are turned on for a legacy
+// model.
+//
+// Diagnostics are recorded by fingerprint: their code, the FQN of the element
+// or relation they are reported on, and a hash of their message with numbers
+// left out. Fingerprints do not change when the model is reformatted or
+// elements move to other lines.
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry is a fingerprint and how many diagnostics have it.
+type BaselineEntry struct {
+	Code  string `json:"code"`
+	FQN   string `json:"fqn,omitempty"`
+	Hash  string `json:"hash"`
+	Count int    `json:"count"`
+}
+
+func (e BaselineEntry) key() string {
+	return e.Code + "\x00" + e.FQN + "\x00" + e.Hash
+}
+
+// NewBaseline records the diagnostics of a program.
+func NewBaseline(program *language.Program, diags []diagnostics.Diagnostic) *Baseline {
+	counts := make(map[string]*BaselineEntry)
+	for _, e := range fingerprints(program, diags) {
+		if prev, ok := counts[e.key()]; ok {
+			prev.Count++
+			continue
+		}
+		e.Count = 1
+		counts[e.key()] = &e
+	}
+	b := &Baseline{Version: baselineVersion, Entries: make([]BaselineEntry, 0, len(counts))}
+	for _, e := range counts {
+		b.Entries = append(b.Entries, *e)
+	}
+	sort.Slice(b.Entries, func(i, j int) bool {
+		x, y := b.Entries[i], b.Entries[j]
+		if x.FQN != y.FQN {
+			return x.FQN < y.FQN
+		}
+		if x.Code != y.Code {
+			return x.Code < y.Code
+		}
+		return x.Hash < y.Hash
+	})
+	return b
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("baseline %s has version %d, expected %d; write it again", path, b.Version, baselineVersion)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline to a file.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644) //nolint:gosec // checked in next to the architecture
+}
+
+// Filter removes the diagnostics of a program that the baseline records. It
+// returns the new diagnostics, and how many recorded ones were not reported
+// any more, which means the baseline can be written again to shrink it.
+func (b *Baseline) Filter(program *language.Program, diags []diagnostics.Diagnostic) (fresh []diagnostics.Diagnostic, fixed int) {
+	remaining := make(map[string]int, len(b.Entries))
+	for _, e := range b.Entries {
+		remaining[e.key()] += e.Count
+	}
+	for i, e := range fingerprints(program, diags) {
+		if remaining[e.key()] > 0 {
+			remaining[e.key()]--
+			continue
+		}
+		fresh = append(fresh, diags[i])
+	}
+	for _, n := range remaining {
+		fixed += n
+	}
+	return fresh, fixed
+}
+
+// digits matches the numbers left out of fingerprinted messages, which often
+// hold line and column numbers.
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// fingerprints returns the fingerprint of each diagnostic, in order.
+func fingerprints(program *language.Program, diags []diagnostics.Diagnostic) []BaselineEntry {
+	subjects := collectSubjects(program)
+	entries := make([]BaselineEntry, len(diags))
+	for i := range diags {
+		d := &diags[i]
+		fqn := subjectOf(subjects, d)
+		if fqn == "" && d.Location.File != "" {
+			fqn = filepath.Base(d.Location.File)
+		}
+		sum := sha256.Sum256([]byte(digits.ReplaceAllString(d.Message, "#")))
+		entries[i] = BaselineEntry{Code: d.Code, FQN: fqn, Hash: hex.EncodeToString(sum[:8])}
+	}
+	return entries
+}
@@ -0,0 +1,429 @@
+// pkg/engine/custom_rule.go
+package engine
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// CustomRule is a user-defined rule compiled from a `rule` declaration.
+// It reports relations matched by its forbid checks and elements that fail its require checks.
+type CustomRule struct {
+	// ID is the rule identifier from the declaration.
+	ID string
+	// Title is the optional human-readable title.
+	Title string
+	// Code is the diagnostic code (CodeCustomRule unless the rule sets one).
+	Code string
+	// Severity is the severity of reported diagnostics (error unless the rule sets one).
+	Severity diagnostics.Severity
+	// Message is the diagnostic message template.
+	Message string
+
+	forbid  []forbidCheck
+	require []requireCheck
+}
+
+type forbidCheck struct {
+	from, to selectorMatcher
+	pattern  string
+}
+
+type requireCheck struct {
+	subject selectorMatcher
+	has     selectorMatcher
+	to      selectorMatcher
+	pattern string
+	// condition describes what matching elements must have, for messages.
+	condition string
+}
+
+// selectorMatcher reports whether an element satisfies a compiled selector.
+type selectorMatcher func(e *ruleElement) bool
+
+// ruleElement is the view of an element that rule predicates are evaluated against.
+type ruleElement struct {
+	fqn         string
+	kind        string
+	tags        map[string]bool
+	metadata    map[string]string
+	technology  bool
+	description bool
+	def         *language.ElementDef
+}
+
+func (r *CustomRule) Name() string {
+	if r.Title != "" {
+		return r.Title
+	}
+	return r.ID
+}
+
+// CompileRule compiles a rule declaration into a CustomRule.
+// Returns an error if the declaration has an invalid severity or no checks.
+func CompileRule(def *language.RuleDef) (*CustomRule, error) {
+	if def == nil {
+		return nil, fmt.Errorf("rule declaration is nil")
+	}
+	if len(def.Forbid) == 0 && len(def.Require) == 0 {
+		return nil, fmt.Errorf("rule %q has no forbid or require checks", def.ID)
+	}
+
+	rule := &CustomRule{
+		ID:       def.ID,
+		Code:     diagnostics.CodeCustomRule,
+		Severity: diagnostics.SeverityError,
+	}
+	if def.Title != nil {
+		rule.Title = *def.Title
+	}
+	if def.Code != nil && *def.Code != "" {
+		rule.Code = *def.Code
+	}
+	if def.Message != nil {
+		rule.Message = *def.Message
+	}
+	if def.Severity != nil {
+		severity, enabled, err := parseSeverity(*def.Severity)
+		if err != nil || !enabled {
+			return nil, fmt.Errorf("rule %q: invalid severity %q (expected error, warning or info)", def.ID, *def.Severity)
+		}
+		rule.Severity = severity
+	}
+
+	for _, f := range def.Forbid {
+		rule.forbid = append(rule.forbid, forbidCheck{
+			from:    compileSelector(f.From),
+			to:      compileSelector(f.To),
+			pattern: f.From.String() + " -> " + f.To.String(),
+		})
+	}
+	for _, req := range def.Require {
+		check := requireCheck{subject: compileSelector(req.Subject)}
+		if req.Has != nil {
+			check.has = compileSelector(req.Has)
+			check.pattern = req.Subject.String() + " has " + req.Has.String()
+			check.condition = req.Has.String()
+		} else {
+			check.to = compileSelector(req.To)
+			check.pattern = req.Subject.String() + " -> " + req.To.String()
+			check.condition = "a relation to " + req.To.String()
+		}
+		rule.require = append(rule.require, check)
+	}
+	return rule, nil
+}
+
+// CompileRules compiles every rule declared in the program.
+// Declarations that cannot be compiled are reported as diagnostics.
+func CompileRules(program *language.Program) ([]Rule, []diagnostics.Diagnostic) {
+	if program == nil || program.Model == nil {
+		return nil, nil
+	}
+	var rules []Rule
+	var diags []diagnostics.Diagnostic
+	for _, item := range program.Model.Items {
+		if item.Rule == nil {
+			continue
+		}
+		rule, err := CompileRule(item.Rule)
+		if err != nil {
+			loc := item.Rule.Location()
+			diags = append(diags, diagnostics.Diagnostic{
+				Code:     diagnostics.CodeInvalidCustomRule,
+				Severity: diagnostics.SeverityError,
+				Message:  err.Error(),
+				Location: diagnostics.SourceLocation{File: loc.File, Line: loc.Line, Column: loc.Column},
+			})
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, diags
+}
+
+// LoadRuleFile parses a file of rule declarations and compiles them.
+func LoadRuleFile(path string) ([]Rule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rule file: %w", err)
+	}
+	parser, err := language.NewParser()
+	if err != nil {
+		return nil, err
+	}
+	program, _, err := parser.Parse(path, string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing rule file %s: %w", path, err)
+	}
+	rules, diags := CompileRules(program)
+	if len(diags) > 0 {
+		return nil, fmt.Errorf("%s: %s", path, diags[0].Message)
+	}
+	return rules, nil
+}
+
+// CustomRulesRule evaluates the rules declared in the validated program itself.
+type CustomRulesRule struct{}
+
+func (r *CustomRulesRule) Name() string {
+	return "Custom Rules"
+}
+
+func (r *CustomRulesRule) Validate(program *language.Program) []diagnostics.Diagnostic {
+	rules, diags := CompileRules(program)
+	for _, rule := range rules {
+		diags = append(diags, rule.Validate(program)...)
+	}
+	return diags
+}
+
+func (r *CustomRule) Validate(program *language.Program) []diagnostics.Diagnostic {
+	if program == nil || program.Model == nil {
+		return nil
+	}
+
+	elements := collectRuleElements(program.Model)
+	relations := collectAllRelations(program.Model)
+	resolve := newRuleRefResolver(elements)
+
+	var diags []diagnostics.Diagnostic
+	for _, check := range r.forbid {
+		for _, rs := range relations {
+			rel := rs.Relation
+			// Implied parent relations repeat the explicit relation they were inferred from.
+			if rel.Implied {
+				continue
+			}
+			from := resolve(rs.Scope, rel.From.String())
+			to := resolve(rs.Scope, rel.To.String())
+			if from == nil || to == nil || !check.from(from) || !check.to(to) {
+				continue
+			}
+			loc := rel.Location()
+			diags = append(diags, r.diagnostic(
+				map[string]string{"from": from.fqn, "to": to.fqn, "id": from.fqn, "kind": from.kind},
+				fmt.Sprintf("Relation '%s -> %s' is forbidden by rule '%s' (%s).", from.fqn, to.fqn, r.ID, check.pattern),
+				loc, check.pattern))
+		}
+	}
+
+	for _, check := range r.require {
+		var targets map[string]bool
+		if check.to != nil {
+			targets = make(map[string]bool)
+			for _, rs := range relations {
+				from := resolve(rs.Scope, rs.Relation.From.String())
+				to := resolve(rs.Scope, rs.Relation.To.String())
+				if from != nil && to != nil && check.to(to) {
+					targets[from.fqn] = true
+				}
+			}
+		}
+		for _, e := range elements.sorted() {
+			if !check.subject(e) {
+				continue
+			}
+			if (check.has != nil && check.has(e)) || (check.to != nil && targets[e.fqn]) {
+				continue
+			}
+			diags = append(diags, r.diagnostic(
+				map[string]string{"id": e.fqn, "kind": e.kind},
+				fmt.Sprintf("'%s' violates rule '%s': it must have %s.", e.fqn, r.ID, check.condition),
+				e.def.Location(), check.pattern))
+		}
+	}
+	return diags
+}
+
+// diagnostic builds a diagnostic for a violation, expanding the rule's message template.
+func (r *CustomRule) diagnostic(vars map[string]string, fallback string, loc language.SourceLocation, pattern string) diagnostics.Diagnostic {
+	msg := fallback
+	if r.Message != "" {
+		vars["rule"] = r.ID
+		pairs := make([]string, 0, len(vars)*2)
+		for k, v := range vars {
+			pairs = append(pairs, "{"+k+"}", v)
+		}
+		msg = strings.NewReplacer(pairs...).Replace(r.Message)
+	}
+	context := []string{"rule " + r.ID + ": " + pattern}
+	if r.Title != "" {
+		context = append(context, r.Title)
+	}
+	return diagnostics.Diagnostic{
+		Code:     r.Code,
+		Severity: r.Severity,
+		Message:  msg,
+		Location: diagnostics.SourceLocation{
+			File:   loc.File,
+			Line:   loc.Line,
+			Column: loc.Column,
+		},
+		Context: context,
+		Rule:    r.Name(),
+	}
+}
+
+// ruleElements indexes the elements of a model by FQN.
+type ruleElements map[string]*ruleElement
+
+// sorted returns the elements in source order, so diagnostics are deterministic.
+func (es ruleElements) sorted() []*ruleElement {
+	list := make([]*ruleElement, 0, len(es))
+	for _, e := range es {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		li, lj := list[i].def.Pos, list[j].def.Pos
+		if li.Filename != lj.Filename {
+			return li.Filename < lj.Filename
+		}
+		if li.Offset != lj.Offset {
+			return li.Offset < lj.Offset
+		}
+		return list[i].fqn < list[j].fqn
+	})
+	return list
+}
+
+func collectRuleElements(model *language.Model) ruleElements {
+	defs, _ := collectElements(model)
+	elements := make(ruleElements, len(defs))
+	for fqn, def := range defs {
+		e := &ruleElement{
+			fqn:      fqn,
+			kind:     strings.ToLower(def.GetKind()),
+			tags:     make(map[string]bool),
+			metadata: make(map[string]string),
+			def:      def,
+		}
+		for _, t := range def.GetTagRefs() {
+			e.tags[strings.TrimPrefix(t, "#")] = true
+		}
+		if body := def.GetBody(); body != nil {
+			for _, item := range body.Items {
+				for _, t := range item.Tags {
+					e.tags[strings.TrimPrefix(t, "#")] = true
+				}
+				for _, t := range item.TagRefs {
+					e.tags[strings.TrimPrefix(t, "#")] = true
+				}
+				if item.Metadata != nil {
+					for _, m := range item.Metadata.Entries {
+						value := ""
+						if m.Value != nil {
+							value = *m.Value
+						} else if len(m.Array) > 0 {
+							value = strings.Join(m.Array, ",")
+						}
+						e.metadata[m.Key] = value
+					}
+				}
+				if item.Technology != nil && *item.Technology != "" {
+					e.technology = true
+				}
+				if item.Description != nil && *item.Description != "" {
+					e.description = true
+				}
+			}
+		}
+		elements[fqn] = e
+	}
+	return elements
+}
+
+// newRuleRefResolver returns a function resolving a relation endpoint, written in the
+// given scope, to its element. Returns nil for unknown or ambiguous references.
+func newRuleRefResolver(elements ruleElements) func(scope, ref string) *ruleElement {
+	suffixes := make(map[string][]*ruleElement, len(elements))
+	for fqn, e := range elements {
+		suffix := extractSuffix(fqn)
+		suffixes[suffix] = append(suffixes[suffix], e)
+	}
+	return func(scope, ref string) *ruleElement {
+		for s := scope; s != ""; s = parentID(s) {
+			if e, ok := elements[s+"."+ref]; ok {
+				return e
+			}
+		}
+		if e, ok := elements[ref]; ok {
+			return e
+		}
+		if matches := suffixes[extractSuffix(ref)]; len(matches) == 1 {
+			return matches[0]
+		}
+		return nil
+	}
+}
+
+// compileSelector turns a selector into a matcher requiring all of its predicates.
+func compileSelector(sel *language.ElementSelector) selectorMatcher {
+	if sel == nil || sel.Any {
+		return func(*ruleElement) bool { return true }
+	}
+	preds := make([]selectorMatcher, 0, len(sel.Predicates))
+	for _, p := range sel.Predicates {
+		preds = append(preds, compilePredicate(p))
+	}
+	return func(e *ruleElement) bool {
+		for _, p := range preds {
+			if !p(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func compilePredicate(p *language.ElementPredicate) selectorMatcher {
+	var match selectorMatcher
+	switch {
+	case p.Kind != nil:
+		kind := strings.ToLower(*p.Kind)
+		match = func(e *ruleElement) bool { return e.kind == kind }
+	case p.Tag != nil:
+		tag := strings.TrimPrefix(*p.Tag, "#")
+		match = func(e *ruleElement) bool { return e.tags[tag] }
+	case p.ID != nil:
+		ref := p.ID.String()
+		match = func(e *ruleElement) bool { return refMatches(e.fqn, ref) }
+	case p.In != nil:
+		ref := p.In.String()
+		match = func(e *ruleElement) bool {
+			for id := parentID(e.fqn); id != ""; id = parentID(id) {
+				if refMatches(id, ref) {
+					return true
+				}
+			}
+			return false
+		}
+	case p.Metadata != nil:
+		key, value := p.Metadata.Key, p.Metadata.Value
+		match = func(e *ruleElement) bool {
+			v, ok := e.metadata[key]
+			return ok && (value == nil || v == *value)
+		}
+	case p.Technology:
+		match = func(e *ruleElement) bool { return e.technology }
+	case p.Description:
+		match = func(e *ruleElement) bool { return e.description }
+	default:
+		match = func(*ruleElement) bool { return true }
+	}
+	if p.Not {
+		return func(e *ruleElement) bool { return !match(e) }
+	}
+	return match
+}
+
+// refMatches reports whether fqn is the element referred to by ref, either by its
+// fully qualified ID or by a trailing part of it.
+func refMatches(fqn, ref string) bool {
+	return fqn == ref || strings.HasSuffix(fqn, "."+ref)
+}
@@ -0,0 +1,234 @@
+// pkg/engine/custom_rule_test.go
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const customRuleModel = `
+shop = system "Shop" {
+  web = container "Web" #frontend
+  admin = container "Admin" #frontend
+  api = container "API" {
+    technology "Go"
+  }
+  orders = queue "Orders" {
+    metadata {
+      owner "team-orders"
+    }
+  }
+  events = queue "Events"
+  db = database "DB"
+
+  web -> db "reads"
+  admin -> api
+  api -> db
+}
+`
+
+func TestCustomRulesRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    string
+		expected []string // Expected messages, in order
+	}{
+		{
+			name: "Forbid relation by kind and tag",
+			rules: `
+				rule frontend-no-db {
+				  forbid kind container #frontend -> kind database
+				}
+			`,
+			expected: []string{"Relation 'shop.web -> shop.db' is forbidden by rule 'frontend-no-db' (kind container #frontend -> kind database)."},
+		},
+		{
+			name: "Require metadata key",
+			rules: `
+				rule queue-owner {
+				  require kind queue has metadata owner
+				}
+			`,
+			expected: []string{"'shop.events' violates rule 'queue-owner': it must have metadata owner."},
+		},
+		{
+			name: "Require relation",
+			rules: `
+				rule frontends-use-api {
+				  require #frontend -> id api
+				}
+			`,
+			expected: []string{"'shop.web' violates rule 'frontends-use-api': it must have a relation to id api."},
+		},
+		{
+			name: "Negated predicate and custom message",
+			rules: `
+				rule tech-declared {
+				  message "{kind} '{id}' must declare its technology"
+				  require kind container not #frontend has technology
+				}
+			`,
+			expected: nil,
+		},
+		{
+			name: "Custom message placeholders",
+			rules: `
+				rule no-db-writes {
+				  message "{rule}: {from} must not call {to}"
+				  forbid in shop not kind database -> kind database
+				}
+			`,
+			expected: []string{
+				"no-db-writes: shop.web must not call shop.db",
+				"no-db-writes: shop.api must not call shop.db",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, _ := language.NewParser()
+			program, _, err := parser.Parse("test.sruja", customRuleModel+tt.rules)
+			if err != nil {
+				t.Fatalf("Failed to parse DSL: %v", err)
+			}
+
+			diags := (&CustomRulesRule{}).Validate(program)
+			if len(diags) != len(tt.expected) {
+				for _, d := range diags {
+					t.Logf("Diagnostic: %s", d.Message)
+				}
+				t.Fatalf("Expected %d diagnostics, got %d", len(tt.expected), len(diags))
+			}
+			for i, want := range tt.expected {
+				if diags[i].Message != want {
+					t.Errorf("Diagnostic %d: expected %q, got %q", i, want, diags[i].Message)
+				}
+				if diags[i].Code != diagnostics.CodeCustomRule || diags[i].Severity != diagnostics.SeverityError {
+					t.Errorf("Expected default code and severity, got %s %s", diags[i].Code, diags[i].Severity)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomRule_CodeAndSeverity(t *testing.T) {
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", customRuleModel+`
+		rule frontend-no-db {
+		  severity warning
+		  code "ARCH001"
+		  forbid #frontend -> kind database
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	diags := (&CustomRulesRule{}).Validate(program)
+	if len(diags) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d", len(diags))
+	}
+	d := diags[0]
+	if d.Code != "ARCH001" || d.Severity != diagnostics.SeverityWarning {
+		t.Errorf("Expected ARCH001 warning, got %s %s", d.Code, d.Severity)
+	}
+	if d.Location.Line == 0 {
+		t.Error("Expected the diagnostic to point at the relation")
+	}
+}
+
+func TestCustomRule_RelationWithoutPosition(t *testing.T) {
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", customRuleModel+`
+		rule frontend-no-db {
+		  forbid #frontend -> kind database
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	// Relations built in code or imported from JSON have no source position
+	program.Model.Items = append(program.Model.Items, language.ModelItem{Relation: &language.Relation{
+		From:  language.QualifiedIdent{Parts: []string{"shop", "admin"}},
+		Arrow: "->",
+		To:    language.QualifiedIdent{Parts: []string{"shop", "db"}},
+	}})
+
+	diags := (&CustomRulesRule{}).Validate(program)
+	if len(diags) != 2 {
+		t.Fatalf("Expected violations for web and admin, got %d: %v", len(diags), diags)
+	}
+}
+
+func TestCompileRules_InvalidDeclarations(t *testing.T) {
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", `
+		rule empty {
+		  severity error
+		}
+		rule bad-severity {
+		  severity fatal
+		  require * has description
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	rules, diags := CompileRules(program)
+	if len(rules) != 0 {
+		t.Errorf("Expected no compiled rules, got %d", len(rules))
+	}
+	if len(diags) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %d", len(diags))
+	}
+	for _, d := range diags {
+		if d.Code != diagnostics.CodeInvalidCustomRule {
+			t.Errorf("Expected code %s, got %s", diagnostics.CodeInvalidCustomRule, d.Code)
+		}
+	}
+	if !strings.Contains(diags[1].Message, `invalid severity "fatal"`) {
+		t.Errorf("Unexpected message: %s", diags[1].Message)
+	}
+}
+
+func TestNewValidatorFromConfig_RuleFiles(t *testing.T) {
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "rules.sruja")
+	if err := os.WriteFile(rulesPath, []byte(`
+		rule queue-owner "Queues need an owner" {
+		  require kind queue has metadata owner
+		}
+	`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := NewValidatorFromConfig(&config.ValidationConfig{
+		Rules:     []string{"unique-ids"},
+		RuleFiles: []string{rulesPath},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewValidatorFromConfig failed: %v", err)
+	}
+
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", customRuleModel)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	diags := v.Validate(program)
+	if len(diags) != 1 || !strings.Contains(diags[0].Message, "shop.events") {
+		t.Errorf("Expected the queue-owner rule to report shop.events, got %v", diags)
+	}
+
+	if _, err := NewValidatorFromConfig(&config.ValidationConfig{RuleFiles: []string{filepath.Join(dir, "missing.sruja")}}, nil); err == nil {
+		t.Error("Expected error for missing rule file")
+	}
+}
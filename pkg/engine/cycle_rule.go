@@ -36,13 +36,30 @@ func (r *CycleDetectionRule) Validate(program *language.Program) []diagnostics.D
 	adjPtr := GetStringSliceMap()
 	adj := *adjPtr
 
+	// An explicit bidirectional relation (A <-> B) is a declared mutual dependency,
+	// not a cycle: it contributes a single edge, and plain relations between the
+	// same pair of elements are covered by it.
+	bidirectional := make(map[string]bool)
+	for _, rel := range relations {
+		if rel.Bidirectional {
+			from := rel.From.String()
+			to := rel.To.String()
+			bidirectional[from+"->"+to] = true
+			bidirectional[to+"->"+from] = true
+		}
+	}
+
 	// Build adjacency list from relations
 	for _, rel := range relations {
 		from := rel.From.String()
 		to := rel.To.String()
-		if from != "" && to != "" {
-			adj[from] = append(adj[from], to)
+		if from == "" || to == "" {
+			continue
+		}
+		if !rel.Bidirectional && bidirectional[from+"->"+to] {
+			continue
 		}
+		adj[from] = append(adj[from], to)
 	}
 
 	// Use pooled visited and recStack maps
@@ -163,3 +163,35 @@ func TestCycleDetectionRule_EmptyFromTo(_ *testing.T) {
 	// Should not panic, empty strings should be handled
 	_ = errs
 }
+
+func TestCycleDetectionRule_BidirectionalRelation(t *testing.T) {
+	dsl := `
+    A = system "System A"
+    B = system "System B"
+    A <-> B "Syncs"
+    B -> A "Notifies"
+`
+	program := parse(t, dsl)
+
+	rule := &engine.CycleDetectionRule{}
+	errs := rule.Validate(program)
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors for explicit bidirectional relation, got %d", len(errs))
+	}
+}
+
+func TestCycleDetectionRule_BackArrowCycle(t *testing.T) {
+	dsl := `
+    A = system "System A"
+    B = system "System B"
+    A -> B "Uses"
+    A <- B "Uses back"
+`
+	program := parse(t, dsl)
+
+	rule := &engine.CycleDetectionRule{}
+	errs := rule.Validate(program)
+	if len(errs) == 0 {
+		t.Error("Expected cycle error for A -> B and A <- B")
+	}
+}
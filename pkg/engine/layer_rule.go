@@ -4,24 +4,195 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/sruja-ai/sruja/pkg/config"
 	"github.com/sruja-ai/sruja/pkg/diagnostics"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
+// defaultLayers is the layer order used when neither the DSL nor the project
+// config declares layers (ordered from top to bottom).
+var defaultLayers = []string{"web", "api", "service", "data", "database"}
+
+// LayerModel describes the ordered layers enforced by LayerViolationRule.
+type LayerModel struct {
+	// Layers lists layer names from top to bottom.
+	Layers []string
+	// Members maps element IDs to their layer. Nested elements inherit the layer of their parent.
+	Members map[string]string
+	// Allowed holds explicitly permitted layer dependencies, keyed by layerEdgeKey.
+	Allowed map[string]bool
+	// AllowSkip permits dependencies that skip intermediate layers.
+	AllowSkip bool
+	// Exceptions holds relations exempt from layer checks, keyed by layerEdgeKey.
+	Exceptions map[string]bool
+	// NameHeuristics infers the layer of undeclared elements from their names.
+	NameHeuristics bool
+}
+
+// DefaultLayerModel returns the built-in web/api/service/data/database model,
+// which allows skipping layers and infers layers from element names.
+func DefaultLayerModel() *LayerModel {
+	return &LayerModel{
+		Layers:         defaultLayers,
+		AllowSkip:      true,
+		NameHeuristics: true,
+	}
+}
+
+// LayerModelFromConfig builds a layer model from the project config.
+// Returns nil when cfg declares no layers.
+func LayerModelFromConfig(cfg *config.LayersConfig) (*LayerModel, error) {
+	if cfg == nil || len(cfg.Order) == 0 {
+		return nil, nil
+	}
+	m := &LayerModel{
+		Layers:    cfg.Order,
+		AllowSkip: true,
+	}
+	if cfg.AllowSkip != nil {
+		m.AllowSkip = *cfg.AllowSkip
+	}
+	known := make(map[string]bool, len(cfg.Order))
+	for _, l := range cfg.Order {
+		known[l] = true
+	}
+	for layer, members := range cfg.Members {
+		if !known[layer] {
+			return nil, fmt.Errorf("layers: members declared for unknown layer %q", layer)
+		}
+		for _, id := range members {
+			m.addMember(id, layer)
+		}
+	}
+	for _, entry := range cfg.Allow {
+		from, to, ok := splitArrow(entry)
+		if !ok || !known[from] || !known[to] {
+			return nil, fmt.Errorf("layers: invalid allow entry %q (expected \"<layer> -> <layer>\")", entry)
+		}
+		m.allow(from, to)
+	}
+	for _, entry := range cfg.Exceptions {
+		from, to, ok := splitArrow(entry)
+		if !ok {
+			return nil, fmt.Errorf("layers: invalid exception %q (expected \"<element> -> <element>\")", entry)
+		}
+		m.except(from, to)
+	}
+	return m, nil
+}
+
+// layerModelFromProgram builds a layer model from the layers blocks in the program.
+// Returns nil when the program declares no layers.
+func layerModelFromProgram(program *language.Program) *LayerModel {
+	if program == nil || program.Model == nil {
+		return nil
+	}
+	var m *LayerModel
+	for _, item := range program.Model.Items {
+		block := item.Layers
+		if block == nil {
+			continue
+		}
+		if m == nil {
+			m = &LayerModel{AllowSkip: true}
+		}
+		for _, def := range block.Layers {
+			m.Layers = append(m.Layers, def.Name)
+			for _, el := range def.Elements {
+				m.addMember(el.String(), def.Name)
+			}
+		}
+		for _, a := range block.Allow {
+			m.allow(a.From, a.To)
+		}
+		for _, e := range block.Exceptions {
+			m.except(e.From.String(), e.To.String())
+		}
+		if block.AllowSkip != nil {
+			m.AllowSkip = *block.AllowSkip
+		}
+	}
+	if m == nil || len(m.Layers) == 0 {
+		return nil
+	}
+	return m
+}
+
+func splitArrow(s string) (from, to string, ok bool) {
+	parts := strings.Split(s, "->")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	from = strings.TrimSpace(parts[0])
+	to = strings.TrimSpace(parts[1])
+	return from, to, from != "" && to != ""
+}
+
+func layerEdgeKey(from, to string) string {
+	return from + "->" + to
+}
+
+func (m *LayerModel) addMember(id, layer string) {
+	if m.Members == nil {
+		m.Members = make(map[string]string)
+	}
+	m.Members[id] = layer
+}
+
+func (m *LayerModel) allow(from, to string) {
+	if m.Allowed == nil {
+		m.Allowed = make(map[string]bool)
+	}
+	m.Allowed[layerEdgeKey(from, to)] = true
+}
+
+func (m *LayerModel) except(from, to string) {
+	if m.Exceptions == nil {
+		m.Exceptions = make(map[string]bool)
+	}
+	m.Exceptions[layerEdgeKey(from, to)] = true
+}
+
+// layerIndex maps lower-cased layer names to their position in the model.
+func (m *LayerModel) layerIndex() map[string]int {
+	index := make(map[string]int, len(m.Layers))
+	for i, l := range m.Layers {
+		index[strings.ToLower(l)] = i
+	}
+	return index
+}
+
 // LayerViolationRule enforces strict layering (e.g., Web -> API -> Database).
-type LayerViolationRule struct{}
+//
+// The layer model comes from a layers block in the DSL, then from Layers (set from
+// the project config), and finally from the built-in web/api/service/data/database model.
+type LayerViolationRule struct {
+	// Layers is the layer model used when the program declares none.
+	Layers *LayerModel
+}
 
 func (r *LayerViolationRule) Name() string {
 	return "Layer Violation"
 }
 
+//nolint:funlen,gocyclo // Validation logic is long and complex
 func (r *LayerViolationRule) Validate(program *language.Program) []diagnostics.Diagnostic {
 	if program == nil || program.Model == nil {
 		return nil
 	}
 
-	// Collect all relations from Model
-	_, relations := collectElements(program.Model)
+	model := layerModelFromProgram(program)
+	if model == nil {
+		model = r.Layers
+	}
+	if model == nil {
+		model = DefaultLayerModel()
+	}
+
+	// Collect all elements and relations from Model
+	elements, relations := collectElements(program.Model)
+	layers := newLayerResolver(model, elements)
+	index := model.layerIndex()
 
 	// Pre-allocate diagnostics slice
 	estimatedDiags := len(relations) / 10
@@ -30,165 +201,198 @@ func (r *LayerViolationRule) Validate(program *language.Program) []diagnostics.D
 	}
 	diags := make([]diagnostics.Diagnostic, 0, estimatedDiags)
 
-	// Define layers (ordered from top to bottom)
-	// In a real implementation, this should be configurable
-	layers := []string{"web", "api", "service", "data", "database"}
-	layerMap := make(map[string]int, len(layers))
-	for i, l := range layers {
-		layerMap[l] = i
-	}
+	for _, rel := range relations {
+		// Implied parent relations would repeat the violation of the explicit
+		// relation they were inferred from.
+		if rel.Implied {
+			continue
+		}
+		fromID := rel.From.String()
+		toID := rel.To.String()
+		if model.Exceptions[layerEdgeKey(fromID, toID)] {
+			continue
+		}
 
-	// Helper to determine layer of an element
-	getLayer := func(name string, metadata []*language.MetaEntry) string {
-		// 1. Check metadata
-		for _, m := range metadata {
-			if m.Key == "layer" && m.Value != nil {
-				return strings.ToLower(strings.Trim(*m.Value, "\""))
-			}
+		fromLayer := layers.layerOf(fromID)
+		toLayer := layers.layerOf(toID)
+		if fromLayer == "" || toLayer == "" {
+			continue
 		}
-		// 2. Check name convention (simple heuristic)
-		lowerName := strings.ToLower(name)
-		for _, l := range layers {
-			if strings.Contains(lowerName, l) {
-				return l
-			}
+		fromIdx, fromOK := index[strings.ToLower(fromLayer)]
+		toIdx, toOK := index[strings.ToLower(toLayer)]
+		if !fromOK || !toOK || fromIdx == toIdx {
+			continue
+		}
+		if model.Allowed[layerEdgeKey(model.Layers[fromIdx], model.Layers[toIdx])] {
+			continue
 		}
-		return ""
-	}
 
-	// Check all relations
-	for _, rel := range relations {
-		fromLayer := ""
-		toLayer := ""
-
-		// Find source element
-		// This is a simplified lookup. In a real implementation, we'd use a symbol table.
-		// For now, we'll just check top-level items or infer from name.
-		// We can try to find the element in the architecture items to get its metadata.
-
-		// Optimization: Build a map of element names to metadata first?
-		// For this rule, let's just iterate items to find metadata if possible.
-		// Or just rely on the name heuristic if metadata isn't easily accessible without a symbol table.
-		// Let's try to find the element definition to get metadata.
-
-		fromMeta := findMetadata(program, rel.From.String())
-		toMeta := findMetadata(program, rel.To.String())
-
-		fromLayer = getLayer(rel.From.String(), fromMeta)
-		toLayer = getLayer(rel.To.String(), toMeta)
-
-		if fromLayer != "" && toLayer != "" {
-			fromIdx := layerMap[fromLayer]
-			toIdx := layerMap[toLayer]
-
-			// Rule: Can only call same layer or one layer below
-			// Strict layering: fromIdx <= toIdx (assuming 0 is top, N is bottom? No, usually Web calls API)
-			// Let's say:
-			// Web (0) -> API (1) : OK (0 < 1)
-			// API (1) -> Data (3) : OK (1 < 3) - Relaxed layering (can skip layers)
-			// Data (3) -> Web (0) : VIOLATION (3 > 0)
-
-			if fromIdx > toIdx {
-				// Build enhanced error message with suggestions
-				var msgSb strings.Builder
-				fromID := rel.From.String()
-				toID := rel.To.String()
-				msgSb.Grow(len(fromID) + len(toID) + len(fromLayer) + len(toLayer) + 120)
-				msgSb.WriteString("Layer violation: '")
-				msgSb.WriteString(fromID)
-				msgSb.WriteString("' (")
-				msgSb.WriteString(fromLayer)
-				msgSb.WriteString(") cannot depend on '")
-				msgSb.WriteString(toID)
-				msgSb.WriteString("' (")
-				msgSb.WriteString(toLayer)
-				msgSb.WriteString("). Dependencies must flow downwards (higher layers can only depend on lower layers).")
-
-				var suggestions []string
-				suggestions = append(suggestions, fmt.Sprintf("Reverse the dependency: '%s -> %s'", toID, fromID))
-				suggestions = append(suggestions, "Or restructure to follow proper layering (e.g., Web -> API -> Data)")
-				suggestions = append(suggestions, "If this is intentional, consider documenting the exception")
-
-				diags = append(diags, diagnostics.Diagnostic{
-					Code:     diagnostics.CodeLayerViolation,
-					Severity: diagnostics.SeverityError,
-					Message:  msgSb.String(),
-					Location: diagnostics.SourceLocation{
-						File:   rel.Location().File,
-						Line:   rel.Location().Line,
-						Column: rel.Location().Column,
-					},
-					Context: []string{
-						fmt.Sprintf("%s -> %s", rel.From.String(), rel.To.String()),
-					},
-					Suggestions: suggestions,
-				})
-			}
+		// Dependencies must flow downwards: a lower index is a higher layer.
+		// Web (0) -> API (1) : OK
+		// API (1) -> Data (3) : OK only if skipping layers is allowed
+		// Data (3) -> Web (0) : VIOLATION
+		var msgSb strings.Builder
+		msgSb.Grow(len(fromID) + len(toID) + len(fromLayer) + len(toLayer) + 160)
+		msgSb.WriteString("Layer violation: '")
+		msgSb.WriteString(fromID)
+		msgSb.WriteString("' (")
+		msgSb.WriteString(fromLayer)
+		msgSb.WriteString(") cannot depend on '")
+		msgSb.WriteString(toID)
+		msgSb.WriteString("' (")
+		msgSb.WriteString(toLayer)
+		msgSb.WriteString("). ")
+
+		var suggestions []string
+		switch {
+		case fromIdx > toIdx:
+			msgSb.WriteString("Dependencies must flow downwards (higher layers can only depend on lower layers).")
+			suggestions = append(suggestions,
+				fmt.Sprintf("Reverse the dependency: '%s -> %s'", toID, fromID),
+				"Or restructure to follow the layer order: "+strings.Join(model.Layers, " -> "),
+			)
+		case toIdx > fromIdx+1 && !model.AllowSkip:
+			skipped := model.Layers[fromIdx+1 : toIdx]
+			msgSb.WriteString("Dependencies cannot skip layers (skips ")
+			msgSb.WriteString(strings.Join(skipped, ", "))
+			msgSb.WriteString(").")
+			suggestions = append(suggestions,
+				fmt.Sprintf("Route the dependency through the '%s' layer", skipped[0]),
+				fmt.Sprintf("Or allow it explicitly: 'allow %s -> %s' in the layers block", model.Layers[fromIdx], model.Layers[toIdx]),
+			)
+		default:
+			continue
 		}
+		suggestions = append(suggestions,
+			fmt.Sprintf("If this is intentional, add 'except %s -> %s' to the layers block", fromID, toID))
+
+		loc := rel.Location()
+		diags = append(diags, diagnostics.Diagnostic{
+			Code:     diagnostics.CodeLayerViolation,
+			Severity: diagnostics.SeverityError,
+			Message:  msgSb.String(),
+			Location: diagnostics.SourceLocation{
+				File:   loc.File,
+				Line:   loc.Line,
+				Column: loc.Column,
+			},
+			Context: []string{
+				fmt.Sprintf("%s -> %s", fromID, toID),
+				layerPath(model.Layers, fromIdx, toIdx),
+			},
+			Suggestions: suggestions,
+		})
 	}
 
 	return diags
 }
 
-func findMetadata(program *language.Program, name string) []*language.MetaEntry {
-	if program == nil || program.Model == nil {
-		return nil
+// layerPath renders the layers traversed by a dependency, e.g. "adapters -> application -> domain".
+func layerPath(layers []string, fromIdx, toIdx int) string {
+	step := 1
+	if toIdx < fromIdx {
+		step = -1
 	}
-
-	// Search for element by qualified name in Model
-	var findElement func(elem *language.ElementDef, currentFQN string) []*language.MetaEntry
-	findElement = func(elem *language.ElementDef, currentFQN string) []*language.MetaEntry {
-		if elem == nil {
-			return nil
+	parts := make([]string, 0, abs(toIdx-fromIdx)+1)
+	for i := fromIdx; ; i += step {
+		parts = append(parts, layers[i])
+		if i == toIdx {
+			break
 		}
+	}
+	return strings.Join(parts, " -> ")
+}
 
-		id := elem.GetID()
-		if id == "" {
-			return nil
-		}
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
 
-		fqn := id
-		if currentFQN != "" {
-			fqn = buildQualifiedID(currentFQN, id)
+// layerResolver determines the layer of elements, caching results per reference.
+type layerResolver struct {
+	model    *LayerModel
+	elements map[string]*language.ElementDef
+	suffixes map[string][]string
+	cache    map[string]string
+}
+
+func newLayerResolver(model *LayerModel, elements map[string]*language.ElementDef) *layerResolver {
+	suffixes := make(map[string][]string, len(elements))
+	for fqn := range elements {
+		suffix := extractSuffix(fqn)
+		suffixes[suffix] = append(suffixes[suffix], fqn)
+	}
+	return &layerResolver{
+		model:    model,
+		elements: elements,
+		suffixes: suffixes,
+		cache:    make(map[string]string, len(elements)),
+	}
+}
+
+// layerOf returns the layer of the referenced element: its own declaration first,
+// then the nearest ancestor's, and finally the name heuristic if enabled.
+func (lr *layerResolver) layerOf(ref string) string {
+	if layer, ok := lr.cache[ref]; ok {
+		return layer
+	}
+
+	fqn := ref
+	if _, ok := lr.elements[fqn]; !ok {
+		if matches := lr.suffixes[extractSuffix(ref)]; len(matches) == 1 {
+			fqn = matches[0]
 		}
+	}
 
-		// Check if this is the element we're looking for
-		if fqn == name || id == name {
-			// Extract metadata from body
-			body := elem.GetBody()
-			if body != nil {
-				for _, item := range body.Items {
-					if item.Metadata != nil {
-						return item.Metadata.Entries
-					}
-				}
+	layer := ""
+	for id := fqn; id != ""; id = parentID(id) {
+		if l := lr.declaredLayer(id); l != "" {
+			layer = l
+			break
+		}
+	}
+	if layer == "" && ref != fqn {
+		layer = lr.model.Members[ref]
+	}
+	if layer == "" && lr.model.NameHeuristics {
+		lowerName := strings.ToLower(ref)
+		for _, l := range lr.model.Layers {
+			if strings.Contains(lowerName, l) {
+				layer = l
+				break
 			}
-			return nil
 		}
+	}
+
+	lr.cache[ref] = layer
+	return layer
+}
 
-		// Recurse into nested elements
-		body := elem.GetBody()
-		if body != nil {
-			for _, bodyItem := range body.Items {
-				if bodyItem.Element != nil {
-					if meta := findElement(bodyItem.Element, fqn); meta != nil {
-						return meta
+// declaredLayer returns the layer declared for an element through metadata or membership.
+func (lr *layerResolver) declaredLayer(fqn string) string {
+	if elem, ok := lr.elements[fqn]; ok {
+		if body := elem.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Metadata == nil {
+					continue
+				}
+				for _, m := range item.Metadata.Entries {
+					if m.Key == "layer" && m.Value != nil {
+						return strings.ToLower(strings.Trim(*m.Value, "\""))
 					}
 				}
 			}
 		}
-
-		return nil
 	}
+	return lr.model.Members[fqn]
+}
 
-	// Search all top-level elements
-	for _, item := range program.Model.Items {
-		if item.ElementDef != nil {
-			if meta := findElement(item.ElementDef, ""); meta != nil {
-				return meta
-			}
-		}
+// parentID returns the qualified ID of the parent element, or "" for top-level IDs.
+func parentID(fqn string) string {
+	if idx := strings.LastIndex(fqn, "."); idx > 0 {
+		return fqn[:idx]
 	}
-
-	return nil
+	return ""
 }
@@ -3,6 +3,7 @@ package engine
 import (
 	"testing"
 
+	"github.com/sruja-ai/sruja/pkg/config"
 	"github.com/sruja-ai/sruja/pkg/diagnostics"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
@@ -114,3 +115,227 @@ func TestLayerViolationRule_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestLayerViolationRule_DeclaredLayers(t *testing.T) {
+	const hexagonal = `
+		shop = system "Shop" {
+			web = container "Web"
+			app = container "Application"
+			core = container "Domain"
+			db = database "DB"
+		}
+	`
+	tests := []struct {
+		name     string
+		dsl      string
+		expected int
+	}{
+		{
+			name: "Downward dependency is allowed",
+			dsl: hexagonal + `
+				layers {
+					adapters [shop.web]
+					application [shop.app]
+					domain [shop.core]
+				}
+				shop.web -> shop.app
+			`,
+			expected: 0,
+		},
+		{
+			name: "Upward dependency is a violation",
+			dsl: hexagonal + `
+				layers {
+					adapters [shop.web]
+					application [shop.app]
+					domain [shop.core]
+				}
+				shop.core -> shop.app
+			`,
+			expected: 1,
+		},
+		{
+			name: "Skipping layers is reported when disabled",
+			dsl: hexagonal + `
+				layers {
+					adapters [shop.web]
+					application [shop.app]
+					domain [shop.core]
+					skip false
+				}
+				shop.web -> shop.core
+			`,
+			expected: 1,
+		},
+		{
+			name: "Allowed layer pair",
+			dsl: hexagonal + `
+				layers {
+					adapters [shop.web]
+					application [shop.app]
+					domain [shop.core]
+					infrastructure [shop.db]
+					allow infrastructure -> domain
+				}
+				shop.db -> shop.core
+			`,
+			expected: 0,
+		},
+		{
+			name: "Relation exception",
+			dsl: hexagonal + `
+				layers {
+					adapters [shop.web]
+					application [shop.app]
+					domain [shop.core]
+					except shop.core -> shop.web "callback"
+				}
+				shop.core -> shop.web
+			`,
+			expected: 0,
+		},
+		{
+			name: "Members inherit the layer of their parent",
+			dsl: `
+				ui = system "UI" {
+					web = container "Web"
+				}
+				backend = system "Backend" {
+					api = container "API"
+				}
+				layers {
+					presentation [ui]
+					business [backend]
+				}
+				backend.api -> ui.web
+			`,
+			expected: 1,
+		},
+		{
+			name: "Name heuristics are disabled once layers are declared",
+			dsl: `
+				MyWeb = container "Web"
+				MyAPI = container "API"
+				layers {
+					adapters
+					domain
+				}
+				MyAPI -> MyWeb
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, _ := language.NewParser()
+			program, diags, err := parser.Parse("test.sruja", tt.dsl)
+			if err != nil {
+				t.Fatalf("Failed to parse DSL: %v", err)
+			}
+			if len(diags) > 0 {
+				t.Fatalf("Parser reported diagnostics: %v", diags)
+			}
+
+			validationDiags := (&LayerViolationRule{}).Validate(program)
+			if len(validationDiags) != tt.expected {
+				t.Errorf("Expected %d layer violations, got %d", tt.expected, len(validationDiags))
+				for _, d := range validationDiags {
+					t.Logf("Diagnostic: %s", d.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestLayerViolationRule_ReportsLayerPath(t *testing.T) {
+	dsl := `
+		a = container "A"
+		b = container "B"
+		c = container "C"
+		layers {
+			top [a]
+			middle [b]
+			bottom [c]
+			skip false
+		}
+		a -> c
+	`
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	diags := (&LayerViolationRule{}).Validate(program)
+	if len(diags) != 1 {
+		t.Fatalf("Expected 1 violation, got %d", len(diags))
+	}
+	d := diags[0]
+	if len(d.Context) != 2 || d.Context[0] != "a -> c" || d.Context[1] != "top -> middle -> bottom" {
+		t.Errorf("Unexpected context: %v", d.Context)
+	}
+}
+
+func TestLayerViolationRule_RelationWithoutPosition(t *testing.T) {
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", `
+		a = container "A"
+		b = container "B"
+		layers {
+			top [a]
+			bottom [b]
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	// Relations built in code or imported from JSON have no source position
+	program.Model.Items = append(program.Model.Items, language.ModelItem{Relation: &language.Relation{
+		From:  language.QualifiedIdent{Parts: []string{"b"}},
+		Arrow: "->",
+		To:    language.QualifiedIdent{Parts: []string{"a"}},
+	}})
+	if diags := (&LayerViolationRule{}).Validate(program); len(diags) != 1 {
+		t.Errorf("Expected 1 violation for a relation without position, got %d: %v", len(diags), diags)
+	}
+}
+
+func TestLayerModelFromConfig(t *testing.T) {
+	skip := false
+	cfg := &config.LayersConfig{
+		Order:      []string{"adapters", "domain", "infrastructure"},
+		Members:    map[string][]string{"adapters": {"web"}, "domain": {"core"}, "infrastructure": {"db"}},
+		AllowSkip:  &skip,
+		Allow:      []string{"infrastructure -> domain"},
+		Exceptions: []string{"core -> web"},
+	}
+	model, err := LayerModelFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("LayerModelFromConfig failed: %v", err)
+	}
+
+	dsl := `
+		web = container "Web"
+		core = container "Core"
+		db = database "DB"
+		web -> db
+		db -> core
+		core -> web
+	`
+	parser, _ := language.NewParser()
+	program, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	diags := (&LayerViolationRule{Layers: model}).Validate(program)
+	if len(diags) != 1 || diags[0].Context[0] != "web -> db" {
+		t.Errorf("Expected only the skipped layer web -> db to be reported, got %v", diags)
+	}
+
+	if _, err := LayerModelFromConfig(&config.LayersConfig{Order: []string{"a"}, Allow: []string{"a -> b"}}); err == nil {
+		t.Error("Expected error for allow entry with unknown layer")
+	}
+	if m, err := LayerModelFromConfig(nil); m != nil || err != nil {
+		t.Errorf("Expected nil model for nil config, got %v, %v", m, err)
+	}
+}
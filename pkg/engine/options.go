@@ -15,6 +15,9 @@ type validatorConfig struct {
 	concurrency  int
 	rules        []Rule
 	defaultRules bool
+	strict       bool
+
+	reportUnusedSuppressions bool
 }
 
 // WithTimeout sets a custom timeout for validation.
@@ -72,12 +75,37 @@ func WithDefaultRules() ValidatorOption {
 	}
 }
 
+// WithStrict promotes warning diagnostics to errors.
+//
+// Example:
+//
+//	validator := NewValidatorWithOptions(WithDefaultRules(), WithStrict())
+func WithStrict() ValidatorOption {
+	return func(c *validatorConfig) {
+		c.strict = true
+	}
+}
+
+// WithUnusedSuppressions reports sruja:ignore comments that silence no
+// diagnostic, so stale suppressions get cleaned up. Only use it with a rule set
+// that covers the codes suppressed, such as the project's full rule set.
+//
+// Example:
+//
+//	validator := NewValidatorWithOptions(WithDefaultRules(), WithUnusedSuppressions())
+func WithUnusedSuppressions() ValidatorOption {
+	return func(c *validatorConfig) {
+		c.reportUnusedSuppressions = true
+	}
+}
+
 // ScorerOption is a functional option for configuring a Scorer.
 type ScorerOption func(*scorerConfig)
 
 // scorerConfig holds configuration for creating a Scorer.
 type scorerConfig struct {
 	validatorOptions []ValidatorOption
+	profile          *ScoringProfile
 }
 
 // WithValidatorOptions passes options to the underlying validator.
@@ -93,6 +121,19 @@ func WithValidatorOptions(opts ...ValidatorOption) ScorerOption {
 	}
 }
 
+// WithScoringProfile sets the weights, penalties and grade thresholds the
+// scorer applies. Default is DefaultScoringProfile().
+//
+// Example:
+//
+//	profile, _ := ScoringProfileFromConfig(cfg.Scoring, "strict")
+//	scorer := NewScorerWithOptions(WithScoringProfile(profile))
+func WithScoringProfile(profile *ScoringProfile) ScorerOption {
+	return func(c *scorerConfig) {
+		c.profile = profile
+	}
+}
+
 // NewValidatorWithOptions creates a new Validator with the given options.
 // If no options are provided, creates an empty validator (use RegisterRule to add rules).
 //
@@ -148,16 +189,17 @@ func NewScorerWithOptions(opts ...ScorerOption) *Scorer {
 		opt(config)
 	}
 
-	// Create validator with options
-	v := NewValidatorWithOptions(config.validatorOptions...)
+	// Create validator with options; the default rules are registered once,
+	// whether or not the options ask for them too
+	v := NewValidatorWithOptions(append(config.validatorOptions, WithDefaultRules())...)
 
-	// Register scoring rules
-	v.RegisterRule(&CycleDetectionRule{})
-	v.RegisterRule(&OrphanDetectionRule{})
-	v.RegisterRule(&LayerViolationRule{})
-	v.RegisterRule(&ValidReferenceRule{})
+	return newScorer(v, config.profile)
+}
 
-	return &Scorer{
-		validator: v,
+// newScorer creates a Scorer, applying the default profile when profile is nil.
+func newScorer(v *Validator, profile *ScoringProfile) *Scorer {
+	if profile == nil {
+		profile = DefaultScoringProfile()
 	}
+	return &Scorer{validator: v, profile: profile}
 }
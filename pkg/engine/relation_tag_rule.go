@@ -1,34 +1,38 @@
 package engine
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/sruja-ai/sruja/pkg/diagnostics"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
+// RelationTagRule checks relation tags against the relation vocabulary: the
+// built-in verbs and the relationship kinds declared in the specification.
+// Relations tagged with a declared kind must also connect element kinds the
+// kind allows.
 type RelationTagRule struct{}
 
 func (r *RelationTagRule) Name() string {
 	return "Relation Tags"
 }
 
-var allowedTags = map[string]bool{
-	"reads":     true,
-	"writes":    true,
-	"sends":     true,
-	"uses":      true,
-	"calls":     true,
-	"processes": true,
-	"queries":   true,
-	"updates":   true,
-	"deletes":   true,
-	"creates":   true,
-	"triggers":  true,
-	"notifies":  true,
+// DefaultRelationVerbs are the relation tags allowed without declaring a
+// relationship kind.
+var DefaultRelationVerbs = []string{
+	"reads", "writes", "sends", "uses", "calls", "processes",
+	"queries", "updates", "deletes", "creates", "triggers", "notifies",
 }
 
-//nolint:gocyclo // Validation logic is complex
+var allowedTags = func() map[string]bool {
+	tags := make(map[string]bool, len(DefaultRelationVerbs))
+	for _, verb := range DefaultRelationVerbs {
+		tags[verb] = true
+	}
+	return tags
+}()
+
 func (r *RelationTagRule) Validate(program *language.Program) []diagnostics.Diagnostic {
 	if program == nil || program.Model == nil {
 		return nil
@@ -36,40 +40,86 @@ func (r *RelationTagRule) Validate(program *language.Program) []diagnostics.Diag
 	// Pre-allocate diagnostics slice with estimated capacity
 	diags := make([]diagnostics.Diagnostic, 0, 10)
 
-	checkTags := func(rel *language.Relation) {
-		if rel == nil {
-			return
+	kinds := program.Specification.RelationshipKinds()
+	vocabulary := make([]string, 0, len(DefaultRelationVerbs)+len(kinds))
+	for _, verb := range DefaultRelationVerbs {
+		vocabulary = append(vocabulary, titleCase(verb))
+	}
+	for _, kind := range kinds {
+		if !allowedTags[strings.ToLower(kind.Name)] {
+			vocabulary = append(vocabulary, kind.Name)
 		}
+	}
+	allowed := strings.Join(vocabulary, ", ")
+
+	elements := collectRuleElements(program.Model)
+	resolve := newRuleRefResolver(elements)
+
+	for _, rs := range collectAllRelations(program.Model) {
+		rel := rs.Relation
+		loc := rel.Location()
+		location := diagnostics.SourceLocation{File: loc.File, Line: loc.Line, Column: loc.Column}
 		for _, tag := range rel.Tags {
-			lowerTag := strings.ToLower(tag)
-			if !allowedTags[lowerTag] {
-				// Build message efficiently
-				var msgSb strings.Builder
-				msgSb.Grow(len(tag) + 120)
-				msgSb.WriteString("Invalid relation tag '")
-				msgSb.WriteString(tag)
-				msgSb.WriteString("'. Allowed tags are: Reads, Writes, Sends, Uses, Calls, Processes, Queries, Updates, Deletes, Creates, Triggers, Notifies.")
-				diags = append(diags, diagnostics.Diagnostic{
-					Code:     diagnostics.CodeValidationRuleError,
-					Severity: diagnostics.SeverityWarning,
-					Message:  msgSb.String(),
-					Location: diagnostics.SourceLocation{
-						File:   rel.Location().File,
-						Line:   rel.Location().Line,
-						Column: rel.Location().Column,
-					},
-				})
+			kind := program.Specification.RelationshipKind(tag)
+			if kind == nil {
+				if !allowedTags[strings.ToLower(tag)] {
+					diags = append(diags, diagnostics.Diagnostic{
+						Code:     diagnostics.CodeValidationRuleError,
+						Severity: diagnostics.SeverityWarning,
+						Message:  fmt.Sprintf("Invalid relation tag '%s'. Allowed tags are: %s.", tag, allowed),
+						Location: location,
+						Suggestions: []string{
+							fmt.Sprintf("Declare the tag as a relationship kind: %s = relationship", tag),
+						},
+					})
+				}
+				continue
+			}
+
+			from := resolve(rs.Scope, rel.From.String())
+			if from != nil && !allowsKind(kind.SourceKinds(), from.kind) {
+				diags = append(diags, relationKindMismatch(rel, kind, "start from", from, kind.SourceKinds(), location))
+			}
+			to := resolve(rs.Scope, rel.To.String())
+			if to != nil && !allowsKind(kind.TargetKinds(), to.kind) {
+				diags = append(diags, relationKindMismatch(rel, kind, "point to", to, kind.TargetKinds(), location))
 			}
 		}
 	}
 
-	// Collect all relations from Model
-	_, relations := collectElements(program.Model)
+	return diags
+}
 
-	// Check all relations
-	for _, rel := range relations {
-		checkTags(rel)
+// allowsKind reports whether an element kind is in kinds; no kinds allow any.
+func allowsKind(kinds []string, kind string) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
 	}
+	return false
+}
 
-	return diags
+// relationKindMismatch reports a relation end whose element kind the
+// relationship kind does not allow.
+func relationKindMismatch(rel *language.Relation, kind *language.RelationshipKindDef, verb string, end *ruleElement, allowed []string, loc diagnostics.SourceLocation) diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Code:     diagnostics.CodeValidationRuleError,
+		Severity: diagnostics.SeverityWarning,
+		Message: fmt.Sprintf("Relation '%s -> %s' is a '%s' relation, which must %s %s, but '%s' is a %s.",
+			rel.From.String(), rel.To.String(), kind.Name, verb, strings.Join(allowed, " or "), end.fqn, end.kind),
+		Location: loc,
+		Context:  []string{fmt.Sprintf("relationship kind %s declared at %s:%d", kind.Name, kind.Pos.Filename, kind.Pos.Line)},
+	}
+}
+
+// titleCase upper-cases the first letter of a verb.
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
 }
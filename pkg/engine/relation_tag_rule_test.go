@@ -162,3 +162,83 @@ func TestRelationTagRule_IntegrationWithValidator(t *testing.T) {
 		t.Errorf("Expected validation warning for invalid tag, but got none. Diagnostics: %v", diags)
 	}
 }
+
+func TestRelationTagRule_DeclaredRelationshipKinds(t *testing.T) {
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+
+	spec := `
+	publishes = relationship "Publishes" {
+		async
+		from [system, container]
+		to [queue]
+	}
+	replicates = relationship "Replicates"
+	`
+
+	tests := []struct {
+		name         string
+		input        string
+		wantMessages []string
+	}{
+		{
+			name: "declared kinds extend the vocabulary",
+			input: spec + `
+			Orders = system "Orders"
+			Events = queue "Events"
+			Replica = database "Replica"
+			Orders -> Events [publishes]
+			Orders -> Replica [Replicates, writes]`,
+		},
+		{
+			name: "undeclared tag lists declared kinds",
+			input: spec + `
+			Orders = system "Orders"
+			Events = queue "Events"
+			Orders -> Events [subscribes]`,
+			wantMessages: []string{"Invalid relation tag 'subscribes'. Allowed tags are: Reads, Writes, Sends, Uses, Calls, Processes, Queries, Updates, Deletes, Creates, Triggers, Notifies, publishes, replicates."},
+		},
+		{
+			name: "source kind not allowed",
+			input: spec + `
+			Customer = person "Customer"
+			Events = queue "Events"
+			Customer -> Events [publishes]`,
+			wantMessages: []string{"Relation 'Customer -> Events' is a 'publishes' relation, which must start from system or container, but 'Customer' is a person."},
+		},
+		{
+			name: "target kind not allowed in nested relation",
+			input: spec + `
+			Shop = system "Shop" {
+				API = container "API"
+				DB = database "DB"
+				API -> DB [publishes]
+			}`,
+			wantMessages: []string{"Relation 'API -> DB' is a 'publishes' relation, which must point to queue, but 'Shop.DB' is a database."},
+		},
+	}
+
+	rule := &RelationTagRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, _, err := parser.Parse("test.sruja", tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			diags := rule.Validate(program)
+			if len(diags) != len(tt.wantMessages) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(tt.wantMessages), len(diags), diags)
+			}
+			for i, want := range tt.wantMessages {
+				if diags[i].Message != want {
+					t.Errorf("Expected message %q, got %q", want, diags[i].Message)
+				}
+				if diags[i].Severity != diagnostics.SeverityWarning {
+					t.Errorf("Expected warning, got %s", diags[i].Severity)
+				}
+			}
+		})
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
-// Resolver updates the AST to use fully qualified names for ambiguous-free references.
+// Resolver updates the AST to use fully qualified names for ambiguous-free references
+// and links each resolved relation to the elements at its ends.
 // It maintains an internal cache for O(1) repeated lookups.
 type Resolver struct {
 	defined      map[string]bool
+	elements     map[string]*language.ElementDef // FQN -> definition
 	suffixMap    map[string][]string
 	resolveCache map[string]string   // Cache for resolved IDs (ref -> resolved)
 	partsCache   map[string][]string // Cache for ID parts (FQN -> []string)
@@ -20,6 +22,7 @@ func NewResolverFromModel(model *language.Model) *Resolver {
 	if model == nil {
 		return &Resolver{
 			defined:      make(map[string]bool, 16),
+			elements:     make(map[string]*language.ElementDef, 16),
 			suffixMap:    make(map[string][]string, 8),
 			resolveCache: make(map[string]string, 16),
 			partsCache:   make(map[string][]string, 16),
@@ -36,6 +39,7 @@ func NewResolverFromModel(model *language.Model) *Resolver {
 
 	r := &Resolver{
 		defined:      make(map[string]bool, estimatedElements),
+		elements:     make(map[string]*language.ElementDef, estimatedElements),
 		suffixMap:    make(map[string][]string, estimatedElements/2),
 		resolveCache: make(map[string]string, estimatedElements),
 		partsCache:   make(map[string][]string, estimatedElements),
@@ -88,6 +92,7 @@ func (r *Resolver) indexFromModel(model *language.Model) {
 
 		// Add to defined and suffix maps
 		r.defined[fqn] = true
+		r.elements[fqn] = elem
 		suffix := extractSuffix(fqn)
 		r.suffixMap[suffix] = append(r.suffixMap[suffix], fqn)
 
@@ -148,6 +153,26 @@ func (r *Resolver) resolveID(ref string) string {
 	return result
 }
 
+// resolveInScope resolves a reference written inside the body of the element
+// scope: an exact FQN wins, then the scope and each of its ancestors are tried
+// as a prefix, and finally the reference is resolved as from the top level.
+func (r *Resolver) resolveInScope(ref, scope string) string {
+	if ref == "" || r.defined[ref] {
+		return ref
+	}
+	for s := scope; s != ""; {
+		if candidate := buildQualifiedID(s, ref); r.defined[candidate] {
+			return candidate
+		}
+		lastDot := strings.LastIndex(s, ".")
+		if lastDot == -1 {
+			break
+		}
+		s = s[:lastDot]
+	}
+	return r.resolveID(ref)
+}
+
 // getParts returns the parts of a fully qualified name, cached to avoid repeated splitting.
 func (r *Resolver) getParts(fqn string) []string {
 	if parts, ok := r.partsCache[fqn]; ok {
@@ -161,6 +186,8 @@ func (r *Resolver) getParts(fqn string) []string {
 }
 
 // ResolveModel updates the program model in place (Sruja syntax).
+// References inside element bodies are resolved relative to the enclosing
+// element, and resolved relations get ResolvedFrom and ResolvedTo set.
 // Uses iterative traversal to avoid closure allocation overhead.
 func (r *Resolver) ResolveModel(model *language.Model) {
 	if model == nil {
@@ -170,17 +197,17 @@ func (r *Resolver) ResolveModel(model *language.Model) {
 	// Use explicit stack for iterative traversal
 	type frame struct {
 		elem *language.ElementDef
+		fqn  string
 	}
 	stack := make([]frame, 0, 16)
 
 	// Process top-level relations
 	for _, item := range model.Items {
 		if item.Relation != nil {
-			r.updateRef(&item.Relation.From)
-			r.updateRef(&item.Relation.To)
+			r.resolveRelation(item.Relation, "")
 		}
-		if item.ElementDef != nil {
-			stack = append(stack, frame{elem: item.ElementDef})
+		if item.ElementDef != nil && item.ElementDef.GetID() != "" {
+			stack = append(stack, frame{elem: item.ElementDef, fqn: item.ElementDef.GetID()})
 		}
 	}
 
@@ -198,23 +225,35 @@ func (r *Resolver) ResolveModel(model *language.Model) {
 
 		for _, bodyItem := range body.Items {
 			if bodyItem.Relation != nil {
-				r.updateRef(&bodyItem.Relation.From)
-				r.updateRef(&bodyItem.Relation.To)
+				r.resolveRelation(bodyItem.Relation, f.fqn)
 			}
-			if bodyItem.Element != nil {
-				stack = append(stack, frame{elem: bodyItem.Element})
+			if bodyItem.Element != nil && bodyItem.Element.GetID() != "" {
+				stack = append(stack, frame{elem: bodyItem.Element, fqn: buildQualifiedID(f.fqn, bodyItem.Element.GetID())})
 			}
 		}
 	}
 }
 
+// resolveRelation qualifies both ends of a relation written in scope and links
+// the ends that resolve to a defined element.
+func (r *Resolver) resolveRelation(rel *language.Relation, scope string) {
+	r.updateRef(&rel.From, scope)
+	r.updateRef(&rel.To, scope)
+	if elem := r.elements[rel.From.String()]; elem != nil {
+		rel.ResolvedFrom = elem
+	}
+	if elem := r.elements[rel.To.String()]; elem != nil {
+		rel.ResolvedTo = elem
+	}
+}
+
 // updateRef updates a QualifiedIdent to use the resolved fully qualified name.
-func (r *Resolver) updateRef(id *language.QualifiedIdent) {
+func (r *Resolver) updateRef(id *language.QualifiedIdent, scope string) {
 	if id == nil {
 		return
 	}
 	original := id.String()
-	resolved := r.resolveID(original)
+	resolved := r.resolveInScope(original, scope)
 	if resolved != original && resolved != "" {
 		// Update the AST node using cached parts
 		id.Parts = r.getParts(resolved)
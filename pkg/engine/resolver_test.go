@@ -107,3 +107,64 @@ func TestResolver(t *testing.T) {
 		}
 	}
 }
+
+func TestResolver_ScopedReferencesAndResolvedEnds(t *testing.T) {
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+
+	dsl := `User = person "User"
+Shop = system "Shop" {
+	API = container "API"
+	DB = database "DB"
+	API -> DB "reads"
+}
+Billing = system "Billing" {
+	API = container "API"
+}
+User -> Shop.API "uses"
+User -> Missing`
+
+	program, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	RunResolution(program)
+
+	var scoped, top, missing *language.Relation
+	for _, item := range program.Model.Items {
+		switch {
+		case item.ElementDef != nil && item.ElementDef.GetID() == "Shop":
+			for _, bi := range item.ElementDef.GetBody().Items {
+				if bi.Relation != nil {
+					scoped = bi.Relation
+				}
+			}
+		case item.Relation != nil && item.Relation.Pos.Line == 10:
+			top = item.Relation
+		case item.Relation != nil && item.Relation.Pos.Line == 11:
+			missing = item.Relation
+		}
+	}
+	if scoped == nil || top == nil || missing == nil {
+		t.Fatal("Expected the scoped, top-level and unresolved relations")
+	}
+
+	// API is ambiguous globally but not inside Shop
+	if scoped.From.String() != "Shop.API" || scoped.To.String() != "Shop.DB" {
+		t.Errorf("Expected Shop.API -> Shop.DB, got %s -> %s", scoped.From.String(), scoped.To.String())
+	}
+	if scoped.ResolvedFrom == nil || scoped.ResolvedFrom.GetID() != "API" || scoped.ResolvedFrom.GetLabel() != "API" {
+		t.Errorf("Expected ResolvedFrom to be Shop.API, got %v", scoped.ResolvedFrom)
+	}
+	if scoped.ResolvedTo == nil || scoped.ResolvedTo.GetID() != "DB" {
+		t.Errorf("Expected ResolvedTo to be Shop.DB, got %v", scoped.ResolvedTo)
+	}
+	if top.ResolvedFrom == nil || top.ResolvedFrom.GetLabel() != "User" {
+		t.Errorf("Expected ResolvedFrom to be User, got %v", top.ResolvedFrom)
+	}
+	if missing.ResolvedFrom == nil || missing.ResolvedTo != nil {
+		t.Errorf("Expected only the defined end to resolve, got %v -> %v", missing.ResolvedFrom, missing.ResolvedTo)
+	}
+}
@@ -0,0 +1,141 @@
+// pkg/engine/rule_config.go
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// parseSeverity converts a config severity value into a diagnostic severity.
+// The second return value is false when the rule is turned off.
+func parseSeverity(value string) (diagnostics.Severity, bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case config.SeverityError:
+		return diagnostics.SeverityError, true, nil
+	case config.SeverityWarning:
+		return diagnostics.SeverityWarning, true, nil
+	case config.SeverityInfo:
+		return diagnostics.SeverityInfo, true, nil
+	case config.SeverityOff:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("invalid severity %q (expected error, warning, info or off)", value)
+	}
+}
+
+// severityOverrideRule rewrites the severity of every diagnostic produced by the wrapped rule.
+type severityOverrideRule struct {
+	Rule
+	severity diagnostics.Severity
+}
+
+func (r *severityOverrideRule) Validate(program *language.Program) []diagnostics.Diagnostic {
+	diags := r.Rule.Validate(program)
+	for i := range diags {
+		diags[i].Severity = r.severity
+	}
+	return diags
+}
+
+// NewValidatorFromConfig builds a validator from the validation section of a project config.
+//
+// cfg.Rules selects the rules to run; when it is empty, defaultIDs is used, and when that
+// is empty too, DefaultRuleIDs. cfg.Severity overrides the severity of individual rules
+// ("off" removes the rule), cfg.Strict promotes warnings to errors, cfg.Layers
+// configures the layer model of the layer-violation rule, and the rules declared in
+// cfg.RuleFiles are added to the selected rules.
+// A nil cfg yields the default rule set. Unknown rule IDs and severities are reported as errors.
+//
+// Example:
+//
+//	cfg, _ := config.LoadConfigFrom(".")
+//	validator, err := NewValidatorFromConfig(cfg.Validation, nil)
+func NewValidatorFromConfig(cfg *config.ValidationConfig, defaultIDs []string, opts ...ValidatorOption) (*Validator, error) {
+	ids := defaultIDs
+	if len(ids) == 0 {
+		ids = DefaultRuleIDs
+	}
+	var overrides map[string]string
+	var layers *LayerModel
+	var fileRules []Rule
+	strict := false
+	if cfg != nil {
+		if len(cfg.Rules) > 0 {
+			ids = cfg.Rules
+		}
+		overrides = cfg.Severity
+		strict = cfg.Strict
+
+		var err error
+		if layers, err = LayerModelFromConfig(cfg.Layers); err != nil {
+			return nil, err
+		}
+		for _, path := range cfg.RuleFiles {
+			loaded, err := LoadRuleFile(path)
+			if err != nil {
+				return nil, err
+			}
+			fileRules = append(fileRules, loaded...)
+		}
+	}
+
+	for id := range overrides {
+		if _, ok := LookupRule(id); !ok {
+			return nil, fmt.Errorf("unknown rule %q in severity overrides (known rules: %s)", id, strings.Join(RuleIDs(), ", "))
+		}
+	}
+
+	rules := make([]Rule, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		rule, ok := NewRule(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q (known rules: %s)", id, strings.Join(RuleIDs(), ", "))
+		}
+		if lr, ok := rule.(*LayerViolationRule); ok {
+			lr.Layers = layers
+		}
+		if value, ok := overrides[id]; ok {
+			severity, enabled, err := parseSeverity(value)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", id, err)
+			}
+			if !enabled {
+				continue
+			}
+			rule = &severityOverrideRule{Rule: rule, severity: severity}
+		}
+		rules = append(rules, rule)
+	}
+	rules = append(rules, fileRules...)
+
+	opts = append(opts, WithRules(rules...))
+	if strict {
+		opts = append(opts, WithStrict())
+	}
+	return NewValidatorWithOptions(opts...), nil
+}
+
+// NewScorerFromConfig creates a Scorer whose validator honours the project config.
+// When cfg selects no rules explicitly, the default rule set is used. Options
+// passed to the validator with WithValidatorOptions are applied as well.
+func NewScorerFromConfig(cfg *config.ValidationConfig, opts ...ScorerOption) (*Scorer, error) {
+	sc := &scorerConfig{}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	v, err := NewValidatorFromConfig(cfg, nil, sc.validatorOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return newScorer(v, sc.profile), nil
+}
@@ -0,0 +1,136 @@
+// pkg/engine/rule_config_test.go
+package engine
+
+import (
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const configTestDSL = `
+system = kind "System"
+A = system "A"
+B = system "B"
+Lonely = system "Lonely"
+A -> B "calls"
+`
+
+func parseConfigTestProgram(t *testing.T) *language.Program {
+	t.Helper()
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("config.sruja", configTestDSL)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	RunResolution(program)
+	return program
+}
+
+func countCode(diags []diagnostics.Diagnostic, code string, severity diagnostics.Severity) int {
+	n := 0
+	for i := range diags {
+		if diags[i].Code == code && diags[i].Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+func TestNewValidatorFromConfig_Defaults(t *testing.T) {
+	v, err := NewValidatorFromConfig(nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(v.Rules) != len(DefaultRuleIDs) {
+		t.Errorf("Expected %d default rules, got %d", len(DefaultRuleIDs), len(v.Rules))
+	}
+}
+
+func TestNewValidatorFromConfig_RuleSelection(t *testing.T) {
+	v, err := NewValidatorFromConfig(&config.ValidationConfig{Rules: []string{"unique-ids", "orphan-detection"}}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(v.Rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(v.Rules))
+	}
+	if v.Rules[1].Name() != (&OrphanDetectionRule{}).Name() {
+		t.Errorf("Expected orphan rule second, got %s", v.Rules[1].Name())
+	}
+}
+
+func TestNewValidatorFromConfig_SeverityOverrides(t *testing.T) {
+	program := parseConfigTestProgram(t)
+
+	v, err := NewValidatorFromConfig(&config.ValidationConfig{
+		Rules:    []string{"orphan-detection"},
+		Severity: map[string]string{"orphan-detection": "error"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if countCode(v.Validate(program), diagnostics.CodeOrphanElement, diagnostics.SeverityError) == 0 {
+		t.Error("Expected orphan diagnostics promoted to error")
+	}
+
+	v, err = NewValidatorFromConfig(&config.ValidationConfig{
+		Rules:    []string{"orphan-detection", "unique-ids"},
+		Severity: map[string]string{"orphan-detection": "off"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(v.Rules) != 1 {
+		t.Errorf("Expected rule turned off to be removed, got %d rules", len(v.Rules))
+	}
+}
+
+func TestNewValidatorFromConfig_Strict(t *testing.T) {
+	program := parseConfigTestProgram(t)
+
+	v, err := NewValidatorFromConfig(&config.ValidationConfig{Strict: true, Rules: []string{"orphan-detection"}}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diags := v.Validate(program)
+	if len(diags) == 0 {
+		t.Fatal("Expected orphan diagnostics")
+	}
+	for _, d := range diags {
+		if d.Severity == diagnostics.SeverityWarning {
+			t.Errorf("Strict mode should promote warnings, got %s", d.Message)
+		}
+	}
+}
+
+func TestNewValidatorFromConfig_Errors(t *testing.T) {
+	if _, err := NewValidatorFromConfig(&config.ValidationConfig{Rules: []string{"no-such-rule"}}, nil); err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+	if _, err := NewValidatorFromConfig(&config.ValidationConfig{Severity: map[string]string{"no-such-rule": "off"}}, nil); err == nil {
+		t.Error("Expected error for unknown rule in severity overrides")
+	}
+	if _, err := NewValidatorFromConfig(&config.ValidationConfig{Severity: map[string]string{"unique-ids": "fatal"}}, nil); err == nil {
+		t.Error("Expected error for invalid severity")
+	}
+}
+
+func TestNewScorerFromConfig(t *testing.T) {
+	program := parseConfigTestProgram(t)
+
+	scorer, err := NewScorerFromConfig(&config.ValidationConfig{Severity: map[string]string{"orphan-detection": "off"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	card := scorer.CalculateScore(program)
+	for _, d := range card.Deductions {
+		if d.Rule == "Orphan Element" {
+			t.Errorf("Orphan rule is off, but got deduction: %s", d.Message)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// RuleDocsIndex is the name of the page linking the rule pages written by WriteRuleDocs.
+const RuleDocsIndex = "README.md"
+
+// WriteRuleDoc writes the markdown documentation page of a rule.
+func WriteRuleDoc(w io.Writer, info RuleInfo) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", info.ID)
+	fmt.Fprintf(&b, "%s\n\n", info.Description)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Name | %s |\n", info.Name())
+	fmt.Fprintf(&b, "| Default severity | %s |\n", strings.ToLower(string(info.Severity)))
+	enabled := "no (enable with `sruja lint --enable " + info.ID + "`)"
+	if info.Default {
+		enabled = "yes"
+	}
+	fmt.Fprintf(&b, "| Enabled by default | %s |\n", enabled)
+
+	if len(info.Codes) > 0 {
+		b.WriteString("\n## Diagnostics\n\n")
+		for _, code := range info.Codes {
+			if title := diagnostics.CodeTitle(code); title != code {
+				fmt.Fprintf(&b, "- `%s`: %s\n", code, title)
+			} else {
+				fmt.Fprintf(&b, "- `%s`\n", code)
+			}
+		}
+	}
+
+	severity := "error"
+	if info.Severity == diagnostics.SeverityError {
+		severity = "warning"
+	}
+	b.WriteString("\n## Configuration\n\n")
+	if !info.Default {
+		fmt.Fprintf(&b, "The rule does not run unless it is enabled. Enable it for a single run with `sruja lint --enable %s`, ", info.ID)
+		b.WriteString("or for the project by listing it under `\"rules\"` in `sruja.config.json`. ")
+		b.WriteString("The list replaces the default rule set, so keep the default rules in it:\n\n")
+		b.WriteString("```json\n{\n  \"validation\": {\n    \"rules\": [\n")
+		for _, id := range append(append([]string(nil), DefaultRuleIDs...), info.ID) {
+			fmt.Fprintf(&b, "      %q,\n", id)
+		}
+		b.Truncate(b.Len() - 2)
+		b.WriteString("\n    ]\n  }\n}\n```\n\n")
+		b.WriteString("Once the rule is enabled, change its severity, or turn it off with `\"off\"`:\n\n")
+	} else {
+		b.WriteString("Change the severity of the rule, or turn it off with `\"off\"`, in `sruja.config.json`:\n\n")
+	}
+	b.WriteString("```json\n")
+	fmt.Fprintf(&b, "{\n  \"validation\": {\n    \"severity\": { %q: %q }\n  }\n}\n", info.ID, severity)
+	b.WriteString("```\n\n")
+	fmt.Fprintf(&b, "Silence a single diagnostic with a `// sruja:ignore <code>` comment on the line before the element or relation.\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteRuleDocs writes one markdown page per registered rule into dir, named
+// after the rule ID, and an index page linking them. It returns the paths written.
+func WriteRuleDocs(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	rules := Rules()
+	paths := make([]string, 0, len(rules)+1)
+
+	var index bytes.Buffer
+	index.WriteString("# Validation Rules\n\n")
+	index.WriteString("| Rule | Default severity | Enabled by default | Description |\n|---|---|---|---|\n")
+	for _, info := range rules {
+		var page bytes.Buffer
+		if err := WriteRuleDoc(&page, info); err != nil {
+			return paths, err
+		}
+		path := filepath.Join(dir, info.ID+".md")
+		if err := os.WriteFile(path, page.Bytes(), 0o644); err != nil { //nolint:gosec // documentation is meant to be read
+			return paths, err
+		}
+		paths = append(paths, path)
+
+		enabled := "no"
+		if info.Default {
+			enabled = "yes"
+		}
+		fmt.Fprintf(&index, "| [%s](%s.md) | %s | %s | %s |\n", info.ID, info.ID, strings.ToLower(string(info.Severity)), enabled, info.Description)
+	}
+
+	path := filepath.Join(dir, RuleDocsIndex)
+	if err := os.WriteFile(path, index.Bytes(), 0o644); err != nil { //nolint:gosec // documentation is meant to be read
+		return paths, err
+	}
+	return append(paths, path), nil
+}
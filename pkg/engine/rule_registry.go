@@ -0,0 +1,224 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// RuleInfo describes a built-in rule in the rule registry.
+type RuleInfo struct {
+	// ID is the stable identifier used in sruja.config.json and on the command line.
+	ID string
+	// Description explains what the rule checks and why.
+	Description string
+	// Severity is the severity of the rule's diagnostics unless the config overrides it.
+	Severity diagnostics.Severity
+	// Codes lists the diagnostic codes the rule reports.
+	Codes []string
+	// Default is set for rules that run unless the config selects other rules.
+	Default bool
+
+	factory func() Rule
+}
+
+// Name returns the human-readable name of the rule, as reported in diagnostics.
+func (info RuleInfo) Name() string {
+	return info.factory().Name()
+}
+
+// New creates the rule.
+func (info RuleInfo) New() Rule {
+	return info.factory()
+}
+
+// ruleRegistry lists the built-in rules. Default rules come first, in the
+// order RegisterDefaultRules registers them.
+var ruleRegistry = []RuleInfo{
+	{
+		ID:          "unique-ids",
+		Description: "Element IDs must be unique within their scope, so that every reference resolves to exactly one element.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeDuplicateIdentifier},
+		Default:     true,
+		factory:     func() Rule { return &UniqueIDRule{} },
+	},
+	{
+		ID:          "valid-references",
+		Description: "Relations must connect elements that are defined in the model or in an imported module.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeReferenceNotFound},
+		Default:     true,
+		factory:     func() Rule { return &ValidReferenceRule{} },
+	},
+	{
+		ID:          "cycle-detection",
+		Description: "Reports circular dependencies between elements. Cycles are valid in many systems (callbacks, events), so they are informational.",
+		Severity:    diagnostics.SeverityInfo,
+		Codes:       []string{diagnostics.CodeCycleDetected},
+		Default:     true,
+		factory:     func() Rule { return &CycleDetectionRule{} },
+	},
+	{
+		ID:          "orphan-detection",
+		Description: "Elements should take part in at least one relation; an element nothing depends on and that depends on nothing is often left over.",
+		Severity:    diagnostics.SeverityWarning,
+		Codes:       []string{diagnostics.CodeOrphanElement},
+		Default:     true,
+		factory:     func() Rule { return &OrphanDetectionRule{} },
+	},
+	{
+		ID:          "simplicity",
+		Description: "Suggests simpler modeling alternatives when the chosen perspective does not fit the model.",
+		Severity:    diagnostics.SeverityInfo,
+		Default:     true,
+		factory:     func() Rule { return &SimplicityRule{} },
+	},
+	{
+		ID:          "layer-violation",
+		Description: "Dependencies must point down the layer model (for example web -> api -> data). Layers come from a layers block, then from validation.layers in sruja.config.json.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeLayerViolation},
+		Default:     true,
+		factory:     func() Rule { return &LayerViolationRule{} },
+	},
+	{
+		ID:          "scenario-references",
+		Description: "Scenario and flow steps must reference defined elements by their fully qualified names.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeReferenceNotFound, diagnostics.CodeValidationRuleError},
+		Default:     true,
+		factory:     func() Rule { return &ScenarioFQNRule{} },
+	},
+	{
+		ID:          "database-isolation",
+		Description: "A database should be owned by one service; integrating services through a shared database couples them. Mark intentionally shared databases with metadata { shared \"true\" }.",
+		Severity:    diagnostics.SeverityWarning,
+		Codes:       []string{diagnostics.CodeBestPractice},
+		Default:     true,
+		factory:     func() Rule { return &DatabaseIsolationRule{} },
+	},
+	{
+		ID:          "public-interface-documentation",
+		Description: "Systems and containers used directly by people must have a description and a technology.",
+		Severity:    diagnostics.SeverityWarning,
+		Codes:       []string{diagnostics.CodeBestPractice},
+		Default:     true,
+		factory:     func() Rule { return &PublicInterfaceDocumentationRule{} },
+	},
+	{
+		ID:          "slo-validation",
+		Description: "SLO blocks must define at least one objective, with percentages, durations and time windows in the expected form.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeValidationRuleError},
+		Default:     true,
+		factory:     func() Rule { return &SLOValidationRule{} },
+	},
+	{
+		ID:          "properties-validation",
+		Description: "Well-known properties (capacity, observability, compliance and cost) must have values of the expected form.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeInvalidProperty},
+		Default:     true,
+		factory:     func() Rule { return &PropertiesValidationRule{} },
+	},
+	{
+		ID:          "governance-validation",
+		Description: "Requirements, ADRs, policies and scenarios must have unique IDs within their kind.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeDuplicateIdentifier},
+		Default:     true,
+		factory:     func() Rule { return &GovernanceValidationRule{} },
+	},
+	{
+		ID:          "custom-rules",
+		Description: "Evaluates the rule declarations of the model itself. Each declared rule chooses its own severity and code.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeCustomRule, diagnostics.CodeInvalidCustomRule},
+		Default:     true,
+		factory:     func() Rule { return &CustomRulesRule{} },
+	},
+	{
+		ID:          "completeness",
+		Description: "Suggests descriptions and technologies for elements that lack them, and content for empty systems and containers.",
+		Severity:    diagnostics.SeverityInfo,
+		Codes:       []string{"SUGGESTION_MISSING_DESC", "SUGGESTION_MISSING_TECH", "SUGGESTION_EMPTY_SYSTEM", "SUGGESTION_EMPTY_CONTAINER"},
+		factory:     func() Rule { return &CompletenessRule{} },
+	},
+	{
+		ID:          "external-dependency",
+		Description: "Child elements must not depend on their parents: following the C4 model, dependencies point to other systems and containers.",
+		Severity:    diagnostics.SeverityError,
+		Codes:       []string{diagnostics.CodeValidationRuleError},
+		factory:     func() Rule { return &ExternalDependencyRule{} },
+	},
+	{
+		ID:          "slo-enforcement",
+		Description: "Suggests SLO blocks when requirements ask for an SLA, availability, uptime or latency.",
+		Severity:    diagnostics.SeverityInfo,
+		factory:     func() Rule { return &SLOEnforcementRule{} },
+	},
+	{
+		ID:          "relation-tags",
+		Description: "Relation tags must be built-in verbs (reads, writes, calls, ...) or relationship kinds declared in the specification, and relations of a declared kind must connect the element kinds it allows.",
+		Severity:    diagnostics.SeverityWarning,
+		Codes:       []string{diagnostics.CodeValidationRuleError},
+		factory:     func() Rule { return &RelationTagRule{} },
+	},
+}
+
+// ruleIndex maps rule IDs to their position in ruleRegistry.
+var ruleIndex = func() map[string]int {
+	index := make(map[string]int, len(ruleRegistry))
+	for i, info := range ruleRegistry {
+		index[info.ID] = i
+	}
+	return index
+}()
+
+// DefaultRuleIDs is the rule set registered by RegisterDefaultRules, in registration order.
+var DefaultRuleIDs = func() []string {
+	var ids []string
+	for _, info := range ruleRegistry {
+		if info.Default {
+			ids = append(ids, info.ID)
+		}
+	}
+	return ids
+}()
+
+// Rules returns the rule registry, sorted by rule ID.
+func Rules() []RuleInfo {
+	rules := make([]RuleInfo, len(ruleRegistry))
+	copy(rules, ruleRegistry)
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// LookupRule returns the registry entry of a rule ID.
+func LookupRule(id string) (RuleInfo, bool) {
+	i, ok := ruleIndex[id]
+	if !ok {
+		return RuleInfo{}, false
+	}
+	return ruleRegistry[i], true
+}
+
+// RuleIDs returns all known rule IDs in sorted order.
+func RuleIDs() []string {
+	ids := make([]string, 0, len(ruleRegistry))
+	for _, info := range ruleRegistry {
+		ids = append(ids, info.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// NewRule creates a rule by its stable ID.
+func NewRule(id string) (Rule, bool) {
+	info, ok := LookupRule(id)
+	if !ok {
+		return nil, false
+	}
+	return info.New(), true
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleRegistry(t *testing.T) {
+	names := make(map[string]string)
+	for _, info := range Rules() {
+		assert.NotEmpty(t, info.Description, "rule %s has no description", info.ID)
+		assert.NotEmpty(t, info.Severity, "rule %s has no default severity", info.ID)
+		_, scored := scoredRules[info.ID]
+		assert.True(t, scored, "rule %s has no scoring", info.ID)
+		if other, ok := names[info.Name()]; ok {
+			t.Errorf("rules %s and %s have the same name %q", other, info.ID, info.Name())
+		}
+		names[info.Name()] = info.ID
+	}
+
+	for _, id := range []string{"completeness", "external-dependency", "slo-enforcement", "relation-tags"} {
+		info, ok := LookupRule(id)
+		require.True(t, ok, "rule %s is not registered", id)
+		assert.False(t, info.Default, "rule %s should be opt-in", id)
+		rule, ok := NewRule(id)
+		require.True(t, ok)
+		assert.Equal(t, info.Name(), rule.Name())
+	}
+	assert.NotContains(t, DefaultRuleIDs, "relation-tags")
+	assert.Equal(t, "unique-ids", DefaultRuleIDs[0], "default rules keep their registration order")
+
+	_, ok := LookupRule("no-such-rule")
+	assert.False(t, ok)
+}
+
+func TestWriteRuleDocs(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := WriteRuleDocs(dir)
+	require.NoError(t, err)
+	assert.Len(t, paths, len(ruleRegistry)+1)
+
+	page, err := os.ReadFile(filepath.Join(dir, "relation-tags.md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(page), "# relation-tags\n"))
+	assert.Contains(t, string(page), "sruja lint --enable relation-tags")
+	assert.Contains(t, string(page), "`E303`")
+	assert.Contains(t, string(page), "\"rules\": [\n      \"unique-ids\",")
+	assert.Contains(t, string(page), "\"custom-rules\",\n      \"relation-tags\"\n    ]")
+
+	page, err = os.ReadFile(filepath.Join(dir, "unique-ids.md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(page), "\"rules\"")
+
+	index, err := os.ReadFile(filepath.Join(dir, RuleDocsIndex))
+	require.NoError(t, err)
+	for _, info := range ruleRegistry {
+		assert.Contains(t, string(index), "("+info.ID+".md)")
+	}
+}
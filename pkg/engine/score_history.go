@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ScoreHistoryFileName is the default name of a score history file, in the
+// directory of the architecture it was recorded for.
+const ScoreHistoryFileName = "sruja.score-history.json"
+
+// scoreHistoryVersion is the version of the score history file format.
+const scoreHistoryVersion = 1
+
+// ScoreHistory is a series of scores recorded over time, so that the health
+// of an architecture can be plotted across commits.
+type ScoreHistory struct {
+	Version int                 `json:"version"`
+	Entries []ScoreHistoryEntry `json:"entries"`
+}
+
+// ScoreHistoryEntry is one recorded score.
+type ScoreHistoryEntry struct {
+	Time       time.Time      `json:"time"`
+	Commit     string         `json:"commit,omitempty"`
+	Profile    string         `json:"profile"`
+	Score      int            `json:"score"`
+	Grade      string         `json:"grade"`
+	Categories CategoryScores `json:"categories"`
+	Deductions int            `json:"deductions"`
+}
+
+// NewScoreHistoryEntry records a score card taken at t.
+func NewScoreHistoryEntry(card ScoreCard, profile string, t time.Time) ScoreHistoryEntry {
+	return ScoreHistoryEntry{
+		Time:       t.UTC(),
+		Profile:    profile,
+		Score:      card.Score,
+		Grade:      card.Grade,
+		Categories: card.Categories,
+		Deductions: len(card.Deductions),
+	}
+}
+
+// LoadScoreHistory reads a score history file. A file that does not exist
+// yet is an empty history.
+func LoadScoreHistory(path string) (*ScoreHistory, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ScoreHistory{Version: scoreHistoryVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read score history: %w", err)
+	}
+	var h ScoreHistory
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parse score history %s: %w", path, err)
+	}
+	if h.Version != scoreHistoryVersion {
+		return nil, fmt.Errorf("score history %s has version %d, expected %d", path, h.Version, scoreHistoryVersion)
+	}
+	return &h, nil
+}
+
+// Last returns the most recent entry, or nil when the history is empty.
+func (h *ScoreHistory) Last() *ScoreHistoryEntry {
+	if len(h.Entries) == 0 {
+		return nil
+	}
+	return &h.Entries[len(h.Entries)-1]
+}
+
+// Append adds an entry to the history.
+func (h *ScoreHistory) Append(e ScoreHistoryEntry) {
+	h.Entries = append(h.Entries, e)
+}
+
+// Save writes the score history to a file.
+func (h *ScoreHistory) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644) //nolint:gosec // checked in next to the architecture
+}
@@ -19,7 +19,7 @@ type CategoryScores struct {
 
 // Scoring Constants
 const (
-	// Category Weights
+	// Category Weights (of the default scoring profile)
 	WeightStructural      = 0.40
 	WeightDocumentation   = 0.20
 	WeightTraceability    = 0.15
@@ -27,11 +27,17 @@ const (
 	WeightStandardization = 0.10
 
 	// Penalties (Structural)
-	PenaltyCycle             = 30
-	PenaltyLayerViolation    = 15
-	PenaltyOrphanElement     = 10
-	PenaltyInvalidReference  = 20
-	PenaltyGenericValidation = 10
+	PenaltyCycle               = 30
+	PenaltyLayerViolation      = 15
+	PenaltyOrphanElement       = 10
+	PenaltyInvalidReference    = 20
+	PenaltyGenericValidation   = 10
+	PenaltyDuplicateIdentifier = 20
+
+	// Penalties (Best practices & Standardization)
+	PenaltyBestPractice    = 5
+	PenaltyInvalidProperty = 5
+	PenaltyGovernance      = 10
 
 	// Penalties (Documentation & Traceability)
 	PenaltyMissingDescription  = 5
@@ -44,7 +50,7 @@ const (
 	ThresholdCriticalStructural = 50
 	MultiplierCritical          = 0.8
 
-	// Grade Thresholds
+	// Grade Thresholds (of the default scoring profile)
 	GradeThresholdA = 90
 	GradeThresholdB = 80
 	GradeThresholdC = 70
@@ -72,19 +78,21 @@ type Deduction struct {
 // Scorer calculates the architecture score.
 type Scorer struct {
 	validator *Validator
+	profile   *ScoringProfile
 }
 
-// NewScorer creates a new Scorer.
+// NewScorer creates a Scorer that runs the default rule set and applies the
+// default scoring profile.
 func NewScorer() *Scorer {
 	v := NewValidator()
-	v.RegisterRule(&CycleDetectionRule{})
-	v.RegisterRule(&OrphanDetectionRule{})
-	v.RegisterRule(&LayerViolationRule{})
-	v.RegisterRule(&ValidReferenceRule{})
+	v.RegisterDefaultRules()
 
-	return &Scorer{
-		validator: v,
-	}
+	return newScorer(v, nil)
+}
+
+// Profile returns the scoring profile the scorer applies.
+func (s *Scorer) Profile() *ScoringProfile {
+	return s.profile
 }
 
 // clampScore ensures a score is between 0 and 100.
@@ -150,49 +158,12 @@ func (s *Scorer) CalculateScore(program *language.Program) ScoreCard {
 
 	deductions := make([]Deduction, 0, 32)
 
-	// 1. Structural Integrity (40%) - Validation Rules
+	// 1. Validation rules, deducted from the category of each rule
 	diags := s.validator.Validate(program)
 	for i := range diags {
-		d := &diags[i]
-		points := 0
-		rule := ""
-		severity := d.Severity
-
-		switch d.Code {
-		case diagnostics.CodeCycleDetected:
-			points = PenaltyCycle
-			rule = "Circular Dependency"
-			severity = diagnostics.SeverityError
-		case diagnostics.CodeLayerViolation:
-			points = PenaltyLayerViolation
-			rule = "Layer Violation"
-			severity = diagnostics.SeverityWarning
-		case diagnostics.CodeOrphanElement:
-			points = PenaltyOrphanElement
-			rule = "Orphan Element"
-			severity = diagnostics.SeverityWarning
-		case diagnostics.CodeReferenceNotFound:
-			points = PenaltyInvalidReference
-			rule = "Invalid Reference"
-			severity = diagnostics.SeverityError
-		default:
-			if d.Severity == diagnostics.SeverityError {
-				points = PenaltyGenericValidation
-				rule = "Validation Error"
-			}
-		}
-
-		if points > 0 {
-			target := formatTarget(d.Location.File, d.Location.Line)
-			deductions = append(deductions, Deduction{
-				Rule:     rule,
-				Points:   points,
-				Message:  d.Message,
-				Target:   target,
-				Severity: severity,
-				Category: "Structural",
-			})
-			scores.Structural -= points
+		if d, ok := s.deductionFor(&diags[i]); ok {
+			deductions = append(deductions, d)
+			scores.deduct(d.Category, d.Points)
 		}
 	}
 
@@ -214,12 +185,12 @@ func (s *Scorer) CalculateScore(program *language.Program) ScoreCard {
 	scores.Standardization = clampScore(scores.Standardization)
 
 	// Calculate Final Weighted Score
-	// Structural (40%), Doc (20%), Trace (15%), Complexity (15%), Standard (10%)
-	finalScore := float64(scores.Structural)*WeightStructural +
-		float64(scores.Documentation)*WeightDocumentation +
-		float64(scores.Traceability)*WeightTraceability +
-		float64(scores.Complexity)*WeightComplexity +
-		float64(scores.Standardization)*WeightStandardization
+	w := s.profile.Weights
+	finalScore := float64(scores.Structural)*w.Structural +
+		float64(scores.Documentation)*w.Documentation +
+		float64(scores.Traceability)*w.Traceability +
+		float64(scores.Complexity)*w.Complexity +
+		float64(scores.Standardization)*w.Standardization
 
 	// Apply critical multiplier if structural is very low
 	if scores.Structural < ThresholdCriticalStructural {
@@ -235,7 +206,7 @@ func (s *Scorer) CalculateScore(program *language.Program) ScoreCard {
 	}
 
 	// Calculate Grade
-	grade := calculateGrade(score)
+	grade := s.profile.Grades.Grade(score)
 
 	return ScoreCard{
 		Score:      score,
@@ -245,19 +216,49 @@ func (s *Scorer) CalculateScore(program *language.Program) ScoreCard {
 	}
 }
 
-// calculateGrade returns the letter grade for a score.
-func calculateGrade(score int) string {
-	switch {
-	case score >= GradeThresholdA:
-		return "A"
-	case score >= GradeThresholdB:
-		return "B"
-	case score >= GradeThresholdC:
-		return "C"
-	case score >= GradeThresholdD:
-		return "D"
-	default:
-		return "F"
+// deductionFor returns the deduction for a diagnostic, or false when the
+// scoring profile does not penalize it.
+func (s *Scorer) deductionFor(d *diagnostics.Diagnostic) (Deduction, bool) {
+	id := ruleIDsByName()[d.Rule]
+	rs, ok := scoredRules[id]
+	if !ok {
+		if d.Severity != diagnostics.SeverityError {
+			return Deduction{}, false
+		}
+		id = PenaltyKeyValidationError
+		rs = ruleScoring{label: "Validation Error", category: CategoryStructural}
+	}
+	points := s.profile.Penalties[id]
+	if points <= 0 {
+		return Deduction{}, false
+	}
+	severity := rs.severity
+	if severity == "" {
+		severity = d.Severity
+	}
+	return Deduction{
+		Rule:     rs.label,
+		Points:   points,
+		Message:  d.Message,
+		Target:   formatTarget(d.Location.File, d.Location.Line),
+		Severity: severity,
+		Category: rs.category,
+	}, true
+}
+
+// deduct subtracts points from a category.
+func (c *CategoryScores) deduct(category string, points int) {
+	switch category {
+	case CategoryStructural:
+		c.Structural -= points
+	case CategoryDocumentation:
+		c.Documentation -= points
+	case CategoryTraceability:
+		c.Traceability -= points
+	case CategoryComplexity:
+		c.Complexity -= points
+	case CategoryStandardization:
+		c.Standardization -= points
 	}
 }
 
@@ -320,37 +321,37 @@ func (s *Scorer) checkDocumentation(model *language.Model, scores *CategoryScore
 			}
 		}
 
-		if !hasDescription {
+		if points := s.profile.Penalties[PenaltyKeyMissingDescription]; !hasDescription && points > 0 {
 			*deductions = append(*deductions, Deduction{
 				Rule:     "Missing Description",
-				Points:   PenaltyMissingDescription,
+				Points:   points,
 				Message:  formatMissingDescription(elementID),
 				Target:   elementID,
 				Severity: diagnostics.SeverityInfo,
-				Category: "Documentation",
+				Category: CategoryDocumentation,
 			})
-			scores.Documentation -= PenaltyMissingDescription
+			scores.Documentation -= points
 		}
 
 		// Only check technology for containers and components
 		kind := elem.GetKind()
 		if kind == "container" || kind == "component" {
-			if !hasTechnology {
+			if points := s.profile.Penalties[PenaltyKeyMissingTechnology]; !hasTechnology && points > 0 {
 				*deductions = append(*deductions, Deduction{
 					Rule:     "Missing Technology",
-					Points:   PenaltyMissingTechnology,
+					Points:   points,
 					Message:  formatMissingTechnology(elementID),
 					Target:   elementID,
 					Severity: diagnostics.SeverityInfo,
-					Category: "Documentation",
+					Category: CategoryDocumentation,
 				})
-				scores.Documentation -= PenaltyMissingTechnology
+				scores.Documentation -= points
 			}
 		}
 
 		// Standardization Checks
 		if !hasMetadata {
-			scores.Standardization -= PenaltyMissingMetadata
+			scores.Standardization -= s.profile.Penalties[PenaltyKeyMissingMetadata]
 		}
 
 		// Push children
@@ -386,15 +387,16 @@ func (s *Scorer) checkTraceability(model *language.Model, scores *CategoryScores
 		}
 	}
 
-	if totalElements > 0 && taggedCount < totalElements/2 { // Using simpler logic for now, could use ThresholdTraceabilityRatio
-		scores.Traceability -= PenaltyLowTraceability // Penalty for low requirement coverage
+	points := s.profile.Penalties[PenaltyKeyLowTraceability]
+	if totalElements > 0 && taggedCount < totalElements/2 && points > 0 { // Using simpler logic for now, could use ThresholdTraceabilityRatio
+		scores.Traceability -= points // Penalty for low requirement coverage
 		*deductions = append(*deductions, Deduction{
 			Rule:     "Low Traceability",
-			Points:   PenaltyLowTraceability,
+			Points:   points,
 			Message:  "Less than 50% of elements are mapped to requirements",
 			Target:   "requirements",
 			Severity: diagnostics.SeverityWarning,
-			Category: "Traceability",
+			Category: CategoryTraceability,
 		})
 	}
 }
@@ -0,0 +1,284 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// DefaultScoringProfileName is the name of the built-in scoring profile.
+const DefaultScoringProfileName = "default"
+
+// Score categories, as reported in deductions.
+const (
+	CategoryStructural      = "Structural"
+	CategoryDocumentation   = "Documentation"
+	CategoryTraceability    = "Traceability"
+	CategoryComplexity      = "Complexity"
+	CategoryStandardization = "Standardization"
+)
+
+// Penalty keys of the scorer's own checks, next to the rule IDs.
+const (
+	PenaltyKeyMissingDescription = "missing-description"
+	PenaltyKeyMissingTechnology  = "missing-technology"
+	PenaltyKeyMissingMetadata    = "missing-metadata"
+	PenaltyKeyLowTraceability    = "low-traceability"
+	// PenaltyKeyValidationError is the penalty of errors reported by rules that
+	// have no scoring of their own, such as rules loaded from rule files.
+	PenaltyKeyValidationError = "validation-error"
+)
+
+// CategoryWeights is the share of each category in the final score.
+type CategoryWeights struct {
+	Structural      float64
+	Documentation   float64
+	Traceability    float64
+	Complexity      float64
+	Standardization float64
+}
+
+// GradeThresholds is the minimum score of each grade; lower scores get an F.
+type GradeThresholds struct {
+	A, B, C, D int
+}
+
+// Grade returns the letter grade for a score.
+func (g GradeThresholds) Grade(score int) string {
+	switch {
+	case score >= g.A:
+		return "A"
+	case score >= g.B:
+		return "B"
+	case score >= g.C:
+		return "C"
+	case score >= g.D:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// ScoringProfile is the set of weights, penalties and grade thresholds the
+// Scorer applies.
+type ScoringProfile struct {
+	Name    string
+	Weights CategoryWeights
+	// Penalties holds the points deducted per finding, keyed by rule ID or
+	// PenaltyKey* check. A penalty of 0 turns the deduction off.
+	Penalties map[string]int
+	Grades    GradeThresholds
+}
+
+// ruleScoring describes how the diagnostics of a rule are scored.
+type ruleScoring struct {
+	label    string
+	category string
+	penalty  int
+	// severity of the deduction; empty keeps the diagnostic's severity.
+	severity diagnostics.Severity
+}
+
+// scoredRules maps rule IDs to their scoring. Every diagnostic of a scored
+// rule is a deduction; other rules only deduct for errors.
+var scoredRules = map[string]ruleScoring{
+	"unique-ids":                     {label: "Duplicate Identifier", category: CategoryStructural, penalty: PenaltyDuplicateIdentifier},
+	"valid-references":               {label: "Invalid Reference", category: CategoryStructural, penalty: PenaltyInvalidReference, severity: diagnostics.SeverityError},
+	"cycle-detection":                {label: "Circular Dependency", category: CategoryStructural, penalty: PenaltyCycle, severity: diagnostics.SeverityError},
+	"orphan-detection":               {label: "Orphan Element", category: CategoryStructural, penalty: PenaltyOrphanElement, severity: diagnostics.SeverityWarning},
+	"layer-violation":                {label: "Layer Violation", category: CategoryStructural, penalty: PenaltyLayerViolation, severity: diagnostics.SeverityWarning},
+	"simplicity":                     {label: "Simplicity", category: CategoryComplexity, penalty: PenaltyGenericValidation},
+	"database-isolation":             {label: "Shared Database", category: CategoryComplexity, penalty: PenaltyBestPractice},
+	"scenario-references":            {label: "Invalid Scenario Reference", category: CategoryTraceability, penalty: PenaltyGenericValidation},
+	"public-interface-documentation": {label: "Undocumented Interface", category: CategoryDocumentation, penalty: PenaltyBestPractice},
+	"slo-validation":                 {label: "Invalid SLO", category: CategoryStandardization, penalty: PenaltyInvalidProperty},
+	"properties-validation":          {label: "Invalid Property", category: CategoryStandardization, penalty: PenaltyInvalidProperty},
+	"governance-validation":          {label: "Governance Violation", category: CategoryStandardization, penalty: PenaltyGovernance},
+	// Violations of declared rules carry the declared rule's name and are
+	// scored as validation errors; this covers declarations that do not compile.
+	"custom-rules":        {label: "Custom Rule", category: CategoryStructural, penalty: PenaltyGenericValidation},
+	"external-dependency": {label: "Dependency on Parent", category: CategoryStructural, penalty: PenaltyLayerViolation},
+	"relation-tags":       {label: "Unknown Relation Tag", category: CategoryStandardization, penalty: PenaltyInvalidProperty},
+	"slo-enforcement":     {label: "Missing SLO", category: CategoryStandardization, penalty: PenaltyBestPractice},
+	// Completeness overlaps the scorer's own documentation checks, so it only
+	// deducts points when a profile sets a penalty for it.
+	"completeness": {label: "Incomplete Element", category: CategoryDocumentation},
+}
+
+// ruleIDsByName maps rule names, as recorded in Diagnostic.Rule, to rule IDs.
+var ruleIDsByName = sync.OnceValue(func() map[string]string {
+	ids := make(map[string]string, len(ruleRegistry))
+	for _, info := range ruleRegistry {
+		ids[info.Name()] = info.ID
+	}
+	return ids
+})
+
+// DefaultScoringProfile returns the built-in scoring profile.
+func DefaultScoringProfile() *ScoringProfile {
+	p := &ScoringProfile{
+		Name: DefaultScoringProfileName,
+		Weights: CategoryWeights{
+			Structural:      WeightStructural,
+			Documentation:   WeightDocumentation,
+			Traceability:    WeightTraceability,
+			Complexity:      WeightComplexity,
+			Standardization: WeightStandardization,
+		},
+		Penalties: map[string]int{
+			PenaltyKeyMissingDescription: PenaltyMissingDescription,
+			PenaltyKeyMissingTechnology:  PenaltyMissingTechnology,
+			PenaltyKeyMissingMetadata:    PenaltyMissingMetadata,
+			PenaltyKeyLowTraceability:    PenaltyLowTraceability,
+			PenaltyKeyValidationError:    PenaltyGenericValidation,
+		},
+		Grades: GradeThresholds{A: GradeThresholdA, B: GradeThresholdB, C: GradeThresholdC, D: GradeThresholdD},
+	}
+	for id, rs := range scoredRules {
+		p.Penalties[id] = rs.penalty
+	}
+	return p
+}
+
+// ScoringProfileFromConfig returns the scoring profile called name, or the one
+// selected by cfg when name is empty. Profiles declared in cfg override the
+// settings of the built-in profile they leave out. Weights are relative: they
+// are scaled to add up to 1.
+//
+// Example:
+//
+//	cfg, _ := config.LoadConfigFrom(".")
+//	profile, err := ScoringProfileFromConfig(cfg.Scoring, "")
+func ScoringProfileFromConfig(cfg *config.ScoringConfig, name string) (*ScoringProfile, error) {
+	if name == "" && cfg != nil {
+		name = cfg.Profile
+	}
+	if name == "" {
+		name = DefaultScoringProfileName
+	}
+
+	var overrides *config.ScoringProfileConfig
+	if cfg != nil {
+		overrides = cfg.Profiles[name]
+	}
+	if overrides == nil && name != DefaultScoringProfileName {
+		return nil, fmt.Errorf("unknown scoring profile %q (known profiles: %s)", name, strings.Join(scoringProfileNames(cfg), ", "))
+	}
+
+	p := DefaultScoringProfile()
+	p.Name = name
+	if overrides == nil {
+		return p, nil
+	}
+	if err := p.applyWeights(overrides.Weights); err != nil {
+		return nil, fmt.Errorf("scoring profile %q: %w", name, err)
+	}
+	if err := p.applyPenalties(overrides.Penalties); err != nil {
+		return nil, fmt.Errorf("scoring profile %q: %w", name, err)
+	}
+	if err := p.applyGrades(overrides.Grades); err != nil {
+		return nil, fmt.Errorf("scoring profile %q: %w", name, err)
+	}
+	return p, nil
+}
+
+// scoringProfileNames returns the names of the profiles cfg can select, sorted.
+func scoringProfileNames(cfg *config.ScoringConfig) []string {
+	names := []string{DefaultScoringProfileName}
+	if cfg != nil {
+		for name := range cfg.Profiles {
+			if name != DefaultScoringProfileName {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+func (p *ScoringProfile) applyWeights(weights map[string]float64) error {
+	if len(weights) == 0 {
+		return nil
+	}
+	for category, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("weight of %q must be a non-negative number", category)
+		}
+		switch strings.ToLower(category) {
+		case "structural":
+			p.Weights.Structural = w
+		case "documentation":
+			p.Weights.Documentation = w
+		case "traceability":
+			p.Weights.Traceability = w
+		case "complexity":
+			p.Weights.Complexity = w
+		case "standardization":
+			p.Weights.Standardization = w
+		default:
+			return fmt.Errorf("unknown category %q in weights (expected structural, documentation, traceability, complexity or standardization)", category)
+		}
+	}
+
+	w := &p.Weights
+	total := w.Structural + w.Documentation + w.Traceability + w.Complexity + w.Standardization
+	if total == 0 {
+		return fmt.Errorf("weights must not all be 0")
+	}
+	if math.Abs(total-1) > 1e-9 {
+		w.Structural /= total
+		w.Documentation /= total
+		w.Traceability /= total
+		w.Complexity /= total
+		w.Standardization /= total
+	}
+	return nil
+}
+
+func (p *ScoringProfile) applyPenalties(penalties map[string]int) error {
+	for key, points := range penalties {
+		if _, ok := p.Penalties[key]; !ok {
+			known := make([]string, 0, len(p.Penalties))
+			for k := range p.Penalties {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("unknown penalty %q (known penalties: %s)", key, strings.Join(known, ", "))
+		}
+		if points < 0 {
+			return fmt.Errorf("penalty %q must not be negative", key)
+		}
+		p.Penalties[key] = points
+	}
+	return nil
+}
+
+func (p *ScoringProfile) applyGrades(grades map[string]int) error {
+	for grade, threshold := range grades {
+		if threshold < 0 || threshold > 100 {
+			return fmt.Errorf("grade %q threshold must be between 0 and 100", grade)
+		}
+		switch strings.ToUpper(grade) {
+		case "A":
+			p.Grades.A = threshold
+		case "B":
+			p.Grades.B = threshold
+		case "C":
+			p.Grades.C = threshold
+		case "D":
+			p.Grades.D = threshold
+		default:
+			return fmt.Errorf("unknown grade %q (expected A, B, C or D)", grade)
+		}
+	}
+	g := p.Grades
+	if g.A < g.B || g.B < g.C || g.C < g.D {
+		return fmt.Errorf("grade thresholds must not increase from A to D (got A=%d B=%d C=%d D=%d)", g.A, g.B, g.C, g.D)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoringProfileFromConfig(t *testing.T) {
+	cfg := &config.ScoringConfig{
+		Profile: "strict",
+		Profiles: map[string]*config.ScoringProfileConfig{
+			"strict": {
+				Weights:   map[string]float64{"structural": 2, "documentation": 1, "traceability": 1, "complexity": 0, "standardization": 0},
+				Penalties: map[string]int{"orphan-detection": 25, "missing-description": 0},
+				Grades:    map[string]int{"A": 95},
+			},
+		},
+	}
+
+	p, err := ScoringProfileFromConfig(cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, "strict", p.Name)
+	assert.InDelta(t, 0.5, p.Weights.Structural, 1e-9, "weights are scaled to add up to 1")
+	assert.InDelta(t, 0.25, p.Weights.Documentation, 1e-9)
+	assert.Equal(t, 25, p.Penalties["orphan-detection"])
+	assert.Equal(t, PenaltyCycle, p.Penalties["cycle-detection"], "penalties left out keep their defaults")
+	assert.Equal(t, GradeThresholds{A: 95, B: GradeThresholdB, C: GradeThresholdC, D: GradeThresholdD}, p.Grades)
+
+	p, err = ScoringProfileFromConfig(cfg, DefaultScoringProfileName)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultScoringProfile(), p)
+
+	_, err = ScoringProfileFromConfig(cfg, "lenient")
+	assert.ErrorContains(t, err, "known profiles: default, strict")
+}
+
+func TestScoringProfileFromConfig_Invalid(t *testing.T) {
+	tests := map[string]*config.ScoringProfileConfig{
+		"unknown category":    {Weights: map[string]float64{"speed": 1}},
+		"negative weight":     {Weights: map[string]float64{"structural": -1}},
+		"all weights zero":    {Weights: map[string]float64{"structural": 0, "documentation": 0, "traceability": 0, "complexity": 0, "standardization": 0}},
+		"unknown penalty":     {Penalties: map[string]int{"no-such-rule": 5}},
+		"negative penalty":    {Penalties: map[string]int{"cycle-detection": -5}},
+		"unknown grade":       {Grades: map[string]int{"E": 50}},
+		"increasing grades":   {Grades: map[string]int{"B": 95}},
+		"threshold above 100": {Grades: map[string]int{"A": 120}},
+	}
+	for name, profile := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.ScoringConfig{Profiles: map[string]*config.ScoringProfileConfig{"custom": profile}}
+			_, err := ScoringProfileFromConfig(cfg, "custom")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScorer_Profile(t *testing.T) {
+	program := parseConfigTestProgram(t)
+
+	base := NewScorer().CalculateScore(program)
+	require.True(t, hasDeduction(base, "Orphan Element"))
+
+	cfg := &config.ScoringConfig{Profiles: map[string]*config.ScoringProfileConfig{
+		"lenient": {
+			Penalties: map[string]int{"orphan-detection": 0, "missing-description": 0},
+			Grades:    map[string]int{"A": 50, "B": 40, "C": 30, "D": 20},
+		},
+	}}
+	profile, err := ScoringProfileFromConfig(cfg, "lenient")
+	require.NoError(t, err)
+	scorer, err := NewScorerFromConfig(nil, WithScoringProfile(profile))
+	require.NoError(t, err)
+	assert.Same(t, profile, scorer.Profile())
+
+	card := scorer.CalculateScore(program)
+	assert.False(t, hasDeduction(card, "Orphan Element"), "a penalty of 0 turns the deduction off")
+	assert.False(t, hasDeduction(card, "Missing Description"))
+	assert.Greater(t, card.Score, base.Score)
+	assert.Equal(t, "A", card.Grade)
+}
+
+func TestScorer_DefaultRulesDeductPerCategory(t *testing.T) {
+	program := parseConfigTestProgram(t)
+	program.Model.Items = append(program.Model.Items, program.Model.Items[1]) // duplicate A
+
+	card := NewScorer().CalculateScore(program)
+	var found bool
+	for _, d := range card.Deductions {
+		if d.Rule == "Duplicate Identifier" {
+			found = true
+			assert.Equal(t, CategoryStructural, d.Category)
+			assert.Equal(t, PenaltyDuplicateIdentifier, d.Points)
+		}
+	}
+	assert.True(t, found, "Expected the unique-ids rule to contribute a deduction, got %+v", card.Deductions)
+}
+
+func TestScorerWithOptions_DefaultRulesOnce(t *testing.T) {
+	program := parseConfigTestProgram(t)
+	program.Model.Items = append(program.Model.Items, program.Model.Items[1]) // duplicate A
+
+	base := NewScorerWithOptions().CalculateScore(program)
+	card := NewScorerWithOptions(WithValidatorOptions(WithDefaultRules())).CalculateScore(program)
+	assert.Equal(t, base.Score, card.Score)
+	assert.Equal(t, len(base.Deductions), len(card.Deductions))
+}
+
+func hasDeduction(card ScoreCard, rule string) bool {
+	for _, d := range card.Deductions {
+		if d.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// subject is an element or relation that diagnostics are reported on,
+// identified by its FQN (for relations, "Scope: From -> To").
+type subject struct {
+	fqn string
+	pos lexer.Position
+}
+
+// collectSubjects returns the elements and relations of a program in source order.
+func collectSubjects(program *language.Program) []subject {
+	if program == nil || program.Model == nil {
+		return nil
+	}
+	var subjects []subject
+	var walk func(scope string, elem *language.ElementDef)
+	walk = func(scope string, elem *language.ElementDef) {
+		id := elem.GetID()
+		if id == "" {
+			return
+		}
+		fqn := buildQualifiedID(scope, id)
+		subjects = append(subjects, subject{fqn: fqn, pos: elem.Pos})
+		body := elem.GetBody()
+		if body == nil {
+			return
+		}
+		for _, item := range body.Items {
+			if item.Element != nil {
+				walk(fqn, item.Element)
+			}
+			if item.Relation != nil {
+				subjects = append(subjects, subject{fqn: relationSubject(fqn, item.Relation), pos: item.Relation.Pos})
+			}
+		}
+	}
+	for _, item := range program.Model.Items {
+		if item.ElementDef != nil {
+			walk("", item.ElementDef)
+		}
+		if item.Relation != nil {
+			subjects = append(subjects, subject{fqn: relationSubject("", item.Relation), pos: item.Relation.Pos})
+		}
+	}
+	sort.SliceStable(subjects, func(i, j int) bool {
+		a, b := subjects[i].pos, subjects[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return subjects
+}
+
+func relationSubject(scope string, rel *language.Relation) string {
+	s := fmt.Sprintf("%s %s %s", rel.From.String(), rel.Arrow, rel.To.String())
+	if scope != "" {
+		s = scope + ": " + s
+	}
+	return s
+}
+
+// reportedOn reports whether a diagnostic is located on a subject.
+func reportedOn(d *diagnostics.Diagnostic, s *subject) bool {
+	if d.Location.Line != s.pos.Line {
+		return false
+	}
+	return d.Location.File == "" || s.pos.Filename == "" || d.Location.File == s.pos.Filename
+}
+
+// subjectOf returns the FQN of the element or relation a diagnostic is
+// reported on, or "" when it is not reported on one.
+func subjectOf(subjects []subject, d *diagnostics.Diagnostic) string {
+	for i := range subjects {
+		if reportedOn(d, &subjects[i]) {
+			return subjects[i].fqn
+		}
+	}
+	return ""
+}
+
+// ApplySuppressions removes the diagnostics silenced by the suppression
+// comments of a program. A suppression applies to the element or relation
+// that follows it in the same file. It returns the diagnostics kept and the
+// suppressions that silenced none, because they name codes no longer reported
+// or nothing follows them.
+func ApplySuppressions(program *language.Program, diags []diagnostics.Diagnostic) ([]diagnostics.Diagnostic, []*language.Suppression) {
+	if program == nil || len(program.Suppressions) == 0 {
+		return diags, nil
+	}
+	subjects := collectSubjects(program)
+
+	// Attach each suppression to the next subject in its file
+	targets := make([]*subject, len(program.Suppressions))
+	for i, sup := range program.Suppressions {
+		for j := range subjects {
+			s := &subjects[j]
+			if s.pos.Filename == sup.Pos.Filename && s.pos.Line > sup.Pos.Line {
+				targets[i] = s
+				break
+			}
+		}
+	}
+
+	used := make([]bool, len(program.Suppressions))
+	kept := diags[:0:0]
+	for i := range diags {
+		d := &diags[i]
+		suppressed := false
+		for j, sup := range program.Suppressions {
+			if targets[j] != nil && sup.Matches(d.Code) && reportedOn(d, targets[j]) {
+				used[j], suppressed = true, true
+			}
+		}
+		if !suppressed {
+			kept = append(kept, *d)
+		}
+	}
+
+	var unused []*language.Suppression
+	for i, sup := range program.Suppressions {
+		if !used[i] {
+			unused = append(unused, sup)
+		}
+	}
+	return kept, unused
+}
+
+// unusedSuppressionDiagnostics reports suppressions that silenced nothing.
+func unusedSuppressionDiagnostics(unused []*language.Suppression) []diagnostics.Diagnostic {
+	diags := make([]diagnostics.Diagnostic, 0, len(unused))
+	for _, sup := range unused {
+		what := "any diagnostic"
+		if len(sup.Codes) > 0 {
+			what = strings.Join(sup.Codes, ", ")
+		}
+		diags = append(diags, diagnostics.Diagnostic{
+			Code:     diagnostics.CodeUnusedSuppression,
+			Severity: diagnostics.SeverityWarning,
+			Message:  fmt.Sprintf("Suppression of %s is unused: nothing it applies to reports it", what),
+			Location: diagnostics.SourceLocation{File: sup.Pos.Filename, Line: sup.Pos.Line, Column: sup.Pos.Column},
+			Suggestions: []string{
+				"Remove the sruja:ignore comment, or move it onto the line before the element or relation it silences",
+			},
+		})
+	}
+	return diags
+}
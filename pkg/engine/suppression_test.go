@@ -0,0 +1,97 @@
+// pkg/engine/suppression_test.go
+package engine_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/engine"
+)
+
+const suppressedDSL = `system = kind "System"
+container = kind "Container"
+Shop = system "Shop" {
+	// sruja:ignore E205 reason="legacy cache"
+	Cache = container "Cache"
+	API = container "API"
+}
+Bank = system "Bank"
+// sruja:ignore E206
+Shop -> Bank "pays"
+`
+
+func TestValidator_Suppressions(t *testing.T) {
+	program := parse(t, suppressedDSL)
+	v := engine.NewValidatorWithOptions(engine.WithRules(&engine.OrphanDetectionRule{}))
+
+	diags := v.Validate(program)
+	for _, d := range diags {
+		if strings.Contains(d.Message, "'Cache'") {
+			t.Errorf("suppressed diagnostic reported: %s", d.Message)
+		}
+		if d.Code == diagnostics.CodeUnusedSuppression {
+			t.Errorf("unused suppressions reported without WithUnusedSuppressions: %s", d.Message)
+		}
+	}
+	if !containsMessage(diags, "'API'") {
+		t.Errorf("expected orphan API to be reported, got %v", diags)
+	}
+
+	v = engine.NewValidatorWithOptions(engine.WithRules(&engine.OrphanDetectionRule{}), engine.WithUnusedSuppressions())
+	var unused []diagnostics.Diagnostic
+	for _, d := range v.Validate(program) {
+		if d.Code == diagnostics.CodeUnusedSuppression {
+			unused = append(unused, d)
+		}
+	}
+	if len(unused) != 1 || unused[0].Location.Line != 9 || !strings.Contains(unused[0].Message, "E206") {
+		t.Errorf("expected the E206 suppression to be unused, got %v", unused)
+	}
+}
+
+func TestBaseline_StableAcrossLineMoves(t *testing.T) {
+	v := engine.NewValidatorWithOptions(engine.WithRules(&engine.OrphanDetectionRule{}))
+	before := parse(t, `system = kind "System"
+A = system "A"
+B = system "B"
+`)
+	baseline := engine.NewBaseline(before, v.Validate(before))
+	if len(baseline.Entries) != 2 || baseline.Entries[0].FQN != "A" || baseline.Entries[0].Code != diagnostics.CodeOrphanElement {
+		t.Fatalf("entries = %+v", baseline.Entries)
+	}
+
+	path := filepath.Join(t.TempDir(), engine.BaselineFileName)
+	if err := baseline.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := engine.LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A moved down, B removed and C added
+	after := parse(t, `system = kind "System"
+
+C = system "C"
+
+A = system "A"
+`)
+	fresh, fixed := loaded.Filter(after, v.Validate(after))
+	if len(fresh) != 1 || !strings.Contains(fresh[0].Message, "'C'") {
+		t.Errorf("fresh = %v", fresh)
+	}
+	if fixed != 1 {
+		t.Errorf("fixed = %d, want 1", fixed)
+	}
+}
+
+func containsMessage(diags []diagnostics.Diagnostic, s string) bool {
+	for _, d := range diags {
+		if strings.Contains(d.Message, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -59,29 +59,14 @@ func (v *Validator) RegisterRule(rule Rule) {
 	v.Rules = append(v.Rules, rule)
 }
 
-// RegisterDefaultRules registers the standard set of validation rules.
+// RegisterDefaultRules registers the standard set of validation rules (see DefaultRuleIDs).
 // This includes correctness checks (UniqueID, Cycles) and best practice checks.
 func (v *Validator) RegisterDefaultRules() {
-	v.RegisterRule(&UniqueIDRule{})
-	v.RegisterRule(&ValidReferenceRule{})
-	v.RegisterRule(&CycleDetectionRule{})
-	v.RegisterRule(&OrphanDetectionRule{})
-	v.RegisterRule(&SimplicityRule{})
-	v.RegisterRule(&LayerViolationRule{})
-	v.RegisterRule(&ScenarioFQNRule{})
-
-	// New Best Practice Rules
-	v.RegisterRule(&DatabaseIsolationRule{})
-	v.RegisterRule(&PublicInterfaceDocumentationRule{})
-
-	// SLO Validation Rule
-	v.RegisterRule(&SLOValidationRule{})
-
-	// Properties Validation Rule
-	v.RegisterRule(&PropertiesValidationRule{})
-
-	// Governance Validation Rule
-	v.RegisterRule(&GovernanceValidationRule{})
+	for _, id := range DefaultRuleIDs {
+		if rule, ok := NewRule(id); ok {
+			v.RegisterRule(rule)
+		}
+	}
 }
 
 // Validate runs all registered validation rules concurrently with timeout and panic recovery.
@@ -104,6 +89,13 @@ func (v *Validator) RegisterDefaultRules() {
 //	    fmt.Printf("Error: %s\n", diag.Message)
 //	}
 func (v *Validator) Validate(program *language.Program) []diagnostics.Diagnostic {
+	return v.ValidateContext(context.Background(), program)
+}
+
+// ValidateContext is like Validate but stops when ctx is cancelled, for callers
+// such as editors that discard results superseded by a newer change. Rules that
+// have not started are skipped and nil is returned.
+func (v *Validator) ValidateContext(parent context.Context, program *language.Program) []diagnostics.Diagnostic {
 	if len(v.Rules) == 0 {
 		return nil
 	}
@@ -119,7 +111,7 @@ func (v *Validator) Validate(program *language.Program) []diagnostics.Diagnostic
 	}
 
 	// Create context with timeout to prevent hanging
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 
 	// Channel to collect results
@@ -145,5 +137,25 @@ func (v *Validator) Validate(program *language.Program) []diagnostics.Diagnostic
 	}
 
 	// Collect results
-	return collectResults(ctx, errChan, panicChan, len(v.Rules))
+	diags := collectResults(ctx, errChan, panicChan, len(v.Rules))
+	if parent.Err() != nil {
+		return nil
+	}
+	diags, unused := ApplySuppressions(program, diags)
+	if v.config.reportUnusedSuppressions {
+		diags = append(diags, unusedSuppressionDiagnostics(unused)...)
+	}
+	if v.config.strict {
+		promoteWarnings(diags)
+	}
+	return diags
+}
+
+// promoteWarnings turns warnings into errors in place (strict mode).
+func promoteWarnings(diags []diagnostics.Diagnostic) {
+	for i := range diags {
+		if diags[i].Severity == diagnostics.SeverityWarning {
+			diags[i].Severity = diagnostics.SeverityError
+		}
+	}
 }
@@ -41,6 +41,11 @@ func runRuleWithTimeout(
 
 	select {
 	case diags := <-diagsChan:
+		for i := range diags {
+			if diags[i].Rule == "" {
+				diags[i].Rule = rule.Name()
+			}
+		}
 		errChan <- diags
 	case <-ctx.Done():
 		// Timeout occurred - create a diagnostic for this rule
@@ -49,6 +54,7 @@ func runRuleWithTimeout(
 				Code:     diagnostics.CodeValidationTimeout,
 				Severity: diagnostics.SeverityError,
 				Message:  fmt.Sprintf("Validation rule '%s' timed out after %v", rule.Name(), DefaultValidationTimeout),
+				Rule:     rule.Name(),
 			},
 		}
 	}
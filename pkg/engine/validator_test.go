@@ -2,6 +2,7 @@
 package engine
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -78,6 +79,32 @@ func TestValidator_Validate_MultipleRules(_ *testing.T) {
 	_ = errs
 }
 
+func TestValidator_ValidateContext_Cancelled(t *testing.T) {
+	v := NewValidator()
+	v.RegisterRule(&UniqueIDRule{})
+
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("test.sruja", `
+		Sys1 = system "System 1"
+		Sys1 = system "System 1 again"
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	if errs := v.ValidateContext(context.Background(), program); len(errs) == 0 {
+		t.Error("Expected a duplicate ID error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if errs := v.ValidateContext(ctx, program); errs != nil {
+		t.Errorf("Expected no diagnostics from a cancelled validation, got %v", errs)
+	}
+}
+
 func TestCycleDetectionRule_Name(t *testing.T) {
 	rule := &CycleDetectionRule{}
 	if rule.Name() != "CycleDetection" {
@@ -49,6 +49,8 @@ type EdgeConstraint struct {
 	Label    EdgeLabel
 	// Constraint affects layout (false = edge doesn't affect node positioning)
 	AffectsLayout bool
+	// Bidirectional draws arrowheads on both ends
+	Bidirectional bool
 }
 
 // GlobalConstraints defines global graph layout constraints.
@@ -440,6 +442,7 @@ func buildEdgeConstraints(relations []*Relation, config Config, nodeCount int, p
 			To:            rel.To,
 			AffectsLayout: true,
 			MinLen:        1,
+			Bidirectional: rel.Bidirectional,
 		}
 
 		// Smarter edge weight based on label AND edge importance
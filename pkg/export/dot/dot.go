@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/export/views"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -29,6 +30,9 @@ type Config struct {
 	ViewLevel int
 	// FocusNodeID specifies the node to focus on for L2/L3 views (optional)
 	FocusNodeID string
+	// ViewName selects a declared view whose include/exclude expressions decide
+	// the visible elements and relations (optional, overrides ViewLevel)
+	ViewName string
 	// NodeSizes provides explicit size overrides for nodes (ID -> {W, H})
 	NodeSizes map[string]struct{ Width, Height float64 }
 	// ElementPositions provides explicit position overrides for nodes (ID -> {X, Y})
@@ -99,7 +103,13 @@ func (e *Exporter) Export(prog *language.Program) *ExportResult {
 	allRelations := extractRelationsFromModel(prog)
 	lookup := buildElementLookup(prog)
 
-	elements, relations := e.computeViewGraph(allElementsMap, allRelations, lookup)
+	var elements []*Element
+	var relations []*Relation
+	if e.Config.ViewName != "" {
+		elements, relations = e.computeDeclaredViewGraph(prog)
+	} else {
+		elements, relations = e.computeViewGraph(allElementsMap, allRelations, lookup)
+	}
 
 	if len(elements) == 0 {
 		return &ExportResult{}
@@ -435,9 +445,10 @@ func (e *Exporter) computeViewGraph(allElements map[string]*Element, allRelation
 		key := fmt.Sprintf("%s->%s:%s", source, target, rel.Label)
 		if !seenRel[key] {
 			proj := Relation{
-				From:  source,
-				To:    target,
-				Label: rel.Label,
+				From:          source,
+				To:            target,
+				Label:         rel.Label,
+				Bidirectional: rel.Bidirectional,
 			}
 			finalRelations = append(finalRelations, &proj)
 			seenRel[key] = true
@@ -473,6 +484,33 @@ func (e *Exporter) computeViewGraph(allElements map[string]*Element, allRelation
 	return finalElements, finalRelations
 }
 
+// computeDeclaredViewGraph returns the elements and relations selected by the
+// declared view named in Config.ViewName. Unknown or invalid views yield nothing.
+func (e *Exporter) computeDeclaredViewGraph(prog *language.Program) ([]*Element, []*Relation) {
+	graph, err := views.ResolveView(prog, views.FindView(prog, e.Config.ViewName))
+	if err != nil {
+		return nil, nil
+	}
+
+	var elements []*Element
+	for _, elem := range e.extractAllElements(prog) {
+		if !graph.Elements[elem.ID] {
+			continue
+		}
+		// Only keep the parent when it is shown too, so clusters stay consistent
+		if !graph.Elements[elem.ParentID] {
+			elem.ParentID = ""
+		}
+		elements = append(elements, elem)
+	}
+
+	relations := make([]*Relation, 0, len(graph.Relations))
+	for _, rel := range graph.Relations {
+		relations = append(relations, &Relation{From: rel.From, To: rel.To, Label: rel.Label, Bidirectional: rel.Bidirectional})
+	}
+	return elements, relations
+}
+
 // extractAllElementsMap returns a map of all elements keyed by ID.
 func (e *Exporter) extractAllElementsMap(prog *language.Program) map[string]*Element {
 	list := e.extractAllElements(prog)
@@ -312,6 +312,11 @@ func writeEdgesFromConstraints(sb *strings.Builder, edges []EdgeConstraint, pare
 			attrs = append(attrs, "color=\"#8a9bab\"")
 		}
 
+		// Draw arrowheads on both ends for bidirectional relations
+		if edge.Bidirectional {
+			attrs = append(attrs, "dir=both")
+		}
+
 		// Add constraint attribute
 		if !edge.AffectsLayout {
 			attrs = append(attrs, "constraint=false")
@@ -141,3 +141,76 @@ func TestExporter_NodeSizes(t *testing.T) {
 		t.Errorf("Expected height=2.00 for node 'sys', got DOT:\n%s", dot)
 	}
 }
+
+func TestExporter_Export_DeclaredView(t *testing.T) {
+	dsl := `
+	User = person "User"
+	Shop = system "Shop" {
+		API = container "API"
+		DB = database "DB"
+	}
+	Payments = system "Payments"
+	User -> Shop.API "Uses"
+	Shop.API -> Shop.DB "Reads"
+	Shop.API -> Payments "Charges"
+
+	view api of Shop {
+		include Shop.API ->
+	}
+`
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	config := dot.DefaultConfig()
+	config.ViewName = "api"
+	result := dot.NewExporter(config).Export(prog)
+
+	ids := make(map[string]bool)
+	for _, elem := range result.Elements {
+		ids[elem.ID] = true
+	}
+	for _, id := range []string{"Shop.API", "Shop.DB", "Payments"} {
+		if !ids[id] {
+			t.Errorf("expected %s in view, got %v", id, ids)
+		}
+	}
+	if ids["User"] {
+		t.Error("expected User to be outside the view")
+	}
+	if len(result.Relations) != 2 {
+		t.Errorf("expected 2 relations, got %d", len(result.Relations))
+	}
+	if strings.Contains(result.DOT, "\"User\"") {
+		t.Error("DOT output should not contain User")
+	}
+}
+
+func TestExporter_Export_BidirectionalRelation(t *testing.T) {
+	dsl := `
+	Cache = system "Cache"
+	Store = system "Store"
+	Cache <-> Store "Syncs"
+`
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	result := dot.NewExporter(dot.DefaultConfig()).Export(prog)
+	if len(result.Relations) != 1 || !result.Relations[0].Bidirectional {
+		t.Fatalf("expected one bidirectional relation, got %+v", result.Relations)
+	}
+	if !strings.Contains(result.DOT, "dir=both") {
+		t.Errorf("expected dir=both on bidirectional edge:\n%s", result.DOT)
+	}
+}
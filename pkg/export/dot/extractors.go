@@ -11,6 +11,8 @@ type Relation struct {
 	From  string
 	To    string
 	Label string
+	// Bidirectional marks relations written with <-> (rendered with arrowheads on both ends)
+	Bidirectional bool
 }
 
 // extractAllElements extracts all elements from the program into a flat list.
@@ -220,9 +222,10 @@ func processRelation(rel *language.Relation, _ []string, relations *[]*Relation)
 	}
 
 	*relations = append(*relations, &Relation{
-		From:  from,
-		To:    to,
-		Label: label,
+		From:          from,
+		To:            to,
+		Label:         label,
+		Bidirectional: rel.Bidirectional,
 	})
 }
 
@@ -3,15 +3,37 @@ package dsl
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/sruja-ai/sruja/pkg/export/json"
 )
 
+// defaultViewTitles are the views the JSON exporter adds to every model. They are
+// not printed unless they were changed, since exporting the DSL adds them again.
+var defaultViewTitles = map[string]string{
+	"index": "Index",
+	"L1":    "Landscape View (L1)",
+	"L2":    "Container View (L2)",
+	"L3":    "Component View (L3)",
+}
+
+// governanceKinds are the element kinds printed from the Sruja extensions rather
+// than as plain elements.
+var governanceKinds = map[string]bool{
+	"requirement": true,
+	"adr":         true,
+	"policy":      true,
+	"scenario":    true,
+	"story":       true,
+	"flow":        true,
+}
+
 // ModelPrinter converts a SrujaModelDump to DSL string format.
 type ModelPrinter struct {
 	indentLevel int
 	sb          strings.Builder
+	elements    map[string]json.ElementDump
 }
 
 // NewModelPrinter creates a new ModelPrinter.
@@ -27,11 +49,22 @@ func (p *ModelPrinter) Print(model *json.SrujaModelDump) string {
 
 	p.sb.Reset()
 	p.indentLevel = 0
+	p.elements = model.Elements
+
+	if model.Sruja != nil {
+		for _, imp := range model.Sruja.Imports {
+			p.writeLine("import { " + strings.Join(imp.Elements, ", ") + " } from \"" + escapeString(imp.From) + "\"")
+		}
+	}
 
-	// Use the flat printing logic directly
+	// Governance elements are printed with their extension data below
+	governed := governanceIDs(model.Sruja)
 	topLevel, children := p.buildChildrenMap(model)
 
 	for _, el := range topLevel {
+		if governed[el.ID] && governanceKinds[strings.ToLower(el.Kind)] {
+			continue
+		}
 		p.printElement(&el, children)
 	}
 
@@ -113,6 +146,11 @@ func escapeString(s string) string {
 	return s
 }
 
+// quote returns s as a DSL string literal.
+func quote(s string) string {
+	return "\"" + escapeString(s) + "\""
+}
+
 func (p *ModelPrinter) printElement(el *json.ElementDump, children map[string][]json.ElementDump) {
 	// Extract short name from FQN
 	name := extractName(el.ID)
@@ -139,29 +177,14 @@ func (p *ModelPrinter) printElement(el *json.ElementDump, children map[string][]
 	}
 
 	// Tags
-	if len(el.Tags) > 0 {
-		p.sb.WriteString(p.indent())
-		p.sb.WriteString("tags [")
-		for i, tag := range el.Tags {
-			if i > 0 {
-				p.sb.WriteString(", ")
-			}
-			p.sb.WriteString("\"")
-			p.sb.WriteString(escapeString(tag))
-			p.sb.WriteString("\"")
-		}
-		p.sb.WriteString("]\n")
-	}
+	p.printTags(el.Tags)
 
 	// Metadata
-	if len(el.Metadata) > 0 {
-		p.writeLine("metadata {")
-		p.indentLevel++
-		for key, value := range el.Metadata {
-			p.writeLine(key + " \"" + escapeString(value) + "\"")
-		}
-		p.indentLevel--
-		p.writeLine("}")
+	p.printMetadata(el.Metadata, nil)
+
+	// SLO
+	if el.SLO != nil {
+		p.printSLO(el.SLO)
 	}
 
 	// Links
@@ -187,22 +210,102 @@ func (p *ModelPrinter) printElement(el *json.ElementDump, children map[string][]
 	p.writeLine("}")
 }
 
+// printMetadata prints a metadata block with its entries sorted by key. Array values
+// replace string values with the same key.
+func (p *ModelPrinter) printTags(tags []string) {
+	if len(tags) > 0 {
+		p.writeLine("tags " + quotedList(tags))
+	}
+}
+
+func (p *ModelPrinter) printMetadata(values map[string]string, arrays map[string][]string) {
+	if len(values) == 0 && len(arrays) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(values)+len(arrays))
+	for key := range values {
+		if _, ok := arrays[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	for key := range arrays {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	p.writeLine("metadata {")
+	p.indentLevel++
+	for _, key := range keys {
+		if array, ok := arrays[key]; ok {
+			p.writeLine(key + " " + quotedList(array))
+		} else {
+			p.writeLine(key + " \"" + escapeString(values[key]) + "\"")
+		}
+	}
+	p.indentLevel--
+	p.writeLine("}")
+}
+
+func (p *ModelPrinter) printSLO(slo *json.SLOJSON) {
+	p.writeLine("slo {")
+	p.indentLevel++
+	if a := slo.Availability; a != nil {
+		p.printSLOBlock("availability", "target", a.Target, "window", a.Window, "current", strVal(a.Current))
+	}
+	if l := slo.Latency; l != nil {
+		p.writeLine("latency {")
+		p.indentLevel++
+		p.printSLOFields("p95", l.P95, "p99", l.P99, "window", l.Window)
+		if l.Current != nil {
+			p.printSLOBlock("current", "p95", l.Current.P95, "p99", l.Current.P99)
+		}
+		p.indentLevel--
+		p.writeLine("}")
+	}
+	if e := slo.ErrorRate; e != nil {
+		p.printSLOBlock("errorRate", "target", e.Target, "window", e.Window, "current", strVal(e.Current))
+	}
+	if t := slo.Throughput; t != nil {
+		p.printSLOBlock("throughput", "target", t.Target, "window", t.Window, "current", strVal(t.Current))
+	}
+	if c := slo.Cost; c != nil {
+		p.printSLOBlock("cost", "target", c.Target, "window", c.Window)
+	}
+	p.indentLevel--
+	p.writeLine("}")
+}
+
+// printSLOBlock prints a named SLO block with the given key/value pairs.
+func (p *ModelPrinter) printSLOBlock(name string, pairs ...string) {
+	p.writeLine(name + " {")
+	p.indentLevel++
+	p.printSLOFields(pairs...)
+	p.indentLevel--
+	p.writeLine("}")
+}
+
+// printSLOFields prints the non-empty values of key/value pairs.
+func (p *ModelPrinter) printSLOFields(pairs ...string) {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			p.writeLine(pairs[i] + " \"" + escapeString(pairs[i+1]) + "\"")
+		}
+	}
+}
+
 func (p *ModelPrinter) printRelation(rel *json.RelationDump) {
 	// Get source and target FQNs
 	sourceFqn := rel.Source.Model
 	targetFqn := rel.Target.Model
 
-	if sourceFqn == "" || targetFqn == "" {
+	// Implied parent relations are inferred again when the source is parsed
+	if sourceFqn == "" || targetFqn == "" || rel.Implied {
 		return
 	}
 
-	// Determine arrow type
 	arrow := "->"
-	switch rel.Kind {
-	case "bidirectional":
+	if rel.Bidirectional {
 		arrow = "<->"
-	case "back":
-		arrow = "<-"
 	}
 
 	p.sb.WriteString(p.indent())
@@ -212,31 +315,41 @@ func (p *ModelPrinter) printRelation(rel *json.RelationDump) {
 	p.sb.WriteString(" ")
 	p.sb.WriteString(targetFqn)
 
-	// Title
-	if rel.Title != "" {
-		p.sb.WriteString(" \"")
-		p.sb.WriteString(escapeString(rel.Title))
-		p.sb.WriteString("\"")
+	// The JSON exporter stores the verb in the description and the label, or the
+	// verb when there is no label, in the title. Technology has no place of its own
+	// in a relation and is printed as the label when there is none.
+	verb, label := rel.Description, rel.Title
+	if verb == "" {
+		verb, label = label, ""
 	}
-
-	// Technology
-	if rel.Technology != "" {
-		p.sb.WriteString(" technology \"")
-		p.sb.WriteString(escapeString(rel.Technology))
-		p.sb.WriteString("\"")
+	if label == verb {
+		label = ""
+	}
+	if label == "" {
+		label = rel.Technology
+	}
+	if verb == "" {
+		verb, label = label, ""
+	}
+	if verb != "" {
+		p.sb.WriteString(" ")
+		p.sb.WriteString(quote(verb))
+	}
+	if label != "" {
+		p.sb.WriteString(" ")
+		p.sb.WriteString(quote(label))
 	}
 
 	// Tags
-	if len(rel.Tags) > 0 {
-		p.sb.WriteString(" tags [")
-		for i, tag := range rel.Tags {
-			if i > 0 {
-				p.sb.WriteString(", ")
-			}
-			p.sb.WriteString("\"")
-			p.sb.WriteString(escapeString(tag))
-			p.sb.WriteString("\"")
+	var tags []string
+	for _, tag := range rel.Tags {
+		if isIdent(tag) {
+			tags = append(tags, tag)
 		}
+	}
+	if len(tags) > 0 {
+		p.sb.WriteString(" [")
+		p.sb.WriteString(strings.Join(tags, ", "))
 		p.sb.WriteString("]")
 	}
 
@@ -253,6 +366,7 @@ func (p *ModelPrinter) printExtensions(ext *json.SrujaExtensions) {
 		len(ext.ADRs) > 0 ||
 		len(ext.Scenarios) > 0 ||
 		len(ext.Flows) > 0 ||
+		len(ext.Deployments) > 0 ||
 		len(ext.Constraints) > 0 ||
 		len(ext.Conventions) > 0
 
@@ -279,98 +393,139 @@ func (p *ModelPrinter) printExtensions(ext *json.SrujaExtensions) {
 
 	// Scenarios
 	for _, scenario := range ext.Scenarios {
-		p.printScenario(&scenario)
+		p.printSteps(p.kindOf(scenario.ID, "scenario"), scenario.ID, scenario.Title, scenario.Description, scenario.Steps)
 	}
 
 	// Flows
 	for _, flow := range ext.Flows {
-		p.printFlow(&flow)
+		p.printSteps("flow", flow.ID, flow.Title, flow.Description, flow.Steps)
+	}
+
+	// Deployments
+	for _, deployment := range ext.Deployments {
+		p.printDeployment(&deployment, "deployment")
 	}
 
 	// Constraints
-	for _, constraint := range ext.Constraints {
-		p.printConstraint(&constraint)
+	if len(ext.Constraints) > 0 {
+		p.writeLine("constraints {")
+		p.indentLevel++
+		for _, constraint := range ext.Constraints {
+			p.printEntry(constraint.ID, constraint.Description)
+		}
+		p.indentLevel--
+		p.writeLine("}")
 	}
 
 	// Conventions
-	for _, convention := range ext.Conventions {
-		p.printConvention(&convention)
+	if len(ext.Conventions) > 0 {
+		p.writeLine("conventions {")
+		p.indentLevel++
+		for _, convention := range ext.Conventions {
+			p.printEntry(convention.ID, convention.Description)
+		}
+		p.indentLevel--
+		p.writeLine("}")
 	}
 }
 
-func (p *ModelPrinter) printRequirement(req *json.RequirementDump) {
-	typeStr := ""
-	if req.Type != "" {
-		typeStr = " " + req.Type
+// printEntry prints a constraint or convention entry. Keys that are not identifiers
+// are dropped.
+func (p *ModelPrinter) printEntry(key, value string) {
+	if isIdent(key) {
+		p.writeLine(key + " \"" + escapeString(value) + "\"")
+	} else {
+		p.writeLine("\"" + escapeString(value) + "\"")
 	}
+}
 
-	// Check if requirement has a body
-	hasBody := req.Description != "" || req.Priority != "" || req.Status != "" || len(req.Elements) > 0
+// header returns the definition header of a governance element. A sub-kind that is
+// not an identifier is returned separately, to be printed in the body.
+func header(id, kind, subKind, title string) (string, string) {
+	line := id + " = " + kind
+	rest := ""
+	if isIdent(subKind) {
+		line += " " + subKind
+	} else {
+		rest = subKind
+	}
+	return line + " \"" + escapeString(title) + "\"", rest
+}
 
-	if hasBody {
-		p.writeLine(req.ID + " = requirement" + typeStr + " \"" + escapeString(req.Title) + "\" {")
-		p.indentLevel++
+func (p *ModelPrinter) printRequirement(req *json.RequirementDump) {
+	line, _ := header(req.ID, "requirement", req.Type, req.Title)
+	el := p.elements[req.ID]
 
-		if req.Description != "" {
-			p.writeLine("description \"" + escapeString(req.Description) + "\"")
-		}
-		if req.Priority != "" {
-			p.writeLine("priority \"" + escapeString(req.Priority) + "\"")
-		}
-		if req.Status != "" {
-			p.writeLine("status \"" + escapeString(req.Status) + "\"")
-		}
-		if len(req.Elements) > 0 {
-			p.sb.WriteString(p.indent())
-			p.sb.WriteString("elements [")
-			p.sb.WriteString(strings.Join(req.Elements, ", "))
-			p.sb.WriteString("]\n")
-		}
+	var arrays map[string][]string
+	if len(req.Elements) > 0 {
+		arrays = map[string][]string{"elements": req.Elements}
+	}
+	metadata := withValue(el.Metadata, "priority", req.Priority)
 
-		p.indentLevel--
-		p.writeLine("}")
-	} else {
-		p.writeLine(req.ID + " = requirement" + typeStr + " \"" + escapeString(req.Title) + "\"")
+	// Check if requirement has a body
+	hasBody := req.Description != "" || req.Status != "" || len(el.Tags) > 0 || len(metadata) > 0 || len(arrays) > 0
+	if !hasBody {
+		p.writeLine(line)
+		return
+	}
+
+	p.writeLine(line + " {")
+	p.indentLevel++
+	if req.Description != "" {
+		p.writeLine("description \"" + escapeString(req.Description) + "\"")
+	}
+	if req.Status != "" {
+		p.writeLine("status \"" + escapeString(req.Status) + "\"")
 	}
+	p.printTags(el.Tags)
+	p.printMetadata(metadata, arrays)
+	p.indentLevel--
+	p.writeLine("}")
 }
 
 func (p *ModelPrinter) printPolicy(policy *json.PolicyDump) {
-	catStr := ""
-	if policy.Category != "" {
-		catStr = " " + policy.Category
+	line, category := header(policy.ID, "policy", policy.Category, policy.Title)
+	el := p.elements[policy.ID]
+
+	var arrays map[string][]string
+	if len(policy.Elements) > 0 {
+		arrays = map[string][]string{"elements": policy.Elements}
 	}
 
 	// Check if policy has a body
-	hasBody := policy.Description != "" || policy.Enforcement != "" || len(policy.Elements) > 0
-
-	if hasBody {
-		p.writeLine(policy.ID + " = policy" + catStr + " \"" + escapeString(policy.Title) + "\" {")
-		p.indentLevel++
-
-		if policy.Description != "" {
-			p.writeLine("description \"" + escapeString(policy.Description) + "\"")
-		}
-		if policy.Enforcement != "" {
-			p.writeLine("enforcement \"" + escapeString(policy.Enforcement) + "\"")
-		}
-		if len(policy.Elements) > 0 {
-			p.sb.WriteString(p.indent())
-			p.sb.WriteString("elements [")
-			p.sb.WriteString(strings.Join(policy.Elements, ", "))
-			p.sb.WriteString("]\n")
-		}
+	hasBody := policy.Description != "" || policy.Enforcement != "" || category != "" || len(el.Tags) > 0 || len(el.Metadata) > 0 || len(arrays) > 0
+	if !hasBody {
+		p.writeLine(line)
+		return
+	}
 
-		p.indentLevel--
-		p.writeLine("}")
-	} else {
-		p.writeLine(policy.ID + " = policy" + catStr + " \"" + escapeString(policy.Title) + "\"")
+	p.writeLine(line + " {")
+	p.indentLevel++
+	if policy.Description != "" {
+		p.writeLine("description \"" + escapeString(policy.Description) + "\"")
+	}
+	if category != "" {
+		p.writeLine("category \"" + escapeString(category) + "\"")
+	}
+	if policy.Enforcement != "" {
+		p.writeLine("enforcement \"" + escapeString(policy.Enforcement) + "\"")
 	}
+	p.printTags(el.Tags)
+	p.printMetadata(el.Metadata, arrays)
+	p.indentLevel--
+	p.writeLine("}")
 }
 
 func (p *ModelPrinter) printADR(adr *json.ADRDump) {
-	p.writeLine("adr " + adr.ID + " \"" + escapeString(adr.Title) + "\" {")
+	line, _ := header(adr.ID, "adr", "", adr.Title)
+	el := p.elements[adr.ID]
+
+	p.writeLine(line + " {")
 	p.indentLevel++
 
+	if el.Description != "" {
+		p.writeLine("description \"" + escapeString(el.Description) + "\"")
+	}
 	if adr.Status != "" {
 		p.writeLine("status \"" + escapeString(adr.Status) + "\"")
 	}
@@ -383,42 +538,30 @@ func (p *ModelPrinter) printADR(adr *json.ADRDump) {
 	if adr.Consequences != "" {
 		p.writeLine("consequences \"" + escapeString(adr.Consequences) + "\"")
 	}
-	if adr.Date != "" {
-		p.writeLine("date \"" + escapeString(adr.Date) + "\"")
-	}
-	if adr.Author != "" {
-		p.writeLine("author \"" + escapeString(adr.Author) + "\"")
-	}
+	p.printTags(el.Tags)
+	// Date and author have no fields of their own
+	p.printMetadata(withValue(withValue(el.Metadata, "date", adr.Date), "author", adr.Author), nil)
 
 	p.indentLevel--
 	p.writeLine("}")
 }
 
-func (p *ModelPrinter) printScenario(scenario *json.ScenarioDump) {
-	p.writeLine("scenario " + scenario.ID + " \"" + escapeString(scenario.Title) + "\" {")
-	p.indentLevel++
+// printSteps prints a scenario or flow. Steps without both endpoints cannot be
+// written in the DSL and are skipped.
+func (p *ModelPrinter) printSteps(kind, id, title, description string, steps []json.StepDump) {
+	line, _ := header(id, kind, "", title)
+	el := p.elements[id]
 
-	if scenario.Description != "" {
-		p.writeLine("description \"" + escapeString(scenario.Description) + "\"")
-	}
-
-	for _, step := range scenario.Steps {
-		p.printStep(&step)
-	}
-
-	p.indentLevel--
-	p.writeLine("}")
-}
-
-func (p *ModelPrinter) printFlow(flow *json.FlowDump) {
-	p.writeLine("flow " + flow.ID + " \"" + escapeString(flow.Title) + "\" {")
+	p.writeLine(line + " {")
 	p.indentLevel++
 
-	if flow.Description != "" {
-		p.writeLine("description \"" + escapeString(flow.Description) + "\"")
+	if description != "" {
+		p.writeLine("description \"" + escapeString(description) + "\"")
 	}
+	p.printTags(el.Tags)
+	p.printMetadata(el.Metadata, nil)
 
-	for _, step := range flow.Steps {
+	for _, step := range steps {
 		p.printStep(&step)
 	}
 
@@ -427,53 +570,89 @@ func (p *ModelPrinter) printFlow(flow *json.FlowDump) {
 }
 
 func (p *ModelPrinter) printStep(step *json.StepDump) {
+	if step.From == "" || step.To == "" {
+		return
+	}
 	p.sb.WriteString(p.indent())
-	p.sb.WriteString("step \"")
-	p.sb.WriteString(escapeString(step.Description))
-	p.sb.WriteString("\"")
-
-	if step.From != "" && step.To != "" {
-		p.sb.WriteString(" from ")
-		p.sb.WriteString(step.From)
-		p.sb.WriteString(" to ")
-		p.sb.WriteString(step.To)
+	p.sb.WriteString("step ")
+	p.sb.WriteString(step.From)
+	p.sb.WriteString(" -> ")
+	p.sb.WriteString(step.To)
+	if step.Description != "" {
+		p.sb.WriteString(" \"")
+		p.sb.WriteString(escapeString(step.Description))
+		p.sb.WriteString("\"")
 	}
-
 	p.sb.WriteString("\n")
 }
 
-func (p *ModelPrinter) printConstraint(constraint *json.ConstraintDump) {
-	typeStr := ""
-	if constraint.Type != "" {
-		typeStr = " " + constraint.Type
+// printDeployment prints a deployment node with its nested nodes, infrastructure
+// nodes and container instances. kind is used when the node has no kind.
+func (p *ModelPrinter) printDeployment(d *json.DeploymentDump, kind string) {
+	if d.Kind != "" {
+		kind = d.Kind
 	}
-	p.writeLine("constraint " + constraint.ID + typeStr + " \"" + escapeString(constraint.Description) + "\"")
-}
-
-func (p *ModelPrinter) printConvention(convention *json.ConventionDump) {
-	p.writeLine("convention " + convention.ID + " \"" + escapeString(convention.Description) + "\"")
-}
-
-func (p *ModelPrinter) printViews(model *json.SrujaModelDump) {
-	if len(model.Views) == 0 {
+	title := d.Title
+	if title == "" {
+		title = d.ID
+	}
+	line := kind + " " + d.ID + " \"" + escapeString(title) + "\""
+	if d.Description != "" {
+		line += " \"" + escapeString(d.Description) + "\""
+	}
+	if kind == "infrastructure" {
+		p.writeLine(line)
+		return
+	}
+	if len(d.Children) == 0 && len(d.Instances) == 0 {
+		p.writeLine(line)
 		return
 	}
 
-	p.sb.WriteString("\n")
+	p.writeLine(line + " {")
+	p.indentLevel++
+	for _, child := range d.Children {
+		p.printDeployment(&child, "node")
+	}
+	for _, instance := range d.Instances {
+		p.writeLine("containerInstance " + instance)
+	}
+	p.indentLevel--
+	p.writeLine("}")
+}
 
+func (p *ModelPrinter) printViews(model *json.SrujaModelDump) {
 	// Sort views for consistent output
 	viewIDs := make([]string, 0, len(model.Views))
-	for id := range model.Views {
-		viewIDs = append(viewIDs, id)
+	for id, view := range model.Views {
+		if !isDefaultView(&view) {
+			viewIDs = append(viewIDs, id)
+		}
+	}
+	if len(viewIDs) == 0 {
+		return
 	}
 	sort.Strings(viewIDs)
 
+	p.sb.WriteString("\n")
 	for _, id := range viewIDs {
 		view := model.Views[id]
 		p.printView(&view)
 	}
 }
 
+// isDefaultView reports whether a view is one of the unchanged views the JSON
+// exporter adds to every model.
+func isDefaultView(view *json.ViewDump) bool {
+	title, ok := defaultViewTitles[view.ID]
+	if !ok || view.Title != title || view.ViewOf != "" || view.Layout != nil || len(view.Rules) != 1 {
+		return false
+	}
+	include := view.Rules[0].Include
+	return include != nil && include.Wildcard && !include.Recursive &&
+		len(include.Elements) == 0 && len(include.Expressions) == 0
+}
+
 func (p *ModelPrinter) printView(view *json.ViewDump) {
 	p.sb.WriteString(p.indent())
 	p.sb.WriteString("view ")
@@ -498,44 +677,142 @@ func (p *ModelPrinter) printView(view *json.ViewDump) {
 	// View rules
 	for _, rule := range view.Rules {
 		if rule.Include != nil {
-			if rule.Include.Wildcard {
-				p.writeLine("include *")
-			} else {
-				for _, el := range rule.Include.Elements {
-					p.writeLine("include " + el)
-				}
-			}
+			p.printViewRule("include", rule.Include)
 		}
 		if rule.Exclude != nil {
-			if rule.Exclude.Wildcard {
-				p.writeLine("exclude *")
-			} else {
-				for _, el := range rule.Exclude.Elements {
-					p.writeLine("exclude " + el)
-				}
-			}
+			p.printViewRule("exclude", rule.Exclude)
 		}
 	}
 
 	// Tags
 	if len(view.Tags) > 0 {
-		p.sb.WriteString(p.indent())
-		p.sb.WriteString("tags [")
-		for i, tag := range view.Tags {
-			if i > 0 {
-				p.sb.WriteString(", ")
-			}
-			p.sb.WriteString("\"")
-			p.sb.WriteString(escapeString(tag))
-			p.sb.WriteString("\"")
+		p.writeLine("tags " + quotedList(view.Tags))
+	}
+
+	// Layout positions
+	if view.Layout != nil && len(view.Layout.Positions) > 0 {
+		ids := make([]string, 0, len(view.Layout.Positions))
+		for id := range view.Layout.Positions {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		p.writeLine("layout {")
+		p.indentLevel++
+		for _, id := range ids {
+			pos := view.Layout.Positions[id]
+			p.writeLine("element " + id + " {")
+			p.indentLevel++
+			p.writeLine("position { x: " + formatNumber(pos.X) + ", y: " + formatNumber(pos.Y) + " }")
+			p.indentLevel--
+			p.writeLine("}")
 		}
-		p.sb.WriteString("]\n")
+		p.indentLevel--
+		p.writeLine("}")
 	}
 
 	p.indentLevel--
 	p.writeLine("}")
 }
 
+func (p *ModelPrinter) printViewRule(keyword string, expr *json.ViewRuleExpr) {
+	if expr.Wildcard {
+		p.writeLine(keyword + " *")
+	}
+	if expr.Recursive {
+		p.writeLine(keyword + " **")
+	}
+	for _, el := range expr.Elements {
+		p.writeLine(keyword + " " + el)
+	}
+	for _, e := range expr.Expressions {
+		p.writeLine(keyword + " " + e)
+	}
+}
+
+// kindOf returns the kind of the element with the given ID, or def if there is no
+// such element.
+func (p *ModelPrinter) kindOf(id, def string) string {
+	if el, ok := p.elements[id]; ok && el.Kind != "" {
+		return el.Kind
+	}
+	return def
+}
+
+// governanceIDs returns the IDs of the elements described by the extensions.
+func governanceIDs(ext *json.SrujaExtensions) map[string]bool {
+	ids := make(map[string]bool)
+	if ext == nil {
+		return ids
+	}
+	for _, req := range ext.Requirements {
+		ids[req.ID] = true
+	}
+	for _, adr := range ext.ADRs {
+		ids[adr.ID] = true
+	}
+	for _, policy := range ext.Policies {
+		ids[policy.ID] = true
+	}
+	for _, scenario := range ext.Scenarios {
+		ids[scenario.ID] = true
+	}
+	for _, flow := range ext.Flows {
+		ids[flow.ID] = true
+	}
+	return ids
+}
+
+// withValue returns a copy of values with key set to value, or values itself when
+// value is empty.
+func withValue(values map[string]string, key, value string) map[string]string {
+	if value == "" {
+		return values
+	}
+	out := make(map[string]string, len(values)+1)
+	for k, v := range values {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// quotedList formats strings as a DSL list of string literals.
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func strVal(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// isIdent reports whether s is a valid DSL identifier.
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // extractName gets the short name from a fully qualified ID.
 func extractName(id string) string {
 	parts := strings.Split(id, ".")
@@ -47,33 +47,11 @@ func TestPrint_ConstraintsAndConventions(t *testing.T) {
 
 	result := Print(model)
 
-	if !strings.Contains(result, `constraint CONS1 technical "Must run on Linux"`) {
-		t.Error("Expected constraint")
+	if !strings.Contains(result, "constraints {\n  CONS1 \"Must run on Linux\"\n}") {
+		t.Errorf("Expected constraints block, got: %s", result)
 	}
-	if !strings.Contains(result, `convention CONV1 "Use snake_case"`) {
-		t.Error("Expected convention")
-	}
-}
-
-func TestPrint_RelationBackArrow(t *testing.T) {
-	model := &json.SrujaModelDump{
-		Elements: map[string]json.ElementDump{
-			"A": {ID: "A"},
-			"B": {ID: "B"},
-		},
-		Relations: []json.RelationDump{
-			{
-				Source: json.FqnRefDump{Model: "A"},
-				Target: json.FqnRefDump{Model: "B"},
-				Kind:   "back",
-			},
-		},
-	}
-
-	result := Print(model)
-
-	if !strings.Contains(result, "A <- B") {
-		t.Error("Expected back arrow relation")
+	if !strings.Contains(result, "conventions {\n  CONV1 \"Use snake_case\"\n}") {
+		t.Errorf("Expected conventions block, got: %s", result)
 	}
 }
 
@@ -113,3 +91,80 @@ func TestPrintFlat_NilModel(t *testing.T) {
 		t.Error("Expected empty string for nil model")
 	}
 }
+
+func TestPrint_SLO(t *testing.T) {
+	model := &json.SrujaModelDump{
+		Elements: map[string]json.ElementDump{
+			"API": {
+				ID:    "API",
+				Kind:  "container",
+				Title: "API",
+				SLO: &json.SLOJSON{
+					Availability: &json.SLOAvailabilityJSON{Target: "99.9%", Window: "30d"},
+					Latency:      &json.SLOLatencyJSON{P95: "200ms"},
+				},
+			},
+		},
+	}
+
+	result := Print(model)
+
+	for _, want := range []string{"slo {", "availability {", `target "99.9%"`, `window "30d"`, "latency {", `p95 "200ms"`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output, got: %s", want, result)
+		}
+	}
+	if strings.Contains(result, "p99") {
+		t.Error("Did not expect empty SLO fields")
+	}
+}
+
+func TestPrint_ViewLayoutAndDefaults(t *testing.T) {
+	model := &json.SrujaModelDump{
+		Elements: map[string]json.ElementDump{},
+		Views: map[string]json.ViewDump{
+			"index": {ID: "index", Title: "Index", Rules: []json.ViewRule{{Include: &json.ViewRuleExpr{Wildcard: true}}}},
+			"main": {
+				ID:    "main",
+				Title: "Main",
+				Rules: []json.ViewRule{{Include: &json.ViewRuleExpr{Expressions: []string{"-> API ->"}}}},
+				Layout: &json.ViewLayoutDump{Positions: map[string]json.ViewPositionDump{
+					"API": {X: 100, Y: 50.5},
+				}},
+			},
+		},
+	}
+
+	result := Print(model)
+
+	if strings.Contains(result, "view index") {
+		t.Error("Did not expect the default index view")
+	}
+	for _, want := range []string{"view main {", "include -> API ->", "element API {", "position { x: 100, y: 50.5 }"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output, got: %s", want, result)
+		}
+	}
+}
+
+func TestPrint_GovernanceElements(t *testing.T) {
+	model := &json.SrujaModelDump{
+		Elements: map[string]json.ElementDump{
+			"ADR1": {ID: "ADR1", Kind: "adr", Title: "Use Go", Metadata: map[string]string{"owner": "arch"}},
+		},
+		Sruja: &json.SrujaExtensions{
+			ADRs: []json.ADRDump{{ID: "ADR1", Title: "Use Go", Status: "accepted", Author: "Ann"}},
+		},
+	}
+
+	result := Print(model)
+
+	if strings.Count(result, "ADR1 = adr") != 1 {
+		t.Errorf("Expected the ADR to be printed once, got: %s", result)
+	}
+	for _, want := range []string{`status "accepted"`, `author "Ann"`, `owner "arch"`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output, got: %s", want, result)
+		}
+	}
+}
@@ -106,11 +106,8 @@ func TestPrint_Relations(t *testing.T) {
 	if !strings.Contains(result, "A -> B") {
 		t.Error("Expected relation from A to B")
 	}
-	if !strings.Contains(result, `"uses"`) {
-		t.Error("Expected relation title")
-	}
-	if !strings.Contains(result, `technology "REST"`) {
-		t.Error("Expected relation technology")
+	if !strings.Contains(result, `A -> B "uses" "REST"`) {
+		t.Errorf("Expected relation title and technology, got: %s", result)
 	}
 }
 
@@ -122,10 +119,10 @@ func TestPrint_BidirectionalRelation(t *testing.T) {
 		},
 		Relations: []json.RelationDump{
 			{
-				ID:     "A-B",
-				Source: json.FqnRefDump{Model: "A"},
-				Target: json.FqnRefDump{Model: "B"},
-				Kind:   "bidirectional",
+				ID:            "A-B",
+				Source:        json.FqnRefDump{Model: "A"},
+				Target:        json.FqnRefDump{Model: "B"},
+				Bidirectional: true,
 			},
 		},
 	}
@@ -137,6 +134,26 @@ func TestPrint_BidirectionalRelation(t *testing.T) {
 	}
 }
 
+func TestPrint_ImpliedRelation(t *testing.T) {
+	model := &json.SrujaModelDump{
+		Elements: map[string]json.ElementDump{
+			"A":   {ID: "A", Kind: "system", Title: "A"},
+			"A.X": {ID: "X", Kind: "container", Title: "X", Parent: "A"},
+			"B":   {ID: "B", Kind: "system", Title: "B"},
+		},
+		Relations: []json.RelationDump{
+			{ID: "AX-B", Source: json.FqnRefDump{Model: "A.X"}, Target: json.FqnRefDump{Model: "B"}},
+			{ID: "A-B", Source: json.FqnRefDump{Model: "A"}, Target: json.FqnRefDump{Model: "B"}, Implied: true},
+		},
+	}
+
+	result := Print(model)
+
+	if !strings.Contains(result, "A.X -> B") || strings.Contains(result, "A -> B") {
+		t.Errorf("Expected only the declared relation, got: %s", result)
+	}
+}
+
 func TestPrint_Tags(t *testing.T) {
 	model := &json.SrujaModelDump{
 		Elements: map[string]json.ElementDump{
@@ -259,7 +276,7 @@ func TestPrint_ADR(t *testing.T) {
 
 	result := Print(model)
 
-	if !strings.Contains(result, `adr ADR001 "Use Go"`) {
+	if !strings.Contains(result, `ADR001 = adr "Use Go" {`) {
 		t.Error("Expected ADR definition")
 	}
 	if !strings.Contains(result, `status "accepted"`) {
@@ -288,10 +305,10 @@ func TestPrint_Scenario(t *testing.T) {
 
 	result := Print(model)
 
-	if !strings.Contains(result, `scenario SC001 "User Login"`) {
+	if !strings.Contains(result, `SC001 = scenario "User Login" {`) {
 		t.Error("Expected scenario definition")
 	}
-	if !strings.Contains(result, `step "User enters credentials" from User to LoginPage`) {
+	if !strings.Contains(result, `step User -> LoginPage "User enters credentials"`) {
 		t.Error("Expected step with from/to")
 	}
 }
@@ -367,13 +384,13 @@ func TestPrint_Flow(t *testing.T) {
 
 	result := Print(model)
 
-	if !strings.Contains(result, `flow FL001 "Order Flow"`) {
+	if !strings.Contains(result, `FL001 = flow "Order Flow" {`) {
 		t.Error("Expected flow definition")
 	}
-	if !strings.Contains(result, `step "Customer places order" from Customer to OrderService`) {
+	if !strings.Contains(result, `step Customer -> OrderService "Customer places order"`) {
 		t.Error("Expected first step")
 	}
-	if !strings.Contains(result, `step "Order is validated" from OrderService to Validator`) {
+	if !strings.Contains(result, `step OrderService -> Validator "Order is validated"`) {
 		t.Error("Expected second step")
 	}
 }
@@ -61,6 +61,12 @@ func convertSLO(slo *language.SLOBlock) *SLOJSON {
 			Current: slo.Throughput.Current,
 		}
 	}
+	if slo.Cost != nil {
+		out.Cost = &SLOCostJSON{
+			Target: strVal(slo.Cost.Target),
+			Window: strVal(slo.Cost.Window),
+		}
+	}
 	return out
 }
 
@@ -37,7 +37,9 @@ func (e *Exporter) convertElementsFromModel(dump *SrujaModelDump, model *languag
 		}
 		description := ""
 		technology := ""
+		var tags []string
 		var metadata []*language.MetaEntry
+		var slo *SLOJSON
 
 		body := elem.GetBody()
 		if body != nil {
@@ -48,9 +50,15 @@ func (e *Exporter) convertElementsFromModel(dump *SrujaModelDump, model *languag
 				if item.Technology != nil {
 					technology = *item.Technology
 				}
+				if len(item.Tags) > 0 {
+					tags = append(tags, item.Tags...)
+				}
 				if item.Metadata != nil {
 					metadata = item.Metadata.Entries
 				}
+				if item.SLO != nil {
+					slo = convertSLO(item.SLO)
+				}
 			}
 		}
 
@@ -61,7 +69,9 @@ func (e *Exporter) convertElementsFromModel(dump *SrujaModelDump, model *languag
 			Title:       title,
 			Description: description,
 			Technology:  technology,
+			Tags:        tags,
 			Metadata:    metaToMap(metadata),
+			SLO:         slo,
 			Parent:      parentFQN,
 		}
 
@@ -120,6 +130,15 @@ func (e *Exporter) convertRelationsFromModel(dump *SrujaModelDump, model *langua
 		return sb.String()
 	}
 
+	// The resolver qualifies the ends of the relations it resolves, including
+	// references to elements outside the enclosing element
+	relationEndFQN := func(qid language.QualifiedIdent, resolved language.Element, contextFQN string) string {
+		if resolved != nil {
+			return qid.String()
+		}
+		return resolveFQN(qid, contextFQN)
+	}
+
 	var collectRelations func(elem *language.ElementDef, contextFQN string)
 	collectRelations = func(elem *language.ElementDef, contextFQN string) {
 		if elem == nil {
@@ -144,8 +163,8 @@ func (e *Exporter) convertRelationsFromModel(dump *SrujaModelDump, model *langua
 			for _, bodyItem := range body.Items {
 				if bodyItem.Relation != nil {
 					rel := bodyItem.Relation
-					fromFQN := resolveFQN(rel.From, elemFQN)
-					toFQN := resolveFQN(rel.To, elemFQN)
+					fromFQN := relationEndFQN(rel.From, rel.ResolvedFrom, elemFQN)
+					toFQN := relationEndFQN(rel.To, rel.ResolvedTo, elemFQN)
 					// Extract title: prefer Label, then Verb, then VerbRaw
 					title := ptrToString(rel.Label)
 					if title == "" {
@@ -156,11 +175,13 @@ func (e *Exporter) convertRelationsFromModel(dump *SrujaModelDump, model *langua
 					}
 
 					dump.Relations = append(dump.Relations, RelationDump{
-						ID:          fmt.Sprintf("rel-%d", relIndex),
-						Source:      NewFqnRef(fromFQN),
-						Target:      NewFqnRef(toFQN),
-						Title:       title,
-						Description: strVal(rel.Verb), // Keep Verb in Description if needed, or swap based on semantics
+						ID:            fmt.Sprintf("rel-%d", relIndex),
+						Source:        NewFqnRef(fromFQN),
+						Target:        NewFqnRef(toFQN),
+						Title:         title,
+						Description:   strVal(rel.Verb), // Keep Verb in Description if needed, or swap based on semantics
+						Tags:          rel.Tags,
+						Bidirectional: rel.Bidirectional,
 					})
 					relIndex++
 				}
@@ -193,11 +214,14 @@ func (e *Exporter) convertRelationsFromModel(dump *SrujaModelDump, model *langua
 			}
 
 			dump.Relations = append(dump.Relations, RelationDump{
-				ID:          fmt.Sprintf("rel-%d", relIndex),
-				Source:      NewFqnRef(fromFQN),
-				Target:      NewFqnRef(toFQN),
-				Title:       title,
-				Description: strVal(item.Relation.Verb),
+				ID:            fmt.Sprintf("rel-%d", relIndex),
+				Source:        NewFqnRef(fromFQN),
+				Target:        NewFqnRef(toFQN),
+				Title:         title,
+				Description:   strVal(item.Relation.Verb),
+				Tags:          item.Relation.Tags,
+				Bidirectional: item.Relation.Bidirectional,
+				Implied:       item.Relation.Implied,
 			})
 			relIndex++
 		}
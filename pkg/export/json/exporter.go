@@ -81,6 +81,7 @@ func (e *Exporter) ToModelDump(program *language.Program) *SrujaModelDump {
 
 		// Convert relations
 		e.convertRelationsFromModel(dump, program.Model)
+		applyRelationshipKinds(dump.Relations, dump.Specification)
 	}
 
 	// Convert views
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/sruja-ai/sruja/pkg/engine"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -210,3 +211,154 @@ func TestExtractTechnology(t *testing.T) {
 		t.Error("expected nil")
 	}
 }
+
+func TestExporter_ViewExpressions(t *testing.T) {
+	dsl := `
+User = person "User"
+Shop = system "Shop" {
+	API = container "API"
+	DB = database "DB"
+}
+Payments = system "Payments"
+User -> Shop.API "Uses"
+Shop.API -> Shop.DB "Reads"
+Shop.API -> Payments "Charges"
+
+view deps of Shop {
+	include Shop.API ->
+	exclude Shop.API -> Payments
+}
+`
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	view, ok := NewExporter().ToModelDump(prog).Views["deps"]
+	if !ok {
+		t.Fatal("expected view 'deps'")
+	}
+	if len(view.Rules) != 2 || len(view.Rules[0].Include.Expressions) != 1 || view.Rules[0].Include.Expressions[0] != "Shop.API ->" {
+		t.Errorf("unexpected rules: %+v", view.Rules)
+	}
+
+	nodes := make(map[string]string)
+	for _, n := range view.Nodes {
+		nodes[n.ID] = n.Parent
+	}
+	if len(nodes) != 3 || nodes["Shop.API"] != "" || nodes["Shop.DB"] != "" {
+		t.Errorf("expected nodes Shop.API, Shop.DB and Payments without parents, got %v", nodes)
+	}
+	if len(view.Edges) != 1 || view.Edges[0].Source != "Shop.API" || view.Edges[0].Target != "Shop.DB" {
+		t.Errorf("expected only Shop.API -> Shop.DB edge, got %+v", view.Edges)
+	}
+}
+
+func TestExporter_BidirectionalRelationKind(t *testing.T) {
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", `
+A = system "A"
+B = system "B"
+A <-> B "Syncs"
+A -> B "Calls"
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	rels := NewExporter().ToModelDump(prog).Relations
+	if len(rels) != 2 {
+		t.Fatalf("expected 2 relations, got %d", len(rels))
+	}
+	if !rels[0].Bidirectional || rels[0].Kind != "" {
+		t.Errorf("expected bidirectional relation without kind, got %+v", rels[0])
+	}
+	if rels[1].Bidirectional {
+		t.Errorf("expected directed relation, got %+v", rels[1])
+	}
+}
+
+func TestExporter_NestedRelationToOuterElement(t *testing.T) {
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", `
+Shop = system "Shop" {
+	API = container "API" {
+		Handler = component "Handler"
+		Handler -> Notify "Sends"
+	}
+}
+Notify = system "Notify"
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	engine.RunResolution(prog)
+
+	rels := NewExporter().ToModelDump(prog).Relations
+	if len(rels) == 0 || rels[0].Source.Model != "Shop.API.Handler" || rels[0].Target.Model != "Notify" {
+		t.Errorf("expected Shop.API.Handler -> Notify, got %+v", rels)
+	}
+}
+
+func TestExporter_RelationshipKinds(t *testing.T) {
+	parser, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := parser.Parse("test.sruja", `
+publishes = relationship "Publishes" {
+	technology "Kafka"
+	async
+	from [system]
+	to [queue]
+}
+replicates = relationship "Replicates" {
+	style { line dotted color "#999999" }
+}
+Orders = system "Orders"
+Events = queue "Events"
+Replica = database "Replica"
+Orders -> Events "Order placed" [publishes]
+Orders <-> Replica [replicates]
+Orders -> Replica "Reads" [reads]
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	dump := NewExporter().ToModelDump(prog)
+	publishes, ok := dump.Specification.Relationships["publishes"]
+	if !ok {
+		t.Fatalf("expected publishes in specification, got %+v", dump.Specification.Relationships)
+	}
+	if publishes.Title != "Publishes" || publishes.Technology != "Kafka" || !publishes.Async || publishes.Line != "dashed" {
+		t.Errorf("unexpected publishes kind: %+v", publishes)
+	}
+	if len(publishes.From) != 1 || publishes.From[0] != "system" || len(publishes.To) != 1 || publishes.To[0] != "queue" {
+		t.Errorf("unexpected publishes element kinds: from %v to %v", publishes.From, publishes.To)
+	}
+
+	rels := dump.Relations
+	if len(rels) != 3 {
+		t.Fatalf("expected 3 relations, got %d", len(rels))
+	}
+	if rels[0].Kind != "publishes" || rels[0].Line != "dashed" || rels[0].Technology != "Kafka" {
+		t.Errorf("expected dashed Kafka publishes relation, got %+v", rels[0])
+	}
+	if rels[1].Kind != "replicates" || !rels[1].Bidirectional || rels[1].Line != "dotted" || rels[1].Color != "#999999" {
+		t.Errorf("expected dotted bidirectional replicates relation, got %+v", rels[1])
+	}
+	if rels[2].Kind != "" || rels[2].Line != "" {
+		t.Errorf("expected plain relation for built-in verb, got %+v", rels[2])
+	}
+}
@@ -1,6 +1,8 @@
 package json
 
 import (
+	"strings"
+
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -24,83 +26,35 @@ func (e *Exporter) buildSrujaExtensionsFromProgram(program *language.Program) *S
 			switch a.Kind {
 			case "requirement", "Requirement":
 				hasContent = true
-				ext.Requirements = append(ext.Requirements, RequirementDump{
-					ID:          a.Name,
-					Title:       ptrToString(a.Title),
-					Type:        ptrToString(a.SubKind),
-					Description: ptrToString(a.Title),
-				})
+				ext.Requirements = append(ext.Requirements, requirementDump(a))
 			case "adr", "Adr", "ADR":
 				hasContent = true
-				adr := ADRDump{
-					ID:    a.Name,
-					Title: ptrToString(a.Title),
-				}
-				// Extract body fields if present
-				if a.Body != nil {
-					for _, bodyItem := range a.Body.Items {
-						if bodyItem.Metadata != nil {
-							for _, entry := range bodyItem.Metadata.Entries {
-								switch entry.Key {
-								case "status":
-									adr.Status = ptrToString(entry.Value)
-								case "context":
-									adr.Context = ptrToString(entry.Value)
-								case "decision":
-									adr.Decision = ptrToString(entry.Value)
-								case "consequences":
-									adr.Consequences = ptrToString(entry.Value)
-								}
-							}
-						}
-					}
-				}
-				ext.ADRs = append(ext.ADRs, adr)
+				ext.ADRs = append(ext.ADRs, adrDump(a))
 			case "policy", "Policy":
 				hasContent = true
-				pol := PolicyDump{
-					ID:    a.Name,
-					Title: ptrToString(a.Title),
-				}
-				// Extract category and enforcement from body
-				if a.Body != nil {
-					for _, bodyItem := range a.Body.Items {
-						if bodyItem.Metadata != nil {
-							for _, entry := range bodyItem.Metadata.Entries {
-								switch entry.Key {
-								case "category":
-									pol.Category = ptrToString(entry.Value)
-								case "enforcement":
-									pol.Enforcement = ptrToString(entry.Value)
-								}
-							}
-						}
-					}
-				}
-				ext.Policies = append(ext.Policies, pol)
+				ext.Policies = append(ext.Policies, policyDump(a))
 			case "scenario", "Scenario", "story", "Story":
 				hasContent = true
-				sc := ScenarioDump{
-					ID:    a.Name,
-					Title: ptrToString(a.Title),
-				}
-				ext.Scenarios = append(ext.Scenarios, sc)
+				ext.Scenarios = append(ext.Scenarios, ScenarioDump{
+					ID:          a.Name,
+					Title:       ptrToString(a.Title),
+					Description: bodyDescription(a.Body),
+					Steps:       stepDumps(a.Body),
+				})
 			case "flow", "Flow":
 				hasContent = true
 				ext.Flows = append(ext.Flows, FlowDump{
-					ID:    a.Name,
-					Title: ptrToString(a.Title),
+					ID:          a.Name,
+					Title:       ptrToString(a.Title),
+					Description: bodyDescription(a.Body),
+					Steps:       stepDumps(a.Body),
 				})
 			}
 		}
 
 		if item.DeploymentNode != nil {
 			hasContent = true
-			d := item.DeploymentNode
-			ext.Deployments = append(ext.Deployments, DeploymentDump{
-				ID:    d.ID,
-				Title: d.Label,
-			})
+			ext.Deployments = append(ext.Deployments, deploymentDump(item.DeploymentNode))
 		}
 
 		if item.ConstraintsBlock != nil {
@@ -138,6 +92,172 @@ func (e *Exporter) buildSrujaExtensionsFromProgram(program *language.Program) *S
 	return ext
 }
 
+// requirementDump converts a requirement. Priority and the elements it applies to
+// are read from its metadata block.
+func requirementDump(a *language.ElementAssignment) RequirementDump {
+	req := RequirementDump{
+		ID:          a.Name,
+		Title:       ptrToString(a.Title),
+		Type:        ptrToString(a.SubKind),
+		Description: bodyDescription(a.Body),
+	}
+	if a.Body == nil {
+		return req
+	}
+	for _, bodyItem := range a.Body.Items {
+		if bodyItem.Status != nil {
+			req.Status = *bodyItem.Status
+		}
+		if bodyItem.Metadata == nil {
+			continue
+		}
+		for _, entry := range bodyItem.Metadata.Entries {
+			switch entry.Key {
+			case "priority":
+				req.Priority = ptrToString(entry.Value)
+			case "status":
+				req.Status = ptrToString(entry.Value)
+			case "elements":
+				req.Elements = entry.Array
+			}
+		}
+	}
+	return req
+}
+
+// adrDump converts an ADR. Date and author are read from its metadata block, which
+// older models also used for the other fields.
+func adrDump(a *language.ElementAssignment) ADRDump {
+	adr := ADRDump{
+		ID:    a.Name,
+		Title: ptrToString(a.Title),
+	}
+	if a.Body == nil {
+		return adr
+	}
+	for _, bodyItem := range a.Body.Items {
+		switch {
+		case bodyItem.Status != nil:
+			adr.Status = *bodyItem.Status
+		case bodyItem.Context != nil:
+			adr.Context = *bodyItem.Context
+		case bodyItem.Decision != nil:
+			adr.Decision = *bodyItem.Decision
+		case bodyItem.Consequences != nil:
+			adr.Consequences = *bodyItem.Consequences
+		case bodyItem.Metadata != nil:
+			for _, entry := range bodyItem.Metadata.Entries {
+				switch entry.Key {
+				case "status":
+					adr.Status = ptrToString(entry.Value)
+				case "context":
+					adr.Context = ptrToString(entry.Value)
+				case "decision":
+					adr.Decision = ptrToString(entry.Value)
+				case "consequences":
+					adr.Consequences = ptrToString(entry.Value)
+				case "date":
+					adr.Date = ptrToString(entry.Value)
+				case "author":
+					adr.Author = ptrToString(entry.Value)
+				}
+			}
+		}
+	}
+	return adr
+}
+
+// policyDump converts a policy. The category is its sub-kind or category field.
+func policyDump(a *language.ElementAssignment) PolicyDump {
+	pol := PolicyDump{
+		ID:          a.Name,
+		Title:       ptrToString(a.Title),
+		Category:    ptrToString(a.SubKind),
+		Description: bodyDescription(a.Body),
+	}
+	if a.Body == nil {
+		return pol
+	}
+	for _, bodyItem := range a.Body.Items {
+		switch {
+		case bodyItem.Category != nil:
+			pol.Category = *bodyItem.Category
+		case bodyItem.Enforcement != nil:
+			pol.Enforcement = *bodyItem.Enforcement
+		case bodyItem.Metadata != nil:
+			for _, entry := range bodyItem.Metadata.Entries {
+				switch entry.Key {
+				case "category":
+					pol.Category = ptrToString(entry.Value)
+				case "enforcement":
+					pol.Enforcement = ptrToString(entry.Value)
+				case "elements":
+					pol.Elements = entry.Array
+				}
+			}
+		}
+	}
+	return pol
+}
+
+// stepDumps converts the steps of a scenario or flow.
+func stepDumps(body *language.ElementDefBody) []StepDump {
+	if body == nil {
+		return nil
+	}
+	var steps []StepDump
+	for _, bodyItem := range body.Items {
+		if bodyItem.Step == nil {
+			continue
+		}
+		steps = append(steps, StepDump{
+			Description: ptrToString(bodyItem.Step.Description),
+			From:        strings.Join(bodyItem.Step.FromParts, "."),
+			To:          strings.Join(bodyItem.Step.ToParts, "."),
+		})
+	}
+	return steps
+}
+
+// deploymentDump converts a deployment node with its nested nodes, infrastructure
+// nodes and container instances.
+func deploymentDump(node *language.DeploymentNode) DeploymentDump {
+	d := DeploymentDump{
+		ID:          node.ID,
+		Kind:        node.Type,
+		Title:       node.Label,
+		Description: ptrToString(node.Description),
+	}
+	for _, item := range node.Items {
+		switch {
+		case item.Node != nil:
+			d.Children = append(d.Children, deploymentDump(item.Node))
+		case item.Infrastructure != nil:
+			d.Children = append(d.Children, DeploymentDump{
+				ID:          item.Infrastructure.ID,
+				Kind:        "infrastructure",
+				Title:       item.Infrastructure.Label,
+				Description: ptrToString(item.Infrastructure.Description),
+			})
+		case item.ContainerInstance != nil:
+			d.Instances = append(d.Instances, item.ContainerInstance.ContainerID)
+		}
+	}
+	return d
+}
+
+func bodyDescription(body *language.ElementDefBody) string {
+	if body == nil {
+		return ""
+	}
+	for _, bodyItem := range body.Items {
+		if bodyItem.Description != nil {
+			return *bodyItem.Description
+		}
+	}
+	return ""
+}
+
 func (e *Exporter) buildSpecification(program *language.Program) SpecificationDump {
 	spec := SpecificationDump{
 		Elements: map[string]ElementKindDump{
@@ -149,6 +269,14 @@ func (e *Exporter) buildSpecification(program *language.Program) SpecificationDu
 			"queue":     {Title: "Queue"},
 		},
 	}
+	if program != nil {
+		for _, kind := range program.Specification.RelationshipKinds() {
+			if spec.Relationships == nil {
+				spec.Relationships = make(map[string]RelationshipKindDump)
+			}
+			spec.Relationships[kind.Name] = relationshipKindDump(kind)
+		}
+	}
 	// Add project to specification if available
 	if program != nil {
 		modelName := "sruja-project"
@@ -162,3 +290,87 @@ func (e *Exporter) buildSpecification(program *language.Program) SpecificationDu
 	}
 	return spec
 }
+
+// relationshipKindDump converts a relationship kind declaration. Asynchronous
+// kinds are drawn dashed unless their style says otherwise.
+func relationshipKindDump(def *language.RelationshipKindDef) RelationshipKindDump {
+	dump := RelationshipKindDump{
+		Title:       def.GetLabel(),
+		Description: def.GetDescription(),
+		Technology:  def.GetTechnology(),
+		Async:       def.IsAsync(),
+		From:        def.SourceKinds(),
+		To:          def.TargetKinds(),
+	}
+	if style := def.GetStyle(); style != nil {
+		for _, entry := range style.Entries {
+			value := ptrToString(entry.Value)
+			switch strings.ToLower(entry.Key) {
+			case "line", "style":
+				dump.Line = strings.ToLower(value)
+			case "dashed":
+				if value == "" || strings.EqualFold(value, "true") {
+					dump.Line = "dashed"
+				}
+			case "color", "colour":
+				dump.Color = value
+			case "head":
+				dump.Head = value
+			case "tail":
+				dump.Tail = value
+			}
+		}
+	}
+	if dump.Line == "" && dump.Async {
+		dump.Line = "dashed"
+	}
+	return dump
+}
+
+// applyRelationshipKinds gives relations tagged with a declared relationship
+// kind that kind and its default technology and style.
+func applyRelationshipKinds(relations []RelationDump, spec SpecificationDump) {
+	if len(spec.Relationships) == 0 {
+		return
+	}
+	for i := range relations {
+		rel := &relations[i]
+		for _, tag := range rel.Tags {
+			name, kind, ok := lookupRelationshipKind(spec.Relationships, tag)
+			if !ok {
+				continue
+			}
+			rel.Kind = name
+			if rel.Technology == "" {
+				rel.Technology = kind.Technology
+			}
+			if rel.Line == "" {
+				rel.Line = kind.Line
+			}
+			if rel.Color == "" {
+				rel.Color = kind.Color
+			}
+			if rel.Head == "" {
+				rel.Head = kind.Head
+			}
+			if rel.Tail == "" {
+				rel.Tail = kind.Tail
+			}
+			break
+		}
+	}
+}
+
+// lookupRelationshipKind finds the kind a relation tag selects; tags match
+// kind names case-insensitively.
+func lookupRelationshipKind(kinds map[string]RelationshipKindDump, tag string) (string, RelationshipKindDump, bool) {
+	if kind, ok := kinds[tag]; ok {
+		return tag, kind, true
+	}
+	for name, kind := range kinds {
+		if strings.EqualFold(name, tag) {
+			return name, kind, true
+		}
+	}
+	return "", RelationshipKindDump{}, false
+}
@@ -1,6 +1,308 @@
 package json
 
-// stringToQualifiedIdent converts a string to a QualifiedIdent
+import (
+	"fmt"
+	"strings"
+)
 
-// ToArchitecture removed - Architecture struct removed (old syntax no longer supported)
-// Use JSON importers instead
+// ArchitectureToModelDump converts the architecture JSON format to a model dump, so
+// both JSON formats can be printed back to DSL. Nested relations are resolved
+// against the element they are declared in, and layout positions become the
+// positions of the index view.
+func ArchitectureToModelDump(arch *ArchitectureJSON) *SrujaModelDump {
+	dump := &SrujaModelDump{
+		Elements:  make(map[string]ElementDump),
+		Relations: []RelationDump{},
+		Views:     make(map[string]ViewDump),
+		Metadata:  ModelMetadata{Name: arch.Metadata.Name, Version: arch.Metadata.Version},
+	}
+	imp := &architectureImporter{dump: dump}
+	body := &arch.Architecture
+
+	for i := range body.Persons {
+		p := &body.Persons[i]
+		imp.addElement(ElementDump{
+			ID:          p.ID,
+			Kind:        "person",
+			Title:       p.Label,
+			Description: strVal(p.Description),
+			Metadata:    metadataMap(p.Metadata),
+		})
+	}
+	for i := range body.Systems {
+		imp.addSystem(&body.Systems[i])
+	}
+	for i := range body.Containers {
+		imp.addContainer(&body.Containers[i], "")
+	}
+	for i := range body.Components {
+		imp.addComponent(&body.Components[i], "")
+	}
+	for i := range body.DataStores {
+		imp.addDataStore(&body.DataStores[i], "")
+	}
+	for i := range body.Queues {
+		imp.addQueue(&body.Queues[i], "")
+	}
+	imp.addRelations(body.Relations, "")
+	imp.resolveRelations()
+
+	ext := &SrujaExtensions{}
+	for _, req := range body.Requirements {
+		title := req.Title
+		if title == "" {
+			title = req.Description
+		}
+		ext.Requirements = append(ext.Requirements, RequirementDump{
+			ID:          req.ID,
+			Title:       title,
+			Type:        req.Type,
+			Description: req.Description,
+		})
+	}
+	for _, adr := range body.ADRs {
+		ext.ADRs = append(ext.ADRs, ADRDump{
+			ID:           adr.ID,
+			Title:        adr.Title,
+			Status:       strVal(adr.Status),
+			Context:      strVal(adr.Context),
+			Decision:     strVal(adr.Decision),
+			Consequences: strVal(adr.Consequences),
+		})
+	}
+	for _, pol := range body.Policies {
+		ext.Policies = append(ext.Policies, PolicyDump{
+			ID:          pol.ID,
+			Title:       idOrLabel(pol.Label, pol.Description),
+			Category:    pol.Category,
+			Enforcement: pol.Enforcement,
+			Description: pol.Description,
+		})
+	}
+	for _, sc := range body.Scenarios {
+		ext.Scenarios = append(ext.Scenarios, ScenarioDump{
+			ID:          sc.ID,
+			Title:       idOrLabel(sc.Title, sc.Label),
+			Description: strVal(sc.Description),
+			Steps:       scenarioSteps(sc.Steps),
+		})
+	}
+	for _, flow := range body.Flows {
+		ext.Flows = append(ext.Flows, FlowDump{
+			ID:          flow.ID,
+			Title:       idOrLabel(flow.Title, flow.Label),
+			Description: strVal(flow.Description),
+			Steps:       scenarioSteps(flow.Steps),
+		})
+	}
+	for _, node := range body.Deployment {
+		ext.Deployments = append(ext.Deployments, DeploymentDump{ID: node.ID, Kind: "deployment", Title: node.Label})
+	}
+	ext.Constraints = append(ext.Constraints, imp.constraints...)
+	for _, c := range body.Constraints {
+		ext.Constraints = append(ext.Constraints, ConstraintDump{ID: c.Key, Description: c.Value})
+	}
+	ext.Conventions = append(ext.Conventions, imp.conventions...)
+	for _, c := range body.Conventions {
+		ext.Conventions = append(ext.Conventions, ConventionDump{ID: c.Key, Description: c.Value})
+	}
+	if len(ext.Requirements)+len(ext.ADRs)+len(ext.Policies)+len(ext.Scenarios)+len(ext.Flows)+
+		len(ext.Deployments)+len(ext.Constraints)+len(ext.Conventions) > 0 {
+		dump.Sruja = ext
+	}
+
+	if len(arch.Metadata.Layout) > 0 {
+		positions := make(map[string]ViewPositionDump, len(arch.Metadata.Layout))
+		for id, layout := range arch.Metadata.Layout {
+			positions[id] = ViewPositionDump{X: float64(layout.X), Y: float64(layout.Y)}
+		}
+		dump.Views["index"] = ViewDump{
+			ID:     "index",
+			Title:  "Index",
+			Rules:  []ViewRule{{Include: &ViewRuleExpr{Wildcard: true}}},
+			Nodes:  []NodeDump{},
+			Edges:  []EdgeDump{},
+			Layout: &ViewLayoutDump{Positions: positions},
+		}
+	}
+	return dump
+}
+
+// architectureImporter collects the elements and relations of an architecture.
+type architectureImporter struct {
+	dump        *SrujaModelDump
+	pending     []pendingRelation
+	constraints []ConstraintDump
+	conventions []ConventionDump
+}
+
+// pendingRelation is a relation whose endpoints are resolved once all elements are
+// known.
+type pendingRelation struct {
+	rel   RelationJSON
+	scope string
+}
+
+func (imp *architectureImporter) addElement(el ElementDump) string {
+	if el.Parent != "" {
+		el.ID = el.Parent + "." + el.ID
+	}
+	imp.dump.Elements[el.ID] = el
+	return el.ID
+}
+
+func (imp *architectureImporter) addSystem(s *SystemJSON) {
+	fqn := imp.addElement(ElementDump{
+		ID:          s.ID,
+		Kind:        "system",
+		Title:       s.Label,
+		Description: strVal(s.Description),
+		Metadata:    metadataMap(s.Metadata),
+		SLO:         s.SLO,
+	})
+	for i := range s.Containers {
+		imp.addContainer(&s.Containers[i], fqn)
+	}
+	for i := range s.Components {
+		imp.addComponent(&s.Components[i], fqn)
+	}
+	for i := range s.DataStores {
+		imp.addDataStore(&s.DataStores[i], fqn)
+	}
+	for i := range s.Queues {
+		imp.addQueue(&s.Queues[i], fqn)
+	}
+	imp.addRelations(s.Relations, fqn)
+	for _, c := range s.Constraints {
+		imp.constraints = append(imp.constraints, ConstraintDump{ID: c.Key, Description: c.Value})
+	}
+	for _, c := range s.Conventions {
+		imp.conventions = append(imp.conventions, ConventionDump{ID: c.Key, Description: c.Value})
+	}
+}
+
+func (imp *architectureImporter) addContainer(c *ContainerJSON, parent string) {
+	fqn := imp.addElement(ElementDump{
+		ID:          c.ID,
+		Kind:        "container",
+		Title:       c.Label,
+		Description: strVal(c.Description),
+		Technology:  strVal(c.Technology),
+		Tags:        c.Tags,
+		Metadata:    metadataMap(c.Metadata),
+		SLO:         c.SLO,
+		Parent:      parent,
+	})
+	for i := range c.Components {
+		imp.addComponent(&c.Components[i], fqn)
+	}
+	for i := range c.DataStores {
+		imp.addDataStore(&c.DataStores[i], fqn)
+	}
+	for i := range c.Queues {
+		imp.addQueue(&c.Queues[i], fqn)
+	}
+	imp.addRelations(c.Relations, fqn)
+}
+
+func (imp *architectureImporter) addComponent(c *ComponentJSON, parent string) {
+	fqn := imp.addElement(ElementDump{
+		ID:          c.ID,
+		Kind:        "component",
+		Title:       c.Label,
+		Description: strVal(c.Description),
+		Technology:  strVal(c.Technology),
+		Metadata:    metadataMap(c.Metadata),
+		Parent:      parent,
+	})
+	imp.addRelations(c.Relations, fqn)
+}
+
+func (imp *architectureImporter) addDataStore(d *DataStoreJSON, parent string) {
+	imp.addElement(ElementDump{
+		ID:          d.ID,
+		Kind:        "database",
+		Title:       d.Label,
+		Description: strVal(d.Description),
+		Technology:  strVal(d.Technology),
+		Metadata:    metadataMap(d.Metadata),
+		Parent:      parent,
+	})
+}
+
+func (imp *architectureImporter) addQueue(q *QueueJSON, parent string) {
+	imp.addElement(ElementDump{
+		ID:          q.ID,
+		Kind:        "queue",
+		Title:       q.Label,
+		Description: strVal(q.Description),
+		Technology:  strVal(q.Technology),
+		Metadata:    metadataMap(q.Metadata),
+		Parent:      parent,
+	})
+}
+
+func (imp *architectureImporter) addRelations(rels []RelationJSON, scope string) {
+	for _, rel := range rels {
+		imp.pending = append(imp.pending, pendingRelation{rel: rel, scope: scope})
+	}
+}
+
+// resolveRelations adds the collected relations with their endpoints resolved to
+// FQNs. Titles and descriptions follow the JSON exporter: the title is the label,
+// falling back to the verb, and the description is the verb.
+func (imp *architectureImporter) resolveRelations() {
+	for _, p := range imp.pending {
+		title := strVal(p.rel.Label)
+		if title == "" {
+			title = strVal(p.rel.Verb)
+		}
+		imp.dump.Relations = append(imp.dump.Relations, RelationDump{
+			ID:          fmt.Sprintf("rel-%d", len(imp.dump.Relations)),
+			Source:      NewFqnRef(imp.resolve(p.rel.From, p.scope)),
+			Target:      NewFqnRef(imp.resolve(p.rel.To, p.scope)),
+			Title:       title,
+			Description: strVal(p.rel.Verb),
+			Tags:        p.rel.Tags,
+		})
+	}
+}
+
+// resolve resolves a reference relative to the scope it is declared in, trying the
+// scope and each of its ancestors before the reference itself.
+func (imp *architectureImporter) resolve(ref, scope string) string {
+	for scope != "" {
+		if _, ok := imp.dump.Elements[scope+"."+ref]; ok {
+			return scope + "." + ref
+		}
+		i := strings.LastIndex(scope, ".")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	return ref
+}
+
+func scenarioSteps(steps []ScenarioStepJSON) []StepDump {
+	var out []StepDump
+	for _, step := range steps {
+		out = append(out, StepDump{From: step.From, To: step.To, Description: strVal(step.Description)})
+	}
+	return out
+}
+
+func metadataMap(meta []MetadataEntryJSON) map[string]string {
+	if len(meta) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(meta))
+	for _, e := range meta {
+		if e.Value != nil {
+			m[e.Key] = *e.Value
+		} else if len(e.Array) > 0 {
+			m[e.Key] = strings.Join(e.Array, ",")
+		}
+	}
+	return m
+}
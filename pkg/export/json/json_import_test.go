@@ -0,0 +1,63 @@
+package json
+
+import "testing"
+
+func TestArchitectureToModelDump(t *testing.T) {
+	desc := "Handles requests"
+	verb := "reads"
+	arch := &ArchitectureJSON{
+		Metadata: MetadataJSON{Name: "Shop", Layout: map[string]LayoutData{"Shop": {X: 10, Y: 20}}},
+		Architecture: ArchitectureBody{
+			Persons: []PersonJSON{{ID: "User", Label: "User"}},
+			Systems: []SystemJSON{{
+				ID:    "Shop",
+				Label: "Shop",
+				Containers: []ContainerJSON{{
+					ID:          "API",
+					Label:       "API",
+					Description: &desc,
+					Components:  []ComponentJSON{{ID: "Handler", Label: "Handler"}},
+				}},
+				DataStores: []DataStoreJSON{{ID: "DB", Label: "DB"}},
+				Relations:  []RelationJSON{{From: "API", To: "DB", Verb: &verb}},
+			}},
+			Relations:    []RelationJSON{{From: "User", To: "Shop.API"}},
+			Requirements: []RequirementJSON{{ID: "R1", Type: "performance", Description: "Fast"}},
+		},
+	}
+
+	dump := ArchitectureToModelDump(arch)
+
+	for fqn, kind := range map[string]string{
+		"User":             "person",
+		"Shop":             "system",
+		"Shop.API":         "container",
+		"Shop.API.Handler": "component",
+		"Shop.DB":          "database",
+	} {
+		if el, ok := dump.Elements[fqn]; !ok || el.Kind != kind {
+			t.Errorf("Expected %s element %s, got %+v", kind, fqn, el)
+		}
+	}
+	if dump.Elements["Shop.API"].Parent != "Shop" || dump.Elements["Shop.API"].Description != desc {
+		t.Errorf("Unexpected API element: %+v", dump.Elements["Shop.API"])
+	}
+
+	if len(dump.Relations) != 2 {
+		t.Fatalf("Expected 2 relations, got %d", len(dump.Relations))
+	}
+	if rel := dump.Relations[0]; rel.Source.Model != "Shop.API" || rel.Target.Model != "Shop.DB" || rel.Title != "reads" {
+		t.Errorf("Expected nested relation resolved to FQNs, got %+v", rel)
+	}
+	if rel := dump.Relations[1]; rel.Source.Model != "User" || rel.Target.Model != "Shop.API" {
+		t.Errorf("Unexpected top-level relation: %+v", rel)
+	}
+
+	if dump.Sruja == nil || len(dump.Sruja.Requirements) != 1 || dump.Sruja.Requirements[0].Title != "Fast" {
+		t.Errorf("Expected requirement R1, got %+v", dump.Sruja)
+	}
+	index, ok := dump.Views["index"]
+	if !ok || index.Layout == nil || index.Layout.Positions["Shop"] != (ViewPositionDump{X: 10, Y: 20}) {
+		t.Errorf("Expected layout positions in the index view, got %+v", index)
+	}
+}
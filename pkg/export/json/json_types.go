@@ -267,6 +267,7 @@ type SLOJSON struct {
 	Latency      *SLOLatencyJSON      `json:"latency,omitempty"`
 	ErrorRate    *SLOErrorRateJSON    `json:"errorRate,omitempty"`
 	Throughput   *SLOThroughputJSON   `json:"throughput,omitempty"`
+	Cost         *SLOCostJSON         `json:"cost,omitempty"`
 }
 
 type SLOAvailabilityJSON struct {
@@ -299,6 +300,11 @@ type SLOThroughputJSON struct {
 	Current *string `json:"current,omitempty"`
 }
 
+type SLOCostJSON struct {
+	Target string `json:"target"`
+	Window string `json:"window"`
+}
+
 type ScaleJSON struct {
 	Min    *int    `json:"min,omitempty"`
 	Max    *int    `json:"max,omitempty"`
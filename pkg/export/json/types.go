@@ -66,8 +66,18 @@ type ElementKindDump struct {
 }
 
 type RelationshipKindDump struct {
-	Title      string `json:"title,omitempty"`
-	Technology string `json:"technology,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Technology  string `json:"technology,omitempty"`
+	Async       bool   `json:"async,omitempty"`
+	// Styling of relations of this kind
+	Color string `json:"color,omitempty"`
+	Line  string `json:"line,omitempty"` // "solid", "dashed", "dotted"
+	Head  string `json:"head,omitempty"`
+	Tail  string `json:"tail,omitempty"`
+	// Element kinds relations of this kind may connect; empty allows any
+	From []string `json:"from,omitempty"`
+	To   []string `json:"to,omitempty"`
 }
 
 type TagDump struct {
@@ -85,6 +95,7 @@ type ElementDump struct {
 	Links       []LinkDump        `json:"links,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 	Style       *StyleDump        `json:"style,omitempty"`
+	SLO         *SLOJSON          `json:"slo,omitempty"`
 	Parent      string            `json:"parent,omitempty"` // Parent FQN
 }
 
@@ -121,9 +132,14 @@ type RelationDump struct {
 	Title       string            `json:"title,omitempty"`
 	Description string            `json:"description,omitempty"`
 	Technology  string            `json:"technology,omitempty"`
-	Kind        string            `json:"kind,omitempty"`
+	Kind        string            `json:"kind,omitempty"` // declared relationship kind
 	Tags        []string          `json:"tags,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Bidirectional is set for <-> relations
+	Bidirectional bool `json:"bidirectional,omitempty"`
+	// Implied is set for parent relations inferred from a relation between
+	// nested elements
+	Implied bool `json:"implied,omitempty"`
 	// Styling
 	Color string `json:"color,omitempty"`
 	Line  string `json:"line,omitempty"` // "solid", "dashed", "dotted"
@@ -166,6 +182,8 @@ type ViewRuleExpr struct {
 	Wildcard  bool     `json:"wildcard,omitempty"`
 	Recursive bool     `json:"recursive,omitempty"`
 	Elements  []string `json:"elements,omitempty"`
+	// Expressions holds relationship patterns and element filters, e.g. "-> api ->"
+	Expressions []string `json:"expressions,omitempty"`
 }
 
 type NodeDump struct {
@@ -3,6 +3,7 @@ package json
 import (
 	"fmt"
 
+	"github.com/sruja-ai/sruja/pkg/export/views"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -86,51 +87,31 @@ func (e *Exporter) convertViewsFromProgram(dump *SrujaModelDump, program *langua
 				viewOf = v.Of.String()
 			}
 
-			// Capture rules if body is present
+			// Capture rules and layout positions if body is present
 			var rules []ViewRule
-			var layoutPositions map[string]map[string]interface{}
+			var positions map[string]ViewPositionDump
 			if v.Body != nil {
 				for _, bitem := range v.Body.Items {
+					if bitem.Title != nil && v.Title == nil {
+						viewTitle = *bitem.Title
+					}
 					if bitem.Include != nil {
-						expr := &ViewRuleExpr{}
-						for _, vexpr := range bitem.Include.Expressions {
-							switch {
-							case vexpr.Wildcard:
-								expr.Wildcard = true
-							case vexpr.Recursive:
-								expr.Recursive = true
-							case vexpr.Selector != nil:
-								expr.Elements = append(expr.Elements, *vexpr.Selector)
-							}
-						}
-						rules = append(rules, ViewRule{Include: expr})
+						rules = append(rules, ViewRule{Include: convertViewRuleExpr(bitem.Include.Expressions)})
 					}
 					if bitem.Exclude != nil {
-						expr := &ViewRuleExpr{}
-						for _, vexpr := range bitem.Exclude.Expressions {
-							switch {
-							case vexpr.Wildcard:
-								expr.Wildcard = true
-							case vexpr.Recursive:
-								expr.Recursive = true
-							case vexpr.Selector != nil:
-								expr.Elements = append(expr.Elements, *vexpr.Selector)
-							}
-						}
-						rules = append(rules, ViewRule{Exclude: expr})
+						rules = append(rules, ViewRule{Exclude: convertViewRuleExpr(bitem.Exclude.Expressions)})
 					}
-					// Capture layout positions if present
 					if bitem.Layout != nil {
-						if layoutPositions == nil {
-							layoutPositions = make(map[string]map[string]interface{})
-						}
 						for _, elLayout := range bitem.Layout.Elements {
-							if elLayout.Position != nil {
-								elementID := elLayout.Element.String()
-								layoutPositions[elementID] = map[string]interface{}{
-									"x": elLayout.Position.X,
-									"y": elLayout.Position.Y,
-								}
+							if elLayout.Position == nil {
+								continue
+							}
+							if positions == nil {
+								positions = make(map[string]ViewPositionDump)
+							}
+							positions[elLayout.Element.String()] = ViewPositionDump{
+								X: elLayout.Position.X(),
+								Y: elLayout.Position.Y(),
 							}
 						}
 					}
@@ -153,33 +134,84 @@ func (e *Exporter) convertViewsFromProgram(dump *SrujaModelDump, program *langua
 				Edges:       []EdgeDump{}, // Critical: Initialize as empty slice to avoid null
 			}
 
-			// Add layout positions if present
-			// Store in view metadata format that frontend expects
+			// Compute the visible nodes and edges from the include/exclude expressions
+			if graph, err := views.ResolveView(program, v); err == nil {
+				viewDump.Nodes, viewDump.Edges = viewGraphDump(program, graph)
+			}
+
 			// Frontend expects: views[viewKey].layout.positions[nodeId]
-			if len(layoutPositions) > 0 {
-				// Convert layoutPositions map to ViewLayoutDump format
-				positions := make(map[string]ViewPositionDump)
-				for elementID, posMap := range layoutPositions {
-					// posMap is map[string]interface{} from the loop above
-					var x, y float64
-					if xVal, ok := posMap["x"].(float64); ok {
-						x = xVal
-					} else if xVal, ok := posMap["x"].(int); ok {
-						x = float64(xVal)
-					}
-					if yVal, ok := posMap["y"].(float64); ok {
-						y = yVal
-					} else if yVal, ok := posMap["y"].(int); ok {
-						y = float64(yVal)
-					}
-					positions[elementID] = ViewPositionDump{X: x, Y: y}
-				}
-				viewDump.Layout = &ViewLayoutDump{
-					Positions: positions,
-				}
+			if len(positions) > 0 {
+				viewDump.Layout = &ViewLayoutDump{Positions: positions}
 			}
 
 			dump.Views[viewID] = viewDump
 		}
 	}
 }
+
+// convertViewRuleExpr converts view expressions to a rule expression. Relationship
+// patterns and element filters are kept verbatim in Expressions.
+func convertViewRuleExpr(exprs []language.ViewExpr) *ViewRuleExpr {
+	expr := &ViewRuleExpr{}
+	for _, vexpr := range exprs {
+		switch {
+		case vexpr.Pattern != nil || vexpr.Filter != nil || vexpr.Incoming || vexpr.Outgoing:
+			expr.Expressions = append(expr.Expressions, vexpr.String())
+		case vexpr.Wildcard:
+			expr.Wildcard = true
+		case vexpr.Recursive:
+			expr.Recursive = true
+		case vexpr.Selector != nil:
+			expr.Elements = append(expr.Elements, vexpr.String())
+		}
+	}
+	return expr
+}
+
+// viewGraphDump converts a resolved view graph to nodes and edges, in model order.
+func viewGraphDump(program *language.Program, graph *views.ViewGraph) ([]NodeDump, []EdgeDump) {
+	nodes := []NodeDump{}
+	var walk func(elem *language.ElementDef, parent string)
+	walk = func(elem *language.ElementDef, parent string) {
+		id := elem.GetID()
+		if id == "" {
+			return
+		}
+		fqn := buildQualifiedIDForView(id)
+		if parent != "" {
+			fqn = buildQualifiedIDForView(parent, id)
+		}
+		if graph.Elements[fqn] {
+			node := NodeDump{ID: fqn, Element: fqn, Title: labelOrID(ptrStr(elem.GetTitle()), id)}
+			if graph.Elements[parent] {
+				node.Parent = parent
+			}
+			nodes = append(nodes, node)
+		}
+		if body := elem.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					walk(item.Element, fqn)
+				}
+			}
+		}
+	}
+	if program.Model != nil {
+		for _, item := range program.Model.Items {
+			if item.ElementDef != nil {
+				walk(item.ElementDef, "")
+			}
+		}
+	}
+
+	edges := make([]EdgeDump, 0, len(graph.Relations))
+	for i, rel := range graph.Relations {
+		edges = append(edges, EdgeDump{
+			ID:     buildEdgeID(rel.From, rel.To, i),
+			Source: rel.From,
+			Target: rel.To,
+			Title:  rel.Label,
+		})
+	}
+	return nodes, edges
+}
@@ -504,3 +504,26 @@ func TestMarkdownExport_OptimizeContent(t *testing.T) {
 		t.Error("Export should produce output even with token limit")
 	}
 }
+
+func TestMarkdownExport_BidirectionalRelation(t *testing.T) {
+	dsl := `
+		Cache = System "Cache"
+		Store = System "Store"
+		Cache <-> Store "Syncs"
+		Cache <- Store "Invalidates"
+	`
+
+	opts := DefaultOptions()
+	opts.Context = ContextAnalysis
+	output := NewExporter(opts).Export(parseDSL(t, dsl))
+
+	if !strings.Contains(output, "Cache <-->|\"Syncs\"| Store") {
+		t.Errorf("Expected bidirectional link in diagram:\n%s", output)
+	}
+	if !strings.Contains(output, "**Cache** ↔ **Store**: Syncs") {
+		t.Errorf("Expected bidirectional relationship in output:\n%s", output)
+	}
+	if !strings.Contains(output, "**Store** → **Cache**: Invalidates") {
+		t.Errorf("Expected back arrow normalized to Store → Cache in output:\n%s", output)
+	}
+}
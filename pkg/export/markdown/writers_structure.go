@@ -151,13 +151,18 @@ func (e *Exporter) writeRelationships(sb *strings.Builder, prog *language.Progra
 			verb = rel.VerbRaw.Value
 		}
 
+		arrow := "→"
+		if rel.Bidirectional {
+			arrow = "↔"
+		}
+
 		switch {
 		case label != "":
-			fmt.Fprintf(sb, "- **%s** → **%s**: %s\n", from, to, label)
+			fmt.Fprintf(sb, "- **%s** %s **%s**: %s\n", from, arrow, to, label)
 		case verb != "":
-			fmt.Fprintf(sb, "- **%s** → **%s**: %s\n", from, to, verb)
+			fmt.Fprintf(sb, "- **%s** %s **%s**: %s\n", from, arrow, to, verb)
 		default:
-			fmt.Fprintf(sb, "- **%s** → **%s**\n", from, to)
+			fmt.Fprintf(sb, "- **%s** %s **%s**\n", from, arrow, to)
 		}
 	}
 
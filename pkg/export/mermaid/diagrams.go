@@ -377,9 +377,10 @@ func (e *Exporter) writeCustomRelation(sb *strings.Builder, from, to string, rel
 		label = getString(rel.Verb)
 	}
 
+	arrow := relationArrow(rel)
 	if label != "" {
-		fmt.Fprintf(sb, "    %s -->|\"%s\"| %s\n", sFrom, escapeQuotes(label), sTo)
+		fmt.Fprintf(sb, "    %s %s|\"%s\"| %s\n", sFrom, arrow, escapeQuotes(label), sTo)
 	} else {
-		fmt.Fprintf(sb, "    %s --> %s\n", sFrom, sTo)
+		fmt.Fprintf(sb, "    %s %s %s\n", sFrom, arrow, sTo)
 	}
 }
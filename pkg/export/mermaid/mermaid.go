@@ -1,6 +1,7 @@
 package mermaid
 
 import (
+	"github.com/sruja-ai/sruja/pkg/export/views"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -13,6 +14,7 @@ type Config struct {
 	UseFrontmatter bool
 	ViewLevel      int    // 1=Context, 2=Container, 3=Component
 	TargetID       string // ID of the System (for L2) or Container (for L3) to focus on
+	ViewName       string // Name of a declared view to render (overrides ViewLevel)
 }
 
 // DefaultConfig returns the default Mermaid configuration.
@@ -41,6 +43,10 @@ func (e *Exporter) Export(prog *language.Program) string {
 		return ""
 	}
 
+	if e.Config.ViewName != "" {
+		return e.GenerateView(prog, views.FindView(prog, e.Config.ViewName))
+	}
+
 	// Dispatch based on view level
 	switch e.Config.ViewLevel {
 	case 2:
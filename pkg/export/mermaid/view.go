@@ -0,0 +1,99 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/export/views"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// GenerateView generates a diagram for a declared view.
+// Shows the elements and relations selected by the view's include/exclude expressions.
+func (e *Exporter) GenerateView(prog *language.Program, view *language.ViewDef) string {
+	if prog == nil || prog.Model == nil {
+		return ""
+	}
+	graph, err := views.ResolveView(prog, view)
+	if err != nil || len(graph.Elements) == 0 {
+		return ""
+	}
+
+	sb := engine.GetStringBuilder()
+	defer engine.PutStringBuilder(sb)
+
+	e.writeHeader(sb)
+	e.writeStyles(sb)
+
+	// Write Elements in model order
+	var walk func(elem *language.ElementDef, parent string)
+	walk = func(elem *language.ElementDef, parent string) {
+		id := elem.GetID()
+		if id == "" {
+			return
+		}
+		fqn := id
+		if parent != "" {
+			fqn = parent + "." + id
+		}
+		if graph.Elements[fqn] {
+			writeViewElement(sb, fqn, elem)
+		}
+		if body := elem.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					walk(item.Element, fqn)
+				}
+			}
+		}
+	}
+	for _, item := range prog.Model.Items {
+		if item.ElementDef != nil {
+			walk(item.ElementDef, "")
+		}
+	}
+
+	// Write Relations
+	for _, rel := range graph.Relations {
+		arrow := "-->"
+		if rel.Bidirectional {
+			arrow = "<-->"
+		}
+		from := sanitizeID(rel.From)
+		to := sanitizeID(rel.To)
+		if rel.Label != "" {
+			fmt.Fprintf(sb, "    %s %s|\"%s\"| %s\n", from, arrow, escapeQuotes(rel.Label), to)
+		} else {
+			fmt.Fprintf(sb, "    %s %s %s\n", from, arrow, to)
+		}
+	}
+
+	return sb.String()
+}
+
+// writeViewElement writes a node for an element of a declared view, shaped by its kind.
+func writeViewElement(sb *strings.Builder, fqn string, elem *language.ElementDef) {
+	id := sanitizeID(fqn)
+	label := escapeQuotes(formatLabel(getString(elem.GetTitle()), elem.GetID(), "", ""))
+	switch strings.ToLower(elem.GetKind()) {
+	case "person":
+		fmt.Fprintf(sb, "    %s[\"%s\"]\n", id, label)
+		fmt.Fprintf(sb, "    class %s %s\n", id, ClassPerson)
+	case "database", "datastore":
+		fmt.Fprintf(sb, "    %s[(\"%s\")]\n", id, label)
+		fmt.Fprintf(sb, "    class %s %s\n", id, ClassDatabase)
+	case "queue":
+		fmt.Fprintf(sb, "    %s(\"%s\")\n", id, label)
+		fmt.Fprintf(sb, "    class %s %s\n", id, ClassQueue)
+	case "container":
+		fmt.Fprintf(sb, "    %s[\"%s\"]\n", id, label)
+		fmt.Fprintf(sb, "    class %s %s\n", id, ClassContainer)
+	case "component":
+		fmt.Fprintf(sb, "    %s[\"%s\"]\n", id, label)
+		fmt.Fprintf(sb, "    class %s %s\n", id, ClassComponent)
+	default:
+		fmt.Fprintf(sb, "    %s[\"%s\"]\n", id, label)
+		fmt.Fprintf(sb, "    class %s %s\n", id, ClassSystem)
+	}
+}
@@ -156,13 +156,22 @@ func (e *Exporter) writeRelation(sb *strings.Builder, rel *language.Relation, _
 		label = getString(rel.Verb)
 	}
 
+	arrow := relationArrow(rel)
 	if label != "" {
-		fmt.Fprintf(sb, "    %s -->|\"%s\"| %s\n", from, escapeQuotes(label), to)
+		fmt.Fprintf(sb, "    %s %s|\"%s\"| %s\n", from, arrow, escapeQuotes(label), to)
 	} else {
-		fmt.Fprintf(sb, "    %s --> %s\n", from, to)
+		fmt.Fprintf(sb, "    %s %s %s\n", from, arrow, to)
 	}
 }
 
+// relationArrow returns the Mermaid link for a relation: <--> for bidirectional relations, --> otherwise.
+func relationArrow(rel *language.Relation) string {
+	if rel.Bidirectional {
+		return "<-->"
+	}
+	return "-->"
+}
+
 // Helpers
 
 func getString(s *string) string {
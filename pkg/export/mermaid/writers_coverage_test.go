@@ -91,6 +91,25 @@ func TestWriter_WriteRelation_LabelFallback(t *testing.T) {
 	}
 }
 
+func TestWriter_WriteRelation_Bidirectional(t *testing.T) {
+	rel := &language.Relation{
+		From:          language.QualifiedIdent{Parts: []string{"A"}},
+		To:            language.QualifiedIdent{Parts: []string{"B"}},
+		Bidirectional: true,
+	}
+
+	exporter := NewExporter(DefaultConfig())
+	sb := engine.GetStringBuilder()
+	defer engine.PutStringBuilder(sb)
+
+	exporter.writeRelation(sb, rel, nil)
+	result := sb.String()
+
+	if !strings.Contains(result, "A <--> B") {
+		t.Errorf("Expected bidirectional link, got: %s", result)
+	}
+}
+
 func TestDiagrams_L2_Generation(t *testing.T) {
 	// Test GenerateL2 logic (Container Diagram)
 	// Needs a program with a system and some external relations
@@ -0,0 +1,84 @@
+package plantuml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// writeDeploymentView writes the deployment nodes with their infrastructure and
+// container instances, and the relations between instances of related containers.
+func (e *Exporter) writeDeploymentView(sb *strings.Builder, idx *modelIndex, prog *language.Program) bool {
+	instances := make(map[string][]string) // container FQN -> instance aliases
+	written := false
+
+	var writeNode func(node *language.DeploymentNode, parentAlias, indent string)
+	writeNode = func(node *language.DeploymentNode, parentAlias, indent string) {
+		nodeAlias := alias(node.ID)
+		if parentAlias != "" {
+			nodeAlias = parentAlias + "_" + nodeAlias
+		}
+		args := []string{nodeAlias, quote(node.Label)}
+		if node.Description != nil {
+			args = append(args, quote(""), quote(*node.Description))
+		}
+		fmt.Fprintf(sb, "%sDeployment_Node(%s) {\n", indent, strings.Join(args, ", "))
+
+		for _, infra := range node.Infrastructure {
+			infraArgs := []string{nodeAlias + "_" + alias(infra.ID), quote(infra.Label)}
+			if infra.Description != nil {
+				infraArgs = append(infraArgs, quote(""), quote(*infra.Description))
+			}
+			fmt.Fprintf(sb, "%s    Node(%s)\n", indent, strings.Join(infraArgs, ", "))
+		}
+		for _, ci := range node.ContainerInstances {
+			fqn := idx.resolve(ci.ContainerID, "")
+			el, ok := idx.elements[fqn]
+			if !ok {
+				continue
+			}
+			instAlias := nodeAlias + "_" + alias(fqn)
+			label := ci.Label
+			if label == "" {
+				label = el.label()
+			}
+			writeElementAs(sb, el, instAlias, label, indent+"    ")
+			instances[fqn] = append(instances[fqn], instAlias)
+		}
+		for _, child := range node.Children {
+			writeNode(child, nodeAlias, indent+"    ")
+		}
+
+		fmt.Fprintf(sb, "%s}\n", indent)
+		written = true
+	}
+
+	for _, item := range prog.Model.Items {
+		if item.DeploymentNode != nil {
+			writeNode(item.DeploymentNode, "", "")
+		}
+	}
+	if !written {
+		return false
+	}
+	sb.WriteString("\n")
+
+	// Relations between deployed containers, lifted from their children
+	deployed := make(map[string]bool, len(instances))
+	for fqn := range instances {
+		deployed[fqn] = true
+	}
+	var rels []relation
+	for _, rel := range idx.project(deployed, func(string) string { return "" }) {
+		for _, from := range instances[rel.From] {
+			for _, to := range instances[rel.To] {
+				r := rel
+				r.From, r.To = from, to
+				rels = append(rels, r)
+			}
+		}
+	}
+	writeRelations(sb, rels)
+	return true
+}
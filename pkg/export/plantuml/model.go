@@ -0,0 +1,251 @@
+package plantuml
+
+import (
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// element is a model element with the properties used by the C4 macros.
+type element struct {
+	FQN         string
+	ID          string
+	Kind        string
+	Title       string
+	Description string
+	Technology  string
+	External    bool
+}
+
+func (el *element) label() string {
+	if el.Title != "" {
+		return el.Title
+	}
+	return el.ID
+}
+
+// relation is a relation between two elements, with FQN endpoints.
+type relation struct {
+	From          string
+	To            string
+	Label         string
+	Technology    string
+	Bidirectional bool
+}
+
+// modelIndex provides element lookups and resolved relations for a program.
+type modelIndex struct {
+	elements  map[string]*element
+	order     []string
+	suffixes  map[string][]string
+	relations []relation
+}
+
+func newModelIndex(prog *language.Program) *modelIndex {
+	idx := &modelIndex{
+		elements: make(map[string]*element),
+		suffixes: make(map[string][]string),
+	}
+
+	type scopedRelation struct {
+		rel   *language.Relation
+		scope string
+	}
+	var rels []scopedRelation
+
+	var walk func(def *language.ElementDef, parent string)
+	walk = func(def *language.ElementDef, parent string) {
+		id := def.GetID()
+		if id == "" {
+			return
+		}
+		fqn := id
+		if parent != "" {
+			fqn = parent + "." + id
+		}
+		el := &element{
+			FQN:   fqn,
+			ID:    id,
+			Kind:  strings.ToLower(def.GetKind()),
+			Title: getString(def.GetTitle()),
+		}
+		tags := def.GetTagRefs()
+		if body := def.GetBody(); body != nil {
+			for _, item := range body.Items {
+				switch {
+				case item.Description != nil && el.Description == "":
+					el.Description = *item.Description
+				case item.Technology != nil && el.Technology == "":
+					el.Technology = *item.Technology
+				case len(item.Tags) > 0:
+					tags = append(tags, item.Tags...)
+				case len(item.TagRefs) > 0:
+					tags = append(tags, item.TagRefs...)
+				}
+			}
+		}
+		for _, tag := range tags {
+			if strings.EqualFold(strings.TrimPrefix(tag, "#"), "external") {
+				el.External = true
+			}
+		}
+
+		idx.elements[fqn] = el
+		idx.order = append(idx.order, fqn)
+		idx.suffixes[id] = append(idx.suffixes[id], fqn)
+
+		if body := def.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					walk(item.Element, fqn)
+				}
+				if item.Relation != nil {
+					rels = append(rels, scopedRelation{rel: item.Relation, scope: fqn})
+				}
+			}
+		}
+	}
+	for _, item := range prog.Model.Items {
+		if item.ElementDef != nil {
+			walk(item.ElementDef, "")
+		}
+		// Projecting relations onto visible ancestors already covers implied
+		// parent relations.
+		if item.Relation != nil && !item.Relation.Implied {
+			rels = append(rels, scopedRelation{rel: item.Relation})
+		}
+	}
+
+	for _, sr := range rels {
+		from := idx.resolve(sr.rel.From.String(), sr.scope)
+		to := idx.resolve(sr.rel.To.String(), sr.scope)
+		if from == "" || to == "" {
+			continue
+		}
+		// A verb with a label reads as "calls" "HTTP": the label is the technology
+		label := getString(sr.rel.Verb)
+		technology := getString(sr.rel.Label)
+		if label == "" {
+			label, technology = technology, ""
+		}
+		idx.relations = append(idx.relations, relation{
+			From:          from,
+			To:            to,
+			Label:         label,
+			Technology:    technology,
+			Bidirectional: sr.rel.Bidirectional,
+		})
+	}
+	return idx
+}
+
+// resolve returns the FQN of ref, looked up relative to scope first, then as an
+// FQN, then by unique suffix. Returns "" if ref cannot be resolved.
+func (idx *modelIndex) resolve(ref, scope string) string {
+	if ref == "" {
+		return ""
+	}
+	for s := scope; s != ""; s = parentOf(s) {
+		if _, ok := idx.elements[s+"."+ref]; ok {
+			return s + "." + ref
+		}
+	}
+	if _, ok := idx.elements[ref]; ok {
+		return ref
+	}
+	last := ref
+	if i := strings.LastIndex(ref, "."); i >= 0 {
+		last = ref[i+1:]
+	}
+	var match string
+	for _, fqn := range idx.suffixes[last] {
+		if strings.HasSuffix(fqn, "."+ref) {
+			if match != "" {
+				return ""
+			}
+			match = fqn
+		}
+	}
+	return match
+}
+
+// project maps relations onto the visible elements. Endpoints are replaced by their
+// closest visible ancestor, or by fallback when none is visible. Relations are kept
+// when at least one endpoint is visible, in source order and without duplicates.
+func (idx *modelIndex) project(visible map[string]bool, fallback func(string) string) []relation {
+	var result []relation
+	seen := make(map[string]bool)
+	for _, rel := range idx.relations {
+		from, fromVisible := visibleAncestor(rel.From, visible)
+		to, toVisible := visibleAncestor(rel.To, visible)
+		if !fromVisible && !toVisible {
+			continue
+		}
+		if !fromVisible {
+			from = fallback(rel.From)
+		}
+		if !toVisible {
+			to = fallback(rel.To)
+		}
+		if from == "" || to == "" || from == to {
+			continue
+		}
+		key := from + "->" + to + ":" + rel.Label
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rel.From, rel.To = from, to
+		result = append(result, rel)
+	}
+	return result
+}
+
+// hasVisibleChildren reports whether any direct child of fqn is visible.
+func (idx *modelIndex) hasVisibleChildren(fqn string, visible map[string]bool) bool {
+	for _, id := range idx.order {
+		if visible[id] && parentOf(id) == fqn {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleAncestor returns fqn or its closest visible ancestor.
+func visibleAncestor(fqn string, visible map[string]bool) (string, bool) {
+	for id := fqn; id != ""; id = parentOf(id) {
+		if visible[id] {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// parentOf returns the FQN of the parent element, or "" for top-level elements.
+func parentOf(fqn string) string {
+	if i := strings.LastIndex(fqn, "."); i >= 0 {
+		return fqn[:i]
+	}
+	return ""
+}
+
+// topLevel returns the top-level ancestor of fqn.
+func topLevel(fqn string) string {
+	return ancestorAtDepth(fqn, 0)
+}
+
+// ancestorAtDepth returns the ancestor of fqn at the given depth (0 = top level).
+func ancestorAtDepth(fqn string, depth int) string {
+	parts := strings.Split(fqn, ".")
+	if depth >= len(parts) {
+		return fqn
+	}
+	return strings.Join(parts[:depth+1], ".")
+}
+
+func getString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
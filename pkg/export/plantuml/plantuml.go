@@ -0,0 +1,221 @@
+// Package plantuml provides C4-PlantUML export for Sruja diagrams.
+package plantuml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// View levels supported by the exporter.
+const (
+	LevelContext    = 1 // C4_Context: persons and systems
+	LevelContainer  = 2 // C4_Container: containers of a system
+	LevelComponent  = 3 // C4_Component: components of a container
+	LevelDeployment = 4 // C4_Deployment: deployment nodes and container instances
+)
+
+// Config represents PlantUML diagram configuration.
+type Config struct {
+	ViewLevel int    // LevelContext, LevelContainer, LevelComponent or LevelDeployment
+	FocusID   string // FQN of the System (for L2) or Container (for L3) to focus on
+	Direction string // TB (default) or LR
+	Title     string // Optional diagram title
+}
+
+// DefaultConfig returns the default PlantUML configuration.
+func DefaultConfig() Config {
+	return Config{
+		ViewLevel: LevelContext,
+		Direction: "TB",
+	}
+}
+
+// Exporter handles C4-PlantUML diagram generation.
+type Exporter struct {
+	Config Config
+}
+
+// NewExporter creates a new PlantUML exporter.
+func NewExporter(config Config) *Exporter {
+	return &Exporter{Config: config}
+}
+
+// Export generates a C4-PlantUML diagram from a program.
+// Returns an empty string when the requested view has no elements.
+func (e *Exporter) Export(prog *language.Program) string {
+	if prog == nil || prog.Model == nil {
+		return ""
+	}
+
+	idx := newModelIndex(prog)
+
+	sb := engine.GetStringBuilder()
+	defer engine.PutStringBuilder(sb)
+
+	var ok bool
+	switch e.Config.ViewLevel {
+	case LevelContainer:
+		e.writeHeader(sb, "C4_Container")
+		ok = e.writeContainerView(sb, idx)
+	case LevelComponent:
+		e.writeHeader(sb, "C4_Component")
+		ok = e.writeComponentView(sb, idx)
+	case LevelDeployment:
+		e.writeHeader(sb, "C4_Deployment")
+		ok = e.writeDeploymentView(sb, idx, prog)
+	default:
+		e.writeHeader(sb, "C4_Context")
+		ok = e.writeContextView(sb, idx)
+	}
+	if !ok {
+		return ""
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+func (e *Exporter) writeHeader(sb *strings.Builder, library string) {
+	sb.WriteString("@startuml\n")
+	fmt.Fprintf(sb, "!include <C4/%s>\n\n", library)
+	if strings.EqualFold(e.Config.Direction, "LR") {
+		sb.WriteString("LAYOUT_LEFT_RIGHT()\n\n")
+	}
+	if e.Config.Title != "" {
+		fmt.Fprintf(sb, "title %s\n\n", e.Config.Title)
+	}
+}
+
+// writeContextView writes the top-level persons and systems.
+func (e *Exporter) writeContextView(sb *strings.Builder, idx *modelIndex) bool {
+	visible := make(map[string]bool)
+	for _, fqn := range idx.order {
+		if parentOf(fqn) == "" {
+			visible[fqn] = true
+		}
+	}
+	if len(visible) == 0 {
+		return false
+	}
+
+	for _, fqn := range idx.order {
+		if visible[fqn] {
+			writeElement(sb, idx.elements[fqn], "")
+		}
+	}
+	sb.WriteString("\n")
+	writeRelations(sb, idx.project(visible, topLevel))
+	return true
+}
+
+// writeContainerView writes the containers of the focused system inside a boundary,
+// with the persons and systems they interact with. Without a focus, every system is
+// drawn as a boundary around its containers.
+func (e *Exporter) writeContainerView(sb *strings.Builder, idx *modelIndex) bool {
+	focus := ""
+	if e.Config.FocusID != "" {
+		focus = idx.resolve(e.Config.FocusID, "")
+		if focus == "" {
+			return false
+		}
+	}
+
+	visible := make(map[string]bool)
+	var boundaries []string
+	for _, fqn := range idx.order {
+		parent := parentOf(fqn)
+		switch {
+		case focus == "" && parent == "":
+			visible[fqn] = true
+		case focus == "" && parentOf(parent) == "":
+			visible[fqn] = true
+		case focus != "" && parent == focus:
+			visible[fqn] = true
+		}
+	}
+	for _, fqn := range idx.order {
+		if visible[fqn] && (focus == "" || fqn == focus) && idx.hasVisibleChildren(fqn, visible) {
+			boundaries = append(boundaries, fqn)
+		}
+	}
+	if focus != "" {
+		boundaries = []string{focus}
+	}
+
+	rels := idx.project(visible, topLevel)
+	// Neighbours of the focused system are shown as top-level elements
+	for _, rel := range rels {
+		visible[rel.From] = true
+		visible[rel.To] = true
+	}
+	if len(visible) == 0 {
+		return false
+	}
+
+	e.writeWithBoundaries(sb, idx, visible, boundaries, "System_Boundary")
+	writeRelations(sb, rels)
+	return true
+}
+
+// writeComponentView writes the components of the focused container inside a boundary,
+// with the containers, persons and systems they interact with.
+func (e *Exporter) writeComponentView(sb *strings.Builder, idx *modelIndex) bool {
+	focus := idx.resolve(e.Config.FocusID, "")
+	if focus == "" {
+		return false
+	}
+
+	visible := make(map[string]bool)
+	for _, fqn := range idx.order {
+		if parentOf(fqn) == focus {
+			visible[fqn] = true
+		}
+	}
+
+	// Neighbours inside the same system are lifted to container level, others to top level
+	system := topLevel(focus)
+	rels := idx.project(visible, func(fqn string) string {
+		if strings.HasPrefix(fqn, system+".") {
+			return ancestorAtDepth(fqn, strings.Count(system, ".")+1)
+		}
+		return topLevel(fqn)
+	})
+	for _, rel := range rels {
+		visible[rel.From] = true
+		visible[rel.To] = true
+	}
+	if len(visible) == 0 {
+		return false
+	}
+
+	e.writeWithBoundaries(sb, idx, visible, []string{focus}, "Container_Boundary")
+	writeRelations(sb, rels)
+	return true
+}
+
+// writeWithBoundaries writes the visible elements, nesting the children of each boundary
+// element inside a boundary macro.
+func (e *Exporter) writeWithBoundaries(sb *strings.Builder, idx *modelIndex, visible map[string]bool, boundaries []string, macro string) {
+	inBoundary := make(map[string]bool)
+	for _, b := range boundaries {
+		elem := idx.elements[b]
+		fmt.Fprintf(sb, "%s(%s, %s) {\n", macro, alias(b), quote(elem.label()))
+		for _, fqn := range idx.order {
+			if visible[fqn] && parentOf(fqn) == b {
+				writeElement(sb, idx.elements[fqn], "    ")
+				inBoundary[fqn] = true
+			}
+		}
+		sb.WriteString("}\n")
+		inBoundary[b] = true
+	}
+	for _, fqn := range idx.order {
+		if visible[fqn] && !inBoundary[fqn] {
+			writeElement(sb, idx.elements[fqn], "")
+		}
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,145 @@
+package plantuml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const testDSL = `
+User = person "User"
+Shop = system "Shop" {
+	description "Online shop"
+	API = container "API" {
+		technology "Go"
+		Handler = component "Handler"
+	}
+	DB = database "DB"
+	Events = queue "Events"
+	API -> DB "reads" "SQL"
+}
+Stripe = system "Stripe" {
+	tags ["external"]
+}
+User -> Shop.API "Uses"
+Shop.API -> Stripe "Charges" "HTTPS"
+Shop.API.Handler <-> Shop.Events "Publishes"
+
+deployment Prod "Production" {
+	node AWS "AWS" {
+		infrastructure LB "Load Balancer"
+		containerInstance API
+		containerInstance DB
+	}
+}
+`
+
+func parseDSL(t *testing.T, dsl string) *language.Program {
+	t.Helper()
+	p, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := p.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return prog
+}
+
+func export(t *testing.T, level int, focus string) string {
+	t.Helper()
+	config := DefaultConfig()
+	config.ViewLevel = level
+	config.FocusID = focus
+	return NewExporter(config).Export(parseDSL(t, testDSL))
+}
+
+func assertContains(t *testing.T, output string, want []string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("missing %q in output:\n%s", w, output)
+		}
+	}
+}
+
+func TestExport_Context(t *testing.T) {
+	output := export(t, LevelContext, "")
+	assertContains(t, output, []string{
+		"@startuml",
+		"!include <C4/C4_Context>",
+		`Person(User, "User")`,
+		`System(Shop, "Shop", "Online shop")`,
+		`System_Ext(Stripe, "Stripe")`,
+		`Rel(User, Shop, "Uses")`,
+		`Rel(Shop, Stripe, "Charges", "HTTPS")`,
+		"@enduml",
+	})
+	if strings.Contains(output, "Shop_API") {
+		t.Errorf("context view should not contain containers:\n%s", output)
+	}
+}
+
+func TestExport_Container(t *testing.T) {
+	output := export(t, LevelContainer, "Shop")
+	assertContains(t, output, []string{
+		"!include <C4/C4_Container>",
+		`System_Boundary(Shop, "Shop") {`,
+		`    Container(Shop_API, "API", "Go")`,
+		`    ContainerDb(Shop_DB, "DB", "")`,
+		`    ContainerQueue(Shop_Events, "Events", "")`,
+		`Person(User, "User")`,
+		`Rel(Shop_API, Shop_DB, "reads", "SQL")`,
+		`Rel(User, Shop_API, "Uses")`,
+		`BiRel(Shop_API, Shop_Events, "Publishes")`,
+	})
+}
+
+func TestExport_Component(t *testing.T) {
+	output := export(t, LevelComponent, "Shop.API")
+	assertContains(t, output, []string{
+		"!include <C4/C4_Component>",
+		`Container_Boundary(Shop_API, "API") {`,
+		`    Component(Shop_API_Handler, "Handler", "")`,
+		`ContainerQueue(Shop_Events, "Events", "")`,
+		`BiRel(Shop_API_Handler, Shop_Events, "Publishes")`,
+	})
+}
+
+func TestExport_Deployment(t *testing.T) {
+	output := export(t, LevelDeployment, "")
+	assertContains(t, output, []string{
+		"!include <C4/C4_Deployment>",
+		`Deployment_Node(Prod, "Production") {`,
+		`Deployment_Node(Prod_AWS, "AWS") {`,
+		`Node(Prod_AWS_LB, "Load Balancer")`,
+		`Container(Prod_AWS_Shop_API, "API", "Go")`,
+		`Rel(Prod_AWS_Shop_API, Prod_AWS_Shop_DB, "reads", "SQL")`,
+	})
+}
+
+func TestExport_RelationWithoutPosition(t *testing.T) {
+	prog := parseDSL(t, testDSL)
+	label := "Pays"
+	// Relations built in code or imported from JSON have no source position
+	prog.Model.Items = append(prog.Model.Items, language.ModelItem{Relation: &language.Relation{
+		From:  language.QualifiedIdent{Parts: []string{"User"}},
+		Arrow: "->",
+		To:    language.QualifiedIdent{Parts: []string{"Stripe"}},
+		Label: &label,
+	}})
+	config := DefaultConfig()
+	config.ViewLevel = LevelContext
+	assertContains(t, NewExporter(config).Export(prog), []string{`Rel(User, Stripe, "Pays")`})
+}
+
+func TestExport_Empty(t *testing.T) {
+	if output := export(t, LevelComponent, "Missing"); output != "" {
+		t.Errorf("expected empty output for unknown focus, got:\n%s", output)
+	}
+	if output := NewExporter(DefaultConfig()).Export(nil); output != "" {
+		t.Errorf("expected empty output for nil program, got:\n%s", output)
+	}
+}
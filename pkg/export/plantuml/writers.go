@@ -0,0 +1,88 @@
+package plantuml
+
+import (
+	"fmt"
+	"strings"
+)
+
+// elementMacro returns the C4-PlantUML macro for an element, based on its kind and
+// nesting depth: top-level elements are systems, their children containers, and
+// deeper elements components.
+func elementMacro(el *element) string {
+	depth := strings.Count(el.FQN, ".")
+	var macro string
+	switch {
+	case el.Kind == "person" || el.Kind == "actor":
+		macro = "Person"
+	case depth == 0:
+		macro = "System"
+	case depth == 1:
+		macro = "Container"
+	default:
+		macro = "Component"
+	}
+
+	if macro != "Person" {
+		switch el.Kind {
+		case "database", "datastore", "db", "storage":
+			macro += "Db"
+		case "queue", "mq":
+			macro += "Queue"
+		}
+	}
+	if el.External {
+		macro += "_Ext"
+	}
+	return macro
+}
+
+// writeElement writes the C4 macro call for an element.
+func writeElement(sb *strings.Builder, el *element, indent string) {
+	writeElementAs(sb, el, alias(el.FQN), el.label(), indent)
+}
+
+// writeElementAs writes the C4 macro call for an element under the given alias and label.
+func writeElementAs(sb *strings.Builder, el *element, elemAlias, label, indent string) {
+	macro := elementMacro(el)
+	args := []string{elemAlias, quote(label)}
+	// Container and Component macros take the technology before the description
+	if strings.HasPrefix(macro, "Container") || strings.HasPrefix(macro, "Component") {
+		args = append(args, quote(el.Technology))
+	}
+	if el.Description != "" {
+		args = append(args, quote(el.Description))
+	}
+	fmt.Fprintf(sb, "%s%s(%s)\n", indent, macro, strings.Join(args, ", "))
+}
+
+// writeRelations writes Rel (or BiRel) macro calls for relations.
+func writeRelations(sb *strings.Builder, rels []relation) {
+	for _, rel := range rels {
+		macro := "Rel"
+		if rel.Bidirectional {
+			macro = "BiRel"
+		}
+		args := []string{alias(rel.From), alias(rel.To), quote(rel.Label)}
+		if rel.Technology != "" {
+			args = append(args, quote(rel.Technology))
+		}
+		fmt.Fprintf(sb, "%s(%s)\n", macro, strings.Join(args, ", "))
+	}
+}
+
+// alias converts an FQN to a PlantUML alias.
+func alias(fqn string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, fqn)
+}
+
+// quote returns s as a PlantUML string argument.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\"", "'")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return "\"" + s + "\""
+}
@@ -0,0 +1,155 @@
+package structurizr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// defaultEnvironment names the environment of deployment nodes declared outside a
+// deployment block.
+const defaultEnvironment = "Default"
+
+// environment collects the elements and relationships of a deployment environment.
+type environment struct {
+	Name            string
+	ElementIDs      []string
+	RelationshipIDs []string
+	instances       []*ContainerInstance
+}
+
+// buildDeployment adds the deployment nodes of every environment. A top-level
+// deployment block is an environment; other top-level nodes belong to the default
+// environment. Relationships between containers are replicated between their
+// instances in the same environment.
+func (b *builder) buildDeployment(ws *Workspace) {
+	envs := make(map[string]*environment)
+	getEnv := func(name string) *environment {
+		env, ok := envs[name]
+		if !ok {
+			env = &environment{Name: name}
+			envs[name] = env
+			b.environments = append(b.environments, env)
+		}
+		return env
+	}
+
+	for _, item := range b.prog.Model.Items {
+		node := item.DeploymentNode
+		if node == nil {
+			continue
+		}
+		if node.Type != "deployment" {
+			env := getEnv(defaultEnvironment)
+			ws.Model.DeploymentNodes = append(ws.Model.DeploymentNodes, b.deploymentNode(node, env))
+			continue
+		}
+		name := node.Label
+		if name == "" {
+			name = node.ID
+		}
+		env := getEnv(name)
+		for _, child := range node.Items {
+			if child.Node != nil {
+				ws.Model.DeploymentNodes = append(ws.Model.DeploymentNodes, b.deploymentNode(child.Node, env))
+			}
+		}
+	}
+
+	for _, env := range b.environments {
+		for _, source := range env.instances {
+			for _, destination := range env.instances {
+				if source == destination {
+					continue
+				}
+				for _, rel := range b.relationships {
+					if rel.SourceID != source.ContainerID || rel.DestinationID != destination.ContainerID {
+						continue
+					}
+					instanceRel := &Relationship{
+						SourceID:             source.ID,
+						DestinationID:        destination.ID,
+						Description:          rel.Description,
+						Technology:           rel.Technology,
+						LinkedRelationshipID: rel.ID,
+					}
+					instanceRel.ID = b.newID()
+					source.Relationships = append(source.Relationships, instanceRel)
+					env.RelationshipIDs = append(env.RelationshipIDs, instanceRel.ID)
+				}
+			}
+		}
+	}
+}
+
+// deploymentNode converts a deployment node with its children, infrastructure nodes
+// and container instances.
+func (b *builder) deploymentNode(node *language.DeploymentNode, env *environment) *DeploymentNode {
+	result := &DeploymentNode{
+		Element: Element{
+			ID:          b.newID(),
+			Name:        node.Label,
+			Description: getString(node.Description),
+			Tags:        TagElement + "," + TagDeploymentNode,
+		},
+		Environment: env.Name,
+	}
+	if result.Name == "" {
+		result.Name = node.ID
+	}
+	env.ElementIDs = append(env.ElementIDs, result.ID)
+
+	for _, item := range node.Items {
+		switch {
+		case item.Node != nil:
+			result.Children = append(result.Children, b.deploymentNode(item.Node, env))
+		case item.Infrastructure != nil:
+			infra := &InfrastructureNode{
+				Element: Element{
+					ID:          b.newID(),
+					Name:        item.Infrastructure.Label,
+					Description: getString(item.Infrastructure.Description),
+					Tags:        TagElement + "," + TagInfrastructureNode,
+				},
+				Environment: env.Name,
+			}
+			result.InfrastructureNodes = append(result.InfrastructureNodes, infra)
+			env.ElementIDs = append(env.ElementIDs, infra.ID)
+		case item.ContainerInstance != nil:
+			container := b.resolveContainer(item.ContainerInstance.ContainerID)
+			if container == "" {
+				continue
+			}
+			instanceID := 1
+			if item.ContainerInstance.InstanceID != nil {
+				if n, err := strconv.Atoi(*item.ContainerInstance.InstanceID); err == nil {
+					instanceID = n
+				}
+			}
+			instance := &ContainerInstance{
+				Element: Element{
+					ID:   b.newID(),
+					Tags: TagContainerInstance,
+				},
+				Environment: env.Name,
+				ContainerID: b.elements[container].Element.ID,
+				InstanceID:  instanceID,
+			}
+			result.ContainerInstances = append(result.ContainerInstances, instance)
+			env.ElementIDs = append(env.ElementIDs, instance.ID)
+			env.instances = append(env.instances, instance)
+		}
+	}
+	return result
+}
+
+// resolveContainer returns the FQN of the container referenced by a container
+// instance, by FQN or by unique container ID.
+func (b *builder) resolveContainer(ref string) string {
+	fqn := b.resolve(ref, "")
+	if fqn == "" || strings.Count(fqn, ".") != 1 {
+		return ""
+	}
+	return fqn
+}
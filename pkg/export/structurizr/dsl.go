@@ -0,0 +1,793 @@
+package structurizr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dslToken is a word or quoted string of a Structurizr DSL statement.
+type dslToken struct {
+	Value  string
+	Quoted bool
+}
+
+// dslStatement is a DSL line with an optional block of nested statements.
+type dslStatement struct {
+	Line   int
+	Tokens []dslToken
+	Block  []*dslStatement
+}
+
+// keyword returns the lower-cased first token of a statement.
+func (s *dslStatement) keyword() string {
+	if len(s.Tokens) == 0 {
+		return ""
+	}
+	return strings.ToLower(s.Tokens[0].Value)
+}
+
+// arg returns the i-th token value, or "" when there are fewer tokens.
+func (s *dslStatement) arg(i int) string {
+	if i < len(s.Tokens) {
+		return s.Tokens[i].Value
+	}
+	return ""
+}
+
+// tokenizeDSL splits DSL source into lines of tokens. Braces are separate tokens;
+// comment lines (// or #) and block comments are dropped.
+func tokenizeDSL(source string) ([][]dslToken, []int, error) {
+	var lines [][]dslToken
+	var lineNumbers []int
+	inComment := false
+	for n, raw := range strings.Split(source, "\n") {
+		line := strings.TrimSpace(raw)
+		if inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				continue
+			}
+			line = strings.TrimSpace(line[end+2:])
+			inComment = false
+		}
+		if strings.HasPrefix(line, "/*") {
+			if end := strings.Index(line, "*/"); end >= 0 {
+				line = strings.TrimSpace(line[end+2:])
+			} else {
+				inComment = true
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var tokens []dslToken
+		for i := 0; i < len(line); {
+			switch c := line[i]; {
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+			case c == '{' || c == '}':
+				tokens = append(tokens, dslToken{Value: string(c)})
+				i++
+			case c == '"':
+				var sb strings.Builder
+				j := i + 1
+				for ; j < len(line) && line[j] != '"'; j++ {
+					if line[j] == '\\' && j+1 < len(line) {
+						j++
+					}
+					sb.WriteByte(line[j])
+				}
+				if j >= len(line) {
+					return nil, nil, fmt.Errorf("line %d: unterminated string", n+1)
+				}
+				tokens = append(tokens, dslToken{Value: sb.String(), Quoted: true})
+				i = j + 1
+			default:
+				j := i
+				for j < len(line) && line[j] != ' ' && line[j] != '\t' && line[j] != '{' && line[j] != '}' && line[j] != '"' {
+					j++
+				}
+				tokens = append(tokens, dslToken{Value: line[i:j]})
+				i = j
+			}
+		}
+		lines = append(lines, tokens)
+		lineNumbers = append(lineNumbers, n+1)
+	}
+	return lines, lineNumbers, nil
+}
+
+// parseDSLStatements builds the statement tree from tokenized lines.
+func parseDSLStatements(source string) ([]*dslStatement, error) {
+	lines, lineNumbers, err := tokenizeDSL(source)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &dslStatement{}
+	stack := []*dslStatement{root}
+	for i, tokens := range lines {
+		var current *dslStatement
+		for _, tok := range tokens {
+			parent := stack[len(stack)-1]
+			switch {
+			case !tok.Quoted && tok.Value == "{":
+				if current == nil {
+					current = &dslStatement{Line: lineNumbers[i]}
+					parent.Block = append(parent.Block, current)
+				}
+				stack = append(stack, current)
+				current = nil
+			case !tok.Quoted && tok.Value == "}":
+				if len(stack) == 1 {
+					return nil, fmt.Errorf("line %d: unexpected }", lineNumbers[i])
+				}
+				stack = stack[:len(stack)-1]
+				current = nil
+			default:
+				if current == nil {
+					current = &dslStatement{Line: lineNumbers[i]}
+					parent.Block = append(parent.Block, current)
+				}
+				current.Tokens = append(current.Tokens, tok)
+			}
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("line %d: missing }", stack[len(stack)-1].Line)
+	}
+	return root.Block, nil
+}
+
+// dslElement is an element declared in the DSL.
+type dslElement struct {
+	Element    *Element
+	Identifier string
+	Parent     *dslElement
+	Depth      int
+	system     *SoftwareSystem
+	container  *Container
+	technology *string
+}
+
+// pendingRelationship is a relationship whose endpoints are resolved once the whole
+// model has been read.
+type pendingRelationship struct {
+	Line        int
+	Source      *dslElement
+	SourceRef   string
+	Destination string
+	Description string
+	Technology  string
+	Tags        []string
+}
+
+// pendingInstance is a container instance whose container is resolved once the whole
+// model has been read.
+type pendingInstance struct {
+	Line     int
+	Instance *ContainerInstance
+	Ref      string
+}
+
+// dslParser converts DSL statements to a workspace.
+type dslParser struct {
+	ws           *Workspace
+	nextID       int
+	hierarchical bool
+	identifiers  map[string]*dslElement
+	byID         map[string]*dslElement
+	order        []*dslElement
+	rels         []pendingRelationship
+	instances    []pendingInstance
+	viewCounts   map[string]int
+}
+
+// ParseDSL reads a Structurizr DSL workspace.
+//
+// The model (people, software systems, containers, components, groups,
+// relationships and deployment environments), the system landscape, system
+// context, container and component views, and the element and relationship
+// styles are read. View include and exclude expressions support *, element
+// identifiers and element.tag==; other expressions, dynamic, filtered and
+// deployment views, and directives such as !include or !docs are ignored.
+func ParseDSL(source string) (*Workspace, error) {
+	stmts, err := parseDSLStatements(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &dslParser{
+		ws:          &Workspace{Name: "Workspace"},
+		identifiers: make(map[string]*dslElement),
+		byID:        make(map[string]*dslElement),
+		viewCounts:  make(map[string]int),
+	}
+
+	var workspace *dslStatement
+	for _, stmt := range stmts {
+		if stmt.keyword() == "workspace" {
+			workspace = stmt
+			break
+		}
+	}
+	if workspace == nil {
+		return nil, fmt.Errorf("no workspace block found")
+	}
+	if name := workspace.arg(1); name != "" && !strings.EqualFold(name, "extends") {
+		p.ws.Name = name
+		p.ws.Description = workspace.arg(2)
+	}
+
+	var views []*dslStatement
+	for _, stmt := range workspace.Block {
+		switch stmt.keyword() {
+		case "name":
+			p.ws.Name = stmt.arg(1)
+		case "description":
+			p.ws.Description = stmt.arg(1)
+		case "!identifiers":
+			p.hierarchical = strings.EqualFold(stmt.arg(1), "hierarchical")
+		case "model":
+			for _, s := range stmt.Block {
+				if s.keyword() == "!identifiers" {
+					p.hierarchical = strings.EqualFold(s.arg(1), "hierarchical")
+				}
+			}
+			if err := p.parseModelBlock(stmt.Block, nil); err != nil {
+				return nil, err
+			}
+		case "views":
+			views = append(views, stmt)
+		}
+	}
+
+	if err := p.resolvePending(); err != nil {
+		return nil, err
+	}
+	for _, stmt := range views {
+		if err := p.parseViews(stmt.Block); err != nil {
+			return nil, err
+		}
+	}
+	return p.ws, nil
+}
+
+func (p *dslParser) newID() string {
+	p.nextID++
+	return strconv.Itoa(p.nextID)
+}
+
+// parseModelBlock reads model statements; parent is the enclosing element, if any.
+func (p *dslParser) parseModelBlock(stmts []*dslStatement, parent *dslElement) error {
+	for _, stmt := range stmts {
+		tokens := withoutIdentifier(stmt.Tokens)
+		identifier := ""
+		if len(tokens) < len(stmt.Tokens) {
+			identifier = stmt.Tokens[0].Value
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		keyword := strings.ToLower(tokens[0].Value)
+
+		// Relationships: a -> b, -> b (source is the enclosing element) or this -> b
+		if arrow := relationshipArrow(tokens); arrow >= 0 {
+			if err := p.addPendingRelationship(stmt, tokens, arrow, parent); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch keyword {
+		case "group", "enterprise":
+			if err := p.parseModelBlock(stmt.Block, parent); err != nil {
+				return err
+			}
+		case "person", "softwaresystem", "container", "component":
+			el, err := p.addElement(stmt, keyword, tokens, identifier, parent)
+			if err != nil {
+				return err
+			}
+			if err := p.parseModelBlock(stmt.Block, el); err != nil {
+				return err
+			}
+		case "deploymentenvironment":
+			env := tokenValue(tokens, 1)
+			if env == "" {
+				return fmt.Errorf("line %d: deploymentEnvironment needs a name", stmt.Line)
+			}
+			for _, s := range stmt.Block {
+				if tokens := withoutIdentifier(s.Tokens); len(tokens) > 0 && strings.EqualFold(tokens[0].Value, "deploymentNode") {
+					node, err := p.parseDeploymentNode(s, env)
+					if err != nil {
+						return err
+					}
+					p.ws.Model.DeploymentNodes = append(p.ws.Model.DeploymentNodes, node)
+				}
+			}
+		case "description":
+			if parent != nil {
+				parent.Element.Description = tokenValue(tokens, 1)
+			}
+		case "technology":
+			if parent != nil && parent.technology != nil {
+				*parent.technology = tokenValue(tokens, 1)
+			}
+		case "tags":
+			if parent != nil {
+				parent.Element.Tags = joinTags(parent.Element.Tags, tagArgs(tokens[1:]))
+			}
+		}
+	}
+	return nil
+}
+
+// relationshipArrow returns the index of the -> token in a statement, or -1.
+func relationshipArrow(tokens []dslToken) int {
+	for i, tok := range tokens {
+		if tok.Quoted {
+			return -1
+		}
+		if tok.Value == "->" {
+			return i
+		}
+		if i > 0 {
+			break
+		}
+	}
+	return -1
+}
+
+func (p *dslParser) addPendingRelationship(stmt *dslStatement, tokens []dslToken, arrow int, parent *dslElement) error {
+	rel := pendingRelationship{Line: stmt.Line}
+	switch {
+	case arrow == 0 || strings.EqualFold(tokens[0].Value, "this"):
+		if parent == nil {
+			return fmt.Errorf("line %d: relationship without a source", stmt.Line)
+		}
+		rel.Source = parent
+	default:
+		rel.SourceRef = tokens[0].Value
+	}
+	rel.Destination = tokenValue(tokens, arrow+1)
+	if rel.Destination == "" {
+		return fmt.Errorf("line %d: relationship without a destination", stmt.Line)
+	}
+	rel.Description = tokenValue(tokens, arrow+2)
+	rel.Technology = tokenValue(tokens, arrow+3)
+	rel.Tags = tagArgs(tokens[min(arrow+4, len(tokens)):])
+	for _, s := range stmt.Block {
+		switch s.keyword() {
+		case "description":
+			rel.Description = s.arg(1)
+		case "technology":
+			rel.Technology = s.arg(1)
+		case "tags":
+			rel.Tags = append(rel.Tags, tagArgs(s.Tokens[1:])...)
+		}
+	}
+	p.rels = append(p.rels, rel)
+	return nil
+}
+
+// addElement declares a person, software system, container or component.
+//
+// person <name> [description] [tags]
+// softwareSystem <name> [description] [tags]
+// container <name> [description] [technology] [tags]
+// component <name> [description] [technology] [tags]
+func (p *dslParser) addElement(stmt *dslStatement, keyword string, tokens []dslToken, identifier string, parent *dslElement) (*dslElement, error) {
+	name := tokenValue(tokens, 1)
+	if name == "" {
+		return nil, fmt.Errorf("line %d: %s needs a name", stmt.Line, tokens[0].Value)
+	}
+	el := &Element{ID: p.newID(), Name: name, Description: tokenValue(tokens, 2)}
+	de := &dslElement{Element: el, Parent: parent}
+
+	var technology string
+	var tags []string
+	switch keyword {
+	case "person", "softwaresystem":
+		if parent != nil {
+			return nil, fmt.Errorf("line %d: %s must be declared at model level", stmt.Line, tokens[0].Value)
+		}
+		tags = tagArgs(tokens[min(3, len(tokens)):])
+	case "container":
+		if parent == nil || parent.Depth != 0 || parent.system == nil {
+			return nil, fmt.Errorf("line %d: container must be declared inside a software system", stmt.Line)
+		}
+		technology = tokenValue(tokens, 3)
+		tags = tagArgs(tokens[min(4, len(tokens)):])
+	case "component":
+		if parent == nil || parent.container == nil {
+			return nil, fmt.Errorf("line %d: component must be declared inside a container", stmt.Line)
+		}
+		technology = tokenValue(tokens, 3)
+		tags = tagArgs(tokens[min(4, len(tokens)):])
+	}
+
+	switch keyword {
+	case "person":
+		el.Tags = joinTags(TagElement+","+TagPerson, tags)
+		person := &Person{Element: *el}
+		p.ws.Model.People = append(p.ws.Model.People, person)
+		de.Element = &person.Element
+	case "softwaresystem":
+		el.Tags = joinTags(TagElement+","+TagSoftwareSystem, tags)
+		system := &SoftwareSystem{Element: *el}
+		p.ws.Model.SoftwareSystems = append(p.ws.Model.SoftwareSystems, system)
+		de.Element = &system.Element
+		de.system = system
+	case "container":
+		el.Tags = joinTags(TagElement+","+TagContainer, tags)
+		container := &Container{Element: *el, Technology: technology}
+		parent.system.Containers = append(parent.system.Containers, container)
+		de.Element = &container.Element
+		de.container = container
+		de.technology = &container.Technology
+		de.Depth = 1
+	case "component":
+		el.Tags = joinTags(TagElement+","+TagComponent, tags)
+		component := &Component{Element: *el, Technology: technology}
+		parent.container.Components = append(parent.container.Components, component)
+		de.Element = &component.Element
+		de.technology = &component.Technology
+		de.Depth = 2
+	}
+
+	if identifier != "" {
+		de.Identifier = identifier
+		if p.hierarchical && parent != nil && parent.Identifier != "" {
+			de.Identifier = parent.Identifier + "." + identifier
+		}
+		de.Element.Properties = map[string]string{PropertyIdentifier: de.Identifier}
+		p.identifiers[de.Identifier] = de
+		if _, taken := p.identifiers[identifier]; !taken {
+			p.identifiers[identifier] = de
+		}
+	}
+	p.byID[de.Element.ID] = de
+	p.order = append(p.order, de)
+	return de, nil
+}
+
+// parseDeploymentNode reads a deployment node:
+//
+// deploymentNode <name> [description] [technology] [tags] [instances] { ... }
+func (p *dslParser) parseDeploymentNode(stmt *dslStatement, env string) (*DeploymentNode, error) {
+	tokens := withoutIdentifier(stmt.Tokens)
+	node := &DeploymentNode{
+		Element: Element{
+			ID:          p.newID(),
+			Name:        tokenValue(tokens, 1),
+			Description: tokenValue(tokens, 2),
+			Tags:        joinTags(TagElement+","+TagDeploymentNode, tagArgs(tokens[min(4, len(tokens)):min(5, len(tokens))])),
+		},
+		Environment: env,
+		Technology:  tokenValue(tokens, 3),
+	}
+	if node.Name == "" {
+		return nil, fmt.Errorf("line %d: deploymentNode needs a name", stmt.Line)
+	}
+
+	for _, s := range stmt.Block {
+		tokens := withoutIdentifier(s.Tokens)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch strings.ToLower(tokens[0].Value) {
+		case "deploymentnode":
+			child, err := p.parseDeploymentNode(s, env)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+		case "infrastructurenode":
+			node.InfrastructureNodes = append(node.InfrastructureNodes, &InfrastructureNode{
+				Element: Element{
+					ID:          p.newID(),
+					Name:        tokenValue(tokens, 1),
+					Description: tokenValue(tokens, 2),
+					Tags:        joinTags(TagElement+","+TagInfrastructureNode, tagArgs(tokens[min(4, len(tokens)):])),
+				},
+				Environment: env,
+				Technology:  tokenValue(tokens, 3),
+			})
+		case "containerinstance":
+			instance := &ContainerInstance{
+				Element:     Element{ID: p.newID(), Tags: TagContainerInstance},
+				Environment: env,
+				InstanceID:  1,
+			}
+			node.ContainerInstances = append(node.ContainerInstances, instance)
+			p.instances = append(p.instances, pendingInstance{Line: s.Line, Instance: instance, Ref: tokenValue(tokens, 1)})
+		case "description":
+			node.Description = tokenValue(tokens, 1)
+		case "technology":
+			node.Technology = tokenValue(tokens, 1)
+		}
+	}
+	return node, nil
+}
+
+// resolvePending resolves relationship endpoints and container instance references.
+func (p *dslParser) resolvePending() error {
+	for _, rel := range p.rels {
+		source := rel.Source
+		if source == nil {
+			source = p.identifiers[rel.SourceRef]
+			if source == nil {
+				return fmt.Errorf("line %d: unknown identifier %q", rel.Line, rel.SourceRef)
+			}
+		}
+		destination := p.identifiers[rel.Destination]
+		if destination == nil {
+			return fmt.Errorf("line %d: unknown identifier %q", rel.Line, rel.Destination)
+		}
+		source.Element.Relationships = append(source.Element.Relationships, &Relationship{
+			ID:            p.newID(),
+			SourceID:      source.Element.ID,
+			DestinationID: destination.Element.ID,
+			Description:   rel.Description,
+			Technology:    rel.Technology,
+			Tags:          joinTags(TagRelationship, rel.Tags),
+		})
+	}
+	for _, pi := range p.instances {
+		container := p.identifiers[pi.Ref]
+		if container == nil || container.container == nil {
+			return fmt.Errorf("line %d: unknown container %q", pi.Line, pi.Ref)
+		}
+		pi.Instance.ContainerID = container.Element.ID
+	}
+	return nil
+}
+
+// parseViews reads the views block.
+func (p *dslParser) parseViews(stmts []*dslStatement) error {
+	for _, stmt := range stmts {
+		switch stmt.keyword() {
+		case "systemlandscape":
+			view := p.newView(stmt, "SystemLandscape", 1)
+			p.ws.Views.SystemLandscapeViews = append(p.ws.Views.SystemLandscapeViews, view)
+			p.fillDSLView(view, stmt, nil, "landscape")
+		case "systemcontext", "container", "component":
+			scope := p.identifiers[stmt.arg(1)]
+			if scope == nil {
+				return fmt.Errorf("line %d: unknown identifier %q", stmt.Line, stmt.arg(1))
+			}
+			switch stmt.keyword() {
+			case "systemcontext":
+				view := p.newView(stmt, "SystemContext", 2)
+				view.SoftwareSystemID = scope.Element.ID
+				p.ws.Views.SystemContextViews = append(p.ws.Views.SystemContextViews, view)
+				p.fillDSLView(view, stmt, scope, "context")
+			case "container":
+				view := p.newView(stmt, "Container", 2)
+				view.SoftwareSystemID = scope.Element.ID
+				p.ws.Views.ContainerViews = append(p.ws.Views.ContainerViews, view)
+				p.fillDSLView(view, stmt, scope, "children")
+			default:
+				view := p.newView(stmt, "Component", 2)
+				view.ContainerID = scope.Element.ID
+				p.ws.Views.ComponentViews = append(p.ws.Views.ComponentViews, view)
+				p.fillDSLView(view, stmt, scope, "children")
+			}
+		case "styles":
+			p.parseStyles(stmt.Block)
+		}
+	}
+	return nil
+}
+
+// newView creates a view from its header; keyIndex is the position of the key token.
+func (p *dslParser) newView(stmt *dslStatement, prefix string, keyIndex int) *View {
+	key := stmt.arg(keyIndex)
+	if key == "" {
+		p.viewCounts[prefix]++
+		key = fmt.Sprintf("%s-%03d", prefix, p.viewCounts[prefix])
+	}
+	view := &View{Key: key, Description: stmt.arg(keyIndex + 1)}
+	for _, s := range stmt.Block {
+		switch s.keyword() {
+		case "title":
+			view.Title = s.arg(1)
+		case "description":
+			view.Description = s.arg(1)
+		}
+	}
+	return view
+}
+
+// fillDSLView evaluates the include and exclude statements of a view. mode selects
+// what * means: "landscape" (people and systems), "context" (the scope and its
+// neighbours) or "children" (the children of the scope and their neighbours).
+func (p *dslParser) fillDSLView(view *View, stmt *dslStatement, scope *dslElement, mode string) {
+	visible := make(map[*dslElement]bool)
+	for _, s := range stmt.Block {
+		keyword := s.keyword()
+		if keyword != "include" && keyword != "exclude" {
+			continue
+		}
+		for _, tok := range s.Tokens[1:] {
+			for _, el := range p.selectDSL(tok.Value, scope, mode) {
+				if keyword == "include" {
+					visible[el] = true
+				} else {
+					delete(visible, el)
+				}
+			}
+		}
+	}
+	for _, de := range p.order {
+		if visible[de] {
+			view.Elements = append(view.Elements, &ElementView{ID: de.Element.ID})
+		}
+	}
+}
+
+// selectDSL returns the elements selected by an include or exclude expression.
+func (p *dslParser) selectDSL(expr string, scope *dslElement, mode string) []*dslElement {
+	switch {
+	case expr == "*":
+		return p.wildcard(scope, mode)
+	case strings.HasPrefix(strings.ToLower(expr), "element.tag=="):
+		tags := strings.Split(expr[len("element.tag=="):], ",")
+		var result []*dslElement
+		for _, de := range p.order {
+			for _, tag := range tags {
+				if hasTag(de.Element, tag) {
+					result = append(result, de)
+					break
+				}
+			}
+		}
+		return result
+	}
+	if de := p.identifiers[expr]; de != nil {
+		return []*dslElement{de}
+	}
+	return nil
+}
+
+// wildcard returns the elements selected by include * for a view.
+func (p *dslParser) wildcard(scope *dslElement, mode string) []*dslElement {
+	var result []*dslElement
+	if mode == "landscape" || scope == nil {
+		for _, de := range p.order {
+			if de.Parent == nil {
+				result = append(result, de)
+			}
+		}
+		return result
+	}
+
+	inScope := func(de *dslElement) bool {
+		for ; de != nil; de = de.Parent {
+			if de == scope {
+				return true
+			}
+		}
+		return false
+	}
+	// Neighbours are shown at the level of the scope's children when they share the
+	// scope's system, and as people or systems otherwise
+	lift := func(de *dslElement) *dslElement {
+		target := 0
+		if mode == "children" && scope.Depth >= 1 && topLevelOf(de) == topLevelOf(scope) {
+			target = scope.Depth
+		}
+		for de.Depth > target && de.Parent != nil {
+			de = de.Parent
+		}
+		return de
+	}
+
+	seen := make(map[*dslElement]bool)
+	add := func(de *dslElement) {
+		if !seen[de] {
+			seen[de] = true
+			result = append(result, de)
+		}
+	}
+	if mode == "context" {
+		add(scope)
+	} else {
+		for _, de := range p.order {
+			if de.Parent == scope {
+				add(de)
+			}
+		}
+	}
+	for _, de := range p.order {
+		for _, rel := range de.Element.Relationships {
+			source, destination := de, p.byID[rel.DestinationID]
+			switch {
+			case inScope(source) && !inScope(destination):
+				add(lift(destination))
+			case inScope(destination) && !inScope(source):
+				add(lift(source))
+			}
+		}
+	}
+	return result
+}
+
+// parseStyles reads element and relationship styles.
+func (p *dslParser) parseStyles(stmts []*dslStatement) {
+	styles := &p.ws.Views.Configuration.Styles
+	for _, stmt := range stmts {
+		props := make(map[string]string)
+		for _, s := range stmt.Block {
+			props[strings.ToLower(s.keyword())] = s.arg(1)
+		}
+		switch stmt.keyword() {
+		case "element":
+			style := &ElementStyle{Tag: stmt.arg(1)}
+			for key, value := range props {
+				setElementProperty(style, key, value)
+			}
+			styles.Elements = append(styles.Elements, style)
+		case "relationship":
+			style := &RelationshipStyle{Tag: stmt.arg(1)}
+			for key, value := range props {
+				setRelationshipProperty(style, key, value)
+			}
+			styles.Relationships = append(styles.Relationships, style)
+		}
+	}
+}
+
+// topLevelOf returns the top-level ancestor of an element.
+func topLevelOf(de *dslElement) *dslElement {
+	for de.Parent != nil {
+		de = de.Parent
+	}
+	return de
+}
+
+// withoutIdentifier strips the "identifier =" prefix of a statement.
+func withoutIdentifier(tokens []dslToken) []dslToken {
+	if len(tokens) >= 3 && !tokens[0].Quoted && !tokens[1].Quoted && tokens[1].Value == "=" {
+		return tokens[2:]
+	}
+	return tokens
+}
+
+// tokenValue returns the value of the i-th token, or "" when there are fewer tokens.
+func tokenValue(tokens []dslToken, i int) string {
+	if i < len(tokens) {
+		return tokens[i].Value
+	}
+	return ""
+}
+
+// tagArgs splits tag arguments, each of which may hold a comma-separated list.
+func tagArgs(tokens []dslToken) []string {
+	var tags []string
+	for _, tok := range tokens {
+		tags = append(tags, splitTags(tok.Value)...)
+	}
+	return tags
+}
+
+// joinTags appends tags to a comma-separated tag list, skipping duplicates.
+func joinTags(existing string, tags []string) string {
+	all := splitTags(existing)
+	for _, tag := range tags {
+		found := false
+		for _, t := range all {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			all = append(all, tag)
+		}
+	}
+	return strings.Join(all, ",")
+}
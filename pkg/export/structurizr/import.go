@@ -0,0 +1,482 @@
+package structurizr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// ImportJSON reads a Structurizr workspace JSON and converts it to a Sruja program.
+func ImportJSON(data []byte) (*language.Program, error) {
+	var ws Workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("invalid workspace JSON: %w", err)
+	}
+	return ToProgram(&ws), nil
+}
+
+// ImportDSL reads a Structurizr DSL workspace and converts it to a Sruja program.
+func ImportDSL(source string) (*language.Program, error) {
+	ws, err := ParseDSL(source)
+	if err != nil {
+		return nil, err
+	}
+	return ToProgram(ws), nil
+}
+
+// Reserved words that cannot be used as element IDs in Sruja.
+var reservedIDs = map[string]bool{
+	"description": true, "technology": true, "tech": true, "tags": true, "version": true,
+	"metadata": true, "style": true, "styles": true, "slo": true, "scale": true,
+	"status": true, "context": true, "decision": true, "consequences": true,
+	"category": true, "enforcement": true, "step": true, "external": true,
+	"story": true, "scenario": true, "flow": true, "policy": true, "import": true,
+	"from": true, "layout": true, "view": true, "include": true, "exclude": true,
+	"title": true, "extend": true, "deployment": true, "node": true,
+	"deploymentNode": true, "infrastructure": true, "containerInstance": true,
+	"rule": true, "kind": true, "tag": true, "overview": true, "element": true,
+}
+
+// importer converts a workspace to a program.
+type importer struct {
+	ws   *Workspace
+	prog *language.Program
+	// fqns maps Structurizr element IDs to Sruja FQNs.
+	fqns map[string]string
+	// kinds records the element kinds used, in order of first use.
+	kinds []string
+}
+
+// ToProgram converts a Structurizr workspace to a Sruja program.
+//
+// People and software systems become top-level person and system elements, with
+// their containers and components nested inside. Containers and components tagged
+// Database or Queue become database and queue elements. Element IDs come from the
+// structurizr.dsl.identifier property when present, or are derived from names.
+// Implied relationships are skipped. System landscape, system context, container
+// and component views become views listing their elements; deployment views are
+// skipped since Sruja derives them from the deployment nodes.
+func ToProgram(ws *Workspace) *language.Program {
+	imp := &importer{
+		ws: ws,
+		prog: &language.Program{
+			Model: &language.Model{},
+		},
+		fqns: make(map[string]string),
+	}
+	imp.convertModel()
+	imp.convertDeployment()
+	imp.convertViews()
+
+	if len(imp.kinds) > 0 {
+		spec := &language.Specification{}
+		for _, kind := range imp.kinds {
+			title := kindTitle(kind)
+			spec.Items = append(spec.Items, language.SpecificationItem{
+				Element: &language.ElementKindDef{Name: kind, Title: &title},
+			})
+		}
+		imp.prog.Specification = spec
+	}
+	return imp.prog
+}
+
+func (imp *importer) convertModel() {
+	ids := make(map[string]bool)
+	for _, person := range imp.ws.Model.People {
+		def := imp.element(&person.Element, "person", "", "", ids)
+		if person.Location == "External" {
+			addExternalTag(def)
+		}
+		imp.prog.Model.Items = append(imp.prog.Model.Items, language.ModelItem{ElementDef: def})
+	}
+	for _, system := range imp.ws.Model.SoftwareSystems {
+		def := imp.element(&system.Element, "system", "", "", ids)
+		if system.Location == "External" {
+			addExternalTag(def)
+		}
+		sysFQN := imp.fqns[system.ID]
+		containerIDs := make(map[string]bool)
+		for _, container := range system.Containers {
+			child := imp.element(&container.Element, nestedKind(container.Tags, "container"), container.Technology, sysFQN, containerIDs)
+			contFQN := imp.fqns[container.ID]
+			componentIDs := make(map[string]bool)
+			for _, component := range container.Components {
+				grandchild := imp.element(&component.Element, nestedKind(component.Tags, "component"), component.Technology, contFQN, componentIDs)
+				addChild(child, grandchild)
+			}
+			addChild(def, child)
+		}
+		imp.prog.Model.Items = append(imp.prog.Model.Items, language.ModelItem{ElementDef: def})
+	}
+
+	var rels []*Relationship
+	for _, person := range imp.ws.Model.People {
+		rels = append(rels, person.Relationships...)
+	}
+	for _, system := range imp.ws.Model.SoftwareSystems {
+		rels = append(rels, system.Relationships...)
+		for _, container := range system.Containers {
+			rels = append(rels, container.Relationships...)
+			for _, component := range container.Components {
+				rels = append(rels, component.Relationships...)
+			}
+		}
+	}
+	for _, rel := range rels {
+		if rel.LinkedRelationshipID != "" {
+			continue
+		}
+		if r := imp.relation(rel); r != nil {
+			imp.prog.Model.Items = append(imp.prog.Model.Items, language.ModelItem{Relation: r})
+		}
+	}
+}
+
+// element converts a Structurizr element to an element definition with a unique ID
+// among its siblings.
+func (imp *importer) element(el *Element, kind, technology, parent string, siblings map[string]bool) *language.ElementDef {
+	id := uniqueID(elementID(el), siblings)
+	fqn := id
+	if parent != "" {
+		fqn = parent + "." + id
+	}
+	imp.fqns[el.ID] = fqn
+	imp.useKind(kind)
+
+	assignment := &language.ElementAssignment{Name: id, Kind: kind}
+	if el.Name != "" {
+		name := el.Name
+		assignment.Title = &name
+	}
+	body := &language.ElementDefBody{}
+	if el.Description != "" {
+		description := el.Description
+		body.Items = append(body.Items, &language.BodyItem{Description: &description})
+	}
+	if technology != "" {
+		body.Items = append(body.Items, &language.BodyItem{Technology: &technology})
+	}
+	if tags := userTags(el.Tags); len(tags) > 0 {
+		body.Items = append(body.Items, &language.BodyItem{Tags: tags})
+	}
+	if len(body.Items) > 0 {
+		assignment.Body = body
+	}
+	return &language.ElementDef{Assignment: assignment}
+}
+
+// relation converts a relationship to a relation between FQNs. The description
+// becomes the verb and the technology the label, as in "calls" "HTTP".
+func (imp *importer) relation(rel *Relationship) *language.Relation {
+	from, to := imp.fqns[rel.SourceID], imp.fqns[rel.DestinationID]
+	if from == "" || to == "" {
+		return nil
+	}
+	r := &language.Relation{
+		From:  language.QualifiedIdent{Parts: strings.Split(from, ".")},
+		Arrow: "->",
+		To:    language.QualifiedIdent{Parts: strings.Split(to, ".")},
+	}
+	if rel.Description != "" {
+		description := rel.Description
+		r.Verb = &description
+	}
+	if rel.Technology != "" {
+		technology := rel.Technology
+		r.Label = &technology
+	}
+	for _, tag := range splitTags(rel.Tags) {
+		switch tag {
+		case TagRelationship:
+		case TagBidirectional:
+			r.Arrow = "<->"
+			r.Bidirectional = true
+		default:
+			r.Tags = append(r.Tags, identifier(tag))
+		}
+	}
+	return r
+}
+
+func (imp *importer) convertDeployment() {
+	var order []string
+	nodes := make(map[string][]*DeploymentNode)
+	for _, node := range imp.ws.Model.DeploymentNodes {
+		env := node.Environment
+		if env == "" {
+			env = defaultEnvironment
+		}
+		if _, ok := nodes[env]; !ok {
+			order = append(order, env)
+		}
+		nodes[env] = append(nodes[env], node)
+	}
+
+	envIDs := make(map[string]bool)
+	for _, env := range order {
+		block := &language.DeploymentNode{
+			Type:  "deployment",
+			ID:    uniqueID(identifier(env), envIDs),
+			Label: env,
+		}
+		ids := make(map[string]bool)
+		for _, node := range nodes[env] {
+			block.Items = append(block.Items, language.DeploymentNodeItem{Node: imp.deploymentNode(node, ids)})
+		}
+		imp.prog.Model.Items = append(imp.prog.Model.Items, language.ModelItem{DeploymentNode: block})
+	}
+}
+
+// deploymentNode converts a deployment node. Nodes and infrastructure nodes without
+// a description use their technology as description.
+func (imp *importer) deploymentNode(node *DeploymentNode, siblings map[string]bool) *language.DeploymentNode {
+	result := &language.DeploymentNode{
+		Type:        "node",
+		ID:          uniqueID(identifier(node.Name), siblings),
+		Label:       node.Name,
+		Description: descriptionOrTechnology(node.Description, node.Technology),
+	}
+	ids := make(map[string]bool)
+	for _, child := range node.Children {
+		result.Items = append(result.Items, language.DeploymentNodeItem{Node: imp.deploymentNode(child, ids)})
+	}
+	for _, infra := range node.InfrastructureNodes {
+		result.Items = append(result.Items, language.DeploymentNodeItem{Infrastructure: &language.InfrastructureNode{
+			ID:          uniqueID(identifier(infra.Name), ids),
+			Label:       infra.Name,
+			Description: descriptionOrTechnology(infra.Description, infra.Technology),
+		}})
+	}
+	for _, instance := range node.ContainerInstances {
+		fqn := imp.fqns[instance.ContainerID]
+		if fqn == "" {
+			continue
+		}
+		ci := &language.ContainerInstance{ContainerID: fqn[strings.LastIndex(fqn, ".")+1:]}
+		if instance.InstanceID > 1 {
+			instanceID := strconv.Itoa(instance.InstanceID)
+			ci.InstanceID = &instanceID
+		}
+		result.Items = append(result.Items, language.DeploymentNodeItem{ContainerInstance: ci})
+	}
+	return result
+}
+
+func (imp *importer) convertViews() {
+	var items []*language.ViewsItem
+	keys := make(map[string]bool)
+	add := func(view *View, scopeID string) {
+		def := &language.ViewDef{}
+		name := uniqueID(identifier(view.Key), keys)
+		def.Name = &name
+		if scope := imp.fqns[scopeID]; scope != "" {
+			def.Of = &language.QualifiedIdent{Parts: strings.Split(scope, ".")}
+		}
+		body := &language.ViewBody{}
+		if title := view.Title; title != "" || view.Description != "" {
+			if title == "" {
+				title = view.Description
+			}
+			body.Items = append(body.Items, &language.ViewItem{Title: &title})
+		}
+		include := &language.IncludePredicate{}
+		for _, el := range view.Elements {
+			if fqn := imp.fqns[el.ID]; fqn != "" {
+				include.Expressions = append(include.Expressions, selectorExpr(fqn))
+			}
+		}
+		if len(include.Expressions) == 0 {
+			include.Expressions = append(include.Expressions, language.ViewExpr{Wildcard: true})
+		}
+		body.Items = append(body.Items, &language.ViewItem{Include: include})
+		def.Body = body
+		items = append(items, &language.ViewsItem{View: def})
+	}
+
+	for _, view := range imp.ws.Views.SystemLandscapeViews {
+		add(view, "")
+	}
+	// System context views have no Sruja scope; they list their elements instead
+	for _, view := range imp.ws.Views.SystemContextViews {
+		add(view, "")
+	}
+	for _, view := range imp.ws.Views.ContainerViews {
+		add(view, view.SoftwareSystemID)
+	}
+	for _, view := range imp.ws.Views.ComponentViews {
+		add(view, view.ContainerID)
+	}
+	if styles := styleDecl(imp.ws.Views.Configuration.Styles); styles != nil {
+		items = append(items, &language.ViewsItem{Styles: styles})
+	}
+	if len(items) > 0 {
+		imp.prog.Views = &language.Views{Items: items}
+	}
+}
+
+func (imp *importer) useKind(kind string) {
+	for _, k := range imp.kinds {
+		if k == kind {
+			return
+		}
+	}
+	imp.kinds = append(imp.kinds, kind)
+}
+
+// selectorExpr builds the view expression selecting an element by FQN.
+func selectorExpr(fqn string) language.ViewExpr {
+	parts := strings.Split(fqn, ".")
+	expr := language.ViewExpr{Selector: &parts[0]}
+	var last *language.ViewExprSuffix
+	for i := 1; i < len(parts); i++ {
+		suffix := &language.ViewExprSuffix{Ident: &parts[i]}
+		if last == nil {
+			expr.Sub = suffix
+		} else {
+			last.Next = suffix
+		}
+		last = suffix
+	}
+	return expr
+}
+
+// nestedKind returns the Sruja kind for a container or component, using the
+// database and queue kinds for elements tagged Database or Queue.
+func nestedKind(tags, kind string) string {
+	for _, tag := range splitTags(tags) {
+		switch tag {
+		case TagDatabase:
+			return "database"
+		case TagQueue:
+			return "queue"
+		}
+	}
+	return kind
+}
+
+// userTags returns the tags of an element without the Structurizr default tags.
+func userTags(tags string) []string {
+	var result []string
+	for _, tag := range splitTags(tags) {
+		switch tag {
+		case TagElement, TagPerson, TagSoftwareSystem, TagContainer, TagComponent, TagDatabase, TagQueue:
+			continue
+		}
+		result = append(result, tagIdent(tag))
+	}
+	return result
+}
+
+func addExternalTag(def *language.ElementDef) {
+	assignment := def.Assignment
+	if assignment.Body == nil {
+		assignment.Body = &language.ElementDefBody{}
+	}
+	for _, item := range assignment.Body.Items {
+		for _, tag := range item.Tags {
+			if strings.EqualFold(tag, "external") {
+				return
+			}
+		}
+		if len(item.Tags) > 0 {
+			item.Tags = append(item.Tags, "external")
+			return
+		}
+	}
+	assignment.Body.Items = append(assignment.Body.Items, &language.BodyItem{Tags: []string{"external"}})
+}
+
+func addChild(parent, child *language.ElementDef) {
+	assignment := parent.Assignment
+	if assignment.Body == nil {
+		assignment.Body = &language.ElementDefBody{}
+	}
+	assignment.Body.Items = append(assignment.Body.Items, &language.BodyItem{Element: child})
+}
+
+// elementID returns the Sruja ID for an element: the last part of its DSL
+// identifier when present, otherwise an identifier derived from its name.
+func elementID(el *Element) string {
+	if ident := el.Properties[PropertyIdentifier]; ident != "" {
+		return identifier(ident[strings.LastIndex(ident, ".")+1:])
+	}
+	return identifier(el.Name)
+}
+
+// identifier converts a name to a camelCase Sruja identifier, e.g. "Web App" to
+// webApp. Single words keep their case, and all-caps first words stay as written
+// ("AWS Cloud" becomes AWSCloud).
+func identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_'
+	})
+	var sb strings.Builder
+	for i, word := range words {
+		switch {
+		case len(words) == 1 || (i == 0 && strings.ToUpper(word) == word):
+			sb.WriteString(word)
+		case i == 0:
+			sb.WriteString(strings.ToLower(word[:1]) + word[1:])
+		default:
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	id := sb.String()
+	switch {
+	case id == "":
+		id = "element"
+	case id[0] >= '0' && id[0] <= '9':
+		id = "_" + id
+	}
+	if reservedIDs[id] {
+		id += "_"
+	}
+	return id
+}
+
+// tagIdent converts a tag to a form usable as a #tag reference, e.g. "Existing System"
+// to Existing-System.
+func tagIdent(tag string) string {
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.TrimSpace(tag))
+	if id == "" || id[0] == '-' || (id[0] >= '0' && id[0] <= '9') {
+		id = "_" + id
+	}
+	return id
+}
+
+// uniqueID returns id, or id with a numeric suffix when it is already taken.
+func uniqueID(id string, taken map[string]bool) string {
+	result := id
+	for n := 2; taken[result]; n++ {
+		result = id + strconv.Itoa(n)
+	}
+	taken[result] = true
+	return result
+}
+
+func descriptionOrTechnology(description, technology string) *string {
+	switch {
+	case description != "":
+		return &description
+	case technology != "":
+		return &technology
+	}
+	return nil
+}
+
+// kindTitle returns the display title of an element kind.
+func kindTitle(kind string) string {
+	if kind == "" {
+		return ""
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
@@ -0,0 +1,460 @@
+// Package structurizr converts between Sruja programs and Structurizr workspaces.
+//
+// The exporter writes a workspace JSON with the model (people, software systems,
+// containers and components), deployment environments, views and styles. The
+// importers read a workspace JSON or a Structurizr DSL file and build a Sruja
+// program, which can be printed with the language printer.
+package structurizr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// Config represents Structurizr workspace export configuration.
+type Config struct {
+	Name          string // Workspace name
+	Description   string // Optional workspace description
+	RankDirection string // Automatic layout direction: TopBottom (default), BottomTop, LeftRight or RightLeft
+}
+
+// DefaultConfig returns the default Structurizr export configuration.
+func DefaultConfig() Config {
+	return Config{
+		Name:          "Sruja Workspace",
+		RankDirection: "TopBottom",
+	}
+}
+
+// Exporter handles Structurizr workspace generation.
+type Exporter struct {
+	Config Config
+}
+
+// NewExporter creates a new Structurizr exporter.
+func NewExporter(config Config) *Exporter {
+	return &Exporter{Config: config}
+}
+
+// Export generates an indented Structurizr workspace JSON from a program.
+func (e *Exporter) Export(prog *language.Program) (string, error) {
+	ws, err := e.ToWorkspace(prog)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal workspace: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToWorkspace converts a program to a Structurizr workspace.
+//
+// Top-level persons become people and other top-level elements software systems;
+// their children become containers and grandchildren components. Deeper elements
+// are folded into their component. Declared views become system landscape views,
+// or container (component) views when they are scoped to a system (container).
+// When no views are declared, a system landscape view and a container view per
+// system are generated. Each deployment environment gets a deployment view.
+func (e *Exporter) ToWorkspace(prog *language.Program) (*Workspace, error) {
+	ws := &Workspace{
+		Name:        e.Config.Name,
+		Description: e.Config.Description,
+	}
+	if prog == nil || prog.Model == nil {
+		return ws, nil
+	}
+
+	b := newBuilder(prog, e.Config)
+	b.buildModel(ws)
+	b.buildDeployment(ws)
+	if err := b.buildViews(ws); err != nil {
+		return nil, err
+	}
+	ws.Views.Configuration.Styles = convertStyles(prog)
+	return ws, nil
+}
+
+// Element kinds that do not describe architecture and are left out of the workspace.
+var skippedKinds = map[string]bool{
+	"requirement": true,
+	"adr":         true,
+	"policy":      true,
+	"flow":        true,
+	"scenario":    true,
+	"story":       true,
+}
+
+// modelElement is a Sruja element indexed by FQN. Element is nil for elements
+// nested below components, which are folded into their component.
+type modelElement struct {
+	FQN     string
+	Kind    string
+	Element *Element
+}
+
+// modelRelation is an explicit relation between two elements, with FQN endpoints.
+type modelRelation struct {
+	From          string
+	To            string
+	Description   string
+	Technology    string
+	Tags          []string
+	Bidirectional bool
+}
+
+// builder accumulates the workspace while walking the program.
+type builder struct {
+	prog     *language.Program
+	config   Config
+	nextID   int
+	elements map[string]*modelElement
+	order    []string
+	suffixes map[string][]string
+	byID     map[string]*modelElement
+
+	// relationships holds every model relationship (explicit and implied), in order.
+	relationships []*Relationship
+	// environments holds the deployment environments, in declaration order.
+	environments []*environment
+}
+
+func newBuilder(prog *language.Program, config Config) *builder {
+	return &builder{
+		prog:     prog,
+		config:   config,
+		elements: make(map[string]*modelElement),
+		suffixes: make(map[string][]string),
+		byID:     make(map[string]*modelElement),
+	}
+}
+
+func (b *builder) newID() string {
+	b.nextID++
+	return strconv.Itoa(b.nextID)
+}
+
+// buildModel adds people, software systems, containers, components and their relationships.
+func (b *builder) buildModel(ws *Workspace) {
+	type scopedRelation struct {
+		rel   *language.Relation
+		scope string
+	}
+	var rels []scopedRelation
+
+	var walk func(def *language.ElementDef, parent string, depth int, system *SoftwareSystem, container *Container)
+	walk = func(def *language.ElementDef, parent string, depth int, system *SoftwareSystem, container *Container) {
+		id := def.GetID()
+		kind := strings.ToLower(def.GetKind())
+		if id == "" || skippedKinds[kind] {
+			return
+		}
+		fqn := id
+		if parent != "" {
+			fqn = parent + "." + id
+		}
+
+		if depth <= 2 {
+			el, technology := b.newElement(def, fqn, kind)
+			switch {
+			case depth == 0 && (kind == "person" || kind == "actor"):
+				person := &Person{Element: *el}
+				if hasTag(el, "external") {
+					person.Location = "External"
+				}
+				ws.Model.People = append(ws.Model.People, person)
+				b.register(fqn, kind, &person.Element)
+			case depth == 0:
+				system = &SoftwareSystem{Element: *el}
+				if hasTag(el, "external") {
+					system.Location = "External"
+				}
+				ws.Model.SoftwareSystems = append(ws.Model.SoftwareSystems, system)
+				b.register(fqn, kind, &system.Element)
+			case depth == 1:
+				container = &Container{Element: *el, Technology: technology}
+				system.Containers = append(system.Containers, container)
+				b.register(fqn, kind, &container.Element)
+			default:
+				component := &Component{Element: *el, Technology: technology}
+				container.Components = append(container.Components, component)
+				b.register(fqn, kind, &component.Element)
+			}
+		} else {
+			b.register(fqn, kind, nil)
+		}
+
+		if body := def.GetBody(); body != nil {
+			for _, item := range body.Items {
+				// People have no children in Structurizr
+				if item.Element != nil && (depth > 0 || system != nil) {
+					walk(item.Element, fqn, depth+1, system, container)
+				}
+				if item.Relation != nil {
+					rels = append(rels, scopedRelation{rel: item.Relation, scope: fqn})
+				}
+			}
+		}
+	}
+	for _, item := range b.prog.Model.Items {
+		if item.ElementDef != nil {
+			walk(item.ElementDef, "", 0, nil, nil)
+		}
+		// Implied parent relations are recreated below the way Structurizr
+		// creates implied relationships.
+		if item.Relation != nil && !item.Relation.Implied {
+			rels = append(rels, scopedRelation{rel: item.Relation})
+		}
+	}
+
+	var relations []modelRelation
+	seen := make(map[string]bool)
+	for _, sr := range rels {
+		from := b.exported(b.resolve(sr.rel.From.String(), sr.scope))
+		to := b.exported(b.resolve(sr.rel.To.String(), sr.scope))
+		if from == "" || to == "" || from == to {
+			continue
+		}
+		// A verb with a label reads as "calls" "HTTP": the label is the technology
+		description := getString(sr.rel.Verb)
+		technology := getString(sr.rel.Label)
+		if description == "" {
+			description, technology = technology, ""
+		}
+		if technology == "" {
+			technology = relationshipKindTechnology(b.prog.Specification, sr.rel.Tags)
+		}
+		key := from + "->" + to + ":" + description
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		relations = append(relations, modelRelation{
+			From:          from,
+			To:            to,
+			Description:   description,
+			Technology:    technology,
+			Tags:          sr.rel.Tags,
+			Bidirectional: sr.rel.Bidirectional,
+		})
+	}
+
+	for _, rel := range relations {
+		tags := []string{TagRelationship}
+		if rel.Bidirectional {
+			tags = append(tags, TagBidirectional)
+		}
+		tags = append(tags, rel.Tags...)
+		b.addRelationship(b.elements[rel.From].Element, &Relationship{
+			SourceID:      b.elements[rel.From].Element.ID,
+			DestinationID: b.elements[rel.To].Element.ID,
+			Description:   rel.Description,
+			Technology:    rel.Technology,
+			Tags:          strings.Join(tags, ","),
+		})
+	}
+	b.addImpliedRelationships()
+}
+
+// newElement creates the Structurizr element for an element definition and returns
+// it with the element's technology.
+func (b *builder) newElement(def *language.ElementDef, fqn, kind string) (*Element, string) {
+	el := &Element{
+		ID:         b.newID(),
+		Name:       def.GetID(),
+		Properties: map[string]string{PropertyIdentifier: fqn},
+	}
+	if title := getString(def.GetTitle()); title != "" {
+		el.Name = title
+	}
+
+	var technology string
+	var tags []string
+	for _, tag := range def.GetTagRefs() {
+		tags = append(tags, strings.TrimPrefix(tag, "#"))
+	}
+	if body := def.GetBody(); body != nil {
+		for _, item := range body.Items {
+			switch {
+			case item.Description != nil && el.Description == "":
+				el.Description = *item.Description
+			case item.Technology != nil && technology == "":
+				technology = *item.Technology
+			case len(item.Tags) > 0:
+				tags = append(tags, item.Tags...)
+			case len(item.TagRefs) > 0:
+				for _, tag := range item.TagRefs {
+					tags = append(tags, strings.TrimPrefix(tag, "#"))
+				}
+			}
+		}
+	}
+	el.Tags = strings.Join(append(kindTags(kind, strings.Count(fqn, ".")), tags...), ",")
+	return el, technology
+}
+
+// kindTags returns the Structurizr tags for an element of the given kind at the given depth.
+func kindTags(kind string, depth int) []string {
+	tags := []string{TagElement}
+	switch {
+	case depth == 0 && (kind == "person" || kind == "actor"):
+		return append(tags, TagPerson)
+	case depth == 0:
+		tags = append(tags, TagSoftwareSystem)
+	case depth == 1:
+		tags = append(tags, TagContainer)
+	default:
+		tags = append(tags, TagComponent)
+	}
+	switch kind {
+	case "person", "actor", "system", "container", "component":
+	case "database", "datastore", "db", "storage":
+		tags = append(tags, TagDatabase)
+	case "queue", "mq":
+		tags = append(tags, TagQueue)
+	default:
+		tags = append(tags, kind)
+	}
+	return tags
+}
+
+func (b *builder) register(fqn, kind string, el *Element) {
+	me := &modelElement{FQN: fqn, Kind: kind, Element: el}
+	b.elements[fqn] = me
+	if el != nil {
+		b.byID[el.ID] = me
+	}
+	b.order = append(b.order, fqn)
+	id := fqn
+	if i := strings.LastIndex(fqn, "."); i >= 0 {
+		id = fqn[i+1:]
+	}
+	b.suffixes[id] = append(b.suffixes[id], fqn)
+}
+
+// resolve returns the FQN of ref, looked up relative to scope first, then as an
+// FQN, then by unique suffix. Returns "" if ref cannot be resolved.
+func (b *builder) resolve(ref, scope string) string {
+	if ref == "" {
+		return ""
+	}
+	for s := scope; s != ""; s = parentOf(s) {
+		if _, ok := b.elements[s+"."+ref]; ok {
+			return s + "." + ref
+		}
+	}
+	if _, ok := b.elements[ref]; ok {
+		return ref
+	}
+	last := ref
+	if i := strings.LastIndex(ref, "."); i >= 0 {
+		last = ref[i+1:]
+	}
+	var match string
+	for _, fqn := range b.suffixes[last] {
+		if strings.HasSuffix(fqn, "."+ref) {
+			if match != "" {
+				return ""
+			}
+			match = fqn
+		}
+	}
+	return match
+}
+
+// exported returns fqn or its closest exported ancestor, or "".
+func (b *builder) exported(fqn string) string {
+	for id := fqn; id != ""; id = parentOf(id) {
+		if me, ok := b.elements[id]; ok && me.Element != nil {
+			return id
+		}
+	}
+	return ""
+}
+
+func (b *builder) addRelationship(source *Element, rel *Relationship) {
+	rel.ID = b.newID()
+	source.Relationships = append(source.Relationships, rel)
+	b.relationships = append(b.relationships, rel)
+}
+
+// addImpliedRelationships adds relationships between the ancestors of related
+// elements, unless a relationship between them already exists. This matches the
+// default implied relationships strategy of Structurizr.
+func (b *builder) addImpliedRelationships() {
+	exists := make(map[string]bool)
+	for _, rel := range b.relationships {
+		exists[rel.SourceID+"->"+rel.DestinationID] = true
+	}
+	explicit := append([]*Relationship(nil), b.relationships...)
+	for _, rel := range explicit {
+		from := b.byID[rel.SourceID].FQN
+		to := b.byID[rel.DestinationID].FQN
+		for src := from; src != ""; src = parentOf(src) {
+			for dst := to; dst != ""; dst = parentOf(dst) {
+				if src == from && dst == to {
+					continue
+				}
+				if src == dst || strings.HasPrefix(dst, src+".") || strings.HasPrefix(src, dst+".") {
+					continue
+				}
+				source := b.elements[src].Element
+				destination := b.elements[dst].Element
+				key := source.ID + "->" + destination.ID
+				if exists[key] {
+					continue
+				}
+				exists[key] = true
+				b.addRelationship(source, &Relationship{
+					SourceID:             source.ID,
+					DestinationID:        destination.ID,
+					Description:          rel.Description,
+					Technology:           rel.Technology,
+					Tags:                 rel.Tags,
+					LinkedRelationshipID: rel.ID,
+				})
+			}
+		}
+	}
+}
+
+// hasTag reports whether an element carries a tag, ignoring case.
+func hasTag(el *Element, tag string) bool {
+	for _, t := range splitTags(el.Tags) {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTags splits a comma-separated Structurizr tag list.
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// parentOf returns the FQN of the parent element, or "" for top-level elements.
+func parentOf(fqn string) string {
+	if i := strings.LastIndex(fqn, "."); i >= 0 {
+		return fqn[:i]
+	}
+	return ""
+}
+
+func getString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
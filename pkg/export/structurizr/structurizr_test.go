@@ -0,0 +1,377 @@
+package structurizr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const testDSL = `
+User = person "User"
+Shop = system "Shop" {
+	description "Online shop"
+	API = container "API" {
+		technology "Go"
+		Handler = component "Handler"
+	}
+	DB = database "DB"
+	API -> DB "reads" "SQL"
+}
+Stripe = system "Stripe" {
+	tags ["external"]
+}
+User -> Shop.API "uses"
+Shop.API -> Stripe "charges" "HTTPS"
+
+deployment Prod "Production" {
+	node AWS "AWS" {
+		infrastructure LB "Load Balancer"
+		containerInstance API
+		containerInstance DB
+	}
+}
+
+view containers of Shop {
+	title "Shop Containers"
+	include *
+}
+
+style {
+	person {
+		shape "person"
+		background "#08427b"
+	}
+	relationship {
+		dashed "false"
+	}
+}
+`
+
+func parseDSL(t *testing.T, dsl string) *language.Program {
+	t.Helper()
+	p, err := language.NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	prog, _, err := p.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return prog
+}
+
+func toWorkspace(t *testing.T) *Workspace {
+	t.Helper()
+	ws, err := NewExporter(DefaultConfig()).ToWorkspace(parseDSL(t, testDSL))
+	if err != nil {
+		t.Fatalf("ToWorkspace failed: %v", err)
+	}
+	return ws
+}
+
+func TestExport_Model(t *testing.T) {
+	ws := toWorkspace(t)
+
+	if len(ws.Model.People) != 1 || ws.Model.People[0].Name != "User" {
+		t.Fatalf("Expected one person User, got %+v", ws.Model.People)
+	}
+	if len(ws.Model.SoftwareSystems) != 2 {
+		t.Fatalf("Expected 2 software systems, got %d", len(ws.Model.SoftwareSystems))
+	}
+	shop := ws.Model.SoftwareSystems[0]
+	if shop.Description != "Online shop" || len(shop.Containers) != 2 {
+		t.Fatalf("Unexpected Shop system: %+v", shop)
+	}
+	api := shop.Containers[0]
+	if api.Technology != "Go" || len(api.Components) != 1 {
+		t.Errorf("Unexpected API container: %+v", api)
+	}
+	if api.Properties[PropertyIdentifier] != "Shop.API" {
+		t.Errorf("Expected identifier Shop.API, got %q", api.Properties[PropertyIdentifier])
+	}
+	if !hasTag(&shop.Containers[1].Element, TagDatabase) {
+		t.Errorf("Expected DB to be tagged %s, got %q", TagDatabase, shop.Containers[1].Tags)
+	}
+	if stripe := ws.Model.SoftwareSystems[1]; stripe.Location != "External" {
+		t.Errorf("Expected Stripe to be external, got %q", stripe.Location)
+	}
+
+	var reads *Relationship
+	for _, rel := range api.Relationships {
+		if rel.Description == "reads" {
+			reads = rel
+		}
+	}
+	if reads == nil || reads.Technology != "SQL" || reads.DestinationID != shop.Containers[1].ID {
+		t.Errorf("Expected API -> DB reads/SQL relationship, got %+v", api.Relationships)
+	}
+
+	// User -> Shop.API implies User -> Shop
+	var implied *Relationship
+	for _, rel := range ws.Model.People[0].Relationships {
+		if rel.DestinationID == shop.ID {
+			implied = rel
+		}
+	}
+	if implied == nil || implied.LinkedRelationshipID == "" {
+		t.Errorf("Expected implied User -> Shop relationship, got %+v", ws.Model.People[0].Relationships)
+	}
+}
+
+func TestExport_ViewsAndDeployment(t *testing.T) {
+	ws := toWorkspace(t)
+
+	if len(ws.Views.ContainerViews) != 1 {
+		t.Fatalf("Expected 1 container view, got %d", len(ws.Views.ContainerViews))
+	}
+	view := ws.Views.ContainerViews[0]
+	if view.Key != "containers" || view.Title != "Shop Containers" {
+		t.Errorf("Unexpected container view: %+v", view)
+	}
+	if view.SoftwareSystemID != ws.Model.SoftwareSystems[0].ID {
+		t.Errorf("Expected container view of Shop, got %q", view.SoftwareSystemID)
+	}
+	if len(view.Elements) == 0 || len(view.Relationships) == 0 {
+		t.Errorf("Expected elements and relationships in container view, got %+v", view)
+	}
+
+	if len(ws.Model.DeploymentNodes) != 1 {
+		t.Fatalf("Expected 1 deployment node, got %d", len(ws.Model.DeploymentNodes))
+	}
+	aws := ws.Model.DeploymentNodes[0]
+	if aws.Environment != "Production" || len(aws.InfrastructureNodes) != 1 || len(aws.ContainerInstances) != 2 {
+		t.Errorf("Unexpected deployment node: %+v", aws)
+	}
+	if len(aws.ContainerInstances[0].Relationships) != 1 {
+		t.Errorf("Expected API instance -> DB instance relationship, got %+v", aws.ContainerInstances[0].Relationships)
+	}
+	if len(ws.Views.DeploymentViews) != 1 || ws.Views.DeploymentViews[0].Environment != "Production" {
+		t.Errorf("Expected a Production deployment view, got %+v", ws.Views.DeploymentViews)
+	}
+}
+
+func TestExport_Styles(t *testing.T) {
+	styles := toWorkspace(t).Views.Configuration.Styles
+	if len(styles.Elements) != 1 {
+		t.Fatalf("Expected 1 element style, got %d", len(styles.Elements))
+	}
+	if person := styles.Elements[0]; person.Tag != TagPerson || person.Shape != "Person" || person.Background != "#08427b" {
+		t.Errorf("Unexpected person style: %+v", person)
+	}
+	if len(styles.Relationships) != 1 || styles.Relationships[0].Dashed == nil || *styles.Relationships[0].Dashed {
+		t.Errorf("Expected solid relationship style, got %+v", styles.Relationships)
+	}
+}
+
+func TestExport_RelationshipKinds(t *testing.T) {
+	ws, err := NewExporter(DefaultConfig()).ToWorkspace(parseDSL(t, `
+		publishes = relationship "Publishes" {
+			technology "Kafka"
+			async
+		}
+		replicates = relationship "Replicates"
+		Orders = system "Orders"
+		Billing = system "Billing"
+		Orders -> Billing "Order placed" [publishes]
+	`))
+	if err != nil {
+		t.Fatalf("ToWorkspace failed: %v", err)
+	}
+
+	styles := ws.Views.Configuration.Styles.Relationships
+	if len(styles) != 1 || styles[0].Tag != "publishes" || styles[0].Dashed == nil || !*styles[0].Dashed {
+		t.Errorf("Expected a dashed style for publishes relationships only, got %+v", styles)
+	}
+	rels := ws.Model.SoftwareSystems[0].Relationships
+	if len(rels) != 1 || rels[0].Technology != "Kafka" || !strings.Contains(rels[0].Tags, "publishes") {
+		t.Errorf("Expected a Kafka relationship tagged publishes, got %+v", rels)
+	}
+}
+
+func TestExport_DefaultViews(t *testing.T) {
+	ws, err := NewExporter(DefaultConfig()).ToWorkspace(parseDSL(t, `
+		User = person "User"
+		S = system "Sys" {
+			C = container "Cont"
+		}
+		User -> S.C "uses"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.Views.SystemLandscapeViews) != 1 || len(ws.Views.SystemLandscapeViews[0].Elements) != 2 {
+		t.Errorf("Expected a landscape view with 2 elements, got %+v", ws.Views.SystemLandscapeViews)
+	}
+	if len(ws.Views.ContainerViews) != 1 || ws.Views.ContainerViews[0].Key != "Containers-S" {
+		t.Fatalf("Expected a Containers-S view, got %+v", ws.Views.ContainerViews)
+	}
+	if got := len(ws.Views.ContainerViews[0].Elements); got != 2 {
+		t.Errorf("Expected container and user in container view, got %d elements", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	output, err := NewExporter(DefaultConfig()).Export(parseDSL(t, testDSL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, err := ImportJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	source := language.NewPrinter().Print(prog)
+	for _, want := range []string{
+		`Shop = system "Shop"`,
+		`API = container "API"`,
+		`technology "Go"`,
+		`Shop.API -> Shop.DB "reads" "SQL"`,
+		`deployment Production "Production"`,
+		`view containers of Shop`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected %q in imported source, got:\n%s", want, source)
+		}
+	}
+
+	// The imported source parses and exports to the same model
+	ws, err := NewExporter(DefaultConfig()).ToWorkspace(parseDSL(t, source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.Model.People) != 1 || len(ws.Model.SoftwareSystems) != 2 || len(ws.Model.DeploymentNodes) != 1 {
+		t.Errorf("Unexpected round-tripped model: %+v", ws.Model)
+	}
+}
+
+func TestExport_ImportedWorkspace(t *testing.T) {
+	output, err := NewExporter(DefaultConfig()).Export(parseDSL(t, testDSL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, err := ImportJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	// Imported relations have no source position but are still exported
+	ws, err := NewExporter(DefaultConfig()).ToWorkspace(prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := ws.Model.SoftwareSystems[0].Containers[0]
+	if len(api.Relationships) == 0 || api.Relationships[0].Description != "reads" {
+		t.Errorf("Expected API -> DB reads relationship, got %+v", api.Relationships)
+	}
+}
+
+func TestParseDSL(t *testing.T) {
+	ws, err := ParseDSL(`
+workspace "Shop" "An online shop" {
+	!identifiers hierarchical
+
+	model {
+		user = person "User"
+		shop = softwareSystem "Shop" {
+			api = container "API" "Serves requests" "Go" {
+				tags "Web"
+			}
+			db = container "DB" {
+				technology "PostgreSQL"
+				tags "Database"
+			}
+			api -> db "Reads from" "SQL"
+		}
+		user -> shop.api "Uses"
+
+		live = deploymentEnvironment "Live" {
+			deploymentNode "Server" {
+				containerInstance shop.api
+			}
+		}
+	}
+
+	views {
+		systemContext shop {
+			include *
+		}
+		container shop "Containers" {
+			include *
+		}
+		styles {
+			element "Database" {
+				shape cylinder
+			}
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatalf("ParseDSL failed: %v", err)
+	}
+	if ws.Name != "Shop" || ws.Description != "An online shop" {
+		t.Errorf("Unexpected workspace header: %q %q", ws.Name, ws.Description)
+	}
+	if len(ws.Model.SoftwareSystems) != 1 || len(ws.Model.SoftwareSystems[0].Containers) != 2 {
+		t.Fatalf("Unexpected model: %+v", ws.Model)
+	}
+	api := ws.Model.SoftwareSystems[0].Containers[0]
+	if api.Description != "Serves requests" || api.Technology != "Go" || !hasTag(&api.Element, "Web") {
+		t.Errorf("Unexpected api container: %+v", api)
+	}
+	if api.Properties[PropertyIdentifier] != "shop.api" {
+		t.Errorf("Expected hierarchical identifier shop.api, got %q", api.Properties[PropertyIdentifier])
+	}
+	if len(api.Relationships) != 1 || api.Relationships[0].Technology != "SQL" {
+		t.Errorf("Expected api -> db relationship, got %+v", api.Relationships)
+	}
+	if len(ws.Model.DeploymentNodes) != 1 || len(ws.Model.DeploymentNodes[0].ContainerInstances) != 1 {
+		t.Errorf("Unexpected deployment nodes: %+v", ws.Model.DeploymentNodes)
+	}
+	if len(ws.Views.SystemContextViews) != 1 || ws.Views.SystemContextViews[0].Key != "SystemContext-001" {
+		t.Errorf("Expected generated system context view key, got %+v", ws.Views.SystemContextViews)
+	}
+	if len(ws.Views.ContainerViews) != 1 || len(ws.Views.ContainerViews[0].Elements) != 3 {
+		t.Errorf("Expected container view with 3 elements, got %+v", ws.Views.ContainerViews)
+	}
+	if styles := ws.Views.Configuration.Styles.Elements; len(styles) != 1 || styles[0].Shape != "Cylinder" {
+		t.Errorf("Expected cylinder style for Database, got %+v", styles)
+	}
+
+	prog := ToProgram(ws)
+	source := language.NewPrinter().Print(prog)
+	for _, want := range []string{
+		`api = container "API"`,
+		`shop.api -> shop.db "Reads from" "SQL"`,
+		`containerInstance api`,
+		"database {",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected %q in imported source, got:\n%s", want, source)
+		}
+	}
+}
+
+func TestParseDSL_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing workspace":  `model { }`,
+		"unknown identifier": "workspace {\n model {\n a = person \"A\"\n a -> b\n }\n}",
+		"unclosed block":     `workspace { model {`,
+	}
+	for name, source := range tests {
+		if _, err := ParseDSL(source); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestImportJSON_Invalid(t *testing.T) {
+	if _, err := ImportJSON([]byte("{")); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	var ws Workspace
+	if err := json.Unmarshal([]byte(`{"name": "Empty"}`), &ws); err != nil {
+		t.Fatal(err)
+	}
+	if prog := ToProgram(&ws); prog.Model == nil {
+		t.Error("Expected an empty model for an empty workspace")
+	}
+}
@@ -0,0 +1,287 @@
+package structurizr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// selectorTags maps Sruja style selectors to the Structurizr tags they style.
+var selectorTags = map[string]string{
+	"element":   TagElement,
+	"person":    TagPerson,
+	"actor":     TagPerson,
+	"system":    TagSoftwareSystem,
+	"container": TagContainer,
+	"component": TagComponent,
+	"database":  TagDatabase,
+	"queue":     TagQueue,
+}
+
+// relationshipSelectors are the Sruja style selectors that style relationships.
+var relationshipSelectors = map[string]bool{
+	"relationship":  true,
+	"relationships": true,
+	"relation":      true,
+	"relations":     true,
+}
+
+// shapes maps lower-case shape names and common aliases to Structurizr shapes.
+var shapes = map[string]string{
+	"box":                   "Box",
+	"rectangle":             "Box",
+	"roundedbox":            "RoundedBox",
+	"rounded":               "RoundedBox",
+	"circle":                "Circle",
+	"ellipse":               "Ellipse",
+	"hexagon":               "Hexagon",
+	"diamond":               "Diamond",
+	"cylinder":              "Cylinder",
+	"database":              "Cylinder",
+	"bucket":                "Bucket",
+	"pipe":                  "Pipe",
+	"queue":                 "Pipe",
+	"person":                "Person",
+	"robot":                 "Robot",
+	"folder":                "Folder",
+	"webbrowser":            "WebBrowser",
+	"browser":               "WebBrowser",
+	"window":                "Window",
+	"terminal":              "Terminal",
+	"shell":                 "Shell",
+	"mobiledeviceportrait":  "MobileDevicePortrait",
+	"mobile":                "MobileDevicePortrait",
+	"mobiledevicelandscape": "MobileDeviceLandscape",
+	"component":             "Component",
+}
+
+// convertStyles converts the style blocks of a program to Structurizr styles. Each
+// selector block (person { ... }, #tag { ... }, relationship { ... }) becomes an
+// element or relationship style; element kind declarations with a style block
+// style the elements of that kind, and relationship kinds style the relationships
+// tagged with them.
+func convertStyles(prog *language.Program) Styles {
+	var styles Styles
+	if prog.Specification != nil {
+		for _, item := range prog.Specification.Items {
+			def := item.Element
+			if def == nil || def.Body == nil || def.Body.Style == nil {
+				continue
+			}
+			addStyle(&styles, def.Name, def.Body.Style.Entries)
+		}
+		for _, kind := range prog.Specification.RelationshipKinds() {
+			if style := relationshipKindStyle(kind); style != nil {
+				styles.Relationships = append(styles.Relationships, style)
+			}
+		}
+	}
+	if prog.Views != nil {
+		for _, item := range prog.Views.Items {
+			if item == nil || item.Styles == nil || item.Styles.Body == nil {
+				continue
+			}
+			for _, entry := range item.Styles.Body.Entries {
+				if entry.Body != nil {
+					addStyle(&styles, entry.Key, entry.Body.Entries)
+				}
+			}
+		}
+	}
+	return styles
+}
+
+// addStyle adds the style for a selector with the given properties.
+func addStyle(styles *Styles, selector string, props []*language.StyleEntry) {
+	key := strings.ToLower(selector)
+	if relationshipSelectors[key] {
+		style := &RelationshipStyle{Tag: TagRelationship}
+		for _, prop := range props {
+			setRelationshipProperty(style, strings.ToLower(prop.Key), getString(prop.Value))
+		}
+		styles.Relationships = append(styles.Relationships, style)
+		return
+	}
+
+	tag, ok := selectorTags[key]
+	switch {
+	case ok:
+	case strings.HasPrefix(selector, "#"):
+		tag = strings.TrimPrefix(selector, "#")
+	default:
+		// Custom element kinds are exported as tags
+		tag = key
+	}
+	style := &ElementStyle{Tag: tag}
+	for _, prop := range props {
+		setElementProperty(style, strings.ToLower(prop.Key), getString(prop.Value))
+	}
+	styles.Elements = append(styles.Elements, style)
+}
+
+func setElementProperty(style *ElementStyle, key, value string) {
+	switch key {
+	case "shape":
+		style.Shape = shapes[strings.ToLower(value)]
+	case "icon":
+		style.Icon = value
+	case "width":
+		style.Width = atoi(value)
+	case "height":
+		style.Height = atoi(value)
+	case "background", "fill", "bg":
+		style.Background = value
+	case "color", "colour", "text":
+		style.Color = value
+	case "stroke":
+		style.Stroke = value
+	case "strokewidth", "stroke-width":
+		style.StrokeWidth = atoi(value)
+	case "fontsize", "font-size":
+		style.FontSize = atoi(value)
+	case "border":
+		style.Border = strings.ToLower(value)
+	case "opacity":
+		style.Opacity = atoi(value)
+	}
+}
+
+// relationshipKindStyle returns the style of the relationships of a kind, which
+// carry the kind's name as a tag. Asynchronous kinds are dashed unless their
+// style says otherwise. Returns nil for kinds without styling.
+func relationshipKindStyle(kind *language.RelationshipKindDef) *RelationshipStyle {
+	style := &RelationshipStyle{Tag: kind.Name}
+	if block := kind.GetStyle(); block != nil {
+		for _, prop := range block.Entries {
+			setRelationshipProperty(style, strings.ToLower(prop.Key), getString(prop.Value))
+		}
+	}
+	if style.Dashed == nil && kind.IsAsync() {
+		dashed := true
+		style.Dashed = &dashed
+	}
+	if *style == (RelationshipStyle{Tag: kind.Name}) {
+		return nil
+	}
+	return style
+}
+
+// relationshipKindTechnology returns the technology of the first declared
+// relationship kind among a relation's tags.
+func relationshipKindTechnology(spec *language.Specification, tags []string) string {
+	for _, tag := range tags {
+		if kind := spec.RelationshipKind(tag); kind != nil {
+			return kind.GetTechnology()
+		}
+	}
+	return ""
+}
+
+func setRelationshipProperty(style *RelationshipStyle, key, value string) {
+	switch key {
+	case "thickness":
+		style.Thickness = atoi(value)
+	case "color", "colour":
+		style.Color = value
+	case "dashed":
+		dashed := value == "" || strings.EqualFold(value, "true")
+		style.Dashed = &dashed
+	case "style", "line":
+		dashed := strings.EqualFold(value, "dashed") || strings.EqualFold(value, "dotted")
+		style.Dashed = &dashed
+	case "routing":
+		style.Routing = value
+	case "fontsize", "font-size":
+		style.FontSize = atoi(value)
+	case "width":
+		style.Width = atoi(value)
+	case "opacity":
+		style.Opacity = atoi(value)
+	}
+}
+
+// styleDecl converts Structurizr styles to a Sruja style block. Styles for the
+// default tags use the matching Sruja selectors and other tags use #tag selectors.
+// Styles for tagged relationships have no Sruja selector and are dropped.
+func styleDecl(styles Styles) *language.StyleDecl {
+	var entries []*language.StyleEntry
+	for _, style := range styles.Elements {
+		var props []*language.StyleEntry
+		props = appendProp(props, "shape", style.Shape)
+		props = appendProp(props, "icon", style.Icon)
+		props = appendProp(props, "width", strconv.Itoa(style.Width))
+		props = appendProp(props, "height", strconv.Itoa(style.Height))
+		props = appendProp(props, "background", style.Background)
+		props = appendProp(props, "color", style.Color)
+		props = appendProp(props, "stroke", style.Stroke)
+		props = appendProp(props, "strokeWidth", strconv.Itoa(style.StrokeWidth))
+		props = appendProp(props, "fontSize", strconv.Itoa(style.FontSize))
+		props = appendProp(props, "border", style.Border)
+		props = appendProp(props, "opacity", strconv.Itoa(style.Opacity))
+		entries = append(entries, &language.StyleEntry{
+			Key:  styleSelector(style.Tag),
+			Body: &language.StyleBlock{Entries: props},
+		})
+	}
+	for _, style := range styles.Relationships {
+		if style.Tag != TagRelationship {
+			continue
+		}
+		var props []*language.StyleEntry
+		props = appendProp(props, "thickness", strconv.Itoa(style.Thickness))
+		props = appendProp(props, "color", style.Color)
+		if style.Dashed != nil {
+			props = appendProp(props, "dashed", strconv.FormatBool(*style.Dashed))
+		}
+		props = appendProp(props, "routing", style.Routing)
+		props = appendProp(props, "fontSize", strconv.Itoa(style.FontSize))
+		props = appendProp(props, "width", strconv.Itoa(style.Width))
+		props = appendProp(props, "opacity", strconv.Itoa(style.Opacity))
+		entries = append(entries, &language.StyleEntry{
+			Key:  "relationship",
+			Body: &language.StyleBlock{Entries: props},
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return &language.StyleDecl{Keyword: "style", Body: &language.StyleBlock{Entries: entries}}
+}
+
+// appendProp appends a style property unless its value is empty or zero.
+func appendProp(props []*language.StyleEntry, key, value string) []*language.StyleEntry {
+	if value == "" || value == "0" {
+		return props
+	}
+	return append(props, &language.StyleEntry{Key: key, Value: &value})
+}
+
+// styleSelector returns the Sruja style selector for a Structurizr tag.
+func styleSelector(tag string) string {
+	switch tag {
+	case TagElement:
+		return "element"
+	case TagPerson:
+		return "person"
+	case TagSoftwareSystem:
+		return "system"
+	case TagContainer:
+		return "container"
+	case TagComponent:
+		return "component"
+	case TagDatabase:
+		return "database"
+	case TagQueue:
+		return "queue"
+	}
+	return "#" + tagIdent(tag)
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(s, "px"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
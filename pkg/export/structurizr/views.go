@@ -0,0 +1,218 @@
+package structurizr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/export/views"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// buildViews adds a view per declared view, or default views when none are
+// declared, and a deployment view per deployment environment.
+func (b *builder) buildViews(ws *Workspace) error {
+	declared := false
+	if b.prog.Views != nil {
+		for _, item := range b.prog.Views.Items {
+			if item == nil || item.View == nil || item.View.Name == nil || *item.View.Name == "" {
+				continue
+			}
+			declared = true
+			if err := b.addDeclaredView(ws, item.View); err != nil {
+				return err
+			}
+		}
+	}
+	if !declared {
+		b.addDefaultViews(ws)
+	}
+
+	for _, env := range b.environments {
+		view := &View{
+			Key:             "Deployment-" + viewKey(env.Name),
+			Title:           env.Name + " Deployment",
+			Environment:     env.Name,
+			AutomaticLayout: b.layout(),
+		}
+		for _, id := range env.ElementIDs {
+			view.Elements = append(view.Elements, &ElementView{ID: id})
+		}
+		for _, id := range env.RelationshipIDs {
+			view.Relationships = append(view.Relationships, &RelationshipRef{ID: id})
+		}
+		ws.Views.DeploymentViews = append(ws.Views.DeploymentViews, view)
+	}
+	return nil
+}
+
+// addDeclaredView converts a declared view. Views scoped to a system become container
+// views, views scoped to a container component views, and other views system
+// landscape views. Elements are lifted to the deepest level the view type can show.
+func (b *builder) addDeclaredView(ws *Workspace, def *language.ViewDef) error {
+	graph, err := views.ResolveView(b.prog, def)
+	if err != nil {
+		return fmt.Errorf("view %s: %w", *def.Name, err)
+	}
+
+	view := &View{Key: viewKey(*def.Name), Title: viewTitle(def), AutomaticLayout: b.layout()}
+	scope := ""
+	if def.Of != nil {
+		scope = b.exported(b.resolve(def.Of.String(), ""))
+	}
+	depth := 0
+	switch {
+	case scope != "" && parentOf(scope) == "" && b.isSystem(scope):
+		view.SoftwareSystemID = b.elements[scope].Element.ID
+		depth = 1
+		ws.Views.ContainerViews = append(ws.Views.ContainerViews, view)
+	case scope != "" && strings.Count(scope, ".") == 1:
+		view.ContainerID = b.elements[scope].Element.ID
+		depth = 2
+		ws.Views.ComponentViews = append(ws.Views.ComponentViews, view)
+	default:
+		scope = ""
+		ws.Views.SystemLandscapeViews = append(ws.Views.SystemLandscapeViews, view)
+	}
+
+	lift := func(fqn string) string {
+		fqn = b.exported(fqn)
+		if fqn == "" {
+			return ""
+		}
+		return ancestorAtDepth(fqn, depth)
+	}
+
+	// The element in scope is drawn as a boundary, not as an element
+	visible := make(map[string]bool)
+	for fqn := range graph.Elements {
+		if id := lift(fqn); id != "" && id != scope {
+			visible[id] = true
+		}
+	}
+	pairs := make(map[string]bool)
+	for _, rel := range graph.Relations {
+		if from, to := lift(rel.From), lift(rel.To); from != "" && to != "" && from != to {
+			pairs[from+"->"+to] = true
+		}
+	}
+	b.fillView(view, visible, pairs)
+	return nil
+}
+
+// addDefaultViews adds a system landscape view and a container view per system with
+// containers, showing the people and systems the containers interact with.
+func (b *builder) addDefaultViews(ws *Workspace) {
+	visible := make(map[string]bool)
+	for _, fqn := range b.order {
+		if parentOf(fqn) == "" {
+			visible[fqn] = true
+		}
+	}
+	landscape := &View{Key: "SystemLandscape", Title: "System Landscape", AutomaticLayout: b.layout()}
+	b.fillView(landscape, visible, nil)
+	ws.Views.SystemLandscapeViews = append(ws.Views.SystemLandscapeViews, landscape)
+
+	for _, system := range ws.Model.SoftwareSystems {
+		if len(system.Containers) == 0 {
+			continue
+		}
+		sysFQN := b.byID[system.ID].FQN
+		visible := make(map[string]bool)
+		for _, container := range system.Containers {
+			visible[b.byID[container.ID].FQN] = true
+		}
+		for _, rel := range b.relationships {
+			from := b.byID[rel.SourceID].FQN
+			to := b.byID[rel.DestinationID].FQN
+			switch {
+			case visible[from] && parentOf(to) == "" && to != sysFQN:
+				visible[to] = true
+			case visible[to] && parentOf(from) == "" && from != sysFQN:
+				visible[from] = true
+			}
+		}
+		view := &View{
+			Key:              "Containers-" + viewKey(sysFQN),
+			Title:            system.Name + " Containers",
+			SoftwareSystemID: system.ID,
+			AutomaticLayout:  b.layout(),
+		}
+		b.fillView(view, visible, nil)
+		ws.Views.ContainerViews = append(ws.Views.ContainerViews, view)
+	}
+}
+
+// fillView adds the visible elements to a view, in model order, with the relationships
+// between them. When pairs is not nil, only relationships whose "from->to" FQN pair
+// it contains are added.
+func (b *builder) fillView(view *View, visible, pairs map[string]bool) {
+	for _, fqn := range b.order {
+		if visible[fqn] {
+			view.Elements = append(view.Elements, &ElementView{ID: b.elements[fqn].Element.ID})
+		}
+	}
+	for _, rel := range b.relationships {
+		from := b.byID[rel.SourceID].FQN
+		to := b.byID[rel.DestinationID].FQN
+		if !visible[from] || !visible[to] {
+			continue
+		}
+		if pairs != nil && !pairs[from+"->"+to] {
+			continue
+		}
+		view.Relationships = append(view.Relationships, &RelationshipRef{ID: rel.ID})
+	}
+}
+
+// isSystem reports whether fqn was exported as a software system.
+func (b *builder) isSystem(fqn string) bool {
+	return !hasTag(b.elements[fqn].Element, TagPerson)
+}
+
+func (b *builder) layout() *AutomaticLayout {
+	direction := b.config.RankDirection
+	if direction == "" {
+		direction = "TopBottom"
+	}
+	return &AutomaticLayout{
+		Implementation: "Graphviz",
+		RankDirection:  direction,
+		RankSeparation: 300,
+		NodeSeparation: 300,
+	}
+}
+
+// viewTitle returns the title of a view, from its header or its body.
+func viewTitle(def *language.ViewDef) string {
+	if def.Title != nil {
+		return *def.Title
+	}
+	if def.Body != nil {
+		for _, item := range def.Body.Items {
+			if item.Title != nil {
+				return *item.Title
+			}
+		}
+	}
+	return ""
+}
+
+// viewKey converts a name to a view key, which may only contain letters, digits,
+// underscores and dashes.
+func viewKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// ancestorAtDepth returns the ancestor of fqn at the given depth (0 = top level).
+func ancestorAtDepth(fqn string, depth int) string {
+	parts := strings.Split(fqn, ".")
+	if depth >= len(parts) {
+		return fqn
+	}
+	return strings.Join(parts[:depth+1], ".")
+}
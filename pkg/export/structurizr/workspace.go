@@ -0,0 +1,199 @@
+package structurizr
+
+// Workspace is a Structurizr workspace as read and written by the Structurizr
+// tooling (workspace.json). Only the parts Sruja can represent are modelled.
+type Workspace struct {
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Model       Model  `json:"model"`
+	Views       Views  `json:"views"`
+}
+
+// Model holds the people, software systems and deployment nodes of a workspace.
+type Model struct {
+	People          []*Person         `json:"people,omitempty"`
+	SoftwareSystems []*SoftwareSystem `json:"softwareSystems,omitempty"`
+	DeploymentNodes []*DeploymentNode `json:"deploymentNodes,omitempty"`
+}
+
+// Element holds the fields shared by all model elements.
+type Element struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name,omitempty"`
+	Description   string            `json:"description,omitempty"`
+	Tags          string            `json:"tags,omitempty"`
+	Properties    map[string]string `json:"properties,omitempty"`
+	Relationships []*Relationship   `json:"relationships,omitempty"`
+}
+
+// Person is a user of the software systems.
+type Person struct {
+	Element
+	Location string `json:"location,omitempty"`
+}
+
+// SoftwareSystem is a top-level software system.
+type SoftwareSystem struct {
+	Element
+	Location   string       `json:"location,omitempty"`
+	Containers []*Container `json:"containers,omitempty"`
+}
+
+// Container is a deployable unit inside a software system.
+type Container struct {
+	Element
+	Technology string       `json:"technology,omitempty"`
+	Components []*Component `json:"components,omitempty"`
+}
+
+// Component is a grouping of functionality inside a container.
+type Component struct {
+	Element
+	Technology string `json:"technology,omitempty"`
+}
+
+// Relationship is a relationship between two elements. Relationships are stored
+// on their source element.
+type Relationship struct {
+	ID                   string            `json:"id"`
+	SourceID             string            `json:"sourceId"`
+	DestinationID        string            `json:"destinationId"`
+	Description          string            `json:"description,omitempty"`
+	Technology           string            `json:"technology,omitempty"`
+	Tags                 string            `json:"tags,omitempty"`
+	Properties           map[string]string `json:"properties,omitempty"`
+	LinkedRelationshipID string            `json:"linkedRelationshipId,omitempty"`
+}
+
+// DeploymentNode is a node in a deployment environment.
+type DeploymentNode struct {
+	Element
+	Environment         string                `json:"environment"`
+	Technology          string                `json:"technology,omitempty"`
+	Children            []*DeploymentNode     `json:"children,omitempty"`
+	InfrastructureNodes []*InfrastructureNode `json:"infrastructureNodes,omitempty"`
+	ContainerInstances  []*ContainerInstance  `json:"containerInstances,omitempty"`
+}
+
+// InfrastructureNode is a piece of infrastructure such as a load balancer or DNS.
+type InfrastructureNode struct {
+	Element
+	Environment string `json:"environment"`
+	Technology  string `json:"technology,omitempty"`
+}
+
+// ContainerInstance is an instance of a container deployed on a deployment node.
+type ContainerInstance struct {
+	Element
+	Environment string `json:"environment"`
+	ContainerID string `json:"containerId"`
+	InstanceID  int    `json:"instanceId"`
+}
+
+// Views holds the views and the view configuration (styles) of a workspace.
+type Views struct {
+	SystemLandscapeViews []*View       `json:"systemLandscapeViews,omitempty"`
+	SystemContextViews   []*View       `json:"systemContextViews,omitempty"`
+	ContainerViews       []*View       `json:"containerViews,omitempty"`
+	ComponentViews       []*View       `json:"componentViews,omitempty"`
+	DeploymentViews      []*View       `json:"deploymentViews,omitempty"`
+	Configuration        Configuration `json:"configuration"`
+}
+
+// View is a diagram definition. Which scope field is set depends on the view type:
+// SoftwareSystemID for system context, container and deployment views, ContainerID
+// for component views and Environment for deployment views.
+type View struct {
+	Key              string             `json:"key"`
+	Title            string             `json:"title,omitempty"`
+	Description      string             `json:"description,omitempty"`
+	SoftwareSystemID string             `json:"softwareSystemId,omitempty"`
+	ContainerID      string             `json:"containerId,omitempty"`
+	Environment      string             `json:"environment,omitempty"`
+	Elements         []*ElementView     `json:"elements,omitempty"`
+	Relationships    []*RelationshipRef `json:"relationships,omitempty"`
+	AutomaticLayout  *AutomaticLayout   `json:"automaticLayout,omitempty"`
+}
+
+// ElementView references an element shown in a view.
+type ElementView struct {
+	ID string `json:"id"`
+}
+
+// RelationshipRef references a relationship shown in a view.
+type RelationshipRef struct {
+	ID string `json:"id"`
+}
+
+// AutomaticLayout asks Structurizr to lay the view out with Graphviz.
+type AutomaticLayout struct {
+	Implementation string `json:"implementation,omitempty"`
+	RankDirection  string `json:"rankDirection"`
+	RankSeparation int    `json:"rankSeparation"`
+	NodeSeparation int    `json:"nodeSeparation"`
+	EdgeSeparation int    `json:"edgeSeparation"`
+	Vertices       bool   `json:"vertices"`
+}
+
+// Configuration holds the view configuration.
+type Configuration struct {
+	Styles Styles `json:"styles"`
+}
+
+// Styles holds the element and relationship styles, matched by tag.
+type Styles struct {
+	Elements      []*ElementStyle      `json:"elements,omitempty"`
+	Relationships []*RelationshipStyle `json:"relationships,omitempty"`
+}
+
+// ElementStyle styles the elements carrying Tag.
+type ElementStyle struct {
+	Tag         string `json:"tag"`
+	Shape       string `json:"shape,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	Background  string `json:"background,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Stroke      string `json:"stroke,omitempty"`
+	StrokeWidth int    `json:"strokeWidth,omitempty"`
+	FontSize    int    `json:"fontSize,omitempty"`
+	Border      string `json:"border,omitempty"`
+	Opacity     int    `json:"opacity,omitempty"`
+}
+
+// RelationshipStyle styles the relationships carrying Tag.
+type RelationshipStyle struct {
+	Tag       string `json:"tag"`
+	Thickness int    `json:"thickness,omitempty"`
+	Color     string `json:"color,omitempty"`
+	Dashed    *bool  `json:"dashed,omitempty"`
+	Routing   string `json:"routing,omitempty"`
+	FontSize  int    `json:"fontSize,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Opacity   int    `json:"opacity,omitempty"`
+}
+
+// Default tags that Structurizr adds to every element or relationship of a type.
+const (
+	TagElement            = "Element"
+	TagPerson             = "Person"
+	TagSoftwareSystem     = "Software System"
+	TagContainer          = "Container"
+	TagComponent          = "Component"
+	TagDeploymentNode     = "Deployment Node"
+	TagInfrastructureNode = "Infrastructure Node"
+	TagContainerInstance  = "Container Instance"
+	TagRelationship       = "Relationship"
+
+	// TagDatabase and TagQueue mark containers and components of the matching Sruja kinds.
+	TagDatabase = "Database"
+	TagQueue    = "Queue"
+	// TagBidirectional marks relationships written with <-> in Sruja.
+	TagBidirectional = "Bidirectional"
+)
+
+// PropertyIdentifier is the element property holding the DSL identifier, as written
+// by the Structurizr DSL. Sruja stores element FQNs in it so imports keep their IDs.
+const PropertyIdentifier = "structurizr.dsl.identifier"
@@ -0,0 +1,366 @@
+// pkg/export/views/graph.go
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// ViewGraph is the set of elements and relations shown by a view.
+type ViewGraph struct {
+	// Elements holds the FQNs of the visible elements.
+	Elements map[string]bool
+	// Relations holds the visible relations, projected onto visible elements, in source order.
+	Relations []ViewRelation
+}
+
+// ViewRelation is a relation between two visible elements.
+type ViewRelation struct {
+	From  string
+	To    string
+	Label string
+	// Bidirectional is set for relations written with <->.
+	Bidirectional bool
+}
+
+// FindView returns the view with the given name, or nil if there is none.
+func FindView(prog *language.Program, name string) *language.ViewDef {
+	if prog == nil || prog.Views == nil {
+		return nil
+	}
+	for _, item := range prog.Views.Items {
+		if item != nil && item.View != nil && item.View.Name != nil && *item.View.Name == name {
+			return item.View
+		}
+	}
+	return nil
+}
+
+// ResolveView computes the elements and relations selected by a view's include
+// and exclude expressions. A view without include expressions shows everything in
+// its scope (include *).
+//
+// Relations between visible elements are shown automatically; relations of nested
+// elements are lifted to their closest visible ancestor. Excluding an element
+// expression hides elements, while excluding a relationship expression (-> X,
+// X ->, X -> Y) hides the matching relations only.
+func ResolveView(prog *language.Program, view *language.ViewDef) (*ViewGraph, error) {
+	if view == nil {
+		return nil, fmt.Errorf("view is nil")
+	}
+	idx := newModelIndex(prog)
+	scope := ""
+	if view.Of != nil {
+		scope = idx.resolve(view.Of.String(), "")
+	}
+
+	included := make(map[string]bool)
+	var excludedRelations []*Pattern
+	hasInclude := false
+
+	if view.Body != nil {
+		for _, item := range view.Body.Items {
+			switch {
+			case item.Include != nil:
+				hasInclude = true
+				for _, expr := range item.Include.Expressions {
+					p, err := ParsePattern(expr.String())
+					if err != nil {
+						return nil, err
+					}
+					for id := range idx.selectPattern(p, scope) {
+						included[id] = true
+					}
+				}
+			case item.Exclude != nil:
+				for _, expr := range item.Exclude.Expressions {
+					p, err := ParsePattern(expr.String())
+					if err != nil {
+						return nil, err
+					}
+					if p.Kind == PatternElements {
+						for id := range idx.selectTerm(p.Source, scope) {
+							delete(included, id)
+						}
+					} else {
+						excludedRelations = append(excludedRelations, p)
+					}
+				}
+			}
+		}
+	}
+	if !hasInclude {
+		for id := range idx.selectTerm(Term{Wildcard: true}, scope) {
+			included[id] = true
+		}
+	}
+
+	graph := &ViewGraph{Elements: included}
+	seen := make(map[string]bool)
+	for _, rel := range idx.relations {
+		from := idx.visibleAncestor(rel.From, included)
+		to := idx.visibleAncestor(rel.To, included)
+		if from == "" || to == "" || from == to {
+			continue
+		}
+		if idx.relationExcluded(rel, excludedRelations, scope) {
+			continue
+		}
+		key := from + "->" + to + ":" + rel.Label
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		graph.Relations = append(graph.Relations, ViewRelation{From: from, To: to, Label: rel.Label, Bidirectional: rel.Bidirectional})
+	}
+	return graph, nil
+}
+
+// modelIndex provides element lookups for evaluating view expressions.
+type modelIndex struct {
+	elements  map[string]*language.ElementDef
+	order     []string
+	suffixes  map[string][]string
+	relations []ViewRelation
+}
+
+func newModelIndex(prog *language.Program) *modelIndex {
+	idx := &modelIndex{
+		elements: make(map[string]*language.ElementDef),
+		suffixes: make(map[string][]string),
+	}
+	if prog == nil || prog.Model == nil {
+		return idx
+	}
+
+	type scopedRelation struct {
+		rel   *language.Relation
+		scope string
+	}
+	var rels []scopedRelation
+
+	var walk func(elem *language.ElementDef, parent string)
+	walk = func(elem *language.ElementDef, parent string) {
+		id := elem.GetID()
+		if id == "" {
+			return
+		}
+		fqn := id
+		if parent != "" {
+			fqn = parent + "." + id
+		}
+		idx.elements[fqn] = elem
+		idx.order = append(idx.order, fqn)
+		idx.suffixes[id] = append(idx.suffixes[id], fqn)
+		if body := elem.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					walk(item.Element, fqn)
+				}
+				if item.Relation != nil {
+					rels = append(rels, scopedRelation{rel: item.Relation, scope: fqn})
+				}
+			}
+		}
+	}
+	for _, item := range prog.Model.Items {
+		if item.ElementDef != nil {
+			walk(item.ElementDef, "")
+		}
+		// Lifting relations to visible ancestors already covers implied parent relations.
+		if item.Relation != nil && !item.Relation.Implied {
+			rels = append(rels, scopedRelation{rel: item.Relation})
+		}
+	}
+
+	for _, sr := range rels {
+		from := idx.resolve(sr.rel.From.String(), sr.scope)
+		to := idx.resolve(sr.rel.To.String(), sr.scope)
+		if from == "" || to == "" {
+			continue
+		}
+		label := ""
+		switch {
+		case sr.rel.Label != nil && *sr.rel.Label != "":
+			label = *sr.rel.Label
+		case sr.rel.Verb != nil:
+			label = *sr.rel.Verb
+		}
+		idx.relations = append(idx.relations, ViewRelation{From: from, To: to, Label: label, Bidirectional: sr.rel.Bidirectional})
+	}
+	return idx
+}
+
+// resolve returns the FQN of ref, looked up relative to scope first, then as an
+// FQN, then by unique suffix. Returns "" if ref cannot be resolved.
+func (idx *modelIndex) resolve(ref, scope string) string {
+	for s := scope; s != ""; s = parentOf(s) {
+		if _, ok := idx.elements[s+"."+ref]; ok {
+			return s + "." + ref
+		}
+	}
+	if _, ok := idx.elements[ref]; ok {
+		return ref
+	}
+	last := ref
+	if i := strings.LastIndex(ref, "."); i >= 0 {
+		last = ref[i+1:]
+	}
+	var match string
+	for _, fqn := range idx.suffixes[last] {
+		if fqn == ref || strings.HasSuffix(fqn, "."+ref) {
+			if match != "" {
+				return ""
+			}
+			match = fqn
+		}
+	}
+	return match
+}
+
+// selectTerm returns the elements selected by a term.
+func (idx *modelIndex) selectTerm(t Term, scope string) map[string]bool {
+	selected := make(map[string]bool)
+	switch {
+	case t.Recursive:
+		for _, fqn := range idx.order {
+			selected[fqn] = true
+		}
+	case t.Wildcard:
+		if scope != "" {
+			selected[scope] = true
+		}
+		for _, fqn := range idx.order {
+			if parentOf(fqn) == scope {
+				selected[fqn] = true
+			}
+		}
+	case t.Kind != "":
+		for _, fqn := range idx.order {
+			if strings.EqualFold(idx.elements[fqn].GetKind(), t.Kind) {
+				selected[fqn] = true
+			}
+		}
+	case t.Tag != "":
+		for _, fqn := range idx.order {
+			if hasTag(idx.elements[fqn], t.Tag) {
+				selected[fqn] = true
+			}
+		}
+	default:
+		ref := idx.resolve(t.Ref, scope)
+		if ref == "" {
+			break
+		}
+		switch {
+		case t.Children:
+			for _, fqn := range idx.order {
+				if parentOf(fqn) == ref {
+					selected[fqn] = true
+				}
+			}
+		case t.Descendants:
+			for _, fqn := range idx.order {
+				if strings.HasPrefix(fqn, ref+".") {
+					selected[fqn] = true
+				}
+			}
+		default:
+			selected[ref] = true
+		}
+	}
+	return selected
+}
+
+// selectPattern returns the elements selected by a pattern.
+func (idx *modelIndex) selectPattern(p *Pattern, scope string) map[string]bool {
+	if p.Kind == PatternElements {
+		return idx.selectTerm(p.Source, scope)
+	}
+
+	selected := make(map[string]bool)
+	if p.Kind == PatternRelation {
+		sources := idx.selectTerm(p.Source, scope)
+		targets := idx.selectTerm(p.Target, scope)
+		for _, rel := range idx.relations {
+			from := idx.visibleAncestor(rel.From, sources)
+			to := idx.visibleAncestor(rel.To, targets)
+			if from != "" && to != "" && from != to {
+				selected[from] = true
+				selected[to] = true
+			}
+		}
+		return selected
+	}
+
+	focus := idx.selectTerm(p.Source, scope)
+	for id := range focus {
+		selected[id] = true
+	}
+	for _, rel := range idx.relations {
+		if p.Kind != PatternOutgoing && idx.visibleAncestor(rel.To, focus) != "" && idx.visibleAncestor(rel.From, focus) == "" {
+			selected[rel.From] = true
+		}
+		if p.Kind != PatternIncoming && idx.visibleAncestor(rel.From, focus) != "" && idx.visibleAncestor(rel.To, focus) == "" {
+			selected[rel.To] = true
+		}
+	}
+	return selected
+}
+
+// relationExcluded reports whether a relation matches one of the excluded relation patterns.
+func (idx *modelIndex) relationExcluded(rel ViewRelation, patterns []*Pattern, scope string) bool {
+	for _, p := range patterns {
+		switch p.Kind {
+		case PatternRelation:
+			if idx.visibleAncestor(rel.From, idx.selectTerm(p.Source, scope)) != "" &&
+				idx.visibleAncestor(rel.To, idx.selectTerm(p.Target, scope)) != "" {
+				return true
+			}
+		case PatternIncoming, PatternOutgoing, PatternInOut:
+			focus := idx.selectTerm(p.Source, scope)
+			if p.Kind != PatternOutgoing && idx.visibleAncestor(rel.To, focus) != "" {
+				return true
+			}
+			if p.Kind != PatternIncoming && idx.visibleAncestor(rel.From, focus) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// visibleAncestor returns fqn or its closest ancestor contained in visible, or "".
+func (idx *modelIndex) visibleAncestor(fqn string, visible map[string]bool) string {
+	for id := fqn; id != ""; id = parentOf(id) {
+		if visible[id] {
+			return id
+		}
+	}
+	return ""
+}
+
+// hasTag reports whether an element carries a tag, on its definition line or in its body.
+func hasTag(elem *language.ElementDef, tag string) bool {
+	for _, meta := range extractMetadata(elem) {
+		if meta.Key != "tags" {
+			continue
+		}
+		for _, t := range meta.Array {
+			if strings.TrimPrefix(t, "#") == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parentOf returns the FQN of the parent element, or "" for top-level elements.
+func parentOf(fqn string) string {
+	if i := strings.LastIndex(fqn, "."); i >= 0 {
+		return fqn[:i]
+	}
+	return ""
+}
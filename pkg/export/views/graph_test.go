@@ -0,0 +1,133 @@
+package views
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const graphTestDSL = `
+User = person "User"
+Shop = system "Shop" {
+	Web = container "Web"
+	API = container "API"
+	DB = database "DB" {
+		tags ["pci"]
+	}
+}
+Payments = system "Payments" {
+	Gateway = container "Gateway"
+}
+User -> Shop.Web "Browses"
+Shop.Web -> Shop.API "Calls"
+Shop.API -> Shop.DB "Reads"
+Shop.API -> Payments.Gateway "Charges"
+`
+
+func resolveTestView(t *testing.T, body string) *ViewGraph {
+	t.Helper()
+	prog := parseDSL(t, graphTestDSL+"\nview test {\n"+body+"\n}\n")
+	view := FindView(prog, "test")
+	if view == nil {
+		t.Fatal("view 'test' not found")
+	}
+	graph, err := ResolveView(prog, view)
+	if err != nil {
+		t.Fatalf("ResolveView error: %v", err)
+	}
+	return graph
+}
+
+func sortedElements(g *ViewGraph) []string {
+	ids := make([]string, 0, len(g.Elements))
+	for id := range g.Elements {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestResolveView_Expressions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"incoming", "include -> Shop.API", []string{"Shop.API", "Shop.Web"}},
+		{"outgoing", "include Shop.API ->", []string{"Payments.Gateway", "Shop.API", "Shop.DB"}},
+		{"in and out", "include -> Shop.API ->", []string{"Payments.Gateway", "Shop.API", "Shop.DB", "Shop.Web"}},
+		{"between children", "include Shop.* -> Payments.*", []string{"Payments.Gateway", "Shop.API"}},
+		{"kind filter", "include element.kind = database", []string{"Shop.DB"}},
+		{"tag filter", "include element.tag = #pci", []string{"Shop.DB"}},
+		{"exclude element", "include Shop.*\nexclude Shop.Web", []string{"Shop.API", "Shop.DB"}},
+		{"default wildcard", "", []string{"Payments", "Shop", "User"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sortedElements(resolveTestView(t, tt.body))
+			if len(got) != len(tt.want) {
+				t.Fatalf("elements = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("elements = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveView_Relations(t *testing.T) {
+	graph := resolveTestView(t, "include *")
+	// Relations between nested elements are lifted to the visible systems.
+	want := map[string]bool{"User->Shop": true, "Shop->Payments": true}
+	if len(graph.Relations) != len(want) {
+		t.Fatalf("relations = %+v, want %v", graph.Relations, want)
+	}
+	for _, rel := range graph.Relations {
+		if !want[rel.From+"->"+rel.To] {
+			t.Errorf("unexpected relation %s -> %s", rel.From, rel.To)
+		}
+	}
+}
+
+func TestResolveView_RelationWithoutPosition(t *testing.T) {
+	prog := parseDSL(t, graphTestDSL+"\nview test {\ninclude Payments.Gateway ->\n}\n")
+	// Relations built in code or imported from JSON have no source position
+	prog.Model.Items = append(prog.Model.Items, language.ModelItem{Relation: &language.Relation{
+		From:  language.QualifiedIdent{Parts: []string{"Payments", "Gateway"}},
+		Arrow: "->",
+		To:    language.QualifiedIdent{Parts: []string{"User"}},
+	}})
+	graph, err := ResolveView(prog, FindView(prog, "test"))
+	if err != nil {
+		t.Fatalf("ResolveView error: %v", err)
+	}
+	if !graph.Elements["User"] {
+		t.Errorf("expected the relation to User to match the pattern, got %v", sortedElements(graph))
+	}
+}
+
+func TestResolveView_ExcludeRelation(t *testing.T) {
+	graph := resolveTestView(t, "include Shop.**\nexclude Shop.API -> Shop.DB")
+	if !graph.Elements["Shop.DB"] {
+		t.Error("excluding a relation should keep its elements")
+	}
+	for _, rel := range graph.Relations {
+		if rel.From == "Shop.API" && rel.To == "Shop.DB" {
+			t.Error("expected Shop.API -> Shop.DB to be excluded")
+		}
+	}
+	if len(graph.Relations) != 1 || graph.Relations[0].Label != "Calls" {
+		t.Errorf("relations = %+v, want only Shop.Web -> Shop.API", graph.Relations)
+	}
+}
+
+func TestParsePattern_Errors(t *testing.T) {
+	for _, expr := range []string{"", "->", "A -> B -> C", "-> A -> B", "element.size = 3", "element.kind ="} {
+		if _, err := ParsePattern(expr); err == nil {
+			t.Errorf("ParsePattern(%q) expected error", expr)
+		}
+	}
+}
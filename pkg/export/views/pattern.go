@@ -0,0 +1,136 @@
+// pkg/export/views/pattern.go
+package views
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PatternKind identifies the shape of a view expression.
+type PatternKind int
+
+const (
+	// PatternElements selects elements: X, X.*, X.**, *, **, element.kind = K, element.tag = #t.
+	PatternElements PatternKind = iota
+	// PatternIncoming selects an element and the elements depending on it: -> X.
+	PatternIncoming
+	// PatternOutgoing selects an element and the elements it depends on: X ->.
+	PatternOutgoing
+	// PatternInOut selects an element with its incoming and outgoing neighbours: -> X ->.
+	PatternInOut
+	// PatternRelation selects the relations between two sets of elements: X -> Y.
+	PatternRelation
+)
+
+// Term selects a set of elements.
+type Term struct {
+	// Ref is the referenced element ("" for wildcards and filters).
+	Ref string
+	// Children selects the direct children of Ref (X.*).
+	Children bool
+	// Descendants selects all descendants of Ref (X.**).
+	Descendants bool
+	// Wildcard selects every element in the view scope (*).
+	Wildcard bool
+	// Recursive selects every element in the model (**).
+	Recursive bool
+	// Kind selects elements of a kind (element.kind = K).
+	Kind string
+	// Tag selects elements carrying a tag, without the leading # (element.tag = #t).
+	Tag string
+}
+
+// Pattern is a parsed view expression.
+type Pattern struct {
+	Kind PatternKind
+	// Source is the selected element term, or the relation source for PatternRelation.
+	Source Term
+	// Target is the relation target (PatternRelation only).
+	Target Term
+}
+
+// ParsePattern parses a view expression such as "->api->", "shop.* -> payments.*"
+// or "element.kind = database".
+func ParsePattern(s string) (*Pattern, error) {
+	expr := strings.TrimSpace(s)
+	if expr == "" {
+		return nil, fmt.Errorf("empty view expression")
+	}
+
+	incoming := strings.HasPrefix(expr, "->")
+	if incoming {
+		expr = strings.TrimSpace(expr[2:])
+	}
+	outgoing := strings.HasSuffix(expr, "->")
+	if outgoing {
+		expr = strings.TrimSpace(expr[:len(expr)-2])
+	}
+
+	parts := strings.Split(expr, "->")
+	switch {
+	case len(parts) == 1:
+		term, err := parseTerm(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid view expression %q: %w", s, err)
+		}
+		p := &Pattern{Source: term}
+		switch {
+		case incoming && outgoing:
+			p.Kind = PatternInOut
+		case incoming:
+			p.Kind = PatternIncoming
+		case outgoing:
+			p.Kind = PatternOutgoing
+		default:
+			p.Kind = PatternElements
+		}
+		return p, nil
+	case len(parts) == 2 && !incoming && !outgoing:
+		source, err := parseTerm(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid view expression %q: %w", s, err)
+		}
+		target, err := parseTerm(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid view expression %q: %w", s, err)
+		}
+		return &Pattern{Kind: PatternRelation, Source: source, Target: target}, nil
+	default:
+		return nil, fmt.Errorf("invalid view expression %q: expected '-> X', 'X ->', '-> X ->' or 'X -> Y'", s)
+	}
+}
+
+// parseTerm parses a single element term.
+func parseTerm(s string) (Term, error) {
+	t := strings.TrimSpace(s)
+	switch {
+	case t == "":
+		return Term{}, fmt.Errorf("missing element")
+	case t == "*":
+		return Term{Wildcard: true}, nil
+	case t == "**":
+		return Term{Recursive: true}, nil
+	case strings.HasPrefix(t, "element."):
+		field, value, ok := strings.Cut(strings.TrimPrefix(t, "element."), "=")
+		field, value = strings.TrimSpace(field), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return Term{}, fmt.Errorf("expected 'element.kind = <kind>' or 'element.tag = #<tag>'")
+		}
+		switch field {
+		case "kind":
+			return Term{Kind: strings.ToLower(value)}, nil
+		case "tag":
+			return Term{Tag: strings.TrimPrefix(value, "#")}, nil
+		default:
+			return Term{}, fmt.Errorf("unknown element filter %q (expected kind or tag)", field)
+		}
+	case strings.HasSuffix(t, ".**"):
+		return Term{Ref: strings.TrimSuffix(t, ".**"), Descendants: true}, nil
+	case strings.HasSuffix(t, ".*"):
+		return Term{Ref: strings.TrimSuffix(t, ".*"), Children: true}, nil
+	case strings.ContainsAny(t, " \t*="):
+		return Term{}, fmt.Errorf("unexpected %q", t)
+	default:
+		return Term{Ref: t}, nil
+	}
+}
@@ -6,20 +6,39 @@ import (
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
-func TestApplyViewExpressions_PatternWildcard(t *testing.T) {
+func TestApplyViewExpressions_Pattern(t *testing.T) {
 	dsl := `
+        User = person "User"
         S = system "Sys" {
             C = container "Cont"
+            D = database "DB"
         }
+        Other = system "Other"
+        User -> S.C "Uses"
+        S.C -> S.D "Reads"
     `
 	prog := parseDSL(t, dsl)
-	pattern := "->Element->"
+	pattern := "->C->"
 	v := &language.View{Scope: &language.QualifiedIdent{Parts: []string{"S"}}, Expressions: []*language.ViewExpression{{Type: "include", Pattern: &pattern}}}
 	inc, err := ApplyViewExpressions(prog, v)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !inc["S.C"] {
-		t.Fatalf("expected S.C included by pattern wildcard")
+	for _, id := range []string{"S.C", "User", "S.D"} {
+		if !inc[id] {
+			t.Errorf("expected %s included by pattern %q, got %v", id, pattern, inc)
+		}
+	}
+	if inc["Other"] {
+		t.Errorf("expected Other not included by pattern %q", pattern)
+	}
+}
+
+func TestApplyViewExpressions_InvalidPattern(t *testing.T) {
+	prog := parseDSL(t, `S = system "Sys"`)
+	pattern := "A -> B -> C"
+	v := &language.View{Scope: &language.QualifiedIdent{Parts: []string{"S"}}, Expressions: []*language.ViewExpression{{Type: "include", Pattern: &pattern}}}
+	if _, err := ApplyViewExpressions(prog, v); err == nil {
+		t.Fatal("expected error for invalid pattern")
 	}
 }
@@ -38,9 +38,15 @@ func ApplyViewExpressions(prog *language.Program, view *language.View) (map[stri
 					included[elem.String()] = true
 				}
 			case expr.Pattern != nil:
-				// Pattern-based include (e.g., "->Element->")
-				// For now, treat as wildcard - full pattern matching can be added later
-				includeAllInScope(prog, *view.Scope, included)
+				// Pattern-based include (e.g., "->Element->", "A.* -> B.*", "element.kind = database")
+				pattern, err := ParsePattern(*expr.Pattern)
+				if err != nil {
+					return nil, err
+				}
+				idx := newModelIndex(prog)
+				for id := range idx.selectPattern(pattern, idx.resolve(view.Scope.String(), "")) {
+					included[id] = true
+				}
 			}
 		case "exclude":
 			if len(expr.Elements) > 0 {
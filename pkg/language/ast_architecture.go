@@ -19,18 +19,21 @@ type File struct {
 // TopLevelItem is a union type for items that can appear at the file top level.
 type TopLevelItem struct {
 	// Top-level declarations
-	KindDef     *ElementKindDef   `parser:"@@"`
-	TagDef      *TagDef           `parser:"| @@"`
-	ElementDef  *ElementDef       `parser:"| @@"`
-	ViewDef     *ViewDef          `parser:"| @@"`
-	Relation    *Relation         `parser:"| @@"`
-	Import      *ImportStatement  `parser:"| @@"`
-	Overview    *OverviewBlock    `parser:"| @@"`
-	Deployment  *DeploymentNode   `parser:"| @@"`
-	Constraints *ConstraintsBlock `parser:"| @@"`
-	Conventions *ConventionsBlock `parser:"| @@"`
-	Extend      *ExtendElement    `parser:"| @@"`
-	Styles      *StyleDecl        `parser:"| @@"`
+	KindDef     *ElementKindDef      `parser:"@@"`
+	TagDef      *TagDef              `parser:"| @@"`
+	RelKindDef  *RelationshipKindDef `parser:"| @@"`
+	ElementDef  *ElementDef          `parser:"| @@"`
+	ViewDef     *ViewDef             `parser:"| @@"`
+	Relation    *Relation            `parser:"| @@"`
+	Import      *ImportStatement     `parser:"| @@"`
+	Overview    *OverviewBlock       `parser:"| @@"`
+	Deployment  *DeploymentNode      `parser:"| @@"`
+	Constraints *ConstraintsBlock    `parser:"| @@"`
+	Conventions *ConventionsBlock    `parser:"| @@"`
+	Layers      *LayersBlock         `parser:"| @@"`
+	Rule        *RuleDef             `parser:"| @@"`
+	Extend      *ExtendElement       `parser:"| @@"`
+	Styles      *StyleDecl           `parser:"| @@"`
 }
 
 func (f *File) Location() SourceLocation {
@@ -44,6 +47,9 @@ type Program struct {
 	Specification *Specification
 	Model         *Model
 	Views         *Views
+
+	// Suppression comments, populated by the parser
+	Suppressions []*Suppression
 }
 
 func (p *Program) PostProcess() {
@@ -57,6 +63,10 @@ func (p *Program) PostProcess() {
 			p.ensureSpecification()
 			p.Specification.Items = append(p.Specification.Items, SpecificationItem{Tag: item.TagDef})
 		}
+		if item.RelKindDef != nil {
+			p.ensureSpecification()
+			p.Specification.Items = append(p.Specification.Items, SpecificationItem{Relationship: item.RelKindDef})
+		}
 		if item.ElementDef != nil {
 			p.ensureModel()
 			p.Model.Items = append(p.Model.Items, ModelItem{ElementDef: item.ElementDef})
@@ -85,6 +95,14 @@ func (p *Program) PostProcess() {
 			p.ensureModel()
 			p.Model.Items = append(p.Model.Items, ModelItem{ConventionsBlock: item.Conventions})
 		}
+		if item.Layers != nil {
+			p.ensureModel()
+			p.Model.Items = append(p.Model.Items, ModelItem{Layers: item.Layers})
+		}
+		if item.Rule != nil {
+			p.ensureModel()
+			p.Model.Items = append(p.Model.Items, ModelItem{Rule: item.Rule})
+		}
 		if item.Extend != nil {
 			p.ensureModel()
 			p.Model.Items = append(p.Model.Items, ModelItem{Extend: item.Extend})
@@ -167,11 +185,12 @@ func (m *Model) InferImpliedRelationships() {
 				parentFrom := QualifiedIdent{Parts: rel.From.Parts[:i]}
 				if !isRelParentChild(parentFrom, rel.To) && !isRelParentChild(rel.To, parentFrom) {
 					inferred = append(inferred, &Relation{
-						From:  parentFrom,
-						Arrow: "->",
-						To:    rel.To,
-						Verb:  rel.Verb,
-						Label: rel.Label,
+						From:    parentFrom,
+						Arrow:   "->",
+						To:      rel.To,
+						Verb:    rel.Verb,
+						Label:   rel.Label,
+						Implied: true,
 					})
 				}
 			}
@@ -180,11 +199,12 @@ func (m *Model) InferImpliedRelationships() {
 				parentTo := QualifiedIdent{Parts: rel.To.Parts[:i]}
 				if !isRelParentChild(rel.From, parentTo) && !isRelParentChild(parentTo, rel.From) {
 					inferred = append(inferred, &Relation{
-						From:  rel.From,
-						Arrow: "->",
-						To:    parentTo,
-						Verb:  rel.Verb,
-						Label: rel.Label,
+						From:    rel.From,
+						Arrow:   "->",
+						To:      parentTo,
+						Verb:    rel.Verb,
+						Label:   rel.Label,
+						Implied: true,
 					})
 				}
 			}
@@ -197,11 +217,12 @@ func (m *Model) InferImpliedRelationships() {
 					toStr := parentTo.String()
 					if fromStr != toStr && !isRelParentChild(parentFrom, parentTo) && !isRelParentChild(parentTo, parentFrom) {
 						inferred = append(inferred, &Relation{
-							From:  parentFrom,
-							Arrow: "->",
-							To:    parentTo,
-							Verb:  rel.Verb,
-							Label: rel.Label,
+							From:    parentFrom,
+							Arrow:   "->",
+							To:      parentTo,
+							Verb:    rel.Verb,
+							Label:   rel.Label,
+							Implied: true,
 						})
 					}
 				}
@@ -258,6 +279,12 @@ func (m *ModelItem) PostProcess() {
 	if m.ElementDef != nil {
 		m.ElementDef.PostProcess()
 	}
+	if m.Layers != nil {
+		m.Layers.PostProcess()
+	}
+	if m.Rule != nil {
+		m.Rule.PostProcess()
+	}
 }
 
 func (v *Views) PostProcess() {
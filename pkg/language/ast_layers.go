@@ -0,0 +1,106 @@
+package language
+
+import (
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// ============================================================================
+// Layers
+// ============================================================================
+
+// LayersBlock declares the layer model enforced by the layer violation rule.
+//
+// Layers are listed from top to bottom. Dependencies may point to the same layer
+// or to any layer below (unless skipping is disabled); anything else must be
+// allowed explicitly, either per layer pair or per relation.
+//
+// Elements join a layer through the layer's member list or a `layer` metadata
+// entry; nested elements inherit the layer of their parent.
+//
+// Example DSL:
+//
+//	layers {
+//	  adapters "Adapters" [shop.web, shop.cli]
+//	  application
+//	  domain
+//	  infrastructure [shop.db]
+//	  allow infrastructure -> domain
+//	  skip false
+//	  except shop.web -> shop.db "legacy reporting path"
+//	}
+type LayersBlock struct {
+	Pos    lexer.Position
+	LBrace string        `parser:"'layers' '{'"`
+	Items  []*LayersItem `parser:"@@*"`
+	RBrace string        `parser:"'}'"`
+
+	// Post-processed fields
+	Layers     []*LayerDef
+	Allow      []*LayerAllow
+	Exceptions []*LayerException
+	AllowSkip  *bool
+}
+
+func (l *LayersBlock) Location() SourceLocation {
+	return SourceLocation{File: l.Pos.Filename, Line: l.Pos.Line, Column: l.Pos.Column, Offset: l.Pos.Offset}
+}
+
+// LayersItem is a union type for items that can appear in a layers block.
+type LayersItem struct {
+	Allow     *LayerAllow     `parser:"'allow' @@"`
+	Exception *LayerException `parser:"| 'except' @@"`
+	Skip      *string         `parser:"| 'skip' @( 'true' | 'false' )"`
+	Layer     *LayerDef       `parser:"| @@"`
+}
+
+// LayerDef declares a single layer and, optionally, the elements that belong to it.
+type LayerDef struct {
+	Pos      lexer.Position
+	Name     string            `parser:"@Ident"`
+	Title    *string           `parser:"( @String )?"`
+	Elements []*QualifiedIdent `parser:"( '[' @@ ( ',' @@ )* ']' )?"`
+}
+
+func (l *LayerDef) Location() SourceLocation {
+	return SourceLocation{File: l.Pos.Filename, Line: l.Pos.Line, Column: l.Pos.Column, Offset: l.Pos.Offset}
+}
+
+// LayerAllow permits dependencies from one layer to another regardless of order.
+type LayerAllow struct {
+	Pos  lexer.Position
+	From string `parser:"@Ident '->'"`
+	To   string `parser:"@Ident"`
+}
+
+// LayerException exempts a single relation from layer checks.
+type LayerException struct {
+	Pos    lexer.Position
+	From   QualifiedIdent `parser:"@@ '->'"`
+	To     QualifiedIdent `parser:"@@"`
+	Reason *string        `parser:"( @String )?"`
+}
+
+func (l *LayerException) Location() SourceLocation {
+	return SourceLocation{File: l.Pos.Filename, Line: l.Pos.Line, Column: l.Pos.Column, Offset: l.Pos.Offset}
+}
+
+// PostProcess distributes the block items into the typed fields.
+func (l *LayersBlock) PostProcess() {
+	l.Layers = l.Layers[:0]
+	l.Allow = l.Allow[:0]
+	l.Exceptions = l.Exceptions[:0]
+	l.AllowSkip = nil
+	for _, item := range l.Items {
+		switch {
+		case item.Layer != nil:
+			l.Layers = append(l.Layers, item.Layer)
+		case item.Allow != nil:
+			l.Allow = append(l.Allow, item.Allow)
+		case item.Exception != nil:
+			l.Exceptions = append(l.Exceptions, item.Exception)
+		case item.Skip != nil:
+			allow := *item.Skip == "true"
+			l.AllowSkip = &allow
+		}
+	}
+}
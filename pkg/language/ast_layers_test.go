@@ -0,0 +1,84 @@
+// pkg/language/ast_layers_test.go
+package language
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayersBlockParsing(t *testing.T) {
+	dsl := `
+shop = system "Shop" {
+  web = container "Web"
+  db = database "DB"
+}
+
+layers {
+  adapters "Adapters" [shop.web]
+  application
+  domain
+  infrastructure [shop.db]
+  allow infrastructure -> domain
+  skip false
+  except shop.web -> shop.db "legacy reporting path"
+}
+`
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("layers.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	var block *LayersBlock
+	for _, item := range program.Model.Items {
+		if item.Layers != nil {
+			block = item.Layers
+		}
+	}
+	if block == nil {
+		t.Fatal("Expected layers block in model")
+	}
+
+	if len(block.Layers) != 4 {
+		t.Fatalf("Expected 4 layers, got %d", len(block.Layers))
+	}
+	if block.Layers[0].Name != "adapters" || block.Layers[0].Title == nil || *block.Layers[0].Title != "Adapters" {
+		t.Errorf("Unexpected first layer: %+v", block.Layers[0])
+	}
+	if len(block.Layers[0].Elements) != 1 || block.Layers[0].Elements[0].String() != "shop.web" {
+		t.Errorf("Expected adapters to contain shop.web, got %v", block.Layers[0].Elements)
+	}
+	if len(block.Allow) != 1 || block.Allow[0].From != "infrastructure" || block.Allow[0].To != "domain" {
+		t.Errorf("Unexpected allow rules: %+v", block.Allow)
+	}
+	if block.AllowSkip == nil || *block.AllowSkip {
+		t.Error("Expected skip false")
+	}
+	if len(block.Exceptions) != 1 || block.Exceptions[0].From.String() != "shop.web" || block.Exceptions[0].To.String() != "shop.db" {
+		t.Errorf("Unexpected exceptions: %+v", block.Exceptions)
+	}
+
+	printed := NewPrinter().Print(program)
+	for _, want := range []string{"layers {", `adapters "Adapters" [shop.web]`, "allow infrastructure -> domain", "skip false", `except shop.web -> shop.db "legacy reporting path"`} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("Printed output missing %q:\n%s", want, printed)
+		}
+	}
+}
+
+func TestLayersKeywordStillUsableAsIdentifier(t *testing.T) {
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("ident.sruja", `layers = system "Layers"`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if program.Model == nil || len(program.Model.Items) != 1 || program.Model.Items[0].ElementDef.GetID() != "layers" {
+		t.Error("Expected 'layers' to parse as an element ID")
+	}
+}
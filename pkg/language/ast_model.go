@@ -1,6 +1,8 @@
 package language
 
 import (
+	"strings"
+
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
@@ -14,8 +16,34 @@ type Specification struct {
 }
 
 type SpecificationItem struct {
-	Element *ElementKindDef
-	Tag     *TagDef
+	Element      *ElementKindDef
+	Tag          *TagDef
+	Relationship *RelationshipKindDef
+}
+
+// RelationshipKinds returns the relationship kinds declared in the specification.
+func (s *Specification) RelationshipKinds() []*RelationshipKindDef {
+	if s == nil {
+		return nil
+	}
+	var kinds []*RelationshipKindDef
+	for _, item := range s.Items {
+		if item.Relationship != nil {
+			kinds = append(kinds, item.Relationship)
+		}
+	}
+	return kinds
+}
+
+// RelationshipKind returns the relationship kind with the given name, matched
+// case-insensitively like relation tags, or nil when none is declared.
+func (s *Specification) RelationshipKind(name string) *RelationshipKindDef {
+	for _, kind := range s.RelationshipKinds() {
+		if strings.EqualFold(kind.Name, name) {
+			return kind
+		}
+	}
+	return nil
 }
 
 type ElementKindDef struct {
@@ -28,7 +56,7 @@ type ElementKindDef struct {
 type ElementKindDefBody struct {
 	Title       *string     `parser:"( 'title' @String )?"`
 	Description *string     `parser:"( 'description' @String )?"`
-	Technology  *string     `parser:"( 'technology' | 'tech' ) @String?"`
+	Technology  *string     `parser:"( ( 'technology' | 'tech' ) @String )?"`
 	Style       *StyleBlock `parser:"( ( 'style' | 'styles' ) '{' @@ '}' )?"`
 }
 
@@ -38,6 +66,131 @@ type TagDef struct {
 	Title *string `parser:"( @String )?"`
 }
 
+// RelationshipKindDef declares a kind of relation. Relations select a kind by
+// listing its name in their tags.
+//
+// Example DSL:
+//
+//	publishes = relationship "Publishes" {
+//	  technology "Kafka"
+//	  async
+//	  from [system, container]
+//	  to [queue]
+//	}
+type RelationshipKindDef struct {
+	Pos   lexer.Position
+	Name  string                   `parser:"@Ident '=' 'relationship'"`
+	Title *string                  `parser:"( @String )?"`
+	Body  *RelationshipKindDefBody `parser:"( '{' @@ '}' )?"`
+}
+
+type RelationshipKindDefBody struct {
+	Items []*RelationshipKindItem `parser:"@@*"`
+}
+
+// RelationshipKindItem is one property of a relationship kind. From and To
+// restrict the element kinds a relation of this kind may connect.
+type RelationshipKindItem struct {
+	Title       *string     `parser:"  'title' @String"`
+	Description *string     `parser:"| 'description' @String"`
+	Technology  *string     `parser:"| ( 'technology' | 'tech' ) @String"`
+	Async       bool        `parser:"| @'async'"`
+	Style       *StyleBlock `parser:"| ( 'style' | 'styles' ) @@"`
+	From        []string    `parser:"| 'from' ( '[' @Ident ( ',' @Ident )* ']' | @Ident )"`
+	To          []string    `parser:"| 'to' ( '[' @Ident ( ',' @Ident )* ']' | @Ident )"`
+}
+
+func (r *RelationshipKindDef) Location() SourceLocation {
+	return SourceLocation{File: r.Pos.Filename, Line: r.Pos.Line, Column: r.Pos.Column, Offset: r.Pos.Offset}
+}
+
+// GetLabel returns the title of the kind, or its name when it has none.
+func (r *RelationshipKindDef) GetLabel() string {
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			if item.Title != nil {
+				return *item.Title
+			}
+		}
+	}
+	if r.Title != nil {
+		return *r.Title
+	}
+	return r.Name
+}
+
+// GetDescription returns the description of the kind.
+func (r *RelationshipKindDef) GetDescription() string {
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			if item.Description != nil {
+				return *item.Description
+			}
+		}
+	}
+	return ""
+}
+
+// GetTechnology returns the technology relations of the kind use by default.
+func (r *RelationshipKindDef) GetTechnology() string {
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			if item.Technology != nil {
+				return *item.Technology
+			}
+		}
+	}
+	return ""
+}
+
+// IsAsync reports whether relations of the kind are asynchronous.
+func (r *RelationshipKindDef) IsAsync() bool {
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			if item.Async {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetStyle returns the default style of relations of the kind.
+func (r *RelationshipKindDef) GetStyle() *StyleBlock {
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			if item.Style != nil {
+				return item.Style
+			}
+		}
+	}
+	return nil
+}
+
+// SourceKinds returns the element kinds a relation of the kind may start
+// from; nil allows any kind.
+func (r *RelationshipKindDef) SourceKinds() []string {
+	var kinds []string
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			kinds = append(kinds, item.From...)
+		}
+	}
+	return kinds
+}
+
+// TargetKinds returns the element kinds a relation of the kind may point to;
+// nil allows any kind.
+func (r *RelationshipKindDef) TargetKinds() []string {
+	var kinds []string
+	if r.Body != nil {
+		for _, item := range r.Body.Items {
+			kinds = append(kinds, item.To...)
+		}
+	}
+	return kinds
+}
+
 // Logical container for model items
 type Model struct {
 	Items []ModelItem
@@ -50,6 +203,8 @@ type ModelItem struct {
 	Overview         *OverviewBlock    `parser:"@@ |"`
 	ConstraintsBlock *ConstraintsBlock `parser:"@@ |"`
 	ConventionsBlock *ConventionsBlock `parser:"@@ |"`
+	Layers           *LayersBlock      `parser:"@@ |"`
+	Rule             *RuleDef          `parser:"@@ |"`
 	Styles           *StyleDecl        `parser:"@@ |"`
 	ElementDef       *ElementDef       `parser:"@@ |"`
 	Relation         *Relation         `parser:"@@"`
@@ -115,6 +270,15 @@ func (e *ElementDef) GetTitle() *string {
 	return nil
 }
 
+// GetLabel returns the element's title, or its ID when it has none, so that
+// element definitions satisfy Element.
+func (e *ElementDef) GetLabel() string {
+	if title := e.GetTitle(); title != nil {
+		return *title
+	}
+	return e.GetID()
+}
+
 func (e *ElementDef) GetBody() *ElementDefBody {
 	if e.Assignment != nil {
 		return e.Assignment.Body
@@ -246,16 +410,38 @@ type ExcludePredicate struct {
 	Expressions []ViewExpr `parser:"'exclude' @@ ( ','? @@ )*"`
 }
 
+// ViewExpr is a single include/exclude expression in a view body.
+//
+// Besides element selectors (*, **, X, X.*, X.**), expressions can select
+// relationships and filter elements:
+//
+//	include -> api ->              // api with its incoming and outgoing neighbours
+//	include api ->                 // api and the elements it depends on
+//	include -> api                 // api and the elements depending on it
+//	include shop.* -> payments.*   // relations between children of shop and payments
+//	include element.kind = database
+//	include element.tag = #pci
+//	include "-> api ->"            // quoted patterns are accepted too
 type ViewExpr struct {
-	Wildcard  bool            `parser:"( @Wildcard"`
-	Recursive bool            `parser:"| @( Wildcard Wildcard )"`
-	Selector  *string         `parser:"| @( Ident | 'element' | 'person' | 'system' | 'container' | 'component' | 'database' | 'queue' | 'style' | 'styles' ) )"`
-	Sub       *ViewExprSuffix `parser:"@@?"`
+	Pattern   *string         `parser:"( @String"`
+	Incoming  bool            `parser:"| @Arrow?"`
+	Filter    *ViewFilter     `parser:"  ( @@"`
+	Recursive bool            `parser:"  | @( Wildcard Wildcard )"`
+	Wildcard  bool            `parser:"  | @Wildcard"`
+	Selector  *string         `parser:"  | (?! ( 'include' | 'exclude' | 'title' | 'layout' ) | ( 'style' | 'styles' ) Ident '{' ) @( Ident | 'element' | 'person' | 'system' | 'container' | 'component' | 'database' | 'queue' | 'style' | 'styles' ) )"`
+	Sub       *ViewExprSuffix `parser:"  @@?"`
+	Outgoing  bool            `parser:"  ( @Arrow"`
+	Target    *ViewExpr       `parser:"    @@? )? )"`
 }
 
 func (v ViewExpr) String() string {
+	if v.Pattern != nil {
+		return *v.Pattern
+	}
 	res := ""
 	switch {
+	case v.Filter != nil:
+		res = v.Filter.String()
 	case v.Recursive:
 		res = "**"
 	case v.Wildcard:
@@ -264,22 +450,51 @@ func (v ViewExpr) String() string {
 		res = *v.Selector
 	}
 	if v.Sub != nil {
-		switch {
-		case v.Sub.Recursive:
-			res += ".**"
-		case v.Sub.Wildcard:
-			res += ".*"
-		case v.Sub.Ident != nil:
-			res += "." + *v.Sub.Ident
+		res += v.Sub.String()
+	}
+	if v.Incoming {
+		res = "-> " + res
+	}
+	if v.Outgoing {
+		res += " ->"
+		if v.Target != nil {
+			res += " " + v.Target.String()
 		}
 	}
 	return res
 }
 
+// ViewFilter selects elements by kind or tag, e.g. element.kind = database or element.tag = #pci.
+type ViewFilter struct {
+	Field string `parser:"'element' '.' @( 'kind' | 'tag' ) '='"`
+	Value string `parser:"@( TagRef | Ident | 'person' | 'system' | 'container' | 'component' | 'database' | 'queue' )"`
+}
+
+func (f *ViewFilter) String() string {
+	return "element." + f.Field + " = " + f.Value
+}
+
 type ViewExprSuffix struct {
-	Recursive bool    `parser:"'.' ( @( Wildcard Wildcard )"`
-	Wildcard  bool    `parser:"| @Wildcard"`
-	Ident     *string `parser:"| @Ident )"`
+	Recursive bool            `parser:"'.' ( @( Wildcard Wildcard )"`
+	Wildcard  bool            `parser:"| @Wildcard"`
+	Ident     *string         `parser:"| @Ident )"`
+	Next      *ViewExprSuffix `parser:"@@?"`
+}
+
+func (s *ViewExprSuffix) String() string {
+	res := ""
+	switch {
+	case s.Recursive:
+		res = ".**"
+	case s.Wildcard:
+		res = ".*"
+	case s.Ident != nil:
+		res = "." + *s.Ident
+	}
+	if s.Next != nil {
+		res += s.Next.String()
+	}
+	return res
 }
 
 type ViewStyle struct {
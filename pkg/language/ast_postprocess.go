@@ -2,11 +2,19 @@
 // Package language provides DSL parsing and AST structures.
 package language
 
-// normalizeRelation normalizes a relation by converting VerbRaw to Verb if needed.
+// normalizeRelation normalizes a relation by converting VerbRaw to Verb if needed,
+// turning back arrows into forward relations and flagging bidirectional ones.
 func normalizeRelation(r *Relation) {
 	if r == nil {
 		return
 	}
+	switch r.Arrow {
+	case "<-":
+		r.From, r.To = r.To, r.From
+		r.Arrow = "->"
+	case "<->":
+		r.Bidirectional = true
+	}
 	if r.Verb == nil && r.VerbRaw != nil {
 		v := r.VerbRaw.Value
 		r.Verb = &v
@@ -157,5 +157,8 @@ func (s *SLOBlock) PostProcess() {
 		if it.Throughput != nil {
 			s.Throughput = it.Throughput
 		}
+		if it.Cost != nil {
+			s.Cost = it.Cost
+		}
 	}
 }
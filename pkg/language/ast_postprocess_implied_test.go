@@ -10,6 +10,7 @@ func TestArchitecture_InferImpliedRelationships(t *testing.T) {
 		name     string
 		dsl      string
 		expected int // Expected number of relationships after inference
+		implied  int // Expected number of them flagged as implied
 	}{
 		{
 			name: "Simple implied relationship",
@@ -20,6 +21,7 @@ func TestArchitecture_InferImpliedRelationships(t *testing.T) {
     
     User -> API.WebApp "Uses"`,
 			expected: 2, // User -> API.WebApp (explicit) + User -> API (implied)
+			implied:  1,
 		},
 		{
 			name: "Multiple implied relationships",
@@ -32,6 +34,7 @@ func TestArchitecture_InferImpliedRelationships(t *testing.T) {
     User -> Shop.WebApp "Uses"
     Shop.WebApp -> Shop.API "Calls"`,
 			expected: 3, // User -> Shop.WebApp, Shop.WebApp -> Shop.API (explicit)
+			implied:  1,
 			// + User -> Shop (implied from User -> Shop.WebApp)
 			// Note: Shop.WebApp -> Shop.API does NOT imply Shop -> Shop.API
 			// because Shop.WebApp is already inside Shop
@@ -66,12 +69,18 @@ func TestArchitecture_InferImpliedRelationships(t *testing.T) {
 			}
 
 			// Count relations in Model
-			actual := 0
+			actual, implied := 0, 0
 			for _, item := range program.Model.Items {
 				if item.Relation != nil {
 					actual++
+					if item.Relation.Implied {
+						implied++
+					}
 				}
 			}
+			if implied != tt.implied {
+				t.Errorf("Expected %d implied relationships, got %d", tt.implied, implied)
+			}
 			if actual != tt.expected {
 				t.Errorf("Expected %d relationships, got %d", tt.expected, actual)
 				// Print actual relationships for debugging
@@ -21,10 +21,16 @@ import (
 //	User -> WebApp "Uses"
 //	WebApp -> Database "Reads/Writes"
 //	API -> UserService "calls" "Makes HTTP requests"
+//	Cache <-> Database "Syncs"
+//	Database <- API "Reads"
+//
+// Back arrows are normalized during post-processing into forward relations
+// (From and To swapped). Bidirectional arrows keep their direction and set
+// Bidirectional.
 type Relation struct {
-	From    QualifiedIdent `parser:"@@"`   // possibly qualified
-	Arrow   string         `parser:"'->'"` // explicit arrow
-	To      QualifiedIdent `parser:"@@"`   // possibly qualified
+	From    QualifiedIdent `parser:"@@"`                       // possibly qualified
+	Arrow   string         `parser:"@( '->' | '<->' | '<-' )"` // explicit arrow
+	To      QualifiedIdent `parser:"@@"`                       // possibly qualified
 	VerbRaw *RelationVerb  `parser:"@@?"`
 	Verb    *string
 	Label   *string  `parser:"( @String )?"`                        // Description
@@ -34,6 +40,13 @@ type Relation struct {
 	ResolvedFrom Element
 	ResolvedTo   Element
 
+	// Post-processed: true for relations written with <->
+	Bidirectional bool
+
+	// Post-processed: true for parent relations inferred from a relation
+	// between nested elements
+	Implied bool
+
 	Pos lexer.Position
 }
 
@@ -76,14 +89,19 @@ func (v *RelationVerb) Parse(lex *lexer.PeekingLexer) error {
 		}
 		return nil
 	}
-	if token.Value == "->" || token.Value == "." || token.Value == "{" || token.Value == "}" || token.Value == "[" {
+	if isArrow(token.Value) || token.Value == "." || token.Value == "{" || token.Value == "}" || token.Value == "[" {
 		return fmt.Errorf("not a verb")
 	}
 	t := lex.Next()
 	next := lex.Peek()
-	if next.Value == "->" || next.Value == "." {
+	if isArrow(next.Value) || next.Value == "." {
 		return fmt.Errorf("ambiguous verb followed by -> or")
 	}
 	v.Value = t.Value
 	return nil
 }
+
+// isArrow reports whether a token value is a relation arrow.
+func isArrow(value string) bool {
+	return value == "->" || value == "<->" || value == "<-"
+}
@@ -0,0 +1,175 @@
+package language
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// ============================================================================
+// Rules (user-defined fitness functions)
+// ============================================================================
+
+// RuleDef declares a user-defined architecture rule that is evaluated by the
+// validation engine alongside the built-in rules.
+//
+// A rule holds one or more checks:
+//   - forbid <selector> -> <selector>: every matching relation is a violation
+//   - require <selector> has <selector>: every matching element must also match the second selector
+//   - require <selector> -> <selector>: every matching element needs a relation to a matching element
+//
+// Selectors combine predicates (all must hold): kind <kind>, #tag, id <ref>,
+// in <ref>, metadata <key> [= "value"], technology, description; any predicate
+// can be negated with not. A single * matches every element.
+//
+// Messages may use the placeholders {rule}, {id}, {kind}, {from} and {to}.
+//
+// Example DSL:
+//
+//	rule frontend-no-db "Frontends must not call databases directly" {
+//	  severity error
+//	  code "ARCH001"
+//	  message "'{from}' calls database '{to}' directly"
+//	  forbid kind container #frontend -> kind database
+//	}
+//
+//	rule queue-owner "Queues need an owner" {
+//	  severity warning
+//	  require kind queue has metadata owner
+//	}
+type RuleDef struct {
+	Pos   lexer.Position
+	ID    string      `parser:"'rule' @Ident"`
+	Title *string     `parser:"( @String )?"`
+	Items []*RuleItem `parser:"'{' @@* '}'"`
+
+	// Post-processed fields
+	Severity *string
+	Code     *string
+	Message  *string
+	Forbid   []*RuleForbid
+	Require  []*RuleRequire
+}
+
+func (r *RuleDef) Location() SourceLocation {
+	return SourceLocation{File: r.Pos.Filename, Line: r.Pos.Line, Column: r.Pos.Column, Offset: r.Pos.Offset}
+}
+
+// PostProcess distributes the rule items into the typed fields.
+func (r *RuleDef) PostProcess() {
+	r.Forbid = r.Forbid[:0]
+	r.Require = r.Require[:0]
+	for _, item := range r.Items {
+		switch {
+		case item.Severity != nil:
+			r.Severity = item.Severity
+		case item.Code != nil:
+			r.Code = item.Code
+		case item.Message != nil:
+			r.Message = item.Message
+		case item.Forbid != nil:
+			r.Forbid = append(r.Forbid, item.Forbid)
+		case item.Require != nil:
+			r.Require = append(r.Require, item.Require)
+		}
+	}
+}
+
+// RuleItem is a union type for items that can appear in a rule body.
+type RuleItem struct {
+	Severity *string      `parser:"'severity' @Ident"`
+	Code     *string      `parser:"| 'code' @String"`
+	Message  *string      `parser:"| 'message' @String"`
+	Forbid   *RuleForbid  `parser:"| 'forbid' @@"`
+	Require  *RuleRequire `parser:"| 'require' @@"`
+}
+
+// RuleForbid reports every relation from an element matching From to an element matching To.
+type RuleForbid struct {
+	Pos  lexer.Position
+	From *ElementSelector `parser:"@@ '->'"`
+	To   *ElementSelector `parser:"@@"`
+}
+
+func (r *RuleForbid) Location() SourceLocation {
+	return SourceLocation{File: r.Pos.Filename, Line: r.Pos.Line, Column: r.Pos.Column, Offset: r.Pos.Offset}
+}
+
+// RuleRequire reports every element matching Subject that does not match Has,
+// or that has no relation to an element matching To.
+type RuleRequire struct {
+	Pos     lexer.Position
+	Subject *ElementSelector `parser:"@@"`
+	Has     *ElementSelector `parser:"( 'has' @@"`
+	To      *ElementSelector `parser:"| '->' @@ )"`
+}
+
+func (r *RuleRequire) Location() SourceLocation {
+	return SourceLocation{File: r.Pos.Filename, Line: r.Pos.Line, Column: r.Pos.Column, Offset: r.Pos.Offset}
+}
+
+// ElementSelector matches elements satisfying all of its predicates.
+type ElementSelector struct {
+	Any        bool                `parser:"( @Wildcard"`
+	Predicates []*ElementPredicate `parser:"| @@+ )"`
+}
+
+func (s *ElementSelector) String() string {
+	if s == nil {
+		return ""
+	}
+	if s.Any {
+		return "*"
+	}
+	parts := make([]string, 0, len(s.Predicates))
+	for _, p := range s.Predicates {
+		parts = append(parts, p.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// ElementPredicate is a single condition on an element.
+type ElementPredicate struct {
+	Not         bool               `parser:"@'not'?"`
+	Kind        *string            `parser:"( 'kind' @( Ident | Policy | Flow | Scenario | Story )"`
+	Tag         *string            `parser:"| @TagRef"`
+	ID          *QualifiedIdent    `parser:"| 'id' @@"`
+	In          *QualifiedIdent    `parser:"| 'in' @@"`
+	Metadata    *MetadataPredicate `parser:"| 'metadata' @@"`
+	Technology  bool               `parser:"| @'technology'"`
+	Description bool               `parser:"| @'description' )"`
+}
+
+func (p *ElementPredicate) String() string {
+	var s string
+	switch {
+	case p.Kind != nil:
+		s = "kind " + *p.Kind
+	case p.Tag != nil:
+		s = *p.Tag
+	case p.ID != nil:
+		s = "id " + p.ID.String()
+	case p.In != nil:
+		s = "in " + p.In.String()
+	case p.Metadata != nil:
+		s = "metadata " + p.Metadata.Key
+		if p.Metadata.Value != nil {
+			s += fmt.Sprintf(" = %q", *p.Metadata.Value)
+		}
+	case p.Technology:
+		s = "technology"
+	case p.Description:
+		s = "description"
+	}
+	if p.Not {
+		return "not " + s
+	}
+	return s
+}
+
+// MetadataPredicate matches elements with a metadata key, optionally with a specific value.
+type MetadataPredicate struct {
+	Key   string  `parser:"@Ident"`
+	Value *string `parser:"( '=' @String )?"`
+}
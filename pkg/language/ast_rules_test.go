@@ -0,0 +1,94 @@
+// pkg/language/ast_rules_test.go
+package language
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleDefParsing(t *testing.T) {
+	dsl := `
+rule frontend-no-db "Frontends must not call databases directly" {
+  severity warning
+  code "ARCH001"
+  message "'{from}' calls '{to}'"
+  forbid kind container #frontend -> kind database
+}
+
+rule queue-owner {
+  require kind queue not in legacy has metadata owner
+  require kind container -> metadata tier = "monitoring"
+  require * has description
+}
+`
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("rules.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	var rules []*RuleDef
+	for _, item := range program.Model.Items {
+		if item.Rule != nil {
+			rules = append(rules, item.Rule)
+		}
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+
+	first := rules[0]
+	if first.ID != "frontend-no-db" || first.Title == nil || *first.Title != "Frontends must not call databases directly" {
+		t.Errorf("Unexpected rule header: %s %v", first.ID, first.Title)
+	}
+	if first.Severity == nil || *first.Severity != "warning" {
+		t.Errorf("Expected severity warning, got %v", first.Severity)
+	}
+	if first.Code == nil || *first.Code != "ARCH001" {
+		t.Errorf("Expected code ARCH001, got %v", first.Code)
+	}
+	if len(first.Forbid) != 1 {
+		t.Fatalf("Expected 1 forbid check, got %d", len(first.Forbid))
+	}
+	if got := first.Forbid[0].From.String(); got != "kind container #frontend" {
+		t.Errorf("Unexpected forbid source selector: %q", got)
+	}
+	if got := first.Forbid[0].To.String(); got != "kind database" {
+		t.Errorf("Unexpected forbid target selector: %q", got)
+	}
+
+	second := rules[1]
+	if len(second.Require) != 3 {
+		t.Fatalf("Expected 3 require checks, got %d", len(second.Require))
+	}
+	if got := second.Require[0].Subject.String(); got != "kind queue not in legacy" {
+		t.Errorf("Unexpected require subject: %q", got)
+	}
+	if second.Require[0].Has == nil || second.Require[0].Has.Predicates[0].Metadata.Key != "owner" {
+		t.Error("Expected 'has metadata owner' condition")
+	}
+	if second.Require[1].To == nil || second.Require[1].To.String() != `metadata tier = "monitoring"` {
+		t.Errorf("Unexpected relation requirement: %v", second.Require[1].To)
+	}
+	if !second.Require[2].Subject.Any {
+		t.Error("Expected wildcard subject")
+	}
+
+	printed := NewPrinter().Print(program)
+	for _, want := range []string{
+		`rule frontend-no-db "Frontends must not call databases directly" {`,
+		"severity warning",
+		`code "ARCH001"`,
+		"forbid kind container #frontend -> kind database",
+		"require kind queue not in legacy has metadata owner",
+		`require kind container -> metadata tier = "monitoring"`,
+		"require * has description",
+	} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("Printed output missing %q:\n%s", want, printed)
+		}
+	}
+}
@@ -220,3 +220,54 @@ styles {
 		})
 	}
 }
+
+func TestParser_ViewRelationshipExpressions(t *testing.T) {
+	dsl := `Shop = system "Shop" {
+	API = container "API"
+	DB = database "DB"
+}
+Payments = system "Payments"
+view deps of Shop {
+	include -> Shop.API ->
+	include Shop.API ->
+	include -> DB
+	include Shop.* -> Payments.*
+	include element.kind = database, element.tag = #pci
+	exclude Shop.API -> DB
+	title "Dependencies"
+}`
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("test.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var got []string
+	for _, item := range program.Views.Items[0].View.Body.Items {
+		if item.Include != nil {
+			for _, expr := range item.Include.Expressions {
+				got = append(got, "include "+expr.String())
+			}
+		}
+		if item.Exclude != nil {
+			for _, expr := range item.Exclude.Expressions {
+				got = append(got, "exclude "+expr.String())
+			}
+		}
+	}
+	want := []string{
+		"include -> Shop.API ->",
+		"include Shop.API ->",
+		"include -> DB",
+		"include Shop.* -> Payments.*",
+		"include element.kind = database",
+		"include element.tag = #pci",
+		"exclude Shop.API -> DB",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expressions = %q, want %q", got, want)
+	}
+}
@@ -289,6 +289,35 @@ func Test_Feature_Relation_Verb_Label(t *testing.T) {
 	}
 }
 
+func Test_Feature_Relation_Arrows(t *testing.T) {
+	dsl := `
+		A = system "A"
+		B = system "B"
+		A <-> B "syncs"
+		A <- B "notifies"
+	`
+	p, _ := language.NewParser()
+	prog, _, err := p.Parse("a.sruja", dsl)
+	if err != nil {
+		t.Fatalf("parse %v", err)
+	}
+	var rels []*language.Relation
+	for _, item := range prog.Model.Items {
+		if item.Relation != nil {
+			rels = append(rels, item.Relation)
+		}
+	}
+	if len(rels) != 2 {
+		t.Fatalf("expected 2 relations, got %d", len(rels))
+	}
+	if !rels[0].Bidirectional || rels[0].From.String() != "A" || rels[0].To.String() != "B" {
+		t.Fatalf("expected bidirectional A <-> B, got %+v", rels[0])
+	}
+	if rels[1].Bidirectional || rels[1].From.String() != "B" || rels[1].To.String() != "A" || rels[1].Arrow != "->" {
+		t.Fatalf("expected back arrow normalized to B -> A, got %+v", rels[1])
+	}
+}
+
 // Journey feature removed - test removed
 
 func Test_Feature_ADR(t *testing.T) {
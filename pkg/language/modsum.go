@@ -0,0 +1,127 @@
+package language
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SumFileName is the name of the file locking the checksums of required
+// modules, next to their sruja.mod.
+//
+// Each line holds a module, its version and the checksum of its files:
+//
+//	acme.com/platform v1.3.0 h1:2jmj7l5rSw0yVb/vlWAYkK/YBwk=
+const SumFileName = "sruja.sum"
+
+func sumKey(path, version string) string {
+	return path + " " + version
+}
+
+// HashDir returns the checksum of the files of a module directory. Files and
+// directories whose name starts with a dot are left out, as are modules
+// vendored into it. The checksum does not depend on where the module is.
+func HashDir(dir string) (string, error) {
+	var lines []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if skipModuleEntry(dir, path, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		lines = append(lines, hex.EncodeToString(sum[:])+"  "+filepath.ToSlash(rel)+"\n")
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash %s: %w", dir, err)
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "")))
+	return "h1:" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// skipModuleEntry reports whether a file of a module directory is left out of
+// its checksum and its vendored copy.
+func skipModuleEntry(dir, path string, d fs.DirEntry) bool {
+	if strings.HasPrefix(d.Name(), ".") {
+		return true
+	}
+	return d.IsDir() && filepath.Dir(path) == dir && d.Name() == VendorDir
+}
+
+// ReadSumFile reads the checksums of a sruja.sum, keyed by module path and
+// version. A missing file has no checksums.
+func ReadSumFile(path string) (map[string]string, error) {
+	sums := make(map[string]string)
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return sums, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || !IsValidVersion(fields[1]) || !strings.HasPrefix(fields[2], "h1:") {
+			return nil, fmt.Errorf("%s:%d: expected '<module> <version> h1:<checksum>', got %q", path, line, scanner.Text())
+		}
+		sums[sumKey(fields[0], fields[1])] = fields[2]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return sums, nil
+}
+
+// WriteSumFile writes the checksums of modules, keyed by module path and
+// version, to a sruja.sum. No checksums remove the file.
+func WriteSumFile(path string, sums map[string]string) error {
+	if len(sums) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	keys := make([]string, 0, len(sums))
+	for key := range sums {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s %s\n", key, sums[key])
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644) //nolint:gosec // checked in next to sruja.mod
+}
@@ -0,0 +1,489 @@
+package language
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/stdlib"
+)
+
+// ModFileName is the name of the file declaring a module, the modules it
+// requires and the aliases its imports use.
+const ModFileName = "sruja.mod"
+
+// VendorDir is the directory next to a sruja.mod that required modules are
+// vendored into, each under its module path.
+const VendorDir = "vendor"
+
+// StdlibModule is the module path of the standard library, as declared by the
+// sruja.mod embedded with it. Its files are loaded from stdlib.FS, never from disk.
+var StdlibModule = stdlibModule()
+
+func stdlibModule() string {
+	if data, err := stdlib.FS.ReadFile(ModFileName); err == nil {
+		if mod, err := ParseModFile(ModFileName, data); err == nil && mod.Module != "" {
+			return mod.Module
+		}
+	}
+	return "sruja.ai/stdlib"
+}
+
+// ModFile is a parsed sruja.mod.
+//
+// Example:
+//
+//	module acme.com/shop
+//	version v1.2.0
+//
+//	// shared kinds and tags of the platform team
+//	require acme.com/platform v1.3.0
+//	// a module checked out next to this one
+//	require acme.com/payments v0.4.0 => ../payments
+//
+//	alias acme.com/legacy => ./legacy
+type ModFile struct {
+	Module   string
+	Version  string
+	Requires []Require
+	Aliases  map[string]string // Module path -> directory, relative to the sruja.mod
+}
+
+// Require is a module required at a version. Dir, when set, is a local
+// directory holding the module, relative to the sruja.mod.
+type Require struct {
+	Path    string
+	Version string
+	Dir     string
+	Line    int // Line of the require in the sruja.mod
+}
+
+// Require returns the requirement of a module, if any.
+func (m *ModFile) Require(path string) (Require, bool) {
+	for _, req := range m.Requires {
+		if req.Path == path {
+			return req, true
+		}
+	}
+	return Require{}, false
+}
+
+// ParseModFile parses the contents of a sruja.mod file.
+func ParseModFile(filename string, data []byte) (*ModFile, error) {
+	mod := &ModFile{Aliases: make(map[string]string)}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "//"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case fields[0] == "module" && len(fields) == 2:
+			if mod.Module != "" {
+				return nil, fmt.Errorf("%s:%d: duplicate module declaration", filename, line)
+			}
+			mod.Module = fields[1]
+		case fields[0] == "version" && len(fields) == 2:
+			if !IsValidVersion(fields[1]) {
+				return nil, fmt.Errorf("%s:%d: invalid version %q, expected vMAJOR.MINOR.PATCH", filename, line, fields[1])
+			}
+			mod.Version = fields[1]
+		case fields[0] == "require" && (len(fields) == 3 || len(fields) == 5 && fields[3] == "=>"):
+			if !IsValidVersion(fields[2]) {
+				return nil, fmt.Errorf("%s:%d: invalid version %q of %s, expected vMAJOR.MINOR.PATCH", filename, line, fields[2], fields[1])
+			}
+			if _, exists := mod.Require(fields[1]); exists {
+				return nil, fmt.Errorf("%s:%d: duplicate require of %s", filename, line, fields[1])
+			}
+			req := Require{Path: fields[1], Version: fields[2], Line: line}
+			if len(fields) == 5 {
+				req.Dir = fields[4]
+			}
+			mod.Requires = append(mod.Requires, req)
+		case fields[0] == "alias" && len(fields) == 4 && fields[2] == "=>":
+			if _, exists := mod.Aliases[fields[1]]; exists {
+				return nil, fmt.Errorf("%s:%d: duplicate alias %q", filename, line, fields[1])
+			}
+			mod.Aliases[fields[1]] = fields[3]
+		default:
+			return nil, fmt.Errorf("%s:%d: expected 'module <path>', 'version <version>', 'require <path> <version> [=> <dir>]' or 'alias <path> => <dir>', got %q", filename, line, strings.TrimSpace(text))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", filename, err)
+	}
+	return mod, nil
+}
+
+var versionPattern = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)$`)
+
+// IsValidVersion reports whether v is a module version, vMAJOR.MINOR.PATCH.
+func IsValidVersion(v string) bool {
+	return versionPattern.MatchString(v)
+}
+
+// CompareVersions returns -1, 0 or 1 as version a is lower than, equal to or
+// higher than version b.
+func CompareVersions(a, b string) int {
+	pa, pb := versionPattern.FindStringSubmatch(a), versionPattern.FindStringSubmatch(b)
+	if pa == nil || pb == nil {
+		return strings.Compare(a, b)
+	}
+	for i := 1; i <= 3; i++ {
+		na, _ := strconv.Atoi(pa[i])
+		nb, _ := strconv.Atoi(pb[i])
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+	}
+	return 0
+}
+
+// ModCacheDir returns the local module cache: $SRUJA_MODCACHE, or sruja/mod in
+// the user cache directory. Version v of module m is cached in <cache>/m@v.
+func ModCacheDir() string {
+	if dir := os.Getenv("SRUJA_MODCACHE"); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "sruja", "mod")
+	}
+	return ""
+}
+
+// cachedModuleDir returns where a version of a module is in the module cache.
+func cachedModuleDir(cache, path, version string) string {
+	return filepath.Join(cache, filepath.FromSlash(path)+"@"+version)
+}
+
+// moduleRoot is a loaded sruja.mod: the module's own path and its aliases,
+// mapped to absolute directories, and its requirements with their checksums.
+type moduleRoot struct {
+	dir     string
+	mod     *ModFile
+	aliases map[string]string
+	sums    map[string]string // sumKey(path, version) -> checksum
+}
+
+// LoadModFile registers the module declared by a sruja.mod and its aliases as
+// workspace aliases, so imports resolve to them from any file.
+func (w *Workspace) LoadModFile(path string) error {
+	root, err := readModFile(path)
+	if err != nil {
+		return err
+	}
+	if w.Aliases == nil {
+		w.Aliases = make(map[string]string)
+	}
+	for alias, dir := range root.aliases {
+		w.Aliases[alias] = dir
+	}
+	return nil
+}
+
+func readModFile(path string) (*moduleRoot, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	mod, err := ParseModFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	sums, err := ReadSumFile(filepath.Join(dir, SumFileName))
+	if err != nil {
+		return nil, err
+	}
+	root := &moduleRoot{dir: dir, mod: mod, aliases: make(map[string]string, len(mod.Aliases)+1), sums: sums}
+	if mod.Module != "" {
+		root.aliases[mod.Module] = dir
+	}
+	for alias, target := range mod.Aliases {
+		root.aliases[alias] = localDir(dir, target)
+	}
+	return root, nil
+}
+
+// localDir resolves a directory named in the sruja.mod of dir.
+func localDir(dir, target string) string {
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(dir, filepath.FromSlash(target))
+}
+
+// modulesOf returns the modules of the sruja.mod files above file, nearest
+// first. Lookups are cached per directory, so every root of a multi-root
+// workspace resolves imports with its own sruja.mod, and a vendored module
+// falls back to the requirements of the module vendoring it.
+func (w *Workspace) modulesOf(file string) []*moduleRoot {
+	if file == "" || w.IsStdLib(file) || strings.Contains(file, "://") {
+		return nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return nil
+	}
+	if chain, ok := w.modules[dir]; ok {
+		return chain
+	}
+	if w.modules == nil {
+		w.modules = make(map[string][]*moduleRoot)
+	}
+	var chain []*moduleRoot
+	for d := dir; ; {
+		root, err := readModFile(filepath.Join(d, ModFileName))
+		if err == nil {
+			chain = append(chain, root)
+			w.addSums(root.sums)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			break // a broken sruja.mod is reported by ParseWorkspace
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+	w.modules[dir] = chain
+	return chain
+}
+
+// FindModFile returns the nearest sruja.mod in dir or its parents, or "".
+func FindModFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ModFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ModuleError is an import of a required module that cannot be loaded. Code is
+// the diagnostic code reporting it.
+type ModuleError struct {
+	Code    string
+	Message string
+}
+
+func (e *ModuleError) Error() string { return e.Message }
+
+// importDiagnostic reports an import that cannot be loaded at the import.
+func importDiagnostic(err error, filename string, imp *ImportStatement) diagnostics.Diagnostic {
+	code := diagnostics.CodeModuleNotFound
+	var merr *ModuleError
+	if errors.As(err, &merr) {
+		code = merr.Code
+	}
+	return diagnostics.Diagnostic{
+		Code:     code,
+		Severity: diagnostics.SeverityError,
+		Message:  err.Error(),
+		Location: diagnostics.SourceLocation{File: filename, Line: imp.Pos.Line, Column: imp.Pos.Column},
+	}
+}
+
+// resolveImport resolves an import path to an absolute path, or to the import
+// path itself for the embedded stdlib and unknown modules. Module paths
+// resolve through the sruja.mod files above the importing file, nearest first,
+// then through the workspace aliases. An import may pin the version it expects,
+// as in acme.com/platform@v1.3.0, which must be the required one.
+func (w *Workspace) resolveImport(currentFile, importPath string) (string, error) {
+	if w.IsStdLib(importPath) {
+		return importPath, nil
+	}
+	if strings.HasPrefix(importPath, ".") {
+		return filepath.Join(filepath.Dir(currentFile), importPath), nil
+	}
+	path, version := splitImportVersion(importPath)
+	chain := w.modulesOf(currentFile)
+	for i, root := range chain {
+		if dir, ok := resolveAlias(root.aliases, path); ok {
+			return dir, nil
+		}
+		for _, req := range root.mod.Requires {
+			if path != req.Path && !strings.HasPrefix(path, req.Path+"/") {
+				continue
+			}
+			if version != "" && version != req.Version {
+				return "", &ModuleError{diagnostics.CodeModuleNotFound, fmt.Sprintf(
+					"import of %s@%s, but %s requires %s %s", req.Path, version, filepath.Join(root.dir, ModFileName), req.Path, req.Version)}
+			}
+			dir, err := w.requiredModuleDir(chain[i:], req)
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(path, req.Path))), nil
+		}
+	}
+	if version != "" {
+		return "", &ModuleError{diagnostics.CodeModuleNotFound, fmt.Sprintf("import of %s@%s, but no %s requires %s", path, version, ModFileName, path)}
+	}
+	if dir, ok := resolveAlias(w.Aliases, path); ok {
+		return dir, nil
+	}
+	return path, nil
+}
+
+// requiredModuleDir returns the directory holding a module required by the
+// first of chain: its copy vendored by that module or one vendoring it, its
+// local directory, or its copy in the module cache. Vendored and cached copies
+// must match their checksum in sruja.sum. Vendoring selects the highest
+// version required in the build, so a vendored copy may be newer than required.
+func (w *Workspace) requiredModuleDir(chain []*moduleRoot, req Require) (string, error) {
+	for _, root := range chain {
+		vendored := filepath.Join(root.dir, VendorDir, filepath.FromSlash(req.Path))
+		if !isDir(vendored) {
+			continue
+		}
+		version, sum, ok := root.vendoredSum(req.Path)
+		if !ok {
+			return "", &ModuleError{diagnostics.CodeModuleChecksum, fmt.Sprintf(
+				"missing %s entry for vendored %s; run 'sruja mod vendor'", SumFileName, req.Path)}
+		}
+		if CompareVersions(version, req.Version) < 0 {
+			return "", &ModuleError{diagnostics.CodeModuleNotFound, fmt.Sprintf(
+				"vendored %s %s is older than the required %s; run 'sruja mod vendor'", req.Path, version, req.Version)}
+		}
+		return vendored, w.verifyModule(req.Path, version, sum, vendored)
+	}
+	if req.Dir != "" {
+		dir := localDir(chain[0].dir, req.Dir)
+		if !isDir(dir) {
+			return "", &ModuleError{diagnostics.CodeModuleNotFound, fmt.Sprintf("module %s %s: directory %s does not exist", req.Path, req.Version, dir)}
+		}
+		return dir, nil
+	}
+	if cache := ModCacheDir(); cache != "" {
+		if cached := cachedModuleDir(cache, req.Path, req.Version); isDir(cached) {
+			// A cached module is checked against the sruja.sum of the module
+			// requiring it or, for modules it requires in turn, of any module
+			// loaded before
+			for _, sums := range append(sumsOf(chain), w.sums) {
+				if sum, ok := sums[sumKey(req.Path, req.Version)]; ok {
+					return cached, w.verifyModule(req.Path, req.Version, sum, cached)
+				}
+			}
+			return "", &ModuleError{diagnostics.CodeModuleChecksum, fmt.Sprintf(
+				"missing %s entry for %s %s; run 'sruja mod tidy'", SumFileName, req.Path, req.Version)}
+		}
+	}
+	return "", &ModuleError{diagnostics.CodeModuleNotFound, fmt.Sprintf(
+		"module %s %s is neither vendored nor in the module cache; run 'sruja mod vendor'", req.Path, req.Version)}
+}
+
+func sumsOf(chain []*moduleRoot) []map[string]string {
+	res := make([]map[string]string, 0, len(chain)+1)
+	for _, root := range chain {
+		res = append(res, root.sums)
+	}
+	return res
+}
+
+func (w *Workspace) addSums(sums map[string]string) {
+	if len(sums) == 0 {
+		return
+	}
+	if w.sums == nil {
+		w.sums = make(map[string]string)
+	}
+	for key, sum := range sums {
+		if _, ok := w.sums[key]; !ok {
+			w.sums[key] = sum
+		}
+	}
+}
+
+// vendoredSum returns the version and checksum sruja.sum records for a module
+// vendored into root.
+func (root *moduleRoot) vendoredSum(path string) (version, sum string, ok bool) {
+	for key, s := range root.sums {
+		if p, v, _ := strings.Cut(key, " "); p == path && (!ok || CompareVersions(v, version) > 0) {
+			version, sum, ok = v, s, true
+		}
+	}
+	return version, sum, ok
+}
+
+// verifyModule checks the files of a version of a module against its checksum.
+func (w *Workspace) verifyModule(path, version, want, dir string) error {
+	got, ok := w.hashes[dir]
+	if !ok {
+		var err error
+		if got, err = HashDir(dir); err != nil {
+			return &ModuleError{diagnostics.CodeModuleChecksum, fmt.Sprintf("module %s %s: %v", path, version, err)}
+		}
+		if w.hashes == nil {
+			w.hashes = make(map[string]string)
+		}
+		w.hashes[dir] = got
+	}
+	if got != want {
+		return &ModuleError{diagnostics.CodeModuleChecksum, fmt.Sprintf(
+			"checksum mismatch for %s %s in %s: %s has %s, the files hash to %s", path, version, dir, SumFileName, want, got)}
+	}
+	return nil
+}
+
+// splitImportVersion splits acme.com/platform@v1.3.0/billing into
+// acme.com/platform/billing and v1.3.0.
+func splitImportVersion(importPath string) (string, string) {
+	at := strings.Index(importPath, "@")
+	if at < 0 {
+		return importPath, ""
+	}
+	rest := importPath[at+1:]
+	if slash := strings.Index(rest, "/"); slash >= 0 {
+		return importPath[:at] + rest[slash:], rest[:slash]
+	}
+	return importPath[:at], rest
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// resolveAlias maps an import path that is, or is inside, an aliased module to
+// a directory, preferring the longest alias.
+func resolveAlias(aliases map[string]string, importPath string) (string, bool) {
+	best := ""
+	for alias := range aliases {
+		if (importPath == alias || strings.HasPrefix(importPath, alias+"/")) && len(alias) > len(best) {
+			best = alias
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	target := aliases[best]
+	if rest := strings.TrimPrefix(importPath, best); rest != "" && target != best {
+		return filepath.Join(target, filepath.FromSlash(rest)), true
+	}
+	return target, true
+}
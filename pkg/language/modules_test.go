@@ -0,0 +1,187 @@
+package language
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeModuleFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseModFile(t *testing.T) {
+	mod, err := ParseModFile("sruja.mod", []byte(`// Shop architecture
+module acme.com/shop
+
+alias acme.com/platform => ../platform // vendored
+alias acme.com/billing => /opt/billing
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mod.Module != "acme.com/shop" {
+		t.Errorf("Module = %q", mod.Module)
+	}
+	if len(mod.Aliases) != 2 || mod.Aliases["acme.com/platform"] != "../platform" || mod.Aliases["acme.com/billing"] != "/opt/billing" {
+		t.Errorf("Aliases = %v", mod.Aliases)
+	}
+
+	for _, bad := range []string{
+		"module a\nmodule b\n",
+		"alias a => ./a\nalias a => ./b\n",
+		"require acme.com/platform\n",
+		"alias acme.com/platform ./platform\n",
+	} {
+		if _, err := ParseModFile("sruja.mod", []byte(bad)); err == nil || !strings.HasPrefix(err.Error(), "sruja.mod:") {
+			t.Errorf("ParseModFile(%q) error = %v", bad, err)
+		}
+	}
+}
+
+func TestParseModFile_VersionAndRequires(t *testing.T) {
+	mod, err := ParseModFile("sruja.mod", []byte(`module acme.com/shop
+version v1.2.0
+
+require acme.com/platform v1.3.0 // shared kinds
+require acme.com/payments v0.4.0 => ../payments
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mod.Version != "v1.2.0" || len(mod.Requires) != 2 {
+		t.Fatalf("Version = %q, Requires = %+v", mod.Version, mod.Requires)
+	}
+	if req, ok := mod.Require("acme.com/platform"); !ok || req.Version != "v1.3.0" || req.Dir != "" || req.Line != 4 {
+		t.Errorf("platform require = %+v", req)
+	}
+	if req, ok := mod.Require("acme.com/payments"); !ok || req.Dir != "../payments" {
+		t.Errorf("payments require = %+v", req)
+	}
+
+	for _, bad := range []string{
+		"version 1.2.0\n",
+		"require acme.com/platform v1.3\n",
+		"require acme.com/platform v1.3.0\nrequire acme.com/platform v1.4.0\n",
+		"require acme.com/platform v1.3.0 ../platform\n",
+	} {
+		if _, err := ParseModFile("sruja.mod", []byte(bad)); err == nil || !strings.HasPrefix(err.Error(), "sruja.mod:") {
+			t.Errorf("ParseModFile(%q) error = %v", bad, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.0", "v1.2.0", 0},
+		{"v1.2.0", "v1.10.0", -1},
+		{"v2.0.0", "v1.9.9", 1},
+		{"v0.0.1", "v0.0.2", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStdlibModule_FromEmbeddedModFile(t *testing.T) {
+	if StdlibModule != "sruja.ai/stdlib" {
+		t.Errorf("StdlibModule = %q", StdlibModule)
+	}
+	if path := NewWorkspace().GetAliasPath(StdlibModule); path != StdlibModule {
+		t.Errorf("stdlib alias = %q, want the embedded module", path)
+	}
+}
+
+func TestParseWorkspace_ModuleAliases(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app")
+	writeModuleFile(t, filepath.Join(app, ModFileName), "module acme.com/shop\nalias acme.com/platform => ../vendor/platform\n")
+	writeModuleFile(t, filepath.Join(app, "main.sruja"), `import { service } from 'acme.com/platform'
+import { * } from 'sruja.ai/stdlib'
+Shop = system "Shop"
+`)
+	writeModuleFile(t, filepath.Join(dir, "vendor", "platform", "kinds.sruja"), `service = kind "Service"
+queueing = kind "Queueing"
+`)
+
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, err := p.ParseWorkspace(app)
+	if err != nil {
+		t.Fatalf("ParseWorkspace error: %v", err)
+	}
+	if _, ok := ws.Programs[filepath.Join(dir, "vendor", "platform", "kinds.sruja")]; !ok {
+		t.Errorf("vendored module not loaded, programs: %v", keys(ws.Programs))
+	}
+	if _, ok := ws.Programs[StdlibModule+"/core.sruja"]; !ok {
+		t.Errorf("stdlib not loaded from the embedded files, programs: %v", keys(ws.Programs))
+	}
+
+	main := ws.Programs[filepath.Join(app, "main.sruja")]
+	names := map[string]bool{}
+	for _, item := range main.Specification.Items {
+		names[ws.getSpecificationItemName(item)] = true
+	}
+	if !names["service"] || names["queueing"] {
+		t.Errorf("expected only the imported platform kind, got %v", names)
+	}
+}
+
+func TestParseWorkspace_InvalidModFile(t *testing.T) {
+	root := t.TempDir()
+	writeModuleFile(t, filepath.Join(root, ModFileName), "module\n")
+	writeModuleFile(t, filepath.Join(root, "main.sruja"), "Shop = system \"Shop\"\n")
+
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.ParseWorkspace(root); err == nil || !strings.Contains(err.Error(), ModFileName) {
+		t.Fatalf("expected a sruja.mod error, got %v", err)
+	}
+}
+
+func TestWorkspace_ResolveImportPath_PerRootModules(t *testing.T) {
+	dir := t.TempDir()
+	for _, root := range []string{"a", "b"} {
+		writeModuleFile(t, filepath.Join(dir, root, ModFileName), "alias acme.com/platform => ./platform-"+root+"\n")
+	}
+	ws := NewWorkspace()
+
+	// Each root resolves the same module path with its own sruja.mod
+	for _, root := range []string{"a", "b"} {
+		file := filepath.Join(dir, root, "nested", "main.sruja")
+		want := filepath.Join(dir, root, "platform-"+root, "billing")
+		if got := ws.resolveImportPath(file, "acme.com/platform/billing"); got != want {
+			t.Errorf("root %s: resolved to %q, want %q", root, got, want)
+		}
+	}
+	if got := ws.resolveImportPath(filepath.Join(dir, "main.sruja"), "acme.com/platform"); got != "acme.com/platform" {
+		t.Errorf("outside the roots: resolved to %q", got)
+	}
+	if got := ws.resolveImportPath(filepath.Join(dir, "a", "main.sruja"), StdlibModule); got != StdlibModule {
+		t.Errorf("stdlib resolved to %q", got)
+	}
+}
+
+func keys(m map[string]*Program) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
@@ -0,0 +1,309 @@
+package language
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// moduleSource is a module version selected for the build, and the directory
+// its files are copied or hashed from.
+type moduleSource struct {
+	path    string
+	version string
+	dir     string
+}
+
+// buildList returns the modules required by a module and, in turn, by them,
+// each at the highest version any of them requires. Only the local directories
+// of the main module's requirements are honoured; other modules come from the
+// module cache or, failing that, from the main module's vendor directory.
+func buildList(main *moduleRoot) ([]moduleSource, error) {
+	selected := make(map[string]moduleSource)
+	queue := append([]Require(nil), main.mod.Requires...)
+	for len(queue) > 0 {
+		req := queue[0]
+		queue = queue[1:]
+		if prev, ok := selected[req.Path]; ok && CompareVersions(prev.version, req.Version) >= 0 {
+			continue
+		}
+		dir, err := moduleSourceDir(main, req)
+		if err != nil {
+			return nil, err
+		}
+		selected[req.Path] = moduleSource{path: req.Path, version: req.Version, dir: dir}
+
+		modPath := filepath.Join(dir, ModFileName)
+		if _, err := os.Stat(modPath); err != nil {
+			continue // a module without a sruja.mod requires nothing
+		}
+		root, err := readModFile(modPath)
+		if err != nil {
+			return nil, err
+		}
+		if root.mod.Module != "" && root.mod.Module != req.Path {
+			return nil, fmt.Errorf("%s declares module %s, but is required as %s", modPath, root.mod.Module, req.Path)
+		}
+		for _, dep := range root.mod.Requires {
+			dep.Dir = ""
+			queue = append(queue, dep)
+		}
+	}
+
+	list := make([]moduleSource, 0, len(selected))
+	for _, src := range selected {
+		list = append(list, src)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].path < list[j].path })
+	return list, nil
+}
+
+// moduleSourceDir returns the directory holding a version of a module to vendor.
+func moduleSourceDir(main *moduleRoot, req Require) (string, error) {
+	if local, ok := main.mod.Require(req.Path); ok && local.Dir != "" {
+		dir := localDir(main.dir, local.Dir)
+		if !isDir(dir) {
+			return "", fmt.Errorf("%s:%d: directory %s of %s does not exist", filepath.Join(main.dir, ModFileName), local.Line, dir, req.Path)
+		}
+		return dir, nil
+	}
+	cache := ModCacheDir()
+	if cache != "" {
+		if dir := cachedModuleDir(cache, req.Path, req.Version); isDir(dir) {
+			return dir, checkModuleVersion(dir, req)
+		}
+	}
+	// A module vendored before can be vendored again without the cache
+	if dir := filepath.Join(main.dir, VendorDir, filepath.FromSlash(req.Path)); isDir(dir) {
+		if version, _, ok := main.vendoredSum(req.Path); ok && version == req.Version {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("module %s %s is not in the module cache %s; copy it to %s or require it from a local directory",
+		req.Path, req.Version, cache, cachedModuleDir(cache, req.Path, req.Version))
+}
+
+// checkModuleVersion checks that a module copy declares the version required.
+func checkModuleVersion(dir string, req Require) error {
+	modPath := filepath.Join(dir, ModFileName)
+	if _, err := os.Stat(modPath); err != nil {
+		return nil
+	}
+	root, err := readModFile(modPath)
+	if err != nil {
+		return err
+	}
+	if root.mod.Version != "" && root.mod.Version != req.Version {
+		return fmt.Errorf("%s declares version %s, but %s %s is required", modPath, root.mod.Version, req.Path, req.Version)
+	}
+	return nil
+}
+
+// VendorModules copies the modules required by the module of a sruja.mod, and
+// the modules they require, into its vendor directory, and records their
+// checksums in its sruja.sum. The vendor directory is replaced as a whole.
+func VendorModules(modPath string) error {
+	main, err := readModFile(modPath)
+	if err != nil {
+		return err
+	}
+	list, err := buildList(main)
+	if err != nil {
+		return err
+	}
+
+	// Modules may be vendored again from the vendor directory, so the new one
+	// is built next to it before replacing it
+	tmp, err := os.MkdirTemp(main.dir, "."+VendorDir+"-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	sums := make(map[string]string, len(list))
+	for _, src := range list {
+		dst := filepath.Join(tmp, filepath.FromSlash(src.path))
+		if err := copyModule(src.dir, dst); err != nil {
+			return fmt.Errorf("vendor %s %s: %w", src.path, src.version, err)
+		}
+		sum, err := HashDir(dst)
+		if err != nil {
+			return err
+		}
+		sums[sumKey(src.path, src.version)] = sum
+	}
+
+	vendor := filepath.Join(main.dir, VendorDir)
+	if err := os.RemoveAll(vendor); err != nil {
+		return err
+	}
+	if len(list) > 0 {
+		if err := os.Rename(tmp, vendor); err != nil {
+			return err
+		}
+	}
+	return WriteSumFile(filepath.Join(main.dir, SumFileName), sums)
+}
+
+// copyModule copies the files of a module directory that its checksum covers.
+func copyModule(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != src && skipModuleEntry(src, path, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o750)
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644) //nolint:gosec // vendored sources are checked in
+	})
+}
+
+// TidyModules removes the requirements of the module of a sruja.mod that none
+// of its files import, and records the checksums of the modules it still
+// requires, and that they require, in its sruja.sum. An import of a module
+// path that is neither required nor aliased is an error.
+func TidyModules(modPath string) error {
+	main, err := readModFile(modPath)
+	if err != nil {
+		return err
+	}
+	imports, err := moduleImports(main.dir)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for _, imp := range imports {
+		path, _ := splitImportVersion(imp.path)
+		if path == StdlibModule || strings.HasPrefix(path, StdlibModule+"/") || strings.HasPrefix(path, ".") {
+			continue
+		}
+		if _, ok := resolveAlias(main.aliases, path); ok {
+			continue
+		}
+		required := false
+		for _, req := range main.mod.Requires {
+			if path == req.Path || strings.HasPrefix(path, req.Path+"/") {
+				used[req.Path], required = true, true
+			}
+		}
+		if !required && strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			return fmt.Errorf("%s: module %s is not required by %s; add 'require <module> <version>'", imp.pos, path, modPath)
+		}
+	}
+
+	var unused []int
+	for _, req := range main.mod.Requires {
+		if !used[req.Path] {
+			unused = append(unused, req.Line)
+		}
+	}
+	if len(unused) > 0 {
+		if err := removeLines(modPath, unused); err != nil {
+			return err
+		}
+		if main, err = readModFile(modPath); err != nil {
+			return err
+		}
+	}
+
+	list, err := buildList(main)
+	if err != nil {
+		return err
+	}
+	sums := make(map[string]string, len(list))
+	for _, src := range list {
+		sum, err := HashDir(src.dir)
+		if err != nil {
+			return err
+		}
+		sums[sumKey(src.path, src.version)] = sum
+	}
+	return WriteSumFile(filepath.Join(main.dir, SumFileName), sums)
+}
+
+// moduleImport is an import path and where it is imported.
+type moduleImport struct {
+	path string
+	pos  string
+}
+
+// moduleImports returns the imports of the .sruja files of a module, leaving
+// out its vendor directory and the modules nested in it.
+func moduleImports(dir string) ([]moduleImport, error) {
+	p, err := NewParser()
+	if err != nil {
+		return nil, err
+	}
+	var imports []moduleImport
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && skipModuleEntry(dir, path, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if _, err := os.Stat(filepath.Join(path, ModFileName)); path != dir && err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".sruja" {
+			return nil
+		}
+		prog, _, err := p.ParseFile(path)
+		if err != nil {
+			return err
+		}
+		if prog == nil || prog.Model == nil {
+			return nil
+		}
+		for _, item := range prog.Model.Items {
+			if item.Import != nil {
+				imports = append(imports, moduleImport{path: item.Import.From, pos: item.Import.Pos.String()})
+			}
+		}
+		return nil
+	})
+	return imports, err
+}
+
+// removeLines removes lines, numbered from 1, from a file.
+func removeLines(path string, lines []int) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	drop := make(map[int]bool, len(lines))
+	for _, l := range lines {
+		drop[l] = true
+	}
+	var kept []string
+	for i, line := range strings.SplitAfter(string(data), "\n") {
+		if !drop[i+1] {
+			kept = append(kept, line)
+		}
+	}
+	return os.WriteFile(path, []byte(strings.Join(kept, "")), 0o644) //nolint:gosec // sruja.mod is checked in
+}
@@ -0,0 +1,189 @@
+package language
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// moduleCache lays out a module cache holding acme.com/platform v1.3.0, which
+// requires acme.com/base v1.0.0, and points SRUJA_MODCACHE at it.
+func moduleCache(t *testing.T) string {
+	t.Helper()
+	cache := t.TempDir()
+	t.Setenv("SRUJA_MODCACHE", cache)
+	platform := cachedModuleDir(cache, "acme.com/platform", "v1.3.0")
+	writeModuleFile(t, filepath.Join(platform, ModFileName), "module acme.com/platform\nversion v1.3.0\nrequire acme.com/base v1.0.0\n")
+	writeModuleFile(t, filepath.Join(platform, "kinds.sruja"), `import { * } from 'acme.com/base'
+service = kind "Service"
+`)
+	base := cachedModuleDir(cache, "acme.com/base", "v1.0.0")
+	writeModuleFile(t, filepath.Join(base, ModFileName), "module acme.com/base\nversion v1.0.0\n")
+	writeModuleFile(t, filepath.Join(base, "tags.sruja"), `critical = tag "Critical"
+`)
+	return cache
+}
+
+func parseModuleWorkspace(t *testing.T, root string) *Workspace {
+	t.Helper()
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, err := p.ParseWorkspace(root)
+	if err != nil {
+		t.Fatalf("ParseWorkspace error: %v", err)
+	}
+	return ws
+}
+
+func moduleDiags(ws *Workspace) []diagnostics.Diagnostic {
+	var res []diagnostics.Diagnostic
+	for _, d := range ws.Diags {
+		if strings.HasPrefix(d.Code, "E5") {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func TestVendorModules(t *testing.T) {
+	moduleCache(t)
+	dir := t.TempDir()
+	app := filepath.Join(dir, "shop")
+	writeModuleFile(t, filepath.Join(dir, "payments", ModFileName), "module acme.com/payments\n")
+	writeModuleFile(t, filepath.Join(dir, "payments", "kinds.sruja"), `gateway = kind "Gateway"
+`)
+	writeModuleFile(t, filepath.Join(app, ModFileName), `module acme.com/shop
+require acme.com/platform v1.3.0
+require acme.com/payments v0.4.0 => ../payments
+`)
+	writeModuleFile(t, filepath.Join(app, "main.sruja"), `import { service } from 'acme.com/platform@v1.3.0'
+import { gateway } from 'acme.com/payments'
+Shop = system "Shop"
+`)
+
+	if err := VendorModules(filepath.Join(app, ModFileName)); err != nil {
+		t.Fatalf("VendorModules error: %v", err)
+	}
+	for _, mod := range []string{"acme.com/platform", "acme.com/base", "acme.com/payments"} {
+		if !isDir(filepath.Join(app, VendorDir, filepath.FromSlash(mod))) {
+			t.Errorf("%s not vendored", mod)
+		}
+	}
+	sums, err := ReadSumFile(filepath.Join(app, SumFileName))
+	if err != nil || len(sums) != 3 || sums[sumKey("acme.com/base", "v1.0.0")] == "" {
+		t.Fatalf("sums = %v, %v", sums, err)
+	}
+
+	// Vendored modules are used without the cache
+	t.Setenv("SRUJA_MODCACHE", t.TempDir())
+	ws := parseModuleWorkspace(t, app)
+	if diags := moduleDiags(ws); len(diags) != 0 {
+		t.Fatalf("unexpected module diagnostics: %v", diags)
+	}
+	names := map[string]bool{}
+	for _, item := range ws.Programs[filepath.Join(app, "main.sruja")].Specification.Items {
+		names[ws.getSpecificationItemName(item)] = true
+	}
+	if !names["service"] || !names["gateway"] {
+		t.Errorf("imported kinds = %v", names)
+	}
+
+	// Edited vendored files no longer match sruja.sum
+	writeModuleFile(t, filepath.Join(app, VendorDir, "acme.com", "platform", "kinds.sruja"), `service = kind "Microservice"
+`)
+	diags := moduleDiags(parseModuleWorkspace(t, app))
+	if len(diags) == 0 || diags[0].Code != diagnostics.CodeModuleChecksum || diags[0].Location.Line != 1 {
+		t.Fatalf("expected a checksum error at the import, got %v", diags)
+	}
+}
+
+func TestParseWorkspace_RequiredModuleErrors(t *testing.T) {
+	moduleCache(t)
+	app := t.TempDir()
+	writeModuleFile(t, filepath.Join(app, ModFileName), "module acme.com/shop\nrequire acme.com/platform v1.3.0\nrequire acme.com/ledger v2.0.0\n")
+	writeModuleFile(t, filepath.Join(app, "main.sruja"), `import { service } from 'acme.com/platform@v1.2.0'
+import { * } from 'acme.com/ledger'
+import { * } from 'acme.com/platform'
+Shop = system "Shop"
+`)
+
+	diags := moduleDiags(parseModuleWorkspace(t, app))
+	byLine := map[int]diagnostics.Diagnostic{}
+	for _, d := range diags {
+		byLine[d.Location.Line] = d
+	}
+	if d := byLine[1]; d.Code != diagnostics.CodeModuleNotFound || !strings.Contains(d.Message, "v1.3.0") {
+		t.Errorf("version mismatch diagnostic = %+v", d)
+	}
+	if d := byLine[2]; d.Code != diagnostics.CodeModuleNotFound || !strings.Contains(d.Message, "sruja mod vendor") {
+		t.Errorf("missing module diagnostic = %+v", d)
+	}
+	// The cached module has no sruja.sum entry yet
+	if d := byLine[3]; d.Code != diagnostics.CodeModuleChecksum {
+		t.Errorf("missing checksum diagnostic = %+v", d)
+	}
+}
+
+func TestTidyModules(t *testing.T) {
+	moduleCache(t)
+	app := t.TempDir()
+	modPath := filepath.Join(app, ModFileName)
+	writeModuleFile(t, modPath, `module acme.com/shop
+// shared kinds
+require acme.com/platform v1.3.0
+require acme.com/ledger v2.0.0
+`)
+	writeModuleFile(t, filepath.Join(app, "main.sruja"), `import { service } from 'acme.com/platform'
+Shop = system "Shop"
+`)
+
+	if err := TidyModules(modPath); err != nil {
+		t.Fatalf("TidyModules error: %v", err)
+	}
+	data, _ := os.ReadFile(modPath)
+	if string(data) != "module acme.com/shop\n// shared kinds\nrequire acme.com/platform v1.3.0\n" {
+		t.Errorf("sruja.mod after tidy:\n%s", data)
+	}
+	sums, err := ReadSumFile(filepath.Join(app, SumFileName))
+	if err != nil || len(sums) != 2 {
+		t.Fatalf("sums = %v, %v", sums, err)
+	}
+	if diags := moduleDiags(parseModuleWorkspace(t, app)); len(diags) != 0 {
+		t.Errorf("cached modules should verify after tidy, got %v", diags)
+	}
+
+	writeModuleFile(t, filepath.Join(app, "billing.sruja"), `import { * } from 'acme.com/billing'
+`)
+	if err := TidyModules(modPath); err == nil || !strings.Contains(err.Error(), "acme.com/billing is not required") {
+		t.Errorf("expected an unrequired module error, got %v", err)
+	}
+}
+
+func TestMergeImports_ConflictingDefinitions(t *testing.T) {
+	app := t.TempDir()
+	writeModuleFile(t, filepath.Join(app, ModFileName), `alias acme.com/a => ./a
+alias acme.com/b => ./b
+alias acme.com/c => ./c
+`)
+	writeModuleFile(t, filepath.Join(app, "a", "kinds.sruja"), "service = kind \"Service\"\n")
+	writeModuleFile(t, filepath.Join(app, "b", "kinds.sruja"), "service = kind \"Service\"\n")
+	writeModuleFile(t, filepath.Join(app, "c", "kinds.sruja"), "service = kind \"Microservice\"\n")
+	writeModuleFile(t, filepath.Join(app, "main.sruja"), `import { service } from 'acme.com/a'
+import { service } from 'acme.com/b'
+import { service } from 'acme.com/c'
+`)
+
+	ws := parseModuleWorkspace(t, app)
+	diags := moduleDiags(ws)
+	if len(diags) != 1 || diags[0].Code != diagnostics.CodeImportConflict || diags[0].Location.Line != 3 {
+		t.Fatalf("expected one conflict at the third import, got %v", diags)
+	}
+	if items := ws.Programs[filepath.Join(app, "main.sruja")].Specification.Items; len(items) != 1 {
+		t.Errorf("service imported %d times", len(items))
+	}
+}
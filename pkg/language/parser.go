@@ -98,30 +98,17 @@ func NewParser() (*Parser, error) {
 
 // Parse parses DSL text into an AST.
 func (p *Parser) Parse(filename, text string) (prog *Program, diags []diagnostics.Diagnostic, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			// Convert panic to diagnostic
-			diags = append(diags, diagnostics.Diagnostic{
-				Code:     diagnostics.CodeSyntaxError,
-				Severity: diagnostics.SeverityError,
-				Message:  fmt.Sprintf("Internal parser panic: %v", r),
-				Location: diagnostics.SourceLocation{File: filename},
-			})
-			// Also return error to indicate critical failure
-			err = fmt.Errorf("internal parser panic: %v", r)
-		}
-	}()
+	defer recoverParsePanic(filename, &diags, &err)
 
-	file, err := p.parser.ParseString(filename, text)
+	items, diags, err := p.parseItems(filename, text)
 	if err != nil {
-		// Convert participle error to diagnostics
-		diags = p.convertErrorToDiagnostics(err, filename, text)
-		return nil, diags, err // Return diagnostics and error
+		return nil, diags, err
 	}
 
 	// Convert File to Program (Logical Model)
 	prog = &Program{
-		Items: file.TopLevelItems,
+		Items:        items,
+		Suppressions: p.ScanSuppressions(filename, text),
 	}
 
 	// Post-process to merge blocks and items
@@ -129,3 +116,36 @@ func (p *Parser) Parse(filename, text string) (prog *Program, diags []diagnostic
 
 	return prog, nil, nil
 }
+
+// ParseItems parses DSL text into top-level items without post-processing them,
+// for callers that parse a file in parts and assemble the Program themselves:
+//
+//	prog := &Program{Items: items}
+//	prog.PostProcess()
+func (p *Parser) ParseItems(filename, text string) (items []TopLevelItem, diags []diagnostics.Diagnostic, err error) {
+	defer recoverParsePanic(filename, &diags, &err)
+	return p.parseItems(filename, text)
+}
+
+func (p *Parser) parseItems(filename, text string) ([]TopLevelItem, []diagnostics.Diagnostic, error) {
+	file, err := p.parser.ParseString(filename, text)
+	if err != nil {
+		// Convert participle error to diagnostics
+		return nil, p.convertErrorToDiagnostics(err, filename, text), err
+	}
+	return file.TopLevelItems, nil, nil
+}
+
+// recoverParsePanic converts a parser panic into a diagnostic and an error.
+func recoverParsePanic(filename string, diags *[]diagnostics.Diagnostic, err *error) {
+	if r := recover(); r != nil {
+		*diags = append(*diags, diagnostics.Diagnostic{
+			Code:     diagnostics.CodeSyntaxError,
+			Severity: diagnostics.SeverityError,
+			Message:  fmt.Sprintf("Internal parser panic: %v", r),
+			Location: diagnostics.SourceLocation{File: filename},
+		})
+		// Also return error to indicate critical failure
+		*err = fmt.Errorf("internal parser panic: %v", r)
+	}
+}
@@ -59,6 +59,40 @@ func TestParser_SpecificationItems(t *testing.T) {
 				assert.Len(t, prog.Specification.Items, 2)
 			},
 		},
+		{
+			name: "Specification with relationship kinds",
+			input: `
+				publishes = relationship "Publishes" {
+					description "Emits an event"
+					technology "Kafka"
+					async
+					style { color "#888888" }
+					from [system, container]
+					to queue
+				}
+				replicates = relationship
+			`,
+			wantErr: false,
+			validate: func(t *testing.T, prog *language.Program) {
+				require.NotNil(t, prog.Specification)
+				kinds := prog.Specification.RelationshipKinds()
+				require.Len(t, kinds, 2)
+				kind := prog.Specification.RelationshipKind("PUBLISHES")
+				require.NotNil(t, kind)
+				assert.Equal(t, "Publishes", kind.GetLabel())
+				assert.Equal(t, "Emits an event", kind.GetDescription())
+				assert.Equal(t, "Kafka", kind.GetTechnology())
+				assert.True(t, kind.IsAsync())
+				require.NotNil(t, kind.GetStyle())
+				assert.Len(t, kind.GetStyle().Entries, 1)
+				assert.Equal(t, []string{"system", "container"}, kind.SourceKinds())
+				assert.Equal(t, []string{"queue"}, kind.TargetKinds())
+				assert.Equal(t, "replicates", kinds[1].GetLabel())
+				assert.False(t, kinds[1].IsAsync())
+				assert.Nil(t, kinds[1].SourceKinds())
+				assert.Nil(t, prog.Model)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -331,3 +365,34 @@ func TestParser_FullFile(t *testing.T) {
 	// Verify views
 	assert.Len(t, prog.Views.Items, 1)
 }
+
+func TestPrinter_RelationshipKindRoundTrip(t *testing.T) {
+	p, err := language.NewParser()
+	require.NoError(t, err)
+
+	input := `publishes = relationship "Publishes" {
+	technology "Kafka"
+	async
+	style { line dashed }
+	from [system, container]
+	to [queue]
+}
+`
+	prog, _, err := p.Parse("test.sruja", input)
+	require.NoError(t, err)
+
+	printed := language.NewPrinter().Print(prog)
+	assert.Contains(t, printed, `publishes = relationship "Publishes" {`)
+	assert.Contains(t, printed, "from [system, container]")
+
+	reparsed, diags, err := p.Parse("printed.sruja", printed)
+	require.NoError(t, err, printed)
+	assert.Empty(t, diags)
+	kind := reparsed.Specification.RelationshipKind("publishes")
+	require.NotNil(t, kind)
+	assert.True(t, kind.IsAsync())
+	assert.Equal(t, "Kafka", kind.GetTechnology())
+	assert.Equal(t, []string{"queue"}, kind.TargetKinds())
+	require.NotNil(t, kind.GetStyle())
+	assert.Equal(t, "dashed", *kind.GetStyle().Entries[0].Value)
+}
@@ -0,0 +1,420 @@
+package language
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// maxRecoveries bounds the syntax errors a partial parse recovers from, as each
+// recovery reparses the text.
+const maxRecoveries = 50
+
+// ParsePartial parses DSL text like Parse, but recovers from syntax errors so
+// editors keep working on the valid parts of a file while it is being typed.
+// A statement in error is skipped up to the next top-level item or the closing
+// brace of its block, blocks left open at the end of the text are closed, and
+// parsing resumes.
+//
+// The returned program holds everything that parsed, and is nil only if the
+// parser fails internally. The diagnostics report every syntax error, and err
+// is the first of them. When recovery gives up, after maxRecoveries errors or
+// on an error no repair gets past, the program holds the top-level items before
+// that error and the diagnostics the errors found so far.
+func (p *Parser) ParsePartial(filename, text string) (prog *Program, diags []diagnostics.Diagnostic, err error) {
+	defer recoverParsePanic(filename, &diags, &err)
+
+	items, diags, err := p.parseItemsPartial(filename, text)
+	prog = &Program{Items: items, Suppressions: p.ScanSuppressions(filename, text)}
+	prog.PostProcess()
+	return prog, diags, err
+}
+
+// ParseItemsPartial parses DSL text into top-level items like ParseItems, with
+// the error recovery of ParsePartial.
+func (p *Parser) ParseItemsPartial(filename, text string) (items []TopLevelItem, diags []diagnostics.Diagnostic, err error) {
+	defer recoverParsePanic(filename, &diags, &err)
+	return p.parseItemsPartial(filename, text)
+}
+
+func (p *Parser) parseItemsPartial(filename, text string) ([]TopLevelItem, []diagnostics.Diagnostic, error) {
+	items, diags, firstErr := p.parseItems(filename, text)
+	if firstErr == nil {
+		return items, nil, nil
+	}
+
+	current, err := text, firstErr
+	seen := make(map[diagnostics.SourceLocation]bool)
+	for _, d := range diags {
+		seen[d.Location] = true
+	}
+	for n := 0; n < maxRecoveries; n++ {
+		// Of the possible repairs, keep the one the parser gets furthest with
+		var file *File
+		next, parseErr, reached := "", error(nil), -1
+		for _, candidate := range repairs(current, errorOffset(err, current), isLexerError(err)) {
+			f, e := p.parser.ParseString(filename, candidate)
+			if e == nil {
+				file, next, parseErr = f, candidate, nil
+				break
+			}
+			if at := errorOffset(e, candidate); at > reached {
+				next, parseErr, reached = candidate, e, at
+			}
+		}
+		if next == "" {
+			return p.parsedPrefix(filename, current, err), diags, firstErr
+		}
+		if parseErr == nil {
+			return file.TopLevelItems, diags, firstErr
+		}
+		current, err = next, parseErr
+		// Context lines come from the text as written; repairs keep its lines
+		for _, d := range p.convertErrorToDiagnostics(err, filename, text) {
+			if !seen[d.Location] {
+				seen[d.Location] = true
+				diags = append(diags, d)
+			}
+		}
+	}
+	return p.parsedPrefix(filename, current, err), diags, firstErr
+}
+
+// parsedPrefix returns the top-level items of text before the one holding the
+// syntax error err, for when recovery gives up on it.
+func (p *Parser) parsedPrefix(filename, text string, err error) []TopLevelItem {
+	offset := errorOffset(err, text)
+	if offset < 0 {
+		return nil
+	}
+	start := 0
+	for _, b := range SplitTopLevel(text) {
+		if b.Offset > offset {
+			break
+		}
+		start = b.Offset
+	}
+	file, err := p.parser.ParseString(filename, text[:start]+blank(text[start:]))
+	if err != nil {
+		return nil
+	}
+	return file.TopLevelItems
+}
+
+func isLexerError(err error) bool {
+	var lexErr *lexer.Error
+	return errors.As(err, &lexErr)
+}
+
+// errorOffset returns the offset in text of a syntax error, or -1 if the error
+// has no position.
+func errorOffset(err error, text string) int {
+	var parseErr participle.Error
+	if !errors.As(err, &parseErr) {
+		return -1
+	}
+	pos := parseErr.Position()
+	if pos.Line < 1 {
+		return -1
+	}
+	offset := 0
+	for line := 1; line < pos.Line; line++ {
+		nl := strings.IndexByte(text[offset:], '\n')
+		if nl < 0 {
+			return len(text)
+		}
+		offset += nl + 1
+	}
+	offset += pos.Column - 1
+	if offset > len(text) {
+		offset = len(text)
+	}
+	return offset
+}
+
+// repairs returns ways to get past the syntax error at offset, most likely
+// first: the text with the statement in error blanked out, or with its open
+// blocks closed when the error is at the end of the text. When the error starts
+// a line inside a block, the statement before it may be the one in error, as
+// in a "technology" missing its value. Blanking keeps line breaks, so positions
+// after it still hold.
+func repairs(text string, offset int, lexical bool) []string {
+	if offset < 0 {
+		return nil
+	}
+	if offset >= len(strings.TrimRight(text, " \t\r\n")) {
+		if open := openBlocks(text); len(open) > 0 {
+			return []string{text + "\n" + closers(open) + "\n"}
+		}
+	}
+	if lexical && offset < len(text) && (text[offset] == '"' || text[offset] == '\'') {
+		// An unterminated string runs to the end of its line
+		quote := unterminatedString(text, offset)
+		end := lineEnd(text, quote)
+		return []string{text[:quote] + blank(text[quote:end]) + text[end:]}
+	}
+
+	var candidates []string
+	start := lineBegin(text, offset)
+	if skipped, ok := skipStatement(text, start, offset); ok {
+		candidates = append(candidates, skipped)
+	}
+	if isBlank(text[start:offset]) && start > 0 && len(openBlocks(text[:start])) > 0 {
+		prev := lineBegin(text, len(strings.TrimRight(text[:start], " \t\r\n")))
+		if !isBlank(text[prev:start]) && !hasBlockDelims(text[prev:start]) {
+			candidates = append(candidates, text[:prev]+blank(text[prev:start])+text[start:])
+		}
+	}
+	return candidates
+}
+
+// skipStatement blanks out the statement in error at offset, from the line
+// start: up to the next top-level item, or inside a block up to the end of its
+// line or the brace closing the block. A statement left empty may have started
+// on an earlier line, so blanking moves back a line at a time.
+func skipStatement(text string, start, offset int) (string, bool) {
+	for {
+		var end int
+		if len(openBlocks(text[:start])) == 0 {
+			end = topLevelEnd(text, offset)
+		} else {
+			end = statementEnd(text, start, offset)
+		}
+		if !isBlank(text[start:end]) {
+			return text[:start] + blank(text[start:end]) + text[end:], true
+		}
+		if start == 0 {
+			return "", false
+		}
+		start = lineBegin(text, len(strings.TrimRight(text[:start], " \t\r\n")))
+	}
+}
+
+// unterminatedString returns the opening quote of the string left unterminated
+// when the lexer fails at the quote at offset. Strings may span lines, so a
+// missing quote pairs the ones after it wrongly up to the last quote of the
+// text, where the lexer fails. The first string spanning lines whose closing
+// quote runs into a word is where the pairing went wrong.
+func unterminatedString(text string, offset int) int {
+	quote := offset
+	walkCode(text[:offset], 0, func(i int, ch byte) bool {
+		if ch != '"' && ch != '\'' {
+			return true
+		}
+		end := stringEnd(text, i)
+		if end < len(text) && end+1 < len(text) && isIdentChar(text[end+1]) && strings.Contains(text[i:end], "\n") {
+			quote = i
+			return false
+		}
+		return true
+	})
+	return quote
+}
+
+// hasBlockDelims reports whether s opens or closes a block or list.
+func hasBlockDelims(s string) bool {
+	found := false
+	walkCode(s, 0, func(_ int, ch byte) bool {
+		found = ch == '{' || ch == '}' || ch == '[' || ch == ']'
+		return !found
+	})
+	return found
+}
+
+// statementEnd returns where the statement starting at start inside a block
+// ends: at the end of the line holding offset and any block it opens, or before
+// the brace closing the enclosing block.
+func statementEnd(text string, start, offset int) int {
+	end, depth := len(text), 0
+	walkCode(text, start, func(i int, ch byte) bool {
+		switch ch {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth < 0 {
+				end = i
+				return false
+			}
+		case '\n':
+			if depth == 0 && i >= offset {
+				end = i
+				return false
+			}
+		}
+		return true
+	})
+	return end
+}
+
+// topLevelEnd returns where the top-level item holding offset ends, which is
+// where the next one starts.
+func topLevelEnd(text string, offset int) int {
+	for _, b := range SplitTopLevel(text) {
+		if end := b.Offset + len(b.Text); offset < end {
+			return end
+		}
+	}
+	return len(text)
+}
+
+// openBlocks returns the braces and brackets left open at the end of text.
+func openBlocks(text string) []byte {
+	var open []byte
+	walkCode(text, 0, func(_ int, ch byte) bool {
+		switch ch {
+		case '{', '[':
+			open = append(open, ch)
+		case '}', ']':
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		}
+		return true
+	})
+	return open
+}
+
+func closers(open []byte) string {
+	var sb strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == '{' {
+			sb.WriteByte('}')
+		} else {
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+// blank replaces everything but line breaks with spaces.
+func blank(s string) string {
+	b := []byte(s)
+	for i, ch := range b {
+		if ch != '\n' && ch != '\r' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// TextBlock is a top-level item of DSL text, with the comments and blank lines
+// that follow it.
+type TextBlock struct {
+	Text   string
+	Offset int
+	Line   int // 1-based, as in lexer positions
+}
+
+// SplitTopLevel splits DSL text into top-level items without parsing it, for
+// callers that parse a file in parts. A block starts at each line that begins
+// with an identifier outside braces, brackets, strings and comments, unless it
+// continues the previous line (after "=", "->", "," or "."). Text before the
+// first item, such as a leading comment, is a block of its own.
+func SplitTopLevel(text string) []TextBlock {
+	var blocks []TextBlock
+	start, startLine := 0, 1
+	depth := 0
+	var last byte // last significant character
+	lineStart := true
+	walkCode(text, 0, func(i int, ch byte) bool {
+		if lineStart && ch != ' ' && ch != '\t' && ch != '\r' && ch != '\n' {
+			lineStart = false
+			if depth == 0 && isIdentStart(ch) && i > start && !continuesLine(last) {
+				begin := lineBegin(text, i)
+				blocks = append(blocks, TextBlock{Text: text[start:begin], Offset: start, Line: startLine})
+				start, startLine = begin, startLine+strings.Count(text[start:begin], "\n")
+			}
+		}
+		switch ch {
+		case '\n':
+			lineStart = true
+		case '{', '[':
+			depth++
+			last = ch
+		case '}', ']':
+			if depth > 0 {
+				depth--
+			}
+			last = ch
+		case ' ', '\t', '\r':
+		default:
+			last = ch
+		}
+		return true
+	})
+	if start < len(text) || len(blocks) == 0 {
+		blocks = append(blocks, TextBlock{Text: text[start:], Offset: start, Line: startLine})
+	}
+	return blocks
+}
+
+func continuesLine(last byte) bool {
+	return last == '=' || last == '>' || last == ',' || last == '.' || last == '-'
+}
+
+// walkCode calls fn for each character of text from start that is outside
+// strings and comments, and once for the opening quote of each string. It
+// stops when fn returns false.
+func walkCode(text string, start int, fn func(i int, ch byte) bool) {
+	for i := start; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case strings.HasPrefix(text[i:], "//"):
+			for i+1 < len(text) && text[i+1] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return
+			}
+			i += end + 3
+			continue
+		}
+		if !fn(i, ch) {
+			return
+		}
+		if ch == '"' || ch == '\'' {
+			i = stringEnd(text, i)
+		}
+	}
+}
+
+// stringEnd returns the offset of the quote closing the string opened at i, or
+// the end of text.
+func stringEnd(text string, i int) int {
+	quote := text[i]
+	for i++; i < len(text) && text[i] != quote; i++ {
+		if text[i] == '\\' {
+			i++
+		}
+	}
+	return i
+}
+
+func isIdentStart(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+}
+
+// lineBegin returns the offset of the start of the line holding offset i.
+func lineBegin(text string, i int) int {
+	return strings.LastIndexByte(text[:i], '\n') + 1
+}
+
+// lineEnd returns the offset of the line break ending the line holding offset
+// i, or the end of text.
+func lineEnd(text string, i int) int {
+	if nl := strings.IndexByte(text[i:], '\n'); nl >= 0 {
+		return i + nl
+	}
+	return len(text)
+}
@@ -0,0 +1,204 @@
+package language
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func partialParse(t *testing.T, text string) (*Program, []int) {
+	t.Helper()
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, diags, _ := p.ParsePartial("test.sruja", text)
+	if prog == nil {
+		t.Fatal("expected a program")
+	}
+	lines := make([]int, 0, len(diags))
+	for _, d := range diags {
+		lines = append(lines, d.Location.Line)
+	}
+	return prog, lines
+}
+
+func elementIDs(prog *Program) []string {
+	var ids []string
+	if prog.Model == nil {
+		return nil
+	}
+	var walk func(prefix string, e *ElementDef)
+	walk = func(prefix string, e *ElementDef) {
+		ids = append(ids, prefix+e.GetID())
+		if body := e.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					walk(prefix+e.GetID()+".", item.Element)
+				}
+			}
+		}
+	}
+	for _, item := range prog.Model.Items {
+		if item.ElementDef != nil {
+			walk("", item.ElementDef)
+		}
+	}
+	return ids
+}
+
+func TestParser_ParsePartial(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		ids   []string
+		lines []int
+	}{
+		{
+			name:  "Valid",
+			input: "Shop = system \"Shop\" {\n  API = container \"API\"\n}\n",
+			ids:   []string{"Shop", "Shop.API"},
+			lines: []int{},
+		},
+		{
+			name:  "Errors In Several Items",
+			input: "A = system \"A\"\nB = = \"B\"\nC = system \"C\"\nD = system \"D\" \"extra\"\nE = person \"E\"\n",
+			ids:   []string{"A", "C", "E"},
+			lines: []int{2, 4},
+		},
+		{
+			name:  "Error Inside A Block",
+			input: "Shop = system \"Shop\" {\n  API = container \"API\"\n  technology\n  DB = database \"DB\"\n}\nUser = person \"User\"\n",
+			ids:   []string{"Shop", "Shop.API", "Shop.DB", "User"},
+			lines: []int{4},
+		},
+		{
+			name:  "Error Before A Closing Brace",
+			input: "Shop = system \"Shop\" {\n  API = container \"API\" {\n    description\n  }\n  DB = database \"DB\"\n}\n",
+			ids:   []string{"Shop", "Shop.API", "Shop.DB"},
+			lines: []int{4},
+		},
+		{
+			name:  "Unclosed Block",
+			input: "User = person \"User\"\nShop = system \"Shop\" {\n  API = container \"API\" {\n",
+			ids:   []string{"User", "Shop", "Shop.API"},
+			lines: []int{4},
+		},
+		{
+			name:  "Unterminated String",
+			input: "Shop = system \"Shop\" {\n  description \"Sells\n}\nUser = person \"User\"\n",
+			// The lexer fails at the last quote, which the missing one left unpaired
+			ids:   []string{"Shop", "User"},
+			lines: []int{4, 3},
+		},
+		{
+			name:  "Incomplete Relation",
+			input: "User = person \"User\"\nShop = system \"Shop\"\nUser ->\n",
+			ids:   []string{"User", "Shop"},
+			lines: []int{4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prog, lines := partialParse(t, tt.input)
+			if ids := elementIDs(prog); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("expected elements %v, got %v", tt.ids, ids)
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("expected errors on lines %v, got %v", tt.lines, lines)
+			}
+		})
+	}
+}
+
+func TestParser_ParsePartialMatchesParse(t *testing.T) {
+	text := "Shop = system \"Shop\" {\n  API = container \"API\"\n}\nUser = person \"User\"\nUser -> Shop.API \"uses\"\n"
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := p.Parse("test.sruja", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, diags, err := p.ParsePartial("test.sruja", text)
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected errors: %v %v", err, diags)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("expected the same program as Parse")
+	}
+
+	// Positions after a skipped statement are those of the text as written
+	broken := strings.Replace(text, "User = person \"User\"", "User = person \"User\" = x", 1)
+	got, _, err = p.ParsePartial("test.sruja", broken)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, item := range got.Items {
+		if item.Relation != nil && (item.Relation.Pos.Line != 5 || item.Relation.Pos.Offset != strings.Index(broken, "User ->")) {
+			t.Errorf("expected the relation at its position in the text, got %+v", item.Relation.Pos)
+		}
+	}
+}
+
+func TestParser_ParsePartialTooManyErrors(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("Shop = system \"Shop\" {\n  API = container \"API\"\n}\n")
+	for i := 0; i < maxRecoveries+10; i++ {
+		sb.WriteString("Broken = = \"B\"\n")
+	}
+	sb.WriteString("Billing = system \"Billing\"\n")
+
+	prog, lines := partialParse(t, sb.String())
+	// Recovery gives up, keeping what parsed before the errors it could not get past
+	if ids := elementIDs(prog); !reflect.DeepEqual(ids, []string{"Shop", "Shop.API"}) {
+		t.Errorf("ids = %v", ids)
+	}
+	if len(lines) < maxRecoveries {
+		t.Errorf("expected at least %d diagnostics, got %d", maxRecoveries, len(lines))
+	}
+}
+
+func TestSplitTopLevel(t *testing.T) {
+	text := `// Shop architecture
+Shop = system "Shop" {
+  description "Sells things
+across lines"
+  API = container "API" {
+    tags ["web",
+      "public"]
+  }
+  /* a } brace in a comment */
+  // and another }
+  DB = database "DB"
+}
+
+User = person "User"
+User ->
+  Shop.API "uses"
+view main {
+  include *
+}
+`
+	blocks := SplitTopLevel(text)
+	var firstLines []string
+	for i, b := range blocks {
+		if !strings.HasPrefix(text[b.Offset:], b.Text) {
+			t.Errorf("block %d text does not match its offset", i)
+		}
+		if want := strings.Count(text[:b.Offset], "\n") + 1; b.Line != want {
+			t.Errorf("block %d: expected line %d, got %d", i, want, b.Line)
+		}
+		firstLines = append(firstLines, strings.SplitN(b.Text, "\n", 2)[0])
+	}
+	// The relation continued on the next line stays in one block
+	want := []string{"// Shop architecture", "Shop = system \"Shop\" {", "User = person \"User\"", "User ->", "view main {"}
+	if !reflect.DeepEqual(firstLines, want) {
+		t.Errorf("expected blocks starting %q, got %q", want, firstLines)
+	}
+	if got := SplitTopLevel(""); len(got) != 1 || got[0].Text != "" {
+		t.Errorf("expected one empty block, got %+v", got)
+	}
+}
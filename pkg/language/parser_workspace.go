@@ -12,7 +12,22 @@ import (
 
 // ParseWorkspace recursively finds and parses all .sruja files in the given directory.
 func (p *Parser) ParseWorkspace(rootPath string) (*Workspace, error) {
+	return p.parseWorkspace(rootPath, nil)
+}
+
+// parseWorkspace parses a workspace, sharing the module checksums of the
+// workspace importing it, if any.
+func (p *Parser) parseWorkspace(rootPath string, parent *Workspace) (*Workspace, error) {
 	ws := NewWorkspace()
+	if parent != nil {
+		ws.addSums(parent.sums)
+		ws.hashes = parent.hashes
+	}
+	if mod := FindModFile(rootPath); mod != "" {
+		if err := ws.LoadModFile(mod); err != nil {
+			return nil, err
+		}
+	}
 
 	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -66,20 +81,16 @@ func (p *Parser) resolveImports(ws *Workspace, prog *Program, filename string) e
 		if item.Import != nil {
 			importPath := item.Import.From
 
-			// Resolve alias or relative path
-			resolvedPath := importPath
-			if alias, ok := ws.Aliases[importPath]; ok {
-				resolvedPath = alias
-			} else if strings.HasPrefix(importPath, ".") {
-				resolvedPath = filepath.Join(filepath.Dir(filename), importPath)
+			// Special case: Standard Library, always embedded (works in WASM)
+			if ws.IsStdLib(importPath) {
+				p.loadFromStdLibFS(ws)
+				continue
 			}
 
-			// Special case: Standard Library (can be embedded or local)
-			if ws.IsStdLib(importPath) {
-				// Try loading from embedded FS first (works in WASM)
-				p.loadFromStdLibFS(ws, importPath)
-				// Continue to next import if loaded successfully
-				// (function is best-effort and always succeeds)
+			// Resolve relative path, required module or module alias
+			resolvedPath, err := ws.resolveImport(filename, importPath)
+			if err != nil {
+				ws.Diags = append(ws.Diags, importDiagnostic(err, filename, item.Import))
 				continue
 			}
 
@@ -91,7 +102,7 @@ func (p *Parser) resolveImports(ws *Workspace, prog *Program, filename string) e
 
 			if info.IsDir() {
 				// Load all files in directory
-				subWS, err := p.ParseWorkspace(resolvedPath)
+				subWS, err := p.parseWorkspace(resolvedPath, ws)
 				if err == nil {
 					for f, p := range subWS.Programs {
 						ws.AddProgram(f, p, nil) // Diagnostics already in subWS
@@ -119,15 +130,13 @@ func (p *Parser) resolveImports(ws *Workspace, prog *Program, filename string) e
 
 // loadFromStdLibFS loads the standard library from the embedded filesystem.
 // This is a best-effort operation that silently continues on errors.
-func (p *Parser) loadFromStdLibFS(ws *Workspace, _ string) {
-	// stdlib files are core.sruja, styles.sruja
-	files := []string{"core.sruja", "styles.sruja"}
-	for _, f := range files {
+func (p *Parser) loadFromStdLibFS(ws *Workspace) {
+	for _, f := range stdlib.Files() {
 		content, err := stdlib.FS.ReadFile(f)
 		if err != nil {
 			continue
 		}
-		path := "sruja.ai/stdlib/" + f
+		path := StdlibModule + "/" + f
 		if _, ok := ws.Programs[path]; !ok {
 			prog, diags, err := p.Parse(path, string(content))
 			if err == nil {
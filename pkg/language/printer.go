@@ -67,6 +67,9 @@ func (p *Printer) PrintSpecification(sb *strings.Builder, spec *Specification) {
 		if item.Tag != nil {
 			p.PrintTagDef(sb, item.Tag)
 		}
+		if item.Relationship != nil {
+			p.PrintRelationshipKindDef(sb, item.Relationship)
+		}
 	}
 }
 
@@ -86,6 +89,44 @@ func (p *Printer) PrintTagDef(sb *strings.Builder, def *TagDef) {
 	sb.WriteString("\n")
 }
 
+func (p *Printer) PrintRelationshipKindDef(sb *strings.Builder, def *RelationshipKindDef) {
+	fmt.Fprintf(sb, "%s = relationship", def.Name)
+	if def.Title != nil {
+		fmt.Fprintf(sb, " %q", *def.Title)
+	}
+	if def.Body == nil || len(def.Body.Items) == 0 {
+		sb.WriteString("\n")
+		return
+	}
+	sb.WriteString(" {\n")
+	p.IndentLevel++
+	indent := p.indent()
+	for _, item := range def.Body.Items {
+		switch {
+		case item.Title != nil:
+			fmt.Fprintf(sb, "%stitle %q\n", indent, *item.Title)
+		case item.Description != nil:
+			fmt.Fprintf(sb, "%sdescription %q\n", indent, *item.Description)
+		case item.Technology != nil:
+			fmt.Fprintf(sb, "%stechnology %q\n", indent, *item.Technology)
+		case item.Async:
+			sb.WriteString(indent + "async\n")
+		case item.Style != nil:
+			sb.WriteString(indent + "style {\n")
+			p.IndentLevel++
+			p.printStyleEntries(sb, item.Style.Entries)
+			p.IndentLevel--
+			sb.WriteString(indent + "}\n")
+		case len(item.From) > 0:
+			fmt.Fprintf(sb, "%sfrom [%s]\n", indent, strings.Join(item.From, ", "))
+		case len(item.To) > 0:
+			fmt.Fprintf(sb, "%sto [%s]\n", indent, strings.Join(item.To, ", "))
+		}
+	}
+	p.IndentLevel--
+	sb.WriteString(p.indent() + "}\n")
+}
+
 func (p *Printer) PrintModelItem(sb *strings.Builder, item ModelItem) {
 	if item.Import != nil {
 		p.PrintImport(sb, item.Import)
@@ -96,6 +137,83 @@ func (p *Printer) PrintModelItem(sb *strings.Builder, item ModelItem) {
 	if item.ElementDef != nil {
 		p.PrintElementDef(sb, item.ElementDef)
 	}
+	if item.Layers != nil {
+		p.PrintLayers(sb, item.Layers)
+	}
+	if item.Rule != nil {
+		p.PrintRule(sb, item.Rule)
+	}
+	if item.DeploymentNode != nil {
+		p.PrintDeploymentNode(sb, item.DeploymentNode)
+	}
+}
+
+func (p *Printer) PrintRule(sb *strings.Builder, rule *RuleDef) {
+	indent := p.indent()
+	fmt.Fprintf(sb, "%srule %s", indent, rule.ID)
+	if rule.Title != nil {
+		fmt.Fprintf(sb, " %q", *rule.Title)
+	}
+	sb.WriteString(" {\n")
+	p.IndentLevel++
+	inner := p.indent()
+	if rule.Severity != nil {
+		fmt.Fprintf(sb, "%sseverity %s\n", inner, *rule.Severity)
+	}
+	if rule.Code != nil {
+		fmt.Fprintf(sb, "%scode %q\n", inner, *rule.Code)
+	}
+	if rule.Message != nil {
+		fmt.Fprintf(sb, "%smessage %q\n", inner, *rule.Message)
+	}
+	for _, f := range rule.Forbid {
+		fmt.Fprintf(sb, "%sforbid %s -> %s\n", inner, f.From.String(), f.To.String())
+	}
+	for _, r := range rule.Require {
+		if r.Has != nil {
+			fmt.Fprintf(sb, "%srequire %s has %s\n", inner, r.Subject.String(), r.Has.String())
+		} else {
+			fmt.Fprintf(sb, "%srequire %s -> %s\n", inner, r.Subject.String(), r.To.String())
+		}
+	}
+	p.IndentLevel--
+	sb.WriteString(indent + "}\n")
+}
+
+func (p *Printer) PrintLayers(sb *strings.Builder, block *LayersBlock) {
+	indent := p.indent()
+	sb.WriteString(indent + "layers {\n")
+	p.IndentLevel++
+	inner := p.indent()
+	for _, layer := range block.Layers {
+		fmt.Fprintf(sb, "%s%s", inner, layer.Name)
+		if layer.Title != nil {
+			fmt.Fprintf(sb, " %q", *layer.Title)
+		}
+		if len(layer.Elements) > 0 {
+			ids := make([]string, len(layer.Elements))
+			for i, el := range layer.Elements {
+				ids[i] = el.String()
+			}
+			fmt.Fprintf(sb, " [%s]", strings.Join(ids, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	for _, a := range block.Allow {
+		fmt.Fprintf(sb, "%sallow %s -> %s\n", inner, a.From, a.To)
+	}
+	if block.AllowSkip != nil {
+		fmt.Fprintf(sb, "%sskip %t\n", inner, *block.AllowSkip)
+	}
+	for _, e := range block.Exceptions {
+		fmt.Fprintf(sb, "%sexcept %s -> %s", inner, e.From.String(), e.To.String())
+		if e.Reason != nil {
+			fmt.Fprintf(sb, " %q", *e.Reason)
+		}
+		sb.WriteString("\n")
+	}
+	p.IndentLevel--
+	sb.WriteString(indent + "}\n")
 }
 
 func (p *Printer) PrintElementDef(sb *strings.Builder, elem *ElementDef) {
@@ -115,6 +233,10 @@ func (p *Printer) PrintElementDef(sb *strings.Builder, elem *ElementDef) {
 		fmt.Fprintf(sb, " %q", *elem.Assignment.Title)
 	}
 
+	for _, tag := range elem.Assignment.TagRefs {
+		fmt.Fprintf(sb, " %s", tag)
+	}
+
 	body := elem.GetBody()
 	if body != nil {
 		sb.WriteString(" {\n")
@@ -137,6 +259,19 @@ func (p *Printer) PrintBodyItem(sb *strings.Builder, item *BodyItem) {
 	if item.Technology != nil {
 		fmt.Fprintf(sb, "%stechnology %q\n", indent, *item.Technology)
 	}
+	if len(item.Tags) > 0 {
+		tags := make([]string, len(item.Tags))
+		for i, tag := range item.Tags {
+			tags[i] = fmt.Sprintf("%q", tag)
+		}
+		fmt.Fprintf(sb, "%stags [%s]\n", indent, strings.Join(tags, ", "))
+	}
+	if len(item.TagRefs) > 0 {
+		fmt.Fprintf(sb, "%s%s\n", indent, strings.Join(item.TagRefs, " "))
+	}
+	if item.Styles != nil {
+		p.PrintStyles(sb, item.Styles)
+	}
 	if item.Element != nil {
 		p.PrintElementDef(sb, item.Element)
 	}
@@ -156,10 +291,16 @@ func (p *Printer) PrintBodyItem(sb *strings.Builder, item *BodyItem) {
 }
 
 func (p *Printer) PrintRelation(sb *strings.Builder, rel *Relation) {
-	fmt.Fprintf(sb, "%s%s %s %s", p.indent(), rel.From, rel.Arrow, rel.To)
+	fmt.Fprintf(sb, "%s%s %s %s", p.indent(), rel.From, relationArrow(rel), rel.To)
+	if rel.Verb != nil {
+		fmt.Fprintf(sb, " %q", *rel.Verb)
+	}
 	if rel.Label != nil {
 		fmt.Fprintf(sb, " %q", *rel.Label)
 	}
+	if len(rel.Tags) > 0 {
+		fmt.Fprintf(sb, " [%s]", strings.Join(rel.Tags, ", "))
+	}
 	sb.WriteString("\n")
 }
 
@@ -196,14 +337,14 @@ func (p *Printer) PrintViewDef(sb *strings.Builder, v *ViewDef) {
 			if item.Include != nil && len(item.Include.Expressions) > 0 {
 				exprs := make([]string, len(item.Include.Expressions))
 				for i, expr := range item.Include.Expressions {
-					exprs[i] = expr.String()
+					exprs[i] = printViewExpr(expr)
 				}
 				fmt.Fprintf(sb, "%sinclude %s\n", p.indent(), strings.Join(exprs, ", "))
 			}
 			if item.Exclude != nil && len(item.Exclude.Expressions) > 0 {
 				exprs := make([]string, len(item.Exclude.Expressions))
 				for i, expr := range item.Exclude.Expressions {
-					exprs[i] = expr.String()
+					exprs[i] = printViewExpr(expr)
 				}
 				fmt.Fprintf(sb, "%sexclude %s\n", p.indent(), strings.Join(exprs, ", "))
 			}
@@ -213,6 +354,14 @@ func (p *Printer) PrintViewDef(sb *strings.Builder, v *ViewDef) {
 	sb.WriteString(indent + "}\n")
 }
 
+// printViewExpr renders a view expression, keeping quoted patterns quoted.
+func printViewExpr(expr ViewExpr) string {
+	if expr.Pattern != nil {
+		return fmt.Sprintf("%q", *expr.Pattern)
+	}
+	return expr.String()
+}
+
 func (p *Printer) PrintRequirement(sb *strings.Builder, req *Requirement) {
 	fmt.Fprintf(sb, "%srequirement %s {\n", p.indent(), req.ID)
 	// Body printing simplified for now
@@ -255,8 +404,73 @@ func (p *Printer) PrintSLO(sb *strings.Builder, _ *SLOBlock) {
 }
 
 func (p *Printer) PrintStyles(sb *strings.Builder, s *StyleDecl) {
-	fmt.Fprintf(sb, "%s%s {\n", p.indent(), s.Keyword)
-	sb.WriteString(p.indent() + "}\n")
+	indent := p.indent()
+	fmt.Fprintf(sb, "%s%s {\n", indent, s.Keyword)
+	if s.Body != nil {
+		p.IndentLevel++
+		p.printStyleEntries(sb, s.Body.Entries)
+		p.IndentLevel--
+	}
+	sb.WriteString(indent + "}\n")
+}
+
+// printStyleEntries prints style properties and nested selector blocks.
+func (p *Printer) printStyleEntries(sb *strings.Builder, entries []*StyleEntry) {
+	indent := p.indent()
+	for _, entry := range entries {
+		sb.WriteString(indent + entry.Key)
+		if entry.Value != nil {
+			fmt.Fprintf(sb, " %q", *entry.Value)
+		}
+		if entry.Body != nil {
+			sb.WriteString(" {\n")
+			p.IndentLevel++
+			p.printStyleEntries(sb, entry.Body.Entries)
+			p.IndentLevel--
+			sb.WriteString(indent + "}")
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// PrintDeploymentNode prints a deployment node with its nested nodes,
+// infrastructure and container instances.
+func (p *Printer) PrintDeploymentNode(sb *strings.Builder, node *DeploymentNode) {
+	indent := p.indent()
+	fmt.Fprintf(sb, "%s%s %s %q", indent, node.Type, node.ID, node.Label)
+	if node.Description != nil {
+		fmt.Fprintf(sb, " %q", *node.Description)
+	}
+	if len(node.Items) == 0 {
+		sb.WriteString("\n")
+		return
+	}
+	sb.WriteString(" {\n")
+	p.IndentLevel++
+	inner := p.indent()
+	for _, item := range node.Items {
+		switch {
+		case item.Node != nil:
+			p.PrintDeploymentNode(sb, item.Node)
+		case item.Infrastructure != nil:
+			fmt.Fprintf(sb, "%sinfrastructure %s %q", inner, item.Infrastructure.ID, item.Infrastructure.Label)
+			if item.Infrastructure.Description != nil {
+				fmt.Fprintf(sb, " %q", *item.Infrastructure.Description)
+			}
+			sb.WriteString("\n")
+		case item.ContainerInstance != nil:
+			fmt.Fprintf(sb, "%scontainerInstance %s", inner, item.ContainerInstance.ContainerID)
+			if item.ContainerInstance.Label != "" {
+				fmt.Fprintf(sb, " %q", item.ContainerInstance.Label)
+			}
+			if item.ContainerInstance.InstanceID != nil {
+				fmt.Fprintf(sb, " instanceId %s", *item.ContainerInstance.InstanceID)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	p.IndentLevel--
+	sb.WriteString(indent + "}\n")
 }
 
 func (p *Printer) PrintMetadataBlock(sb *strings.Builder, _ *MetadataBlock) {
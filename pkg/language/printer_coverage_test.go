@@ -103,3 +103,52 @@ func TestPrinter_Coverage(t *testing.T) {
 func sPtr(s string) *string {
 	return &s
 }
+
+func TestPrinter_DeploymentStylesAndTags(t *testing.T) {
+	source := `S = system "Sys" {
+	tags ["external"]
+	C = container "Cont"
+}
+S.C -> S "calls" "HTTP" [async]
+deployment Prod "Production" {
+	node AWS "AWS" "Cloud" {
+		infrastructure LB "Load Balancer"
+		containerInstance C instanceId 2
+	}
+}
+style {
+	person {
+		shape "person"
+	}
+}
+`
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program, _, err := p.Parse("test.sruja", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := NewPrinter().Print(program)
+	checks := []string{
+		`tags ["external"]`,
+		`S.C -> S "calls" "HTTP" [async]`,
+		`deployment Prod "Production" {`,
+		`node AWS "AWS" "Cloud" {`,
+		`infrastructure LB "Load Balancer"`,
+		`containerInstance C instanceId 2`,
+		`shape "person"`,
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q\nFull output:\n%s", check, output)
+		}
+	}
+
+	// The printed program parses again
+	if _, _, err := p.Parse("printed.sruja", output); err != nil {
+		t.Errorf("Failed to parse printed output: %v\n%s", err, output)
+	}
+}
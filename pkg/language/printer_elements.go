@@ -12,7 +12,7 @@ func (p *Printer) printRelation(sb *strings.Builder, rel *Relation) {
 	indent := p.indent()
 	sb.WriteString(indent)
 	sb.WriteString(strings.Join(rel.From.Parts, "."))
-	sb.WriteString(" -> ")
+	sb.WriteString(" " + relationArrow(rel) + " ")
 	sb.WriteString(strings.Join(rel.To.Parts, "."))
 	if len(rel.Tags) > 0 {
 		sb.WriteString(" [")
@@ -38,3 +38,11 @@ func (p *Printer) printRelation(sb *strings.Builder, rel *Relation) {
 	}
 	sb.WriteString("\n")
 }
+
+// relationArrow returns the arrow to print for a relation.
+func relationArrow(rel *Relation) string {
+	if rel.Bidirectional {
+		return "<->"
+	}
+	return "->"
+}
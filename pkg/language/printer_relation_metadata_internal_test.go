@@ -11,13 +11,15 @@ func TestPrinter_printRelation_VerbFormsAndTags(t *testing.T) {
 	label := "HTTP"
 	rel1 := &Relation{From: QualifiedIdent{Parts: []string{"A"}}, To: QualifiedIdent{Parts: []string{"B"}}, Verb: &verbQuoted, Label: &label, Tags: []string{"sync", "critical"}}
 	rel2 := &Relation{From: QualifiedIdent{Parts: []string{"B"}}, To: QualifiedIdent{Parts: []string{"C"}}, Verb: &verbIdent}
+	rel3 := &Relation{From: QualifiedIdent{Parts: []string{"C"}}, To: QualifiedIdent{Parts: []string{"D"}}, Bidirectional: true}
 
 	var sb strings.Builder
 	p := NewPrinter()
 	p.printRelation(&sb, rel1)
 	p.printRelation(&sb, rel2)
+	p.printRelation(&sb, rel3)
 	out := sb.String()
-	checks := []string{"A -> B [sync, critical] calls \"HTTP\"", "B -> C uses"}
+	checks := []string{"A -> B [sync, critical] calls \"HTTP\"", "B -> C uses", "C <-> D"}
 	for _, c := range checks {
 		if !strings.Contains(out, c) {
 			t.Fatalf("missing %q in output:\n%s", c, out)
@@ -0,0 +1,106 @@
+package language
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// suppressionDirective starts a comment suppressing diagnostics.
+const suppressionDirective = "sruja:ignore"
+
+// Suppression is an inline comment silencing diagnostics reported on the
+// element or relation that follows it:
+//
+//	// sruja:ignore E205 reason="legacy"
+//	Cache = container "Cache"
+//
+// Several codes are separated by spaces or commas. A suppression without codes
+// silences every diagnostic.
+type Suppression struct {
+	Pos    lexer.Position
+	Codes  []string
+	Reason string
+}
+
+// Matches reports whether the suppression silences a diagnostic code.
+func (s *Suppression) Matches(code string) bool {
+	if len(s.Codes) == 0 {
+		return true
+	}
+	for _, c := range s.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns where the suppression comment is.
+func (s *Suppression) Location() SourceLocation {
+	return SourceLocation{File: s.Pos.Filename, Line: s.Pos.Line, Column: s.Pos.Column, Offset: s.Pos.Offset}
+}
+
+// ScanSuppressions returns the suppression comments of DSL text, in source
+// order. Text that does not lex is scanned up to the first error.
+func (p *Parser) ScanSuppressions(filename, text string) []*Suppression {
+	def := p.parser.Lexer()
+	comment, ok := def.Symbols()["Comment"]
+	if !ok {
+		return nil
+	}
+	lex, err := def.Lex(filename, strings.NewReader(text))
+	if err != nil {
+		return nil
+	}
+	var sups []*Suppression
+	for {
+		tok, err := lex.Next()
+		if err != nil || tok.EOF() {
+			return sups
+		}
+		if tok.Type != comment {
+			continue
+		}
+		if s := parseSuppression(tok.Value); s != nil {
+			s.Pos = tok.Pos
+			sups = append(sups, s)
+		}
+	}
+}
+
+// parseSuppression parses the text of a comment, returning nil when it is not
+// a suppression.
+func parseSuppression(comment string) *Suppression {
+	body := comment
+	switch {
+	case strings.HasPrefix(body, "//"):
+		body = body[2:]
+	case strings.HasPrefix(body, "/*"):
+		body = strings.TrimSuffix(body[2:], "*/")
+	}
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, suppressionDirective) {
+		return nil
+	}
+	rest := strings.TrimPrefix(body, suppressionDirective)
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil // e.g. sruja:ignored
+	}
+
+	s := &Suppression{}
+	if i := strings.Index(rest, "reason="); i >= 0 {
+		reason := strings.TrimSpace(rest[i+len("reason="):])
+		if len(reason) >= 2 && (reason[0] == '"' || reason[0] == '\'') {
+			if end := strings.IndexByte(reason[1:], reason[0]); end >= 0 {
+				reason = reason[1 : end+1]
+			}
+		}
+		s.Reason = reason
+		rest = rest[:i]
+	}
+	for _, code := range strings.FieldsFunc(rest, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' }) {
+		s.Codes = append(s.Codes, code)
+	}
+	return s
+}
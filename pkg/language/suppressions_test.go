@@ -0,0 +1,51 @@
+// pkg/language/suppressions_test.go
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanSuppressions(t *testing.T) {
+	dsl := `system = kind "System"
+// sruja:ignore E205 reason="legacy"
+A = system "A // sruja:ignore E201"
+// sruja:ignore E205, E206 reason='kept for billing'
+B = system "B"
+/* sruja:ignore */
+C = system "C"
+// sruja:ignored E205
+// a plain comment
+`
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	program, _, err := parser.Parse("arch.sruja", dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	sups := program.Suppressions
+	if len(sups) != 3 {
+		t.Fatalf("expected 3 suppressions, got %d: %+v", len(sups), sups)
+	}
+	tests := []struct {
+		line   int
+		codes  []string
+		reason string
+	}{
+		{2, []string{"E205"}, "legacy"},
+		{4, []string{"E205", "E206"}, "kept for billing"},
+		{6, nil, ""},
+	}
+	for i, tt := range tests {
+		s := sups[i]
+		if s.Pos.Line != tt.line || s.Pos.Filename != "arch.sruja" || !reflect.DeepEqual(s.Codes, tt.codes) || s.Reason != tt.reason {
+			t.Errorf("suppression %d = %+v at %v, want line %d codes %v reason %q", i, s, s.Pos, tt.line, tt.codes, tt.reason)
+		}
+	}
+	if !sups[1].Matches("E206") || sups[1].Matches("E201") || !sups[2].Matches("E201") {
+		t.Error("unexpected Matches results")
+	}
+}
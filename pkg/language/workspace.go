@@ -1,9 +1,13 @@
 package language
 
 import (
+	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 
+	"github.com/alecthomas/participle/v2/lexer"
+
 	"github.com/sruja-ai/sruja/pkg/diagnostics"
 )
 
@@ -12,15 +16,20 @@ import (
 type Workspace struct {
 	Programs map[string]*Program      // Filename -> Program
 	Diags    []diagnostics.Diagnostic // Combined diagnostics
-	Aliases  map[string]string        // Alias -> Absolute Path (e.g., sruja.ai/stdlib -> ...)
+	Aliases  map[string]string        // Alias -> Absolute Path, or the module itself when embedded (sruja.ai/stdlib)
+
+	modules map[string][]*moduleRoot // Directory -> sruja.mod files above it, nearest first
+	hashes  map[string]string        // Module directory -> checksum
+	sums    map[string]string        // Checksums from every sruja.sum loaded, for the requirements of cached modules
 }
 
 // NewWorkspace creates a new empty workspace with default aliases.
+// The standard library is embedded, so its alias names no directory.
 func NewWorkspace() *Workspace {
 	return &Workspace{
 		Programs: make(map[string]*Program),
 		Aliases: map[string]string{
-			"sruja.ai/stdlib": "/Users/dilipkola/Workspace/sruja/pkg/stdlib",
+			StdlibModule: StdlibModule,
 		},
 	}
 }
@@ -53,6 +62,7 @@ func (w *Workspace) MergedProgram() *Program {
 		if prog.Views != nil {
 			merged.Views.Items = append(merged.Views.Items, prog.Views.Items...)
 		}
+		merged.Suppressions = append(merged.Suppressions, prog.Suppressions...)
 	}
 
 	// If no items were added, return nil matching single-file behavior
@@ -80,9 +90,7 @@ func (w *Workspace) GetAliasPath(alias string) string {
 
 // IsStdLib returns true if the path/alias refers to the standard library.
 func (w *Workspace) IsStdLib(path string) bool {
-	return path == "sruja.ai/stdlib" ||
-		strings.HasPrefix(path, "sruja.ai/stdlib/") ||
-		strings.Contains(path, "pkg/stdlib")
+	return path == StdlibModule || strings.HasPrefix(path, StdlibModule+"/")
 }
 
 // ResolveRelativePath resolves an import path relative to the current file.
@@ -114,6 +122,7 @@ func (w *Workspace) mergeImportsForProgram(prog *Program, filename string, visit
 	}
 	visited[filename] = true
 
+	imported := make(map[string]SpecificationItem)
 	for _, item := range prog.Model.Items {
 		if item.Import == nil {
 			continue
@@ -145,35 +154,89 @@ func (w *Workspace) mergeImportsForProgram(prog *Program, filename string, visit
 		}
 
 		for _, specItem := range importedSpecs {
-			if isWildcard {
-				// Import all
-				prog.Specification.Items = append(prog.Specification.Items, specItem)
-			} else {
-				// Import only requested names
-				name := w.getSpecificationItemName(specItem)
-				if requested[name] {
-					prog.Specification.Items = append(prog.Specification.Items, specItem)
+			// Import all, or only requested names
+			if !isWildcard && !requested[w.getSpecificationItemName(specItem)] {
+				continue
+			}
+			// The same kind or tag may come through several imports, but
+			// definitions from different files must agree
+			key := specificationItemKey(specItem)
+			if prev, ok := imported[key]; ok {
+				if !sameSpecificationItem(prev, specItem) {
+					w.Diags = append(w.Diags, importConflict(filename, item.Import, prev, specItem))
 				}
+				continue
 			}
+			imported[key] = specItem
+			prog.Specification.Items = append(prog.Specification.Items, specItem)
 		}
 	}
 }
 
-// resolveImportPath resolves an import path to an absolute path or alias.
-func (w *Workspace) resolveImportPath(currentFile, importPath string) string {
-	// Check if it's an alias
-	if alias, ok := w.Aliases[importPath]; ok {
-		return alias
+// specificationItemKey identifies a kind, tag or relationship kind by its sort and name.
+func specificationItemKey(item SpecificationItem) string {
+	if item.Tag != nil {
+		return "tag " + item.Tag.Name
 	}
-	// Check for stdlib
-	if w.IsStdLib(importPath) {
-		return importPath
+	if item.Element != nil {
+		return "kind " + item.Element.Name
 	}
-	// Relative path
-	if strings.HasPrefix(importPath, ".") {
-		return filepath.Join(filepath.Dir(currentFile), importPath)
+	if item.Relationship != nil {
+		return "relationship " + item.Relationship.Name
 	}
-	return importPath
+	return ""
+}
+
+// sameSpecificationItem reports whether two specification items are defined alike,
+// wherever they are defined.
+func sameSpecificationItem(a, b SpecificationItem) bool {
+	switch {
+	case a.Element != nil && b.Element != nil:
+		x, y := *a.Element, *b.Element
+		x.Pos, y.Pos = lexer.Position{}, lexer.Position{}
+		return reflect.DeepEqual(x, y)
+	case a.Tag != nil && b.Tag != nil:
+		x, y := *a.Tag, *b.Tag
+		x.Pos, y.Pos = lexer.Position{}, lexer.Position{}
+		return reflect.DeepEqual(x, y)
+	case a.Relationship != nil && b.Relationship != nil:
+		x, y := *a.Relationship, *b.Relationship
+		x.Pos, y.Pos = lexer.Position{}, lexer.Position{}
+		return reflect.DeepEqual(x, y)
+	}
+	return false
+}
+
+// importConflict reports an import bringing in a kind or tag that an earlier
+// import defines differently.
+func importConflict(filename string, imp *ImportStatement, prev, item SpecificationItem) diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Code:     diagnostics.CodeImportConflict,
+		Severity: diagnostics.SeverityError,
+		Message: fmt.Sprintf("%s imported from '%s' is defined at %s and differently at %s",
+			specificationItemKey(item), imp.From, specificationItemPos(prev), specificationItemPos(item)),
+		Location: diagnostics.SourceLocation{File: filename, Line: imp.Pos.Line, Column: imp.Pos.Column},
+	}
+}
+
+func specificationItemPos(item SpecificationItem) lexer.Position {
+	if item.Tag != nil {
+		return item.Tag.Pos
+	}
+	if item.Relationship != nil {
+		return item.Relationship.Pos
+	}
+	return item.Element.Pos
+}
+
+// resolveImportPath resolves an import path like resolveImport, falling back
+// to the import path itself when a required module cannot be loaded.
+func (w *Workspace) resolveImportPath(currentFile, importPath string) string {
+	resolved, err := w.resolveImport(currentFile, importPath)
+	if err != nil {
+		return importPath
+	}
+	return resolved
 }
 
 // getSpecificationItemsFromPath returns specification items from all programs matching the path.
@@ -211,5 +274,8 @@ func (w *Workspace) getSpecificationItemName(item SpecificationItem) string {
 	if item.Tag != nil {
 		return item.Tag.Name
 	}
+	if item.Relationship != nil {
+		return item.Relationship.Name
+	}
 	return ""
 }
@@ -118,8 +118,12 @@ func TestWorkspace_IsStdLib(t *testing.T) {
 	if !ws.IsStdLib("sruja.ai/stdlib") {
 		t.Error("Expected true for stdlib alias")
 	}
-	if !ws.IsStdLib("/path/to/pkg/stdlib/foo.sruja") {
-		t.Error("Expected true for stdlib path")
+	if !ws.IsStdLib("sruja.ai/stdlib/core.sruja") {
+		t.Error("Expected true for stdlib file")
+	}
+	// Only the module path names the stdlib, not checkouts of its sources
+	if ws.IsStdLib("/path/to/pkg/stdlib/foo.sruja") {
+		t.Error("Expected false for a local path")
 	}
 	if ws.IsStdLib("other/path") {
 		t.Error("Expected false for other path")
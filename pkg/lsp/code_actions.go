@@ -3,6 +3,9 @@ package lsp
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/sourcegraph/go-lsp"
@@ -10,167 +13,511 @@ import (
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
-// CodeAction provides quick fixes and refactoring actions for diagnostics
-// Note: Using Command-based approach as CodeAction type may not be available in this go-lsp version
-func (s *Server) CodeAction(_ context.Context, params lsp.CodeActionParams) ([]lsp.Command, error) {
-	var actions []lsp.Command
+// CodeAction is a quick fix or refactoring with the edit that applies it,
+// which go-lsp predates.
+type CodeAction struct {
+	Title       string             `json:"title"`
+	Kind        lsp.CodeActionKind `json:"kind,omitempty"`
+	Diagnostics []lsp.Diagnostic   `json:"diagnostics,omitempty"`
+	IsPreferred bool               `json:"isPreferred,omitempty"`
+	Edit        *WorkspaceEdit     `json:"edit,omitempty"`
+}
+
+// WorkspaceEdit is go-lsp's workspace edit with document changes, which can
+// create files as well as edit them.
+type WorkspaceEdit struct {
+	Changes         map[string][]lsp.TextEdit `json:"changes,omitempty"`
+	DocumentChanges []interface{}             `json:"documentChanges,omitempty"` // CreateFile or TextDocumentEdit
+}
+
+// CreateFile is a document change that creates a file.
+type CreateFile struct {
+	Kind string          `json:"kind"` // always "create"
+	URI  lsp.DocumentURI `json:"uri"`
+}
+
+// TextDocumentEdit is a document change that edits a document.
+type TextDocumentEdit struct {
+	TextDocument VersionedDocument `json:"textDocument"`
+	Edits        []lsp.TextEdit    `json:"edits"`
+}
+
+// VersionedDocument identifies a document at a version. A nil version matches
+// any, as for a file created by the same edit.
+type VersionedDocument struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int            `json:"version"`
+}
+
+// childKinds is the kind of element a stub nested in an element of each kind
+// gets.
+var childKinds = map[string]string{
+	"system":    "container",
+	"container": "component",
+}
+
+// CodeAction returns quick fixes for the diagnostics of the request and the
+// refactorings of the element defined on the line its range starts at. Each
+// action carries the edit that applies it.
+func (s *Server) CodeAction(_ context.Context, params lsp.CodeActionParams) ([]CodeAction, error) {
+	var actions []CodeAction
 
 	doc := s.workspace.GetDocument(params.TextDocument.URI)
 	if doc == nil {
 		return actions, nil
 	}
-
-	// Get diagnostics for this document
-	program := doc.EnsureParsed()
-	if program == nil {
+	prog, _ := doc.parse()
+	if prog == nil {
 		return actions, nil
 	}
+	c := newActionContext(doc, prog)
 
 	// Generate code actions based on diagnostics
 	for _, diag := range params.Context.Diagnostics {
-		action := s.generateCodeAction(doc, diag, params.TextDocument.URI)
-		if action != nil {
-			actions = append(actions, *action)
-		}
+		actions = append(actions, s.generateCodeActions(c, diag)...)
 	}
+	actions = append(actions, s.generateRefactorings(c, params.Range.Start)...)
 
 	return actions, nil
 }
 
-// generateCodeAction creates a code action for a specific diagnostic
-func (s *Server) generateCodeAction(doc *Document, diag lsp.Diagnostic, uri lsp.DocumentURI) *lsp.Command {
+// generateCodeActions creates the quick fixes for a specific diagnostic
+func (s *Server) generateCodeActions(c *actionContext, diag lsp.Diagnostic) []CodeAction {
 	switch diag.Code {
 	case diagnostics.CodeReferenceNotFound:
-		return s.generateQuickFixForUndefinedRef(doc, diag, uri)
+		return s.generateQuickFixForUndefinedRef(c, diag)
 	case diagnostics.CodeDuplicateIdentifier:
-		return s.generateQuickFixForDuplicateID(doc, diag, uri)
+		return s.generateQuickFixForDuplicateID(c, diag)
 	case diagnostics.CodeOrphanElement:
-		return s.generateQuickFixForOrphan(doc, diag, uri)
+		return s.generateQuickFixForOrphan(c, diag)
+	case diagnostics.CodeBestPractice:
+		return s.generateQuickFixForDocumentation(c, diag)
 	case diagnostics.CodeUnexpectedToken:
-		return s.generateQuickFixForSyntax(doc, diag, uri)
+		return s.generateQuickFixForSyntax(c, diag)
 	default:
 		return nil
 	}
 }
 
-// generateQuickFixForUndefinedRef suggests fixes for undefined references
-func (s *Server) generateQuickFixForUndefinedRef(doc *Document, diag lsp.Diagnostic, uri lsp.DocumentURI) *lsp.Command {
-	// Extract the undefined element name from the message
-	msg := diag.Message
-	startIdx := strings.Index(msg, "'")
-	if startIdx == -1 {
+// generateQuickFixForUndefinedRef replaces an undefined reference with the most
+// similar element, or creates a stub for it: in the element it is qualified by
+// or the scope it is used in, or at the end of the document.
+func (s *Server) generateQuickFixForUndefinedRef(c *actionContext, diag lsp.Diagnostic) []CodeAction {
+	names := quotedNames(diag.Message)
+	if len(names) == 0 {
 		return nil
 	}
-	endIdx := strings.Index(msg[startIdx+1:], "'")
-	if endIdx == -1 {
-		return nil
+	name, scope := names[0], ""
+	if strings.Contains(diag.Message, " in scope '") && len(names) > 1 {
+		scope = names[1]
 	}
-	elementName := msg[startIdx+1 : startIdx+1+endIdx]
 
-	// Find similar elements in the document
-	program := doc.EnsureParsed()
-	if program == nil || program.Model == nil {
-		return nil
+	var actions []CodeAction
+	if tok, ok := c.refToken(diag.Range.Start.Line, name); ok {
+		if similar := s.findSimilarElementNames(lastSegment(name), c.prog.Model); len(similar) > 0 {
+			last := len(tok.parts) - 1
+			actions = append(actions, c.quickFix("Replace with '"+similar[0]+"'", diag,
+				c.replace(tok.offsets[last], len(tok.parts[last]), similar[0])))
+		}
 	}
 
-	similar := s.findSimilarElementNames(elementName, program.Model)
-	if len(similar) == 0 {
-		return nil
+	var owner *elementInfo
+	switch parent := parentFQN(name); {
+	case parent != "" && scope != "":
+		if owner = c.element(scope + "." + parent); owner == nil {
+			owner = c.element(parent)
+		}
+	case parent != "":
+		owner = c.element(parent)
+	case scope != "":
+		owner = c.element(scope)
 	}
+	id := lastSegment(name)
+	if owner == nil {
+		if parentFQN(name) != "" || scope != "" || !isValidIdentifier(id) {
+			return actions // the stub's parent is elsewhere
+		}
+		return append(actions, c.quickFix("Create element '"+id+"'", diag,
+			c.appendLine(fmt.Sprintf("%s = system %q", id, id))))
+	}
+	kind, ok := childKinds[owner.def.GetKind()]
+	if !ok {
+		kind = "component"
+	}
+	return append(actions, c.quickFix("Create element '"+owner.fqn+"."+id+"'", diag,
+		c.addToBody(owner, fmt.Sprintf("%s = %s %q", id, kind, id))))
+}
 
-	// Create quick fix to replace with the most similar element
-	bestMatch := similar[0]
-
-	// Use Command approach for code actions
-	return &lsp.Command{
-		Title:     "Replace with '" + bestMatch + "'",
-		Command:   "sruja.replaceElement",
-		Arguments: []interface{}{string(uri), diag.Range.Start.Line, diag.Range.Start.Character, diag.Range.End.Line, diag.Range.End.Character, bestMatch},
+// generateQuickFixForDuplicateID renames a duplicate element to a free ID
+func (s *Server) generateQuickFixForDuplicateID(c *actionContext, diag lsp.Diagnostic) []CodeAction {
+	names := quotedNames(diag.Message)
+	if len(names) == 0 {
+		return nil
+	}
+	e := c.elementAt(diag.Range.Start.Line, names[0])
+	if e == nil {
+		return nil
 	}
+	id := e.def.GetID()
+	newName := c.freeID(id)
+	action := c.quickFix("Rename to '"+newName+"'", diag, c.replace(e.def.Pos.Offset, len(id), newName))
+	action.IsPreferred = true
+	return []CodeAction{action}
 }
 
-// generateQuickFixForDuplicateID suggests renaming for duplicate IDs
-func (s *Server) generateQuickFixForDuplicateID(_ *Document, diag lsp.Diagnostic, uri lsp.DocumentURI) *lsp.Command {
-	// Extract the duplicate ID from the message
-	msg := diag.Message
-	startIdx := strings.Index(msg, "'")
-	if startIdx == -1 {
+// generateQuickFixForOrphan relates an orphan element to a sibling, preferring
+// a person, with a relation added after the element
+func (s *Server) generateQuickFixForOrphan(c *actionContext, diag lsp.Diagnostic) []CodeAction {
+	names := quotedNames(diag.Message)
+	if len(names) == 0 {
 		return nil
 	}
-	endIdx := strings.Index(msg[startIdx+1:], "'")
-	if endIdx == -1 {
+	var e *elementInfo
+	if len(names) > 1 {
+		e = c.element(names[1]) // the full ID
+	}
+	if e == nil {
+		e = c.elementAt(diag.Range.Start.Line, names[0])
+	}
+	if e == nil {
 		return nil
 	}
-	elementID := msg[startIdx+1 : startIdx+1+endIdx]
 
-	// Suggest renaming with a suffix
-	newName := elementID + "2"
+	var from *elementInfo
+	for _, o := range c.elems {
+		if o == e || o.parent != e.parent || governanceKinds[o.def.GetKind()] {
+			continue
+		}
+		if from == nil || (o.def.GetKind() == "person" && from.def.GetKind() != "person") {
+			from = o
+		}
+	}
+	if from == nil {
+		return nil
+	}
+	rel := from.def.GetID() + " -> " + e.def.GetID()
+	indent := lineIndent(c.doc.Text, e.def.Pos.Offset)
+	return []CodeAction{c.quickFix("Add relation '"+rel+"'", diag,
+		c.insert(elementEnd(c.doc.Text, e.def), "\n"+indent+rel+` "uses"`))}
+}
 
-	return &lsp.Command{
-		Title:     "Rename to '" + newName + "'",
-		Command:   "sruja.renameElement",
-		Arguments: []interface{}{string(uri), diag.Range.Start.Line, diag.Range.Start.Character, diag.Range.End.Line, diag.Range.End.Character, newName},
+// generateQuickFixForDocumentation adds the description or technology a
+// documentation warning asks for, as a placeholder to fill in
+func (s *Server) generateQuickFixForDocumentation(c *actionContext, diag lsp.Diagnostic) []CodeAction {
+	var property string
+	switch {
+	case strings.Contains(diag.Message, "lacks a description"):
+		property = "description"
+	case strings.Contains(diag.Message, "lacks technology"):
+		property = "technology"
+	default:
+		return nil
+	}
+	names := quotedNames(diag.Message)
+	if len(names) == 0 {
+		return nil
+	}
+	e := c.elementAt(diag.Range.Start.Line, names[0])
+	if e == nil {
+		return nil
 	}
+	return []CodeAction{c.quickFix("Add "+property+" to '"+e.def.GetID()+"'", diag,
+		c.addToBody(e, property+` "TODO"`))}
 }
 
-// generateQuickFixForOrphan suggests adding a relation for orphan elements
-func (s *Server) generateQuickFixForOrphan(_ *Document, diag lsp.Diagnostic, uri lsp.DocumentURI) *lsp.Command {
-	// Extract the orphan element name
-	msg := diag.Message
-	startIdx := strings.Index(msg, "'")
-	if startIdx == -1 {
+// generateQuickFixForSyntax removes an unexpected token
+func (s *Server) generateQuickFixForSyntax(c *actionContext, diag lsp.Diagnostic) []CodeAction {
+	if !strings.Contains(diag.Message, "unexpected token") {
+		return nil
+	}
+	names := quotedNames(diag.Message)
+	if len(names) == 0 {
 		return nil
 	}
-	endIdx := strings.Index(msg[startIdx+1:], "'")
-	if endIdx == -1 {
+	edit := lsp.TextEdit{Range: diag.Range}
+	return []CodeAction{c.quickFix("Remove unexpected token '"+names[0]+"'", diag, edit)}
+}
+
+// generateRefactorings offers to nest a stub child in an element without a
+// body, and to move a top-level element of a workspace root to a file of its
+// own.
+func (s *Server) generateRefactorings(c *actionContext, pos lsp.Position) []CodeAction {
+	e := c.elementAt(pos.Line, "")
+	if e == nil || governanceKinds[e.def.GetKind()] {
 		return nil
 	}
-	elementName := msg[startIdx+1 : startIdx+1+endIdx]
-
-	// Get the line where the orphan is defined
-	line := diag.Range.Start.Line
-
-	// Find a good place to add a relation (after the element definition)
-	// Simple heuristic: add after the current line
-	insertLine := line + 1
-
-	var relationSb strings.Builder
-	relationSb.Grow(len(elementName) + 60)
-	relationSb.WriteString("  // TODO: Add relation using this element\n")
-	relationSb.WriteString("  // Example: SomeElement -> ")
-	relationSb.WriteString(elementName)
-
-	return &lsp.Command{
-		Title:     "Add relation using '" + elementName + "'",
-		Command:   "sruja.addRelation",
-		Arguments: []interface{}{string(uri), insertLine, 0, relationSb.String()},
-	}
-}
-
-// generateQuickFixForSyntax suggests fixes for syntax errors
-func (s *Server) generateQuickFixForSyntax(_ *Document, diag lsp.Diagnostic, uri lsp.DocumentURI) *lsp.Command {
-	msg := diag.Message
-	// Check for common syntax errors and suggest fixes
-	if strings.Contains(msg, "unexpected token") {
-		// Extract the unexpected token
-		startIdx := strings.Index(msg, "'")
-		if startIdx != -1 {
-			endIdx := strings.Index(msg[startIdx+1:], "'")
-			if endIdx != -1 {
-				token := msg[startIdx+1 : startIdx+1+endIdx]
-				// For now, just provide a generic fix suggestion
-				// More sophisticated fixes can be added based on token type
-				return &lsp.Command{
-					Title:     "Remove unexpected token '" + token + "'",
-					Command:   "sruja.removeToken",
-					Arguments: []interface{}{string(uri), diag.Range.Start.Line, diag.Range.Start.Character, diag.Range.End.Line, diag.Range.End.Character},
+	id := e.def.GetID()
+
+	var actions []CodeAction
+	if kind, ok := childKinds[e.def.GetKind()]; ok && e.def.GetBody() == nil {
+		child := c.freeID(id + strings.ToUpper(kind[:1]) + kind[1:])
+		actions = append(actions, CodeAction{
+			Title: "Convert '" + id + "' to a nested element",
+			Kind:  lsp.CAKRefactorRewrite,
+			Edit:  c.edit(c.addToBody(e, fmt.Sprintf("%s = %s %q", child, kind, child))),
+		})
+	}
+
+	path := uriToPath(c.doc.URI)
+	if e.parent == nil && path != "" && s.workspace.RootOf(path) != "" {
+		text := c.doc.Text
+		start, end := lineStart(text, e.def.Pos.Offset), elementEnd(text, e.def)
+		if end < len(text) && text[end] == '\n' {
+			end++
+		}
+		block := text[start:end]
+		if !strings.HasSuffix(block, "\n") {
+			block += "\n"
+		}
+		target := s.freeFile(filepath.Dir(path), strings.ToLower(id))
+		uri := pathToURI(target)
+		version := c.doc.Version
+		actions = append(actions, CodeAction{
+			Title: "Extract '" + id + "' to " + filepath.Base(target),
+			Kind:  lsp.CAKRefactorExtract,
+			Edit: &WorkspaceEdit{DocumentChanges: []interface{}{
+				CreateFile{Kind: "create", URI: uri},
+				TextDocumentEdit{TextDocument: VersionedDocument{URI: uri}, Edits: []lsp.TextEdit{{NewText: block}}},
+				TextDocumentEdit{TextDocument: VersionedDocument{URI: c.doc.URI, Version: &version}, Edits: []lsp.TextEdit{c.replace(start, end-start, "")}},
+			}},
+		})
+	}
+	return actions
+}
+
+// freeFile returns a path in dir for a new .sruja file named after base that
+// is neither on disk nor open.
+func (s *Server) freeFile(dir, base string) string {
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		path := filepath.Join(dir, name+srujaExt)
+		if _, err := os.Stat(path); os.IsNotExist(err) && s.workspace.GetDocument(pathToURI(path)) == nil {
+			return path
+		}
+	}
+}
+
+// actionContext is a document code actions are computed for, with its
+// elements as parsed.
+type actionContext struct {
+	doc   *Document
+	prog  *language.Program
+	elems []*elementInfo // in document order
+}
+
+// elementInfo is an element of a document and the element it is nested in.
+type elementInfo struct {
+	def    *language.ElementDef
+	fqn    string
+	parent *elementInfo
+}
+
+func newActionContext(doc *Document, prog *language.Program) *actionContext {
+	c := &actionContext{doc: doc, prog: prog}
+	var collect func(e *language.ElementDef, parent *elementInfo)
+	collect = func(e *language.ElementDef, parent *elementInfo) {
+		id := e.GetID()
+		if id == "" {
+			return
+		}
+		info := &elementInfo{def: e, fqn: id, parent: parent}
+		if parent != nil {
+			info.fqn = parent.fqn + "." + id
+		}
+		c.elems = append(c.elems, info)
+		if body := e.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					collect(item.Element, info)
 				}
 			}
 		}
 	}
+	for _, item := range prog.Items {
+		if item.ElementDef != nil {
+			collect(item.ElementDef, nil)
+		}
+	}
+	return c
+}
+
+func (c *actionContext) element(fqn string) *elementInfo {
+	for _, e := range c.elems {
+		if e.fqn == fqn {
+			return e
+		}
+	}
 	return nil
 }
 
+// elementAt returns the element defined on a line, by ID or fully qualified ID
+// unless id is empty.
+func (c *actionContext) elementAt(line int, id string) *elementInfo {
+	for _, e := range c.elems {
+		if e.def.Pos.Line-1 == line && (id == "" || e.def.GetID() == id || e.fqn == id) {
+			return e
+		}
+	}
+	return nil
+}
+
+// freeID returns id, or id with the first number from 2 that makes it unused
+// by the document's elements.
+func (c *actionContext) freeID(id string) string {
+	used := make(map[string]bool, len(c.elems))
+	for _, e := range c.elems {
+		used[e.def.GetID()] = true
+	}
+	free := id
+	for n := 2; used[free]; n++ {
+		free = fmt.Sprintf("%s%d", id, n)
+	}
+	return free
+}
+
+// refToken returns the reference to name on a line.
+func (c *actionContext) refToken(line int, name string) (qualifiedToken, bool) {
+	start := positionToOffset(c.doc, lsp.Position{Line: line})
+	for _, tok := range scanQualifiedIdents(c.doc.GetLine(line)) {
+		if strings.Join(tok.parts, ".") == name {
+			for i := range tok.offsets {
+				tok.offsets[i] += start
+			}
+			return tok, true
+		}
+	}
+	return qualifiedToken{}, false
+}
+
+func (c *actionContext) quickFix(title string, diag lsp.Diagnostic, edit lsp.TextEdit) CodeAction {
+	return CodeAction{
+		Title:       title,
+		Kind:        lsp.CAKQuickFix,
+		Diagnostics: []lsp.Diagnostic{diag},
+		Edit:        c.edit(edit),
+	}
+}
+
+func (c *actionContext) edit(edits ...lsp.TextEdit) *WorkspaceEdit {
+	return &WorkspaceEdit{Changes: map[string][]lsp.TextEdit{string(c.doc.URI): edits}}
+}
+
+func (c *actionContext) insert(offset int, text string) lsp.TextEdit {
+	return c.replace(offset, 0, text)
+}
+
+func (c *actionContext) replace(offset, length int, text string) lsp.TextEdit {
+	r := offsetToRange(c.doc.Text, offset, length)
+	r.End = offsetToRange(c.doc.Text, offset+length, 0).Start
+	return lsp.TextEdit{Range: r, NewText: text}
+}
+
+// appendLine adds a top-level line at the end of the document.
+func (c *actionContext) appendLine(line string) lsp.TextEdit {
+	text := c.doc.Text
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		line = "\n" + line
+	}
+	return c.insert(len(text), line+"\n")
+}
+
+// addToBody adds a line at the end of the body of an element, giving it a body
+// if it has none.
+func (c *actionContext) addToBody(e *elementInfo, line string) lsp.TextEdit {
+	text := c.doc.Text
+	indent := lineIndent(text, e.def.Pos.Offset)
+	if end := bodyClose(text, e.def); end >= 0 {
+		if start := lineStart(text, end); strings.TrimSpace(text[start:end]) == "" {
+			return c.insert(start, indent+"  "+line+"\n")
+		}
+		return c.insert(end, "\n"+indent+"  "+line+"\n"+indent)
+	}
+	return c.insert(headerEnd(text, e.def.Pos.Offset), " {\n"+indent+"  "+line+"\n"+indent+"}")
+}
+
+// elementEnd returns the offset after the definition of an element.
+func elementEnd(text string, e *language.ElementDef) int {
+	if end := bodyClose(text, e); end >= 0 {
+		return end + 1
+	}
+	return headerEnd(text, e.Pos.Offset)
+}
+
+// bodyClose returns the offset of the brace closing the body of an element, or
+// -1 if it has none.
+func bodyClose(text string, e *language.ElementDef) int {
+	if e.GetBody() == nil {
+		return -1
+	}
+	start := headerEnd(text, e.Pos.Offset)
+	open := strings.IndexByte(text[start:], '{')
+	if open < 0 {
+		return -1
+	}
+	depth := 0
+	for i := start + open; i < len(text); i++ {
+		switch c := text[i]; {
+		case strings.HasPrefix(text[i:], "//"):
+			for i+1 < len(text) && text[i+1] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			if end := strings.Index(text[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				return -1
+			}
+		case c == '"' || c == '\'':
+			for i++; i < len(text) && text[i] != c; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+			}
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func lineStart(text string, offset int) int {
+	return strings.LastIndexByte(text[:offset], '\n') + 1
+}
+
+// lineIndent returns the leading whitespace of the line holding offset.
+func lineIndent(text string, offset int) string {
+	start := lineStart(text, offset)
+	end := start
+	for end < len(text) && (text[end] == ' ' || text[end] == '\t') {
+		end++
+	}
+	return text[start:end]
+}
+
+// quotedNames returns the names quoted in a diagnostic message, in order.
+func quotedNames(msg string) []string {
+	var names []string
+	for {
+		start := strings.Index(msg, "'")
+		if start < 0 {
+			return names
+		}
+		end := strings.Index(msg[start+1:], "'")
+		if end < 0 {
+			return names
+		}
+		names = append(names, msg[start+1:start+1+end])
+		msg = msg[start+end+2:]
+	}
+}
+
 // findSimilarElementNames finds element names similar to the given name
 func (s *Server) findSimilarElementNames(name string, model *language.Model) []string {
 	if model == nil {
@@ -2,16 +2,20 @@ package lsp
 
 import (
 	"context"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/sourcegraph/go-lsp"
 	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/engine"
 )
 
 func TestCodeAction_GeneratesActionsForDiagnostics(t *testing.T) {
 	s := NewServer()
 	uri := lsp.DocumentURI("file:///actions.sruja")
-	text := "System=kind \"System\"\nContainer=kind \"Container\"\nS = System \"S\" {\n    Cont = Container \"Container\"\n}\n"
+	text := "System=kind \"System\"\nContainer=kind \"Container\"\nS = System \"S\" {\n    Cont = Container \"Container\"\n    S -> Cont1 \"uses\"\n}\n"
 	_ = s.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{
 		TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1},
 	})
@@ -21,8 +25,8 @@ func TestCodeAction_GeneratesActionsForDiagnostics(t *testing.T) {
 			Code:    diagnostics.CodeReferenceNotFound,
 			Message: "Undefined reference 'Cont1'",
 			Range: lsp.Range{
-				Start: lsp.Position{Line: 3, Character: 14}, // Line 3: Cont = ...
-				End:   lsp.Position{Line: 3, Character: 19},
+				Start: lsp.Position{Line: 4, Character: 4}, // Line 4: S -> Cont1
+				End:   lsp.Position{Line: 4, Character: 5},
 			},
 		},
 		{
@@ -63,34 +67,331 @@ func TestCodeAction_GeneratesActionsForDiagnostics(t *testing.T) {
 		t.Fatalf("expected some actions, got 0")
 	}
 
-	var hasReplace bool
-	var hasRename bool
-	var hasAddRelation bool
-	var hasRemoveToken bool
+	titles := make(map[string]bool)
 	for _, a := range actions {
-		if a.Command == "sruja.replaceElement" {
-			hasReplace = true
+		if a.Kind != lsp.CAKQuickFix || a.Edit == nil || len(a.Edit.Changes[string(uri)]) == 0 {
+			t.Fatalf("action %q has no quick fix edit", a.Title)
 		}
-		if a.Command == "sruja.renameElement" && a.Title != "" {
-			hasRename = true
+		titles[a.Title] = true
+	}
+	for _, title := range []string{"Replace with 'Cont'", "Remove unexpected token 'foo'"} {
+		if !titles[title] {
+			t.Fatalf("expected action %q, got %v", title, titles)
+		}
+	}
+}
+
+// codeActionFixtures are documents with a diagnostic and the text a quick fix
+// for it leaves.
+var codeActionFixtures = []struct {
+	name  string
+	text  string
+	code  string
+	title string
+	want  string
+}{
+	{
+		name: "stub for undefined reference in scope",
+		text: `Shop = system "Shop" {
+  API = container "API"
+  API -> Cache "reads"
+}
+`,
+		code:  diagnostics.CodeReferenceNotFound,
+		title: "Create element 'Shop.Cache'",
+		want: `Shop = system "Shop" {
+  API = container "API"
+  API -> Cache "reads"
+  Cache = container "Cache"
+}
+`,
+	},
+	{
+		name: "stub for undefined top-level reference",
+		text: `User = person "User"
+User -> Billing "pays"`,
+		code:  diagnostics.CodeReferenceNotFound,
+		title: "Create element 'Billing'",
+		want: `User = person "User"
+User -> Billing "pays"
+Billing = system "Billing"
+`,
+	},
+	{
+		name: "stub for qualified reference",
+		text: `Shop = system "Shop"
+User = person "User"
+User -> Shop.API "uses"
+`,
+		code:  diagnostics.CodeReferenceNotFound,
+		title: "Create element 'Shop.API'",
+		want: `Shop = system "Shop" {
+  API = container "API"
+}
+User = person "User"
+User -> Shop.API "uses"
+`,
+	},
+	{
+		name: "rename duplicate",
+		text: `User = person "User"
+User2 = person "Admin"
+User = person "Guest"
+User -> User2 "helps"
+`,
+		code:  diagnostics.CodeDuplicateIdentifier,
+		title: "Rename to 'User3'",
+		want: `User = person "User"
+User2 = person "Admin"
+User3 = person "Guest"
+User -> User2 "helps"
+`,
+	},
+	{
+		name: "relate orphan",
+		text: `Shop = system "Shop"
+User = person "User"
+User -> Shop "uses"
+Audit = system "Audit" {
+  Log = container "Log"
+}
+`,
+		code:  diagnostics.CodeOrphanElement,
+		title: "Add relation 'User -> Audit'",
+		want: `Shop = system "Shop"
+User = person "User"
+User -> Shop "uses"
+Audit = system "Audit" {
+  Log = container "Log"
+}
+User -> Audit "uses"
+`,
+	},
+	{
+		name: "add description",
+		text: `User = person "User"
+Shop = system "Shop" {
+  technology "Go"
+}
+User -> Shop "uses"
+`,
+		code:  diagnostics.CodeBestPractice,
+		title: "Add description to 'Shop'",
+		want: `User = person "User"
+Shop = system "Shop" {
+  technology "Go"
+  description "TODO"
+}
+User -> Shop "uses"
+`,
+	},
+	{
+		name: "add technology",
+		text: `User = person "User"
+Shop = system "Shop" {
+  description "Storefront"
+  API = container "API" {
+    description "Public API"
+  }
+}
+User -> Shop.API "uses"
+`,
+		code:  diagnostics.CodeBestPractice,
+		title: "Add technology to 'API'",
+		want: `User = person "User"
+Shop = system "Shop" {
+  description "Storefront"
+  API = container "API" {
+    description "Public API"
+    technology "TODO"
+  }
+}
+User -> Shop.API "uses"
+`,
+	},
+}
+
+func TestCodeAction_QuickFixFixtures(t *testing.T) {
+	for _, tc := range codeActionFixtures {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := NewServer()
+			uri := lsp.DocumentURI("file:///fix.sruja")
+			_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: tc.text, Version: 1}})
+
+			action := findAction(t, srv, uri, fixtureDiagnostics(t, srv, uri, tc.code), tc.title)
+			if action.Kind != lsp.CAKQuickFix || len(action.Diagnostics) != 1 {
+				t.Fatalf("action = %+v", action)
+			}
+			if got := applyEdits(tc.text, action.Edit.Changes[string(uri)]); got != tc.want {
+				t.Fatalf("fixed text:\n%s\nwant:\n%s", got, tc.want)
+			}
+			for _, d := range fixtureDiagnostics(t, reopen(srv, uri, tc.want), uri, tc.code) {
+				if d.Message == action.Diagnostics[0].Message {
+					t.Fatalf("diagnostic left after the fix: %+v", d)
+				}
+			}
+		})
+	}
+}
+
+func TestCodeAction_ConvertToNested(t *testing.T) {
+	srv := NewServer()
+	uri := lsp.DocumentURI("file:///nested.sruja")
+	text := "Shop = system \"Shop\"\nShopContainer = system \"Other\"\n"
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1}})
+
+	action := findActionAt(t, srv, uri, lsp.Position{Line: 0, Character: 1}, "Convert 'Shop' to a nested element")
+	if action.Kind != lsp.CAKRefactorRewrite {
+		t.Fatalf("kind = %q", action.Kind)
+	}
+	want := "Shop = system \"Shop\" {\n  ShopContainer2 = container \"ShopContainer2\"\n}\nShopContainer = system \"Other\"\n"
+	if got := applyEdits(text, action.Edit.Changes[string(uri)]); got != want {
+		t.Fatalf("converted text:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCodeAction_ExtractToNewFile(t *testing.T) {
+	root := t.TempDir()
+	text := `User = person "User"
+// The storefront
+Shop = system "Shop" {
+  API = container "API"
+}
+User -> Shop.API "uses"
+`
+	writeIndexFile(t, filepath.Join(root, "main.sruja"), text)
+	writeIndexFile(t, filepath.Join(root, "shop.sruja"), "")
+	srv := NewServer()
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(root)}); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	uri := pathToURI(filepath.Join(root, "main.sruja"))
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 3}})
+
+	action := findActionAt(t, srv, uri, lsp.Position{Line: 2, Character: 0}, "Extract 'Shop' to shop2.sruja")
+	if action.Kind != lsp.CAKRefactorExtract || len(action.Edit.DocumentChanges) != 3 {
+		t.Fatalf("action = %+v", action)
+	}
+	target := pathToURI(filepath.Join(root, "shop2.sruja"))
+	if create, ok := action.Edit.DocumentChanges[0].(CreateFile); !ok || create.URI != target {
+		t.Fatalf("first change = %+v, want creating %s", action.Edit.DocumentChanges[0], target)
+	}
+	moved := action.Edit.DocumentChanges[1].(TextDocumentEdit)
+	if moved.TextDocument.URI != target || moved.TextDocument.Version != nil {
+		t.Fatalf("new file edit = %+v", moved)
+	}
+	if got, want := applyEdits("", moved.Edits), "Shop = system \"Shop\" {\n  API = container \"API\"\n}\n"; got != want {
+		t.Fatalf("new file:\n%s\nwant:\n%s", got, want)
+	}
+	removed := action.Edit.DocumentChanges[2].(TextDocumentEdit)
+	if removed.TextDocument.Version == nil || *removed.TextDocument.Version != 3 {
+		t.Fatalf("source edit version = %v", removed.TextDocument.Version)
+	}
+	if got, want := applyEdits(text, removed.Edits), "User = person \"User\"\n// The storefront\nUser -> Shop.API \"uses\"\n"; got != want {
+		t.Fatalf("source after extract:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Nested elements stay where they are
+	for _, a := range codeActionsAt(t, srv, uri, lsp.Position{Line: 3, Character: 2}) {
+		if strings.HasPrefix(a.Title, "Extract") {
+			t.Fatalf("unexpected action %q for a nested element", a.Title)
 		}
-		if a.Command == "sruja.addRelation" {
-			hasAddRelation = true
+	}
+}
+
+// fixtureDiagnostics returns the diagnostics with a code the server reports
+// for a document, and for best practices those of the documentation rule,
+// which is not enabled by default.
+func fixtureDiagnostics(t *testing.T, srv *Server, uri lsp.DocumentURI, code string) []lsp.Diagnostic {
+	t.Helper()
+	doc := srv.workspace.GetDocument(uri)
+	var all []lsp.Diagnostic
+	if code == diagnostics.CodeBestPractice {
+		prog := parseDSL(t, doc.Text)
+		engine.RunResolution(prog)
+		all = srv.convertDiagnosticsToLSP((&engine.PublicInterfaceDocumentationRule{}).Validate(prog))
+	} else {
+		all = srv.documentDiagnostics(context.Background(), doc.snapshot())
+	}
+	var diags []lsp.Diagnostic
+	for _, d := range all {
+		if d.Code == code {
+			diags = append(diags, d)
 		}
-		if a.Command == "sruja.removeToken" {
-			hasRemoveToken = true
+	}
+	return diags
+}
+
+func reopen(srv *Server, uri lsp.DocumentURI, text string) *Server {
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 2}})
+	return srv
+}
+
+func codeActionsAt(t *testing.T, srv *Server, uri lsp.DocumentURI, pos lsp.Position) []CodeAction {
+	t.Helper()
+	actions, err := srv.CodeAction(context.Background(), lsp.CodeActionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Range:        lsp.Range{Start: pos, End: pos},
+	})
+	if err != nil {
+		t.Fatalf("CodeAction error: %v", err)
+	}
+	return actions
+}
+
+func findActionAt(t *testing.T, srv *Server, uri lsp.DocumentURI, pos lsp.Position, title string) CodeAction {
+	t.Helper()
+	var titles []string
+	for _, a := range codeActionsAt(t, srv, uri, pos) {
+		if a.Title == title {
+			return a
 		}
+		titles = append(titles, a.Title)
 	}
-	if !hasReplace {
-		t.Fatalf("expected replaceElement action for undefined reference")
+	t.Fatalf("no action %q in %v", title, titles)
+	return CodeAction{}
+}
+
+func findAction(t *testing.T, srv *Server, uri lsp.DocumentURI, diags []lsp.Diagnostic, title string) CodeAction {
+	t.Helper()
+	if len(diags) == 0 {
+		t.Fatalf("no diagnostics to fix")
 	}
-	if !hasRename {
-		t.Fatalf("expected renameElement action for duplicate identifier")
+	var titles []string
+	for _, d := range diags {
+		actions, err := srv.CodeAction(context.Background(), lsp.CodeActionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Range:        d.Range,
+			Context:      lsp.CodeActionContext{Diagnostics: []lsp.Diagnostic{d}},
+		})
+		if err != nil {
+			t.Fatalf("CodeAction error: %v", err)
+		}
+		for _, a := range actions {
+			if a.Title == title {
+				return a
+			}
+			titles = append(titles, a.Title)
+		}
 	}
-	if !hasAddRelation {
-		t.Fatalf("expected addRelation action for orphan element")
+	t.Fatalf("no action %q in %v", title, titles)
+	return CodeAction{}
+}
+
+// applyEdits applies non-overlapping text edits to text.
+func applyEdits(text string, edits []lsp.TextEdit) string {
+	offset := func(pos lsp.Position) int {
+		lines := strings.SplitAfter(text, "\n")
+		o := 0
+		for i := 0; i < pos.Line && i < len(lines); i++ {
+			o += len(lines[i])
+		}
+		return o + pos.Character
 	}
-	if !hasRemoveToken {
-		t.Fatalf("expected removeToken action for unexpected token")
+	sorted := append([]lsp.TextEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool { return offset(sorted[i].Range.Start) > offset(sorted[j].Range.Start) })
+	for _, e := range sorted {
+		text = text[:offset(e.Range.Start)] + e.NewText + text[offset(e.Range.End):]
 	}
+	return text
 }
@@ -0,0 +1,130 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/dx"
+	"github.com/sruja-ai/sruja/pkg/engine"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// ShowElementDiagramCommand is the command of element code lenses. Its
+// arguments are the document URI and the fully qualified ID of the element to
+// focus the diagram on.
+const ShowElementDiagramCommand = "sruja.showElementDiagram"
+
+// governanceKinds are the element kinds that describe the architecture rather
+// than being part of it, so they get no code lens or refactorings.
+var governanceKinds = map[string]bool{
+	"adr":         true,
+	"scenario":    true,
+	"story":       true,
+	"flow":        true,
+	"requirement": true,
+	"policy":      true,
+}
+
+// CodeLenses shows above each element its incoming and outgoing relations and
+// the ADRs and scenarios linked to it, counted across the document's workspace
+// root, with a command to open a diagram focused on the element.
+func (s *Server) CodeLenses(_ context.Context, params lsp.CodeLensParams) ([]lsp.CodeLens, error) {
+	doc := s.workspace.GetDocument(params.TextDocument.URI)
+	if doc == nil {
+		return nil, nil
+	}
+	prog, _ := doc.parse()
+	if prog == nil {
+		return nil, nil
+	}
+	explainer := dx.NewExplainer(s.resolvedModel(doc))
+	pos := newPositionIndex(doc.Text)
+
+	lenses := []lsp.CodeLens{}
+	var walk func(e *language.ElementDef, parent string)
+	walk = func(e *language.ElementDef, parent string) {
+		id := e.GetID()
+		if id == "" || governanceKinds[e.GetKind()] {
+			return
+		}
+		fqn := id
+		if parent != "" {
+			fqn = parent + "." + id
+		}
+		if ex, err := explainer.ExplainElement(fqn); err == nil {
+			start := pos.at(e.Pos.Offset)
+			lenses = append(lenses, lsp.CodeLens{
+				Range: lsp.Range{Start: start, End: lsp.Position{Line: start.Line, Character: start.Character + len(id)}},
+				Command: lsp.Command{
+					Title:     lensTitle(ex),
+					Command:   ShowElementDiagramCommand,
+					Arguments: []interface{}{string(doc.URI), fqn},
+				},
+			})
+		}
+		if body := e.GetBody(); body != nil {
+			for _, item := range body.Items {
+				if item.Element != nil {
+					walk(item.Element, fqn)
+				}
+			}
+		}
+	}
+	for _, item := range prog.Items {
+		if item.ElementDef != nil {
+			walk(item.ElementDef, "")
+		}
+	}
+	return lenses, nil
+}
+
+// lensTitle summarizes an element explanation, as in
+// "2 incoming · 1 outgoing · ADRs: ADR001 · scenarios: Checkout".
+func lensTitle(ex *dx.ElementExplanation) string {
+	parts := []string{
+		fmt.Sprintf("%d incoming", len(ex.Relations.Incoming)),
+		fmt.Sprintf("%d outgoing", len(ex.Relations.Outgoing)),
+	}
+	if len(ex.ADRs) > 0 {
+		parts = append(parts, "ADRs: "+strings.Join(ex.ADRs, ", "))
+	}
+	if len(ex.Scenarios) > 0 {
+		ids := make([]string, len(ex.Scenarios))
+		for i, sc := range ex.Scenarios {
+			ids[i] = sc.ID
+		}
+		parts = append(parts, "scenarios: "+strings.Join(ids, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// resolvedModel parses and resolves the documents of the document's workspace
+// root, or the document alone outside any root, into one program.
+func (s *Server) resolvedModel(doc *Document) *language.Program {
+	prog, _ := s.resolveRoot(doc)
+	return prog
+}
+
+// resolveRoot is resolvedModel that also returns the parsed snapshots keyed by
+// the file name of their positions.
+func (s *Server) resolveRoot(doc *Document) (*language.Program, map[string]docSnapshot) {
+	snaps := []docSnapshot{doc.snapshot()}
+	if root := s.workspace.RootOf(uriToPath(doc.URI)); root != "" {
+		snaps = s.workspace.snapshotRoot(root)
+	}
+	files := make(map[string]docSnapshot, len(snaps))
+	ws := language.NewWorkspace()
+	for _, d := range snaps {
+		path := d.path
+		if path == "" {
+			path = string(d.uri)
+		}
+		files[path] = d
+		prog, diags, _ := d.blocks.parse(path, d.text)
+		ws.AddProgram(path, prog, diags)
+	}
+	engine.RunWorkspaceResolution(ws)
+	return ws.MergedProgram(), files
+}
@@ -0,0 +1,77 @@
+package lsp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+func TestCodeLenses_SummarizeElements(t *testing.T) {
+	root := t.TempDir()
+	systemsText := `Shop = system "Shop" {
+  API = container "API"
+  DB = database "DB"
+  API -> DB "reads"
+}
+`
+	writeIndexFile(t, filepath.Join(root, "systems.sruja"), systemsText)
+	writeIndexFile(t, filepath.Join(root, "uses.sruja"), `User = person "User"
+User -> Shop.API "uses"
+Checkout = scenario "Checkout" {
+  step User -> Shop.API "browses"
+}
+`)
+	srv := NewServer()
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(root)}); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	systems := pathToURI(filepath.Join(root, "systems.sruja"))
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: systems, Text: systemsText, Version: 1}})
+
+	lenses, err := srv.CodeLenses(context.Background(), lsp.CodeLensParams{TextDocument: lsp.TextDocumentIdentifier{URI: systems}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	titles := make(map[string]string)
+	for _, l := range lenses {
+		if l.Command.Command != ShowElementDiagramCommand || len(l.Command.Arguments) != 2 || l.Command.Arguments[0] != string(systems) {
+			t.Fatalf("unexpected command %+v", l.Command)
+		}
+		titles[l.Command.Arguments[1].(string)] = l.Command.Title
+	}
+
+	// Relations and scenario steps in uses.sruja count towards Shop.API
+	want := map[string]string{
+		"Shop":     "1 incoming · 0 outgoing",
+		"Shop.API": "1 incoming · 1 outgoing · scenarios: Checkout",
+		"Shop.DB":  "1 incoming · 0 outgoing",
+	}
+	for fqn, title := range want {
+		if titles[fqn] != title {
+			t.Errorf("%s lens = %q, want %q", fqn, titles[fqn], title)
+		}
+	}
+	for _, l := range lenses {
+		if l.Command.Arguments[1] == "Shop.API" && l.Range != (lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 5}}) {
+			t.Errorf("Shop.API lens at %+v", l.Range)
+		}
+	}
+}
+
+func TestCodeLenses_SkipsGovernanceElements(t *testing.T) {
+	uri := pathToURI(filepath.Join(t.TempDir(), "adr.sruja"))
+	text := `Shop = system "Shop"
+ADR001 = adr "Use Shop for checkout"
+`
+	srv := NewServer()
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1}})
+	lenses, err := srv.CodeLenses(context.Background(), lsp.CodeLensParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lenses) != 1 || lenses[0].Command.Title != "0 incoming · 0 outgoing · ADRs: ADR001" {
+		t.Fatalf("lenses = %+v", lenses)
+	}
+}
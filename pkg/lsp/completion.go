@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/engine"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
 
@@ -127,6 +128,12 @@ func (s *Server) Completion(_ context.Context, params lsp.CompletionParams) (*ls
 					addItem(&items, name, lsp.CIKKeyword)
 				}
 			}
+			if item.Relationship != nil {
+				name := item.Relationship.Name
+				if token == "" || strings.HasPrefix(strings.ToLower(name), strings.ToLower(token)) {
+					addItem(&items, name, lsp.CIKKeyword)
+				}
+			}
 		}
 	}
 
@@ -147,7 +154,10 @@ func (s *Server) Completion(_ context.Context, params lsp.CompletionParams) (*ls
 		after := before[idx+2:]
 		// if after contains an identifier and some trailing space, we are likely at verb position
 		ident := lastToken(after)
-		if ident != "" && (len(after) > len(ident)) {
+		if open := strings.LastIndex(after, "["); open >= 0 && !strings.Contains(after[open:], "]") {
+			// Inside the relation's tag list: suggest the relation vocabulary
+			items = append(items, relationTagItems(program, token)...)
+		} else if ident != "" && (len(after) > len(ident)) {
 			for _, v := range []string{"reads", "writes", "calls", "uses", "publishes"} {
 				addItem(&items, v, lsp.CIKFunction)
 			}
@@ -157,6 +167,35 @@ func (s *Server) Completion(_ context.Context, params lsp.CompletionParams) (*ls
 	return &lsp.CompletionList{IsIncomplete: false, Items: items}, nil
 }
 
+// relationTagItems returns the relation tags the relation-tags rule accepts:
+// the built-in verbs and the relationship kinds declared in the specification.
+func relationTagItems(program *language.Program, token string) []lsp.CompletionItem {
+	var items []lsp.CompletionItem
+	matches := func(name string) bool {
+		return token == "" || strings.HasPrefix(strings.ToLower(name), strings.ToLower(token))
+	}
+	declared := make(map[string]bool)
+	if program != nil {
+		for _, kind := range program.Specification.RelationshipKinds() {
+			declared[strings.ToLower(kind.Name)] = true
+			if matches(kind.Name) {
+				items = append(items, lsp.CompletionItem{
+					Label:         kind.Name,
+					Kind:          lsp.CIKEnumMember,
+					Detail:        kind.GetLabel(),
+					Documentation: kind.GetDescription(),
+				})
+			}
+		}
+	}
+	for _, verb := range engine.DefaultRelationVerbs {
+		if !declared[verb] && matches(verb) {
+			items = append(items, lsp.CompletionItem{Label: verb, Kind: lsp.CIKEnumMember})
+		}
+	}
+	return items
+}
+
 func lastToken(s string) string {
 	i := len(s) - 1
 	for i >= 0 && !isIdentChar(s[i]) {
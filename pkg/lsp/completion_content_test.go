@@ -90,3 +90,34 @@ func TestCompletion_RelationVerbSuggestions(t *testing.T) {
 		t.Fatalf("expected verb 'uses' after relation arrow")
 	}
 }
+
+func TestCompletion_RelationTagsIncludeRelationshipKinds(t *testing.T) {
+	s := NewServer()
+	uri := lsp.DocumentURI("file:///kinds.sruja")
+	text := "publishes = relationship \"Publishes\"\nA = system \"A\"\nQ = queue \"Q\"\nA -> Q [pu\n"
+	_ = s.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1},
+	})
+
+	res, err := s.Completion(context.Background(), lsp.CompletionParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Position:     lsp.Position{Line: 3, Character: 10},
+		},
+	})
+	if err != nil {
+		t.Fatalf("completion error: %v", err)
+	}
+	var kind *lsp.CompletionItem
+	for i, it := range res.Items {
+		if it.Label == "uses" {
+			t.Fatalf("expected only tags starting with 'pu' inside the tag list, got %q", it.Label)
+		}
+		if it.Label == "publishes" && it.Kind == lsp.CIKEnumMember {
+			kind = &res.Items[i]
+		}
+	}
+	if kind == nil || kind.Detail != "Publishes" {
+		t.Fatalf("expected relationship kind 'publishes' in tag completion, got %+v", res.Items)
+	}
+}
@@ -0,0 +1,79 @@
+package lsp
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/engine"
+)
+
+// configValidator is a validator built from a sruja.config.json, tagged with the
+// modification time of the file it was built from.
+type configValidator struct {
+	modTime   time.Time
+	validator *engine.Validator
+}
+
+// uriToPath converts a file:// document URI to a filesystem path.
+// Returns "" for URIs that do not refer to local files.
+func uriToPath(uri lsp.DocumentURI) string {
+	s := string(uri)
+	if !strings.HasPrefix(s, "file://") {
+		if filepath.IsAbs(s) {
+			return s
+		}
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return filepath.FromSlash(strings.TrimPrefix(s, "file://"))
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// validatorFor returns the validator for a document, honouring the nearest
+// sruja.config.json above the document. Falls back to the server's default
+// validator when there is no config file; a config that cannot be loaded or
+// names unknown rules is reported through the returned error.
+func (s *Server) validatorFor(uri lsp.DocumentURI) (*engine.Validator, error) {
+	path := uriToPath(uri)
+	if path == "" {
+		return s.validator, nil
+	}
+	return s.validatorForDir(filepath.Dir(path))
+}
+
+// validatorForDir returns the validator for the sruja.config.json found by walking
+// up from dir, like validatorFor.
+func (s *Server) validatorForDir(dir string) (*engine.Validator, error) {
+	configPath := config.FindConfigFile(dir)
+	if configPath == "" {
+		return s.validator, nil
+	}
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return s.validator, nil
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	if cached, ok := s.configValidators[configPath]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.validator, nil
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return s.validator, err
+	}
+	v, err := engine.NewValidatorFromConfig(cfg.Validation, engine.DefaultRuleIDs)
+	if err != nil {
+		return s.validator, err
+	}
+	s.configValidators[configPath] = configValidator{modTime: info.ModTime(), validator: v}
+	return v, nil
+}
@@ -0,0 +1,59 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/engine"
+)
+
+func TestValidatorFor_ProjectConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	docPath := filepath.Join(tmpDir, "models", "main.sruja")
+	uri := lsp.DocumentURI("file://" + docPath)
+
+	s := NewServer()
+	v, err := s.validatorFor(uri)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v != s.validator {
+		t.Error("Expected default validator without a config file")
+	}
+	if len(v.Rules) != len(engine.DefaultRuleIDs) {
+		t.Errorf("Expected the %d default rules of the CLI, got %d", len(engine.DefaultRuleIDs), len(v.Rules))
+	}
+
+	configJSON := `{"validation": {"rules": ["unique-ids", "cycle-detection"]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "sruja.config.json"), []byte(configJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	v, err = s.validatorFor(uri)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(v.Rules) != 2 {
+		t.Errorf("Expected 2 configured rules, got %d", len(v.Rules))
+	}
+	if cached, _ := s.validatorFor(uri); cached != v {
+		t.Error("Expected validator to be cached while the config is unchanged")
+	}
+}
+
+func TestValidatorFor_InvalidConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "sruja.config.json"), []byte(`{"validation": {"rules": ["bogus"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer()
+	v, err := s.validatorFor(lsp.DocumentURI("file://" + filepath.Join(tmpDir, "main.sruja")))
+	if err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+	if v != s.validator {
+		t.Error("Expected fallback to the default validator")
+	}
+}
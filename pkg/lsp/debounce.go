@@ -0,0 +1,72 @@
+package lsp
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+// defaultDiagnosticsDelay is how long validation waits after an edit, so a burst
+// of keystrokes is validated once.
+const defaultDiagnosticsDelay = 250 * time.Millisecond
+
+// pendingDiagnostics is a validation scheduled or running for a document or
+// workspace root.
+type pendingDiagnostics struct {
+	cancel context.CancelFunc
+}
+
+// scheduleDiagnostics validates a document after the diagnostics delay,
+// cancelling the validation still pending or running for the same document or
+// workspace root. The validation ends with ctx. With no delay it runs at once.
+func (s *Server) scheduleDiagnostics(ctx context.Context, uri lsp.DocumentURI) error {
+	key, job := s.diagnosticsJob(uri)
+	if job == nil {
+		return nil
+	}
+	if s.diagnosticsDelay <= 0 {
+		s.cancelDiagnostics(key)
+		return job(ctx)
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	p := &pendingDiagnostics{cancel: cancel}
+	s.pendingMu.Lock()
+	if prev := s.pending[key]; prev != nil {
+		prev.cancel()
+	}
+	s.pending[key] = p
+	s.pendingMu.Unlock()
+
+	delay := s.diagnosticsDelay
+	go func() {
+		defer func() {
+			s.pendingMu.Lock()
+			if s.pending[key] == p {
+				delete(s.pending, key)
+			}
+			s.pendingMu.Unlock()
+			cancel()
+		}()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-jobCtx.Done():
+			return
+		case <-timer.C:
+		}
+		_ = job(jobCtx) // nowhere to report a failed notification from here
+	}()
+	return nil
+}
+
+// cancelDiagnostics cancels the validation pending or running under key.
+func (s *Server) cancelDiagnostics(key string) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if p := s.pending[key]; p != nil {
+		p.cancel()
+		delete(s.pending, key)
+	}
+}
@@ -0,0 +1,135 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// recordingClient collects the diagnostics a server publishes over an
+// in-memory connection.
+type recordingClient struct {
+	mu        sync.Mutex
+	published []lsp.PublishDiagnosticsParams
+}
+
+func (c *recordingClient) Handle(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Method != "textDocument/publishDiagnostics" || req.Params == nil {
+		return
+	}
+	var params lsp.PublishDiagnosticsParams
+	if err := json.Unmarshal(*req.Params, &params); err == nil {
+		c.mu.Lock()
+		c.published = append(c.published, params)
+		c.mu.Unlock()
+	}
+}
+
+func (c *recordingClient) snapshot() []lsp.PublishDiagnosticsParams {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]lsp.PublishDiagnosticsParams(nil), c.published...)
+}
+
+// debouncedServer connects a server with the given diagnostics delay to a
+// recording client.
+func debouncedServer(t *testing.T, delay time.Duration) (*Server, *recordingClient) {
+	t.Helper()
+	serverSide, clientSide := net.Pipe()
+	client := &recordingClient{}
+	clientConn := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(clientSide, jsonrpc2.VSCodeObjectCodec{}), client)
+	serverConn := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(serverSide, jsonrpc2.VSCodeObjectCodec{}), jsonrpc2.HandlerWithError(func(context.Context, *jsonrpc2.Conn, *jsonrpc2.Request) (interface{}, error) {
+		return nil, nil
+	}))
+	t.Cleanup(func() {
+		_ = clientConn.Close()
+		_ = serverConn.Close()
+	})
+
+	srv := NewServer()
+	srv.conn = serverConn
+	srv.diagnosticsDelay = delay
+	return srv, client
+}
+
+func change(srv *Server, uri lsp.DocumentURI, version int, text string) error {
+	return srv.DidChange(context.Background(), lsp.DidChangeTextDocumentParams{
+		TextDocument:   lsp.VersionedTextDocumentIdentifier{TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: uri}, Version: version},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: text}},
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServer_DidChangeDebouncesDiagnostics(t *testing.T) {
+	srv, client := debouncedServer(t, 50*time.Millisecond)
+	uri := lsp.DocumentURI("file:///debounce.sruja")
+	srv.workspace.AddDocument(uri, "User = person \"User\"\n", 1)
+
+	// A burst of edits, the last one referencing an unknown element
+	texts := []string{
+		"User = person \"User\"\nUser -> A \"uses\"\n",
+		"User = person \"User\"\nUser -> Ap \"uses\"\n",
+		"User = person \"User\"\nUser -> Api \"uses\"\n",
+	}
+	for i, text := range texts {
+		if err := change(srv, uri, i+2, text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool { return len(client.snapshot()) > 0 })
+	time.Sleep(100 * time.Millisecond) // no further publish is pending
+
+	published := client.snapshot()
+	if len(published) != 1 {
+		t.Fatalf("expected one publish for the burst, got %d", len(published))
+	}
+	found := false
+	for _, d := range published[0].Diagnostics {
+		if strings.Contains(d.Message, "Api") {
+			found = true
+		} else if strings.Contains(d.Message, "Ap") {
+			t.Errorf("expected diagnostics of the last edit only, got %q", d.Message)
+		}
+	}
+	if !found {
+		t.Errorf("expected a diagnostic for Api, got %+v", published[0].Diagnostics)
+	}
+}
+
+func TestServer_DidCloseCancelsPendingDiagnostics(t *testing.T) {
+	srv, client := debouncedServer(t, 50*time.Millisecond)
+	uri := lsp.DocumentURI("file:///closed.sruja")
+	srv.workspace.AddDocument(uri, "User = person \"User\"\n", 1)
+
+	if err := change(srv, uri, 2, "User = person \"User\"\nUser -> Api \"uses\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.DidClose(context.Background(), lsp.DidCloseTextDocumentParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return len(client.snapshot()) > 0 })
+	time.Sleep(100 * time.Millisecond)
+
+	// Only the clearing publish of the close remains
+	published := client.snapshot()
+	if len(published) != 1 || len(published[0].Diagnostics) != 0 {
+		t.Errorf("expected only cleared diagnostics after close, got %+v", published)
+	}
+}
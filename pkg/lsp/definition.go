@@ -27,6 +27,13 @@ func (s *Server) Definition(_ context.Context, params lsp.TextDocumentPositionPa
 		return nil, nil
 	}
 
+	idx := s.workspace.indexSymbols(doc)
+	if occ := idx.occurrenceAt(doc, params.Position); occ != nil {
+		if def := idx.defs[occ.fqn]; def != nil {
+			return []lsp.Location{def.location()}, nil
+		}
+	}
+
 	if uri, rng, ok := s.workspace.FindDefinition(token); ok {
 		return []lsp.Location{{URI: uri, Range: rng}}, nil
 	}
@@ -0,0 +1,79 @@
+package lsp
+
+import (
+	"context"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+// LinkedEditingRanges are ranges of a document that have the same content and
+// are edited together.
+type LinkedEditingRanges struct {
+	Ranges      []lsp.Range `json:"ranges"`
+	WordPattern string      `json:"wordPattern,omitempty"`
+}
+
+// identifierPattern matches what an element ID can be edited to.
+const identifierPattern = `[A-Za-z_][A-Za-z0-9_-]*`
+
+// DocumentHighlights highlights the occurrences of the element under the cursor
+// in the document, its definition as a write and its references as reads.
+func (s *Server) DocumentHighlights(_ context.Context, params lsp.TextDocumentPositionParams) ([]lsp.DocumentHighlight, error) {
+	doc := s.workspace.GetDocument(params.TextDocument.URI)
+	if doc == nil {
+		return nil, nil
+	}
+	occs := s.localOccurrences(doc, params.Position)
+	if occs == nil {
+		return nil, nil
+	}
+	highlights := make([]lsp.DocumentHighlight, 0, len(occs))
+	for _, o := range occs {
+		kind := lsp.Read
+		if o.def {
+			kind = lsp.Write
+		}
+		highlights = append(highlights, lsp.DocumentHighlight{Range: o.location().Range, Kind: kind})
+	}
+	return highlights, nil
+}
+
+// LinkedEditingRanges links the ID of an element defined in the document to its
+// references in the document, so they change together as the ID is typed.
+// Elements defined in other documents are left to rename.
+func (s *Server) LinkedEditingRanges(_ context.Context, params lsp.TextDocumentPositionParams) (*LinkedEditingRanges, error) {
+	doc := s.workspace.GetDocument(params.TextDocument.URI)
+	if doc == nil {
+		return nil, nil
+	}
+	occs := s.localOccurrences(doc, params.Position)
+	defined := false
+	for _, o := range occs {
+		defined = defined || o.def
+	}
+	if !defined {
+		return nil, nil
+	}
+	ranges := make([]lsp.Range, 0, len(occs))
+	for _, o := range occs {
+		ranges = append(ranges, o.location().Range)
+	}
+	return &LinkedEditingRanges{Ranges: ranges, WordPattern: identifierPattern}, nil
+}
+
+// localOccurrences returns the occurrences in doc of the element named under
+// the cursor, or nil if the cursor is not on an element.
+func (s *Server) localOccurrences(doc *Document, pos lsp.Position) []*symbolOccurrence {
+	idx := s.workspace.indexSymbols(doc)
+	occ := idx.occurrenceAt(doc, pos)
+	if occ == nil {
+		return nil
+	}
+	var res []*symbolOccurrence
+	for _, o := range idx.occurrencesOf(occ.fqn, true) {
+		if o.doc == doc {
+			res = append(res, o)
+		}
+	}
+	return res
+}
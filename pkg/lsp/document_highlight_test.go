@@ -0,0 +1,81 @@
+package lsp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+const highlightText = `Shop = system "Shop" {
+  API = container "API"
+  DB = database "DB"
+  API -> DB "reads"
+}
+Billing = system "Billing" {
+  API = container "API"
+}
+User = person "User"
+User -> Shop.API "uses"
+`
+
+func positionParams(uri lsp.DocumentURI, line, character int) lsp.TextDocumentPositionParams {
+	return lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Position:     lsp.Position{Line: line, Character: character},
+	}
+}
+
+func TestDocumentHighlights_DefinitionAndReferences(t *testing.T) {
+	srv := NewServer()
+	uri := lsp.DocumentURI("file:///highlight.sruja")
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: highlightText, Version: 1}})
+
+	highlights, err := srv.DocumentHighlights(context.Background(), positionParams(uri, 3, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Billing.API is another element and is not highlighted
+	want := []lsp.DocumentHighlight{
+		{Range: lineRange(1, 2, 5), Kind: lsp.Write},
+		{Range: lineRange(3, 2, 5), Kind: lsp.Read},
+		{Range: lineRange(9, 13, 16), Kind: lsp.Read},
+	}
+	if len(highlights) != len(want) {
+		t.Fatalf("highlights = %+v", highlights)
+	}
+	for i := range want {
+		if highlights[i] != want[i] {
+			t.Errorf("highlight %d = %+v, want %+v", i, highlights[i], want[i])
+		}
+	}
+
+	if highlights, _ := srv.DocumentHighlights(context.Background(), positionParams(uri, 0, 16)); highlights != nil {
+		t.Errorf("expected no highlights in a string, got %+v", highlights)
+	}
+}
+
+func TestLinkedEditingRanges_LocalDefinitions(t *testing.T) {
+	srv := NewServer()
+	systems := lsp.DocumentURI("file:///systems.sruja")
+	users := lsp.DocumentURI("file:///users.sruja")
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: systems, Text: highlightText, Version: 1}})
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: users, Text: "Admin = person \"Admin\"\nAdmin -> Shop \"manages\"\n", Version: 1}})
+
+	linked, err := srv.LinkedEditingRanges(context.Background(), positionParams(systems, 1, 3))
+	if err != nil || linked == nil {
+		t.Fatalf("linked = %+v, %v", linked, err)
+	}
+	if len(linked.Ranges) != 3 || linked.Ranges[2] != lineRange(9, 13, 16) || linked.WordPattern == "" {
+		t.Errorf("unexpected linked ranges %+v", linked)
+	}
+
+	linked, _ = srv.LinkedEditingRanges(context.Background(), positionParams(users, 1, 0))
+	if linked == nil || len(linked.Ranges) != 2 {
+		t.Errorf("Admin linked ranges = %+v", linked)
+	}
+	// Shop is defined in another document, which linked editing would not update
+	if linked, _ := srv.LinkedEditingRanges(context.Background(), positionParams(users, 1, 10)); linked != nil {
+		t.Errorf("expected no linked ranges for Shop, got %+v", linked)
+	}
+}
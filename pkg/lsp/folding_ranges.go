@@ -39,15 +39,11 @@ func (s *Server) FoldingRanges(_ context.Context, params FoldingRangeParams) ([]
 		return nil, nil
 	}
 
-	// Parse the document to get AST
-	parser, err := language.NewParser()
-	if err != nil {
-		return nil, nil
-	}
-
-	program, _, err := parser.Parse(string(params.TextDocument.URI), doc.Text)
-	if err != nil || program == nil {
-		// If parsing fails, still try to provide basic folding based on braces
+	// Parse the document to get AST. Syntax errors leave a partial program
+	// holding everything that parsed.
+	program, _, _ := doc.blocks.parse(string(params.TextDocument.URI), doc.Text)
+	if program == nil {
+		// If the parser fails, still try to provide basic folding based on braces
 		return s.foldingRangesFromText(doc.Text), nil
 	}
 
@@ -133,8 +129,8 @@ func (s *Server) findBlockEnd(text string, startLine, startCol int) int {
 		return 0
 	}
 
-	// Count braces to find matching closing brace
-	depth := 0
+	// Count braces after the opening one to find the matching closing brace
+	depth := 1
 	for i := searchLine; i < len(lines); i++ {
 		line := lines[i]
 		startIdx := 0
@@ -160,7 +156,7 @@ func (s *Server) findBlockEnd(text string, startLine, startCol int) int {
 }
 
 // foldingRangesFromText provides basic folding ranges based on brace matching
-// when AST parsing fails.
+// when the parser fails to return a program.
 func (s *Server) foldingRangesFromText(text string) []FoldingRange {
 	lines := strings.Split(text, "\n")
 	ranges := make([]FoldingRange, 0, 16)
@@ -62,9 +62,9 @@ description "A user of the system"
 }
 
 func TestFoldingRanges_NestedBlocks(t *testing.T) {
-	text := `system API "API System" {
-container WebApp "Web Application" {
-  component Auth "Authentication"
+	text := `API = system "API System" {
+WebApp = container "Web Application" {
+  Auth = component "Authentication"
 }
 }`
 	ws := NewWorkspace()
@@ -87,6 +87,11 @@ container WebApp "Web Application" {
 func TestFoldingRanges_InvalidSyntax(t *testing.T) {
 	text := `this is not valid sruja syntax {
   but it has braces
+}
+Shop = system "Shop" {
+  API = container "API" {
+    technology "Go"
+  }
 }`
 	ws := NewWorkspace()
 	ws.AddDocument("file:///test.sruja", text, 1)
@@ -99,9 +104,9 @@ func TestFoldingRanges_InvalidSyntax(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	// Should fall back to text-based folding
-	if len(ranges) == 0 {
-		t.Fatalf("expected fallback text-based folding ranges, got 0")
+	// Ranges come from the partial program: the invalid block is left out
+	if len(ranges) != 2 || ranges[0].StartLine != 3 || ranges[0].EndLine != 7 || ranges[1].StartLine != 4 || ranges[1].EndLine != 6 {
+		t.Fatalf("expected ranges of Shop and API, got %+v", ranges)
 	}
 }
 
@@ -126,9 +131,9 @@ func TestFindBlockEnd_Nested(t *testing.T) {
 }`
 	srv := &Server{}
 	endLine := srv.findBlockEnd(text, 0, 0)
-	// The function should find the closing brace
-	if endLine < 4 {
-		t.Fatalf("expected end line >= 4, got %d", endLine)
+	// The function should find the closing brace of the outer block
+	if endLine != 5 {
+		t.Fatalf("expected end line 5, got %d", endLine)
 	}
 }
 
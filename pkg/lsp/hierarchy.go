@@ -0,0 +1,278 @@
+package lsp
+
+import (
+	"context"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// HierarchyItem is an element in a call or type hierarchy, which share their
+// item shape. Data carries the element's fully qualified ID between requests.
+type HierarchyItem struct {
+	Name           string          `json:"name"`
+	Kind           lsp.SymbolKind  `json:"kind"`
+	Detail         string          `json:"detail,omitempty"`
+	URI            lsp.DocumentURI `json:"uri"`
+	Range          lsp.Range       `json:"range"`
+	SelectionRange lsp.Range       `json:"selectionRange"`
+	Data           string          `json:"data,omitempty"`
+}
+
+// HierarchyItemParams are the parameters of the requests walking a call or
+// type hierarchy from an item.
+type HierarchyItemParams struct {
+	Item HierarchyItem `json:"item"`
+}
+
+// CallHierarchyIncomingCall is an element with relations to the requested one,
+// and the ranges of those relations in the document of the element.
+type CallHierarchyIncomingCall struct {
+	From       HierarchyItem `json:"from"`
+	FromRanges []lsp.Range   `json:"fromRanges"`
+}
+
+// CallHierarchyOutgoingCall is an element the requested one has relations to,
+// and the ranges of those relations in the document of the requested element.
+type CallHierarchyOutgoingCall struct {
+	To         HierarchyItem `json:"to"`
+	FromRanges []lsp.Range   `json:"fromRanges"`
+}
+
+// PrepareCallHierarchy returns the element named under the cursor, the root of
+// its incoming and outgoing relations.
+func (s *Server) PrepareCallHierarchy(_ context.Context, params lsp.TextDocumentPositionParams) ([]HierarchyItem, error) {
+	return s.prepareHierarchy(params)
+}
+
+// IncomingCalls returns the elements with relations to the item, across the
+// workspace root of its document.
+func (s *Server) IncomingCalls(_ context.Context, params HierarchyItemParams) ([]CallHierarchyIncomingCall, error) {
+	g := s.hierarchyGraph(params.Item.URI)
+	if g == nil {
+		return nil, nil
+	}
+	calls := []CallHierarchyIncomingCall{}
+	index := map[string]int{}
+	for _, e := range g.edges {
+		if e.to != params.Item.Data {
+			continue
+		}
+		i, ok := index[e.from]
+		if !ok {
+			i = len(calls)
+			index[e.from] = i
+			calls = append(calls, CallHierarchyIncomingCall{From: g.item(e.from), FromRanges: []lsp.Range{}})
+		}
+		if r, ok := g.relationRange(e.rel, e.from); ok {
+			calls[i].FromRanges = append(calls[i].FromRanges, r)
+		}
+	}
+	return calls, nil
+}
+
+// OutgoingCalls returns the elements the item has relations to, across the
+// workspace root of its document.
+func (s *Server) OutgoingCalls(_ context.Context, params HierarchyItemParams) ([]CallHierarchyOutgoingCall, error) {
+	g := s.hierarchyGraph(params.Item.URI)
+	if g == nil {
+		return nil, nil
+	}
+	calls := []CallHierarchyOutgoingCall{}
+	index := map[string]int{}
+	for _, e := range g.edges {
+		if e.from != params.Item.Data {
+			continue
+		}
+		i, ok := index[e.to]
+		if !ok {
+			i = len(calls)
+			index[e.to] = i
+			calls = append(calls, CallHierarchyOutgoingCall{To: g.item(e.to), FromRanges: []lsp.Range{}})
+		}
+		if r, ok := g.relationRange(e.rel, e.from); ok {
+			calls[i].FromRanges = append(calls[i].FromRanges, r)
+		}
+	}
+	return calls, nil
+}
+
+// PrepareTypeHierarchy returns the element named under the cursor, the root of
+// its containment tree.
+func (s *Server) PrepareTypeHierarchy(_ context.Context, params lsp.TextDocumentPositionParams) ([]HierarchyItem, error) {
+	return s.prepareHierarchy(params)
+}
+
+// Supertypes returns the element containing the item, if any.
+func (s *Server) Supertypes(_ context.Context, params HierarchyItemParams) ([]HierarchyItem, error) {
+	g := s.hierarchyGraph(params.Item.URI)
+	if g == nil {
+		return nil, nil
+	}
+	items := []HierarchyItem{}
+	if parent := parentFQN(params.Item.Data); g.defs[parent] != nil {
+		items = append(items, g.item(parent))
+	}
+	return items, nil
+}
+
+// Subtypes returns the elements nested in the item.
+func (s *Server) Subtypes(_ context.Context, params HierarchyItemParams) ([]HierarchyItem, error) {
+	g := s.hierarchyGraph(params.Item.URI)
+	if g == nil {
+		return nil, nil
+	}
+	items := []HierarchyItem{}
+	for _, child := range g.children[params.Item.Data] {
+		items = append(items, g.item(child))
+	}
+	return items, nil
+}
+
+func (s *Server) prepareHierarchy(params lsp.TextDocumentPositionParams) ([]HierarchyItem, error) {
+	doc := s.workspace.GetDocument(params.TextDocument.URI)
+	if doc == nil {
+		return nil, nil
+	}
+	o := s.workspace.indexSymbols(doc).occurrenceAt(doc, params.Position)
+	if o == nil {
+		return nil, nil
+	}
+	g := s.hierarchyGraph(doc.URI)
+	if g == nil || g.defs[o.fqn] == nil {
+		return nil, nil
+	}
+	return []HierarchyItem{g.item(o.fqn)}, nil
+}
+
+// elementGraph is the containment tree of the elements of a workspace root and
+// the relations between them, as resolved by the engine.
+type elementGraph struct {
+	files    map[string]docSnapshot // file name of positions -> snapshot
+	lines    map[string]positionIndex
+	defs     map[string]*language.ElementDef // FQN -> definition
+	children map[string][]string
+	edges    []relationEdge
+}
+
+// relationEdge is a relation between two elements, in the direction of the
+// dependency: bidirectional relations give an edge each way.
+type relationEdge struct {
+	from, to string
+	rel      *language.Relation
+}
+
+// hierarchyGraph builds the element graph of the workspace root of the
+// document at uri, or of the document alone outside any root.
+func (s *Server) hierarchyGraph(uri lsp.DocumentURI) *elementGraph {
+	doc := s.workspace.documentAt(uri)
+	if doc == nil {
+		return nil
+	}
+	prog, files := s.resolveRoot(doc)
+	g := &elementGraph{
+		files:    files,
+		lines:    map[string]positionIndex{},
+		defs:     map[string]*language.ElementDef{},
+		children: map[string][]string{},
+	}
+	if prog.Model == nil {
+		return g
+	}
+	fqns := map[*language.ElementDef]string{}
+	var rels []*language.Relation
+	var walk func(e *language.ElementDef, parent string)
+	walk = func(e *language.ElementDef, parent string) {
+		id := e.GetID()
+		if id == "" || governanceKinds[e.GetKind()] {
+			return
+		}
+		fqn := id
+		if parent != "" {
+			fqn = parent + "." + id
+			g.children[parent] = append(g.children[parent], fqn)
+		}
+		g.defs[fqn] = e
+		fqns[e] = fqn
+		if body := e.GetBody(); body != nil {
+			for _, item := range body.Items {
+				switch {
+				case item.Element != nil:
+					walk(item.Element, fqn)
+				case item.Relation != nil:
+					rels = append(rels, item.Relation)
+				}
+			}
+		}
+	}
+	for _, item := range prog.Model.Items {
+		switch {
+		case item.ElementDef != nil:
+			walk(item.ElementDef, "")
+		case item.Relation != nil:
+			rels = append(rels, item.Relation)
+		}
+	}
+
+	for _, rel := range rels {
+		// Relations implied by nested ones are not written in any document.
+		if rel.Implied {
+			continue
+		}
+		from, _ := rel.ResolvedFrom.(*language.ElementDef)
+		to, _ := rel.ResolvedTo.(*language.ElementDef)
+		if fqns[from] == "" || fqns[to] == "" {
+			continue
+		}
+		g.edges = append(g.edges, relationEdge{from: fqns[from], to: fqns[to], rel: rel})
+		if rel.Bidirectional {
+			g.edges = append(g.edges, relationEdge{from: fqns[to], to: fqns[from], rel: rel})
+		}
+	}
+	return g
+}
+
+// item returns the hierarchy item of a defined element: its range spans the
+// whole definition and its selection range the name.
+func (g *elementGraph) item(fqn string) HierarchyItem {
+	e := g.defs[fqn]
+	item := HierarchyItem{
+		Name:   e.GetID(),
+		Kind:   symbolKind(e.GetKind()),
+		Detail: fqn,
+		Data:   fqn,
+	}
+	snap, ok := g.files[e.Pos.Filename]
+	if !ok {
+		return item
+	}
+	item.URI = snap.uri
+	pos := g.positions(e.Pos.Filename)
+	start := pos.at(e.Pos.Offset)
+	item.Range = lsp.Range{Start: start, End: pos.at(elementEnd(snap.text, e))}
+	item.SelectionRange = lsp.Range{Start: start, End: lsp.Position{Line: start.Line, Character: start.Character + len(e.GetID())}}
+	return item
+}
+
+// relationRange returns the range of a relation statement if it is written in
+// the same document as the definition of fqn.
+func (g *elementGraph) relationRange(rel *language.Relation, fqn string) (lsp.Range, bool) {
+	if rel.Pos.Filename != g.defs[fqn].Pos.Filename {
+		return lsp.Range{}, false
+	}
+	snap, ok := g.files[rel.Pos.Filename]
+	if !ok {
+		return lsp.Range{}, false
+	}
+	pos := g.positions(rel.Pos.Filename)
+	return lsp.Range{Start: pos.at(rel.Pos.Offset), End: pos.at(headerEnd(snap.text, rel.Pos.Offset))}, true
+}
+
+func (g *elementGraph) positions(file string) positionIndex {
+	pos, ok := g.lines[file]
+	if !ok {
+		pos = newPositionIndex(g.files[file].text)
+		g.lines[file] = pos
+	}
+	return pos
+}
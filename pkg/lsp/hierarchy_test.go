@@ -0,0 +1,172 @@
+package lsp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+// hierarchyServer opens systems.sruja of a root that also holds uses.sruja.
+// API is defined in two systems, so only its scope resolves it.
+func hierarchyServer(t *testing.T) (*Server, lsp.DocumentURI, lsp.DocumentURI) {
+	t.Helper()
+	root := t.TempDir()
+	systemsText := `Shop = system "Shop" {
+  API = container "API"
+  DB = database "DB"
+  API -> DB "reads"
+}
+Billing = system "Billing" {
+  API = container "API"
+  API -> Shop.API "charges"
+}
+`
+	writeIndexFile(t, filepath.Join(root, "systems.sruja"), systemsText)
+	writeIndexFile(t, filepath.Join(root, "uses.sruja"), `User = person "User"
+User -> Shop.API "uses"
+`)
+	srv := NewServer()
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(root)}); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	systems := pathToURI(filepath.Join(root, "systems.sruja"))
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: systems, Text: systemsText, Version: 1}})
+	return srv, systems, pathToURI(filepath.Join(root, "uses.sruja"))
+}
+
+func lineRange(line, start, end int) lsp.Range {
+	return lsp.Range{Start: lsp.Position{Line: line, Character: start}, End: lsp.Position{Line: line, Character: end}}
+}
+
+func TestCallHierarchy_WalksRelations(t *testing.T) {
+	srv, systems, uses := hierarchyServer(t)
+	ctx := context.Background()
+
+	items, err := srv.PrepareCallHierarchy(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: systems},
+		Position:     lsp.Position{Line: 3, Character: 3},
+	})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("prepare = %+v, %v", items, err)
+	}
+	api := items[0]
+	if api.Data != "Shop.API" || api.Name != "API" || api.Kind != lsp.SKModule || api.URI != systems {
+		t.Fatalf("unexpected item %+v", api)
+	}
+	if api.SelectionRange != lineRange(1, 2, 5) || api.Range != lineRange(1, 2, 23) {
+		t.Errorf("item ranges %+v, %+v", api.Range, api.SelectionRange)
+	}
+
+	incoming, err := srv.IncomingCalls(ctx, HierarchyItemParams{Item: api})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	callers := map[string][]lsp.Range{}
+	for _, c := range incoming {
+		callers[c.From.Data] = c.FromRanges
+	}
+	if len(callers) != 2 {
+		t.Fatalf("incoming = %+v", incoming)
+	}
+	// Ranges are in the document of each caller
+	if r := callers["Billing.API"]; len(r) != 1 || r[0] != lineRange(7, 2, 27) {
+		t.Errorf("Billing.API ranges = %+v", r)
+	}
+	if r := callers["User"]; len(r) != 1 || r[0] != lineRange(1, 0, 23) {
+		t.Errorf("User ranges = %+v", r)
+	}
+
+	outgoing, err := srv.OutgoingCalls(ctx, HierarchyItemParams{Item: api})
+	if err != nil || len(outgoing) != 1 {
+		t.Fatalf("outgoing = %+v, %v", outgoing, err)
+	}
+	if outgoing[0].To.Data != "Shop.DB" || len(outgoing[0].FromRanges) != 1 || outgoing[0].FromRanges[0] != lineRange(3, 2, 19) {
+		t.Errorf("unexpected outgoing call %+v", outgoing[0])
+	}
+
+	// Items of files that are not open can be walked further
+	var user HierarchyItem
+	for _, c := range incoming {
+		if c.From.Data == "User" {
+			user = c.From
+		}
+	}
+	if user.URI != uses {
+		t.Fatalf("User item in %s", user.URI)
+	}
+	outgoing, err = srv.OutgoingCalls(ctx, HierarchyItemParams{Item: user})
+	if err != nil || len(outgoing) != 1 || outgoing[0].To.Data != "Shop.API" {
+		t.Fatalf("User outgoing = %+v, %v", outgoing, err)
+	}
+}
+
+func TestCallHierarchy_BidirectionalRelations(t *testing.T) {
+	uri := pathToURI(filepath.Join(t.TempDir(), "sync.sruja"))
+	text := `Cache = database "Cache"
+Store = database "Store"
+Cache <-> Store "syncs"
+`
+	srv := NewServer()
+	ctx := context.Background()
+	_ = srv.DidOpen(ctx, lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1}})
+	items, err := srv.PrepareCallHierarchy(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Position:     lsp.Position{Line: 1, Character: 1},
+	})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("prepare = %+v, %v", items, err)
+	}
+	incoming, _ := srv.IncomingCalls(ctx, HierarchyItemParams{Item: items[0]})
+	outgoing, _ := srv.OutgoingCalls(ctx, HierarchyItemParams{Item: items[0]})
+	if len(incoming) != 1 || incoming[0].From.Data != "Cache" || len(outgoing) != 1 || outgoing[0].To.Data != "Cache" {
+		t.Fatalf("incoming = %+v, outgoing = %+v", incoming, outgoing)
+	}
+}
+
+func TestCallHierarchy_SkipsImpliedRelations(t *testing.T) {
+	srv, systems, _ := hierarchyServer(t)
+	ctx := context.Background()
+	items, err := srv.PrepareCallHierarchy(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: systems},
+		Position:     lsp.Position{Line: 0, Character: 1},
+	})
+	if err != nil || len(items) != 1 || items[0].Data != "Shop" {
+		t.Fatalf("prepare = %+v, %v", items, err)
+	}
+	// Billing -> Shop and User -> Shop are only implied by relations to Shop.API
+	incoming, err := srv.IncomingCalls(ctx, HierarchyItemParams{Item: items[0]})
+	if err != nil || len(incoming) != 0 {
+		t.Fatalf("incoming = %+v, %v", incoming, err)
+	}
+}
+
+func TestTypeHierarchy_WalksContainment(t *testing.T) {
+	srv, systems, _ := hierarchyServer(t)
+	ctx := context.Background()
+
+	items, err := srv.PrepareTypeHierarchy(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: systems},
+		Position:     lsp.Position{Line: 0, Character: 2},
+	})
+	if err != nil || len(items) != 1 || items[0].Data != "Shop" {
+		t.Fatalf("prepare = %+v, %v", items, err)
+	}
+	if items[0].Range != (lsp.Range{Start: lsp.Position{Line: 0}, End: lsp.Position{Line: 4, Character: 1}}) {
+		t.Errorf("Shop range %+v", items[0].Range)
+	}
+
+	subtypes, err := srv.Subtypes(ctx, HierarchyItemParams{Item: items[0]})
+	if err != nil || len(subtypes) != 2 || subtypes[0].Data != "Shop.API" || subtypes[1].Data != "Shop.DB" {
+		t.Fatalf("subtypes = %+v, %v", subtypes, err)
+	}
+	supertypes, err := srv.Supertypes(ctx, HierarchyItemParams{Item: subtypes[1]})
+	if err != nil || len(supertypes) != 1 || supertypes[0].Data != "Shop" {
+		t.Fatalf("supertypes = %+v, %v", supertypes, err)
+	}
+	supertypes, _ = srv.Supertypes(ctx, HierarchyItemParams{Item: items[0]})
+	if len(supertypes) != 0 {
+		t.Errorf("top-level supertypes = %+v", supertypes)
+	}
+}
@@ -0,0 +1,154 @@
+package lsp
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// parseCache parses a document one top-level block at a time and keeps the
+// parsed items of each block, so an edit only reparses the blocks it touches.
+// It is safe for concurrent use.
+type parseCache struct {
+	mu     sync.Mutex
+	blocks map[string]*parsedBlock // keyed by block text
+}
+
+// parsedBlock holds the items and syntax errors of a block as parsed, before
+// post-processing and resolution. They are never handed out, only copies moved
+// to where the block currently is.
+type parsedBlock struct {
+	items  []language.TopLevelItem
+	diags  []diagnostics.Diagnostic
+	err    error
+	offset int
+	line   int
+}
+
+func newParseCache() *parseCache {
+	return &parseCache{blocks: make(map[string]*parsedBlock)}
+}
+
+// parse returns a post-processed program for text. The program is not shared,
+// so callers may resolve it in place. Blocks with syntax errors are parsed as
+// by language.Parser.ParsePartial, so the program holds everything that parsed
+// and the diagnostics report every syntax error; err is the first of them.
+func (c *parseCache) parse(filename, text string) (*language.Program, []diagnostics.Diagnostic, error) {
+	p, err := language.DefaultParser()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	blocks := language.SplitTopLevel(text)
+	next := make(map[string]*parsedBlock, len(blocks))
+	var items []language.TopLevelItem
+	var diags []diagnostics.Diagnostic
+	var firstErr error
+	for _, b := range blocks {
+		pb := next[b.Text]
+		if pb == nil {
+			pb = c.blocks[b.Text]
+		}
+		if pb == nil {
+			// Leading newlines put the block's items on their lines; offsets are
+			// moved when the items are copied
+			parsed, blockDiags, err := p.ParseItemsPartial(filename, strings.Repeat("\n", b.Line-1)+b.Text)
+			pb = &parsedBlock{items: parsed, diags: blockDiags, err: err, offset: b.Line - 1, line: b.Line}
+		}
+		next[b.Text] = pb
+		items = append(items, cloneItems(pb.items, filename, b.Offset-pb.offset, b.Line-pb.line)...)
+		for _, d := range pb.diags {
+			d.Location.File = filename
+			d.Location.Line += b.Line - pb.line
+			diags = append(diags, d)
+		}
+		if firstErr == nil {
+			firstErr = pb.err
+		}
+	}
+	c.blocks = next
+
+	prog := &language.Program{Items: items, Suppressions: p.ScanSuppressions(filename, text)}
+	prog.PostProcess()
+	return prog, diags, firstErr
+}
+
+var positionType = reflect.TypeOf(lexer.Position{})
+
+// cloneItems deep-copies parsed items, moving their positions by the given
+// offset and line deltas and into filename. Columns are unchanged since blocks
+// start at the beginning of a line.
+func cloneItems(items []language.TopLevelItem, filename string, dOffset, dLine int) []language.TopLevelItem {
+	c := &astCloner{filename: filename, dOffset: dOffset, dLine: dLine, seen: make(map[uintptr]reflect.Value)}
+	return c.clone(reflect.ValueOf(items)).Interface().([]language.TopLevelItem)
+}
+
+type astCloner struct {
+	filename       string
+	dOffset, dLine int
+	seen           map[uintptr]reflect.Value // preserves pointers shared within the AST
+}
+
+func (c *astCloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		if copied, ok := c.seen[v.Pointer()]; ok {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		c.seen[v.Pointer()] = copied
+		copied.Elem().Set(c.clone(v.Elem()))
+		return copied
+	case reflect.Struct:
+		if v.Type() == positionType {
+			pos := v.Interface().(lexer.Position)
+			pos.Filename = c.filename
+			pos.Offset += c.dOffset
+			pos.Line += c.dLine
+			return reflect.ValueOf(pos)
+		}
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			copied.Field(i).Set(c.clone(v.Field(i)))
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), c.clone(iter.Value()))
+		}
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(c.clone(v.Elem()))
+		return copied
+	default:
+		return v
+	}
+}
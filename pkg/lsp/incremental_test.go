@@ -0,0 +1,215 @@
+package lsp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+const incrementalText = `// Shop architecture
+Shop = system "Shop" {
+  description "Sells things
+across lines"
+  API = container "API" {
+    tags ["web",
+      "public"]
+  }
+  /* a } brace in a comment */
+  // and another }
+  DB = database "DB"
+}
+
+User = person "User"
+User ->
+  Shop.API "uses"
+view main {
+  include *
+}
+`
+
+func fullParse(t *testing.T, text string) *language.Program {
+	t.Helper()
+	p, err := language.NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog, _, err := p.Parse("file.sruja", text)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	return prog
+}
+
+func TestParseCache_MatchesFullParse(t *testing.T) {
+	c := newParseCache()
+	prog, diags, err := c.parse("file.sruja", incrementalText)
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("parse error: %v %v", err, diags)
+	}
+	if !reflect.DeepEqual(prog, fullParse(t, incrementalText)) {
+		t.Fatal("expected the block parse to equal a full parse")
+	}
+
+	// Editing one block reuses the others, moved to their new positions
+	comment := c.blocks[language.SplitTopLevel(incrementalText)[0].Text]
+	shop := c.blocks[language.SplitTopLevel(incrementalText)[1].Text]
+	edited := strings.Replace(incrementalText, `User = person "User"`, "Admin = person \"Admin\"\nUser = person \"User\"", 1)
+	edited = strings.Replace(edited, `"Sells things`, `"Sells many things`, 1)
+	prog, _, err = c.parse("file.sruja", edited)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !reflect.DeepEqual(prog, fullParse(t, edited)) {
+		t.Fatal("expected the incremental parse to equal a full parse")
+	}
+	blocks := language.SplitTopLevel(edited)
+	if c.blocks[blocks[0].Text] != comment {
+		t.Error("expected the unchanged block to be reused")
+	}
+	if c.blocks[blocks[1].Text] == shop {
+		t.Error("expected the edited block to be reparsed")
+	}
+	if len(c.blocks) != len(blocks) {
+		t.Errorf("expected %d cached blocks, got %d", len(blocks), len(c.blocks))
+	}
+}
+
+func TestParseCache_ProgramsAreNotShared(t *testing.T) {
+	c := newParseCache()
+	text := "Shop = system \"Shop\" {\n  API = container \"API\"\n}\nUser = person \"User\"\nUser -> API \"uses\"\n"
+	first, _, _ := c.parse("file.sruja", text)
+	for _, item := range first.Model.Items {
+		if item.Relation != nil {
+			item.Relation.To.Parts = []string{"Shop", "API"} // as resolution does
+		}
+	}
+	second, _, _ := c.parse("other.sruja", text)
+	for _, item := range second.Items {
+		if item.Relation != nil && item.Relation.To.String() != "API" {
+			t.Errorf("expected the cached relation to be unchanged, got %s", item.Relation.To.String())
+		}
+		if item.ElementDef != nil && item.ElementDef.Pos.Filename != "other.sruja" {
+			t.Errorf("expected positions in other.sruja, got %s", item.ElementDef.Pos.Filename)
+		}
+	}
+}
+
+func TestParseCache_SyntaxErrorsKeepOtherBlocks(t *testing.T) {
+	c := newParseCache()
+	text := "Shop = system \"Shop\" {\n  API = container \"API\" {\n    technology\n  }\n}\nUser = person \"User\"\nUser -> \n"
+	prog, diags, err := c.parse("file.sruja", text)
+	if err == nil || prog == nil {
+		t.Fatalf("expected a partial program and an error, got %v %v", prog, err)
+	}
+	lines := map[int]bool{}
+	for _, d := range diags {
+		lines[d.Location.Line] = true
+	}
+	if !lines[4] || !lines[8] {
+		t.Errorf("expected errors on lines 4 and 8, got %+v", diags)
+	}
+	if prog.Model == nil || len(prog.Model.Items) != 2 {
+		t.Fatalf("expected Shop and User, got %+v", prog.Model)
+	}
+	if body := prog.Model.Items[0].ElementDef.GetBody(); body == nil || len(body.Items) != 1 || body.Items[0].Element == nil {
+		t.Error("expected Shop to keep API")
+	}
+
+	// Moving the broken blocks moves their diagnostics
+	_, moved, _ := c.parse("file.sruja", "\n"+text)
+	if len(moved) != len(diags) || moved[0].Location.Line != diags[0].Location.Line+1 {
+		t.Errorf("expected diagnostics moved one line down, got %+v", moved)
+	}
+}
+
+// largeModel generates a model of about lines lines: systems with containers and
+// relations between them, as in a large real-world workspace.
+func largeModel(lines int) string {
+	var sb strings.Builder
+	for i := 0; sb.Len() == 0 || strings.Count(sb.String(), "\n") < lines; i++ {
+		fmt.Fprintf(&sb, "S%d = system \"System %d\" {\n  description \"System number %d\"\n", i, i, i)
+		for j := 0; j < 5; j++ {
+			fmt.Fprintf(&sb, "  C%d = container \"Container %d\" {\n    technology \"Go\"\n  }\n", j, j)
+		}
+		sb.WriteString("  C0 -> C1 \"calls\"\n}\n")
+		if i > 0 {
+			fmt.Fprintf(&sb, "S%d.C0 -> S%d.C1 \"uses\"\n", i, i-1)
+		}
+	}
+	return sb.String()
+}
+
+// BenchmarkReparse_LargeModel measures reparsing a 5000-line model after an
+// edit to one of its systems, in full and by blocks.
+func BenchmarkReparse_LargeModel(b *testing.B) {
+	text := largeModel(5000)
+	edited := strings.Replace(text, `"System number 100"`, `"System number one hundred"`, 1)
+
+	b.Run("FullParse", func(b *testing.B) {
+		p, err := language.DefaultParser()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := p.Parse("large.sruja", edited); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Incremental", func(b *testing.B) {
+		c := newParseCache()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			if _, _, err := c.parse("large.sruja", text); err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+			if _, _, err := c.parse("large.sruja", edited); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkDidChange_LargeModel measures the editor-facing latency of a
+// keystroke in a 5000-line model: applying the change and re-parsing and
+// resolving the document, with validation debounced.
+func BenchmarkDidChange_LargeModel(b *testing.B) {
+	text := largeModel(5000)
+	srv := NewServer()
+	uri := lsp.DocumentURI("file:///large.sruja")
+	srv.workspace.AddDocument(uri, text, 1)
+	doc := srv.workspace.GetDocument(uri)
+	doc.EnsureParsed()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		doc.SetText(strings.Replace(text, `"System number 100"`, fmt.Sprintf(`"System number %d"`, i), 1))
+		if doc.EnsureParsed() == nil {
+			b.Fatal("expected a program")
+		}
+	}
+}
+
+func TestParseCache_Suppressions(t *testing.T) {
+	text := "Shop = system \"Shop\" {\n  // sruja:ignore E205\n  API = container \"API\"\n}\n"
+	c := newParseCache()
+	prog, _, err := c.parse("file.sruja", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prog.Suppressions, fullParse(t, text).Suppressions) {
+		t.Errorf("Suppressions = %+v, want those of a full parse", prog.Suppressions)
+	}
+	if len(prog.Suppressions) != 1 || prog.Suppressions[0].Pos.Line != 2 {
+		t.Errorf("Suppressions = %+v", prog.Suppressions)
+	}
+}
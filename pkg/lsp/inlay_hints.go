@@ -0,0 +1,180 @@
+package lsp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// InlayHintParams represents the parameters of the inlay hint request, which
+// go-lsp predates.
+type InlayHintParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+	Range        lsp.Range                  `json:"range"`
+}
+
+// InlayHint is a label shown inline in the document.
+type InlayHint struct {
+	Position    lsp.Position  `json:"position"`
+	Label       string        `json:"label"`
+	Kind        InlayHintKind `json:"kind,omitempty"`
+	Tooltip     string        `json:"tooltip,omitempty"`
+	PaddingLeft bool          `json:"paddingLeft,omitempty"`
+}
+
+// InlayHintKind is the kind of an inlay hint.
+type InlayHintKind int
+
+// InlayHintKind constants
+const (
+	InlayHintKindType      InlayHintKind = 1
+	InlayHintKindParameter InlayHintKind = 2
+)
+
+// InlayHints shows the fully qualified IDs that short relation endpoints
+// resolve to, and the technology elements inherit from their nearest ancestor
+// that has one.
+func (s *Server) InlayHints(_ context.Context, params InlayHintParams) ([]InlayHint, error) {
+	doc := s.workspace.GetDocument(params.TextDocument.URI)
+	if doc == nil {
+		return nil, nil
+	}
+	pos := newPositionIndex(doc.Text)
+
+	hints := []InlayHint{}
+	seen := make(map[int]bool)
+	for _, ref := range s.workspace.indexSymbols(doc).refs {
+		end := ref.token.end()
+		if ref.doc != doc || !ref.endpoint || seen[end] || strings.Join(ref.token.parts, ".") == ref.fqn {
+			continue
+		}
+		seen[end] = true
+		hints = append(hints, InlayHint{
+			Position: pos.at(end),
+			Label:    ": " + ref.fqn,
+			Kind:     InlayHintKindType,
+		})
+	}
+
+	if prog, _ := doc.parse(); prog != nil {
+		for _, item := range prog.Items {
+			if item.ElementDef != nil {
+				hints = technologyHints(hints, doc.Text, pos, item.ElementDef, "", "", "")
+			}
+		}
+	}
+
+	// An unset range, as from older clients, covers the whole document
+	if params.Range != (lsp.Range{}) {
+		inRange := hints[:0]
+		for _, h := range hints {
+			if !positionBefore(h.Position, params.Range.Start) && !positionBefore(params.Range.End, h.Position) {
+				inRange = append(inRange, h)
+			}
+		}
+		hints = inRange
+	}
+	sort.SliceStable(hints, func(i, j int) bool { return positionBefore(hints[i].Position, hints[j].Position) })
+	return hints, nil
+}
+
+// technologyHints adds a hint after the header of each element without a
+// technology of its own, naming the technology it inherits and where from.
+func technologyHints(hints []InlayHint, text string, pos positionIndex, e *language.ElementDef, parent, inherited, from string) []InlayHint {
+	id := e.GetID()
+	if id == "" {
+		return hints
+	}
+	fqn := id
+	if parent != "" {
+		fqn = parent + "." + id
+	}
+	own := elementTechnology(e)
+	switch {
+	case own != "":
+		inherited, from = own, fqn
+	case inherited != "":
+		hints = append(hints, InlayHint{
+			Position:    pos.at(headerEnd(text, e.Pos.Offset)),
+			Label:       "technology: " + inherited,
+			Kind:        InlayHintKindType,
+			Tooltip:     "Inherited from " + from,
+			PaddingLeft: true,
+		})
+	}
+	if body := e.GetBody(); body != nil {
+		for _, item := range body.Items {
+			if item.Element != nil {
+				hints = technologyHints(hints, text, pos, item.Element, fqn, inherited, from)
+			}
+		}
+	}
+	return hints
+}
+
+func elementTechnology(e *language.ElementDef) string {
+	if body := e.GetBody(); body != nil {
+		for _, item := range body.Items {
+			if item.Technology != nil {
+				return *item.Technology
+			}
+		}
+	}
+	return ""
+}
+
+// headerEnd returns the offset after the last token of the element header
+// starting at offset, before its body and any trailing comment.
+func headerEnd(text string, offset int) int {
+	end := offset
+	for i := offset; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '{' || c == '\n' || strings.HasPrefix(text[i:], "//") || strings.HasPrefix(text[i:], "/*"):
+			return end
+		case c == '"' || c == '\'':
+			for i++; i < len(text) && text[i] != c; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+			}
+			end = i + 1
+		case c != ' ' && c != '\t' && c != '\r':
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// positionIndex converts byte offsets of a text to positions, whose
+// characters count UTF-16 code units as LSP requires.
+type positionIndex struct {
+	text   string
+	starts []int // offsets of line starts
+}
+
+func newPositionIndex(text string) positionIndex {
+	starts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return positionIndex{text: text, starts: starts}
+}
+
+func (p positionIndex) at(offset int) lsp.Position {
+	line := sort.SearchInts(p.starts, offset+1) - 1
+	character := 0
+	for _, r := range p.text[p.starts[line]:min(offset, len(p.text))] {
+		character += utf16.RuneLen(r)
+	}
+	return lsp.Position{Line: line, Character: character}
+}
+
+func positionBefore(a, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
@@ -0,0 +1,73 @@
+package lsp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+func TestInlayHints_EndpointsAndTechnology(t *testing.T) {
+	uri := lsp.DocumentURI("file://hints.sruja")
+	text := `Shop = system "Shop" {
+  technology "Go"
+  API = container "API"
+  DB = database "DB" {
+    technology "Postgres"
+  }
+  API -> DB "reads"
+}
+User = person "User"
+User -> Shop.API "uses"
+`
+	srv := NewServer()
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1}})
+	hints, err := srv.InlayHints(context.Background(), InlayHintParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []InlayHint{
+		{Position: lsp.Position{Line: 2, Character: 23}, Label: "technology: Go", Kind: InlayHintKindType, Tooltip: "Inherited from Shop", PaddingLeft: true},
+		{Position: lsp.Position{Line: 6, Character: 5}, Label: ": Shop.API", Kind: InlayHintKindType},
+		{Position: lsp.Position{Line: 6, Character: 11}, Label: ": Shop.DB", Kind: InlayHintKindType},
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("got %d hints, want %d: %+v", len(hints), len(want), hints)
+	}
+	for i := range want {
+		if hints[i] != want[i] {
+			t.Errorf("hint %d = %+v, want %+v", i, hints[i], want[i])
+		}
+	}
+
+	// Only the hints within the requested range
+	hints, _ = srv.InlayHints(context.Background(), InlayHintParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Range:        lsp.Range{Start: lsp.Position{Line: 6}, End: lsp.Position{Line: 7}},
+	})
+	if len(hints) != 2 || hints[0].Label != ": Shop.API" {
+		t.Fatalf("ranged hints = %+v", hints)
+	}
+}
+
+func TestInlayHints_MultiByteLabel(t *testing.T) {
+	uri := lsp.DocumentURI("file://hints-utf16.sruja")
+	text := `Shop = system "Shop" {
+  technology "Go"
+  API = container "Bezahlung für 🛒"
+}
+`
+	srv := NewServer()
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1}})
+	hints, err := srv.InlayHints(context.Background(), InlayHintParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Characters count UTF-16 code units: "ü" is one, "🛒" two.
+	want := lsp.Position{Line: 2, Character: 36}
+	if len(hints) != 1 || hints[0].Position != want {
+		t.Fatalf("hints = %+v, want one at %+v", hints, want)
+	}
+}
@@ -17,6 +17,20 @@ func (s *Server) References(_ context.Context, params lsp.ReferenceParams) ([]ls
 	if params.Position.Character > len(line) {
 		return nil, nil
 	}
+
+	// Elements are found by what their references resolve to, so A.API and
+	// B.API are told apart
+	idx := s.workspace.indexSymbols(doc)
+	if occ := idx.occurrenceAt(doc, params.Position); occ != nil {
+		occs := idx.occurrencesOf(occ.fqn, params.Context.IncludeDeclaration)
+		locs := make([]lsp.Location, 0, len(occs))
+		for _, o := range occs {
+			locs = append(locs, o.location())
+		}
+		return locs, nil
+	}
+
+	// Fall back to matching the token in documents that do not parse
 	start, end := wordBounds(line, params.Position.Character)
 	token := line[start:end]
 	if token == "" {
@@ -3,6 +3,9 @@ package lsp
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/sourcegraph/go-lsp"
 )
@@ -21,6 +24,10 @@ func (s *Server) Rename(_ context.Context, params lsp.RenameParams) (*lsp.Worksp
 		return nil, nil
 	}
 
+	if edit, ok, err := s.renameSymbol(doc, params); ok {
+		return edit, err
+	}
+
 	// Extract the identifier at the cursor position
 	identifier := extractIdentifier(line, pos.Character)
 	if identifier == "" {
@@ -68,6 +75,62 @@ func (s *Server) Rename(_ context.Context, params lsp.RenameParams) (*lsp.Worksp
 	}, nil
 }
 
+// renameSymbol renames the element under the cursor: its definition and the
+// segment naming it in every reference, across the documents it is resolved
+// against. It reports false when the cursor is not on a known element.
+func (s *Server) renameSymbol(doc *Document, params lsp.RenameParams) (*lsp.WorkspaceEdit, bool, error) {
+	idx := s.workspace.indexSymbols(doc)
+	occ := idx.occurrenceAt(doc, params.Position)
+	if occ == nil {
+		return nil, false, nil
+	}
+	newName := params.NewName
+	if !isValidIdentifier(newName) {
+		return nil, true, fmt.Errorf("%q is not a valid identifier", newName)
+	}
+	if newName == lastSegment(occ.fqn) {
+		return &lsp.WorkspaceEdit{Changes: map[string][]lsp.TextEdit{}}, true, nil
+	}
+	if err := idx.checkRename(occ.fqn, newName); err != nil {
+		return nil, true, err
+	}
+
+	changes := make(map[string][]lsp.TextEdit)
+	for _, o := range idx.occurrencesOf(occ.fqn, true) {
+		uri := string(o.doc.URI)
+		changes[uri] = append(changes[uri], lsp.TextEdit{
+			Range:   offsetToRange(o.doc.Text, o.offset, o.length),
+			NewText: newName,
+		})
+	}
+	return &lsp.WorkspaceEdit{Changes: changes}, true, nil
+}
+
+// checkRename rejects renaming fqn to newName when an element of that name
+// already exists in the same scope, or when references resolved by their last
+// segment alone would become ambiguous.
+func (x *symbolIndex) checkRename(fqn, newName string) error {
+	target := newName
+	if parent := parentFQN(fqn); parent != "" {
+		target = parent + "." + newName
+	}
+	if _, exists := x.defs[target]; exists {
+		return fmt.Errorf("cannot rename %s: %s is already defined", fqn, target)
+	}
+	others := x.suffixes[newName]
+	if len(others) == 0 {
+		return nil
+	}
+	for _, o := range x.occurrences {
+		if o.bySuffix && (o.fqn == fqn || lastSegment(o.fqn) == newName) {
+			names := append([]string{fqn}, others...)
+			sort.Strings(names)
+			return fmt.Errorf("cannot rename %s to %s: references by name alone would be ambiguous between %s", fqn, newName, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
 // extractIdentifier extracts the identifier at the given column position
 func extractIdentifier(line string, col int) string {
 	if col >= len(line) {
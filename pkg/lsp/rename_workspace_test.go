@@ -0,0 +1,180 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+const (
+	renameSystemsText = `Shop = system "Shop" {
+  API = container "API"
+  DB = database "DB"
+  API -> DB "reads"
+}
+Billing = system "Billing" {
+  API = container "Billing API"
+}
+`
+	renameUsesText = `User = person "User"
+User -> Shop.API "uses"
+Checkout = scenario "Checkout" {
+  step User -> Shop.API "browses"
+}
+deployment Prod "Production" {
+  node AWS "AWS" {
+    containerInstance DB
+  }
+}
+view main of Shop {
+  include Shop.API Shop.DB
+  exclude Billing.API
+  layout {
+    element Shop.API {
+      position { x: 10, y: 20 }
+    }
+  }
+}
+`
+)
+
+// renameServer indexes a root holding systems.sruja and uses.sruja and opens
+// only uses.sruja.
+func renameServer(t *testing.T) (srv *Server, systems, uses lsp.DocumentURI) {
+	t.Helper()
+	root := t.TempDir()
+	writeIndexFile(t, filepath.Join(root, "systems.sruja"), renameSystemsText)
+	writeIndexFile(t, filepath.Join(root, "uses.sruja"), renameUsesText)
+
+	srv = NewServer()
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(root)}); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	uses = pathToURI(filepath.Join(root, "uses.sruja"))
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uses, Text: renameUsesText, Version: 1}})
+	return srv, pathToURI(filepath.Join(root, "systems.sruja")), uses
+}
+
+func rename(srv *Server, uri lsp.DocumentURI, line, char int, newName string) (*lsp.WorkspaceEdit, error) {
+	return srv.Rename(context.Background(), lsp.RenameParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Position:     lsp.Position{Line: line, Character: char},
+		NewName:      newName,
+	})
+}
+
+// editedLines returns the 0-based start positions of edits as sorted "line:col".
+func editedLines(edits []lsp.TextEdit) []string {
+	res := make([]string, 0, len(edits))
+	for _, e := range edits {
+		res = append(res, fmt.Sprintf("%02d:%02d", e.Range.Start.Line, e.Range.Start.Character))
+	}
+	sort.Strings(res)
+	return res
+}
+
+func TestRename_QualifiedElementAcrossFiles(t *testing.T) {
+	srv, systems, uses := renameServer(t)
+
+	// "API" in "User -> Shop.API"
+	edit, err := rename(srv, uses, 1, 14, "Gateway")
+	if err != nil || edit == nil {
+		t.Fatalf("rename error: %v", err)
+	}
+
+	// Definition and the unqualified reference inside Shop, not Billing.API
+	if got := editedLines(edit.Changes[string(systems)]); strings.Join(got, " ") != "01:02 03:02" {
+		t.Errorf("unexpected edits in systems.sruja: %v", got)
+	}
+	// Relation, scenario step, view include and layout element, not the exclude
+	if got := editedLines(edit.Changes[string(uses)]); strings.Join(got, " ") != "01:13 03:20 11:15 14:17" {
+		t.Errorf("unexpected edits in uses.sruja: %v", got)
+	}
+	for _, edits := range edit.Changes {
+		for _, e := range edits {
+			if e.NewText != "Gateway" || e.Range.End.Character-e.Range.Start.Character != len("API") {
+				t.Errorf("expected only the API segment replaced, got %+v", e)
+			}
+		}
+	}
+}
+
+func TestRename_ParentUpdatesQualifiedReferences(t *testing.T) {
+	srv, systems, uses := renameServer(t)
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: systems, Text: renameSystemsText, Version: 1}})
+
+	// "Shop" in its definition
+	edit, err := rename(srv, systems, 0, 1, "Store")
+	if err != nil || edit == nil {
+		t.Fatalf("rename error: %v", err)
+	}
+	if n := len(edit.Changes[string(systems)]); n != 1 {
+		t.Errorf("expected only the definition renamed in systems.sruja, got %d edits", n)
+	}
+	// Relation, step, view of, two includes and the layout element
+	if n := len(edit.Changes[string(uses)]); n != 6 {
+		t.Errorf("expected 6 edits in uses.sruja, got %+v", edit.Changes[string(uses)])
+	}
+}
+
+func TestRename_RejectsConflicts(t *testing.T) {
+	srv, _, uses := renameServer(t)
+
+	if _, err := rename(srv, uses, 1, 14, "DB"); err == nil || !strings.Contains(err.Error(), "Shop.DB is already defined") {
+		t.Errorf("expected a conflict with Shop.DB, got %v", err)
+	}
+	// containerInstance DB resolves by name alone
+	if _, err := rename(srv, uses, 12, 18, "DB"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+	if _, err := rename(srv, uses, 1, 14, "not valid"); err == nil {
+		t.Error("expected an invalid identifier error")
+	}
+	// Billing.API may take a name unused in Billing
+	if _, err := rename(srv, uses, 12, 18, "Invoices"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReferencesAndDefinition_Qualified(t *testing.T) {
+	srv, systems, uses := renameServer(t)
+
+	// "API" in "exclude Billing.API"
+	pos := lsp.TextDocumentPositionParams{TextDocument: lsp.TextDocumentIdentifier{URI: uses}, Position: lsp.Position{Line: 12, Character: 18}}
+	locs, err := srv.Definition(context.Background(), pos)
+	if err != nil || len(locs) != 1 {
+		t.Fatalf("definition error: %v %+v", err, locs)
+	}
+	if locs[0].URI != systems || locs[0].Range.Start.Line != 6 {
+		t.Errorf("expected Billing.API defined at systems.sruja:7, got %+v", locs[0])
+	}
+
+	refs, err := srv.References(context.Background(), lsp.ReferenceParams{TextDocumentPositionParams: pos})
+	if err != nil {
+		t.Fatalf("references error: %v", err)
+	}
+	if len(refs) != 1 || refs[0].URI != uses || refs[0].Range.Start.Line != 12 {
+		t.Errorf("expected only the exclude to reference Billing.API, got %+v", refs)
+	}
+}
+
+func TestRename_ImportList(t *testing.T) {
+	srv := NewServer()
+	decl := lsp.DocumentURI("file:///decl.sruja")
+	use := lsp.DocumentURI("file:///use.sruja")
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: decl, Text: "Shop = system \"Shop\"\n"}})
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: use, Text: "import { Shop } from 'decl'\nUser = person \"User\"\nUser -> Shop \"uses\"\n"}})
+
+	edit, err := rename(srv, decl, 0, 0, "Store")
+	if err != nil || edit == nil {
+		t.Fatalf("rename error: %v", err)
+	}
+	if got := editedLines(edit.Changes[string(use)]); strings.Join(got, " ") != "00:09 02:08" {
+		t.Errorf("expected the import list and relation renamed, got %v", got)
+	}
+}
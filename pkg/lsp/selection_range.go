@@ -0,0 +1,142 @@
+package lsp
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// SelectionRangeParams are the parameters of a selection range request.
+type SelectionRangeParams struct {
+	TextDocument lsp.TextDocumentIdentifier `json:"textDocument"`
+	Positions    []lsp.Position             `json:"positions"`
+}
+
+// SelectionRange is a range to select and the range containing it.
+type SelectionRange struct {
+	Range  lsp.Range       `json:"range"`
+	Parent *SelectionRange `json:"parent,omitempty"`
+}
+
+// SelectionRanges expands the selection at each position from the identifier
+// to the qualified reference, the relation or step, the element body, the
+// element, and on through the enclosing elements.
+func (s *Server) SelectionRanges(_ context.Context, params SelectionRangeParams) ([]SelectionRange, error) {
+	doc := s.workspace.GetDocument(params.TextDocument.URI)
+	if doc == nil {
+		return nil, nil
+	}
+	prog, _ := doc.parse()
+	tokens := scanQualifiedIdents(doc.Text)
+	pos := newPositionIndex(doc.Text)
+
+	res := make([]SelectionRange, 0, len(params.Positions))
+	for _, p := range params.Positions {
+		offset := positionToOffset(doc, p)
+		spans := identifierSpans(tokens, offset)
+		if prog != nil {
+			spans = append(spans, statementSpans(doc.Text, prog, offset)...)
+		}
+		res = append(res, selectionChain(spans, pos, p))
+	}
+	return res, nil
+}
+
+// span is a range of a document as offsets.
+type span struct{ start, end int }
+
+func (sp span) contains(o span) bool { return sp.start <= o.start && o.end <= sp.end }
+
+// identifierSpans returns the identifier at offset and, if it is part of a
+// qualified reference, the whole reference.
+func identifierSpans(tokens []qualifiedToken, offset int) []span {
+	i := sort.Search(len(tokens), func(i int) bool { return tokens[i].end() >= offset })
+	if i == len(tokens) || tokens[i].offsets[0] > offset {
+		return nil
+	}
+	tok := tokens[i]
+	var spans []span
+	for j, part := range tok.parts {
+		if tok.offsets[j] <= offset && offset <= tok.offsets[j]+len(part) {
+			spans = append(spans, span{tok.offsets[j], tok.offsets[j] + len(part)})
+		}
+	}
+	if len(tok.parts) > 1 {
+		spans = append(spans, span{tok.offsets[0], tok.end()})
+	}
+	return spans
+}
+
+// statementSpans returns the relations, steps, element bodies and elements of
+// the program that contain offset.
+func statementSpans(text string, prog *language.Program, offset int) []span {
+	var spans []span
+	add := func(sp span) {
+		if sp.start <= offset && offset <= sp.end {
+			spans = append(spans, sp)
+		}
+	}
+	line := func(start int) span { return span{start, headerEnd(text, start)} }
+
+	var element func(e *language.ElementDef)
+	element = func(e *language.ElementDef) {
+		whole := span{e.Pos.Offset, elementEnd(text, e)}
+		if offset < whole.start || offset > whole.end {
+			return
+		}
+		spans = append(spans, whole)
+		end := bodyClose(text, e)
+		if end < 0 {
+			return
+		}
+		header := headerEnd(text, e.Pos.Offset)
+		start := header + strings.IndexByte(text[header:], '{') + 1
+		if inner := strings.TrimSpace(text[start:end]); inner != "" {
+			start += strings.Index(text[start:end], inner)
+			add(span{start, start + len(inner)})
+		}
+		for _, item := range e.GetBody().Items {
+			switch {
+			case item.Element != nil:
+				element(item.Element)
+			case item.Relation != nil:
+				add(line(item.Relation.Pos.Offset))
+			case item.Step != nil:
+				add(line(item.Step.Pos.Offset))
+			}
+		}
+	}
+	for _, item := range prog.Items {
+		switch {
+		case item.ElementDef != nil:
+			element(item.ElementDef)
+		case item.Relation != nil:
+			add(line(item.Relation.Pos.Offset))
+		}
+	}
+	return spans
+}
+
+// selectionChain links the spans containing one another from the innermost
+// out. A position in no span selects nothing.
+func selectionChain(spans []span, pos positionIndex, p lsp.Position) SelectionRange {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].end-spans[i].start > spans[j].end-spans[j].start
+	})
+	var chain *SelectionRange
+	var last span
+	for _, sp := range spans {
+		if chain != nil && (sp == last || !last.contains(sp)) {
+			continue
+		}
+		chain = &SelectionRange{Range: lsp.Range{Start: pos.at(sp.start), End: pos.at(sp.end)}, Parent: chain}
+		last = sp
+	}
+	if chain == nil {
+		return SelectionRange{Range: lsp.Range{Start: p, End: p}}
+	}
+	return *chain
+}
@@ -0,0 +1,68 @@
+package lsp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+func selectionRanges(r SelectionRange) []lsp.Range {
+	ranges := []lsp.Range{r.Range}
+	for p := r.Parent; p != nil; p = p.Parent {
+		ranges = append(ranges, p.Range)
+	}
+	return ranges
+}
+
+func TestSelectionRanges_ExpandOutwards(t *testing.T) {
+	srv := NewServer()
+	uri := lsp.DocumentURI("file:///selection.sruja")
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: highlightText, Version: 1}})
+
+	res, err := srv.SelectionRanges(context.Background(), SelectionRangeParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Positions:    []lsp.Position{{Line: 3, Character: 3}, {Line: 9, Character: 14}, {Line: 5, Character: 22}},
+	})
+	if err != nil || len(res) != 3 {
+		t.Fatalf("selection ranges = %+v, %v", res, err)
+	}
+
+	cases := [][]lsp.Range{
+		// identifier, relation, Shop body, Shop
+		{lineRange(3, 2, 5), lineRange(3, 2, 19),
+			{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 3, Character: 19}},
+			{Start: lsp.Position{Line: 0}, End: lsp.Position{Line: 4, Character: 1}}},
+		// segment, qualified reference, top-level relation
+		{lineRange(9, 13, 16), lineRange(9, 8, 16), lineRange(9, 0, 23)},
+		// the title of Billing
+		{{Start: lsp.Position{Line: 5}, End: lsp.Position{Line: 7, Character: 1}}},
+	}
+	for i, want := range cases {
+		got := selectionRanges(res[i])
+		if len(got) != len(want) {
+			t.Errorf("position %d: ranges = %+v, want %+v", i, got, want)
+			continue
+		}
+		for j := range want {
+			if got[j] != want[j] {
+				t.Errorf("position %d, range %d = %+v, want %+v", i, j, got[j], want[j])
+			}
+		}
+	}
+}
+
+func TestSelectionRanges_OutsideStatements(t *testing.T) {
+	srv := NewServer()
+	uri := lsp.DocumentURI("file:///blank.sruja")
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: "Shop = system \"Shop\"\n\n", Version: 1}})
+
+	pos := lsp.Position{Line: 1, Character: 0}
+	res, _ := srv.SelectionRanges(context.Background(), SelectionRangeParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		Positions:    []lsp.Position{pos},
+	})
+	if len(res) != 1 || res[0].Range != (lsp.Range{Start: pos, End: pos}) || res[0].Parent != nil {
+		t.Fatalf("selection ranges = %+v", res)
+	}
+}
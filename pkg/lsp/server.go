@@ -6,9 +6,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/jsonrpc2"
+	"github.com/sruja-ai/sruja/pkg/config"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
 	"github.com/sruja-ai/sruja/pkg/engine"
 	"github.com/sruja-ai/sruja/pkg/language"
 )
@@ -17,50 +24,112 @@ type Server struct {
 	conn      *jsonrpc2.Conn
 	workspace *Workspace
 	validator *engine.Validator
+
+	// configValidators caches validators built from sruja.config.json files, keyed by path.
+	configValidators map[string]configValidator
+	configMu         sync.Mutex
+
+	// watchFiles is set when the client can register file watchers dynamically.
+	watchFiles bool
+
+	// diagnosticsDelay debounces validation after edits. pending holds the
+	// validations scheduled or running, keyed by workspace root or document URI.
+	diagnosticsDelay time.Duration
+	pending          map[string]*pendingDiagnostics
+	pendingMu        sync.Mutex
 }
 
 func NewServer() *Server {
 	v := engine.NewValidator()
-	v.RegisterRule(&engine.UniqueIDRule{})
-	v.RegisterRule(&engine.ValidReferenceRule{})
-	v.RegisterRule(&engine.CycleDetectionRule{})
-	v.RegisterRule(&engine.OrphanDetectionRule{})
-	v.RegisterRule(&engine.SimplicityRule{})
-	v.RegisterRule(&engine.LayerViolationRule{})
-	v.RegisterRule(&engine.ScenarioFQNRule{})
+	v.RegisterDefaultRules()
 	return &Server{
-		workspace: NewWorkspace(),
-		validator: v,
+		workspace:        NewWorkspace(),
+		validator:        v,
+		configValidators: make(map[string]configValidator),
+		diagnosticsDelay: defaultDiagnosticsDelay,
+		pending:          make(map[string]*pendingDiagnostics),
+	}
+}
+
+// WorkspaceFolder is a workspace folder sent with the initialize request.
+type WorkspaceFolder struct {
+	URI  lsp.DocumentURI `json:"uri"`
+	Name string          `json:"name"`
+}
+
+// IndexWorkspaceFolders indexes the .sruja files of the given workspace folders.
+func (s *Server) IndexWorkspaceFolders(folders []WorkspaceFolder) {
+	for _, f := range folders {
+		if path := uriToPath(f.URI); path != "" {
+			_ = s.workspace.IndexRoot(path) // unreadable folders are left unindexed
+		}
 	}
 }
 
-//nolint:gocritic,revive // params is large but standard, unused param required by signature
-func (s *Server) Initialize(_ context.Context, _ lsp.InitializeParams) (*lsp.InitializeResult, error) {
-	return &lsp.InitializeResult{
-		Capabilities: lsp.ServerCapabilities{
-			TextDocumentSync:           &lsp.TextDocumentSyncOptionsOrKind{Options: &lsp.TextDocumentSyncOptions{OpenClose: true, Change: lsp.TDSKIncremental}},
-			HoverProvider:              true,
-			CompletionProvider:         &lsp.CompletionOptions{TriggerCharacters: []string{".", ":"}},
-			DefinitionProvider:         true,
-			ReferencesProvider:         true,
-			CodeActionProvider:         true, // Enable code actions (using Command-based approach)
-			DocumentSymbolProvider:     true,
-			WorkspaceSymbolProvider:    true,
-			DocumentFormattingProvider: true,
-			RenameProvider:             true, // Enable rename provider
-			// Note: DocumentLinkProvider and FoldingRangeProvider are not in go-lsp ServerCapabilities
-			// but we handle the requests manually in the switch statement below
+// Initialize indexes the workspace root, unless workspace folders were indexed
+// already, and reports the server capabilities.
+//
+//nolint:gocritic // params is large but standard
+func (s *Server) Initialize(_ context.Context, params lsp.InitializeParams) (*InitializeResult, error) {
+	if watch := params.Capabilities.Workspace.DidChangeWatchedFiles; watch != nil {
+		s.watchFiles = watch.DynamicRegistration
+	}
+	if len(s.workspace.Roots()) == 0 {
+		if root := uriToPath(params.Root()); root != "" {
+			_ = s.workspace.IndexRoot(root)
+		}
+	}
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			ServerCapabilities: lsp.ServerCapabilities{
+				TextDocumentSync:           &lsp.TextDocumentSyncOptionsOrKind{Options: &lsp.TextDocumentSyncOptions{OpenClose: true, Change: lsp.TDSKIncremental}},
+				HoverProvider:              true,
+				CompletionProvider:         &lsp.CompletionOptions{TriggerCharacters: []string{".", ":"}},
+				DefinitionProvider:         true,
+				ReferencesProvider:         true,
+				DocumentHighlightProvider:  true,
+				CodeActionProvider:         true, // Quick fixes and refactorings with workspace edits
+				DocumentSymbolProvider:     true,
+				WorkspaceSymbolProvider:    true,
+				DocumentFormattingProvider: true,
+				RenameProvider:             true, // Enable rename provider
+				CodeLensProvider:           &lsp.CodeLensOptions{},
+				// Note: DocumentLinkProvider and FoldingRangeProvider are not in go-lsp ServerCapabilities
+				// but we handle the requests manually in the switch statement below
+			},
+			InlayHintProvider:          true,
+			CallHierarchyProvider:      true,
+			TypeHierarchyProvider:      true,
+			SelectionRangeProvider:     true,
+			LinkedEditingRangeProvider: true,
 		},
 	}, nil
 }
 
+// InitializeResult is the result of the initialize request, with the
+// capabilities go-lsp does not model.
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities,omitempty"`
+}
+
+// ServerCapabilities extends go-lsp's server capabilities.
+type ServerCapabilities struct {
+	lsp.ServerCapabilities
+	InlayHintProvider          bool `json:"inlayHintProvider,omitempty"`
+	CallHierarchyProvider      bool `json:"callHierarchyProvider,omitempty"`
+	TypeHierarchyProvider      bool `json:"typeHierarchyProvider,omitempty"`
+	SelectionRangeProvider     bool `json:"selectionRangeProvider,omitempty"`
+	LinkedEditingRangeProvider bool `json:"linkedEditingRangeProvider,omitempty"`
+}
+
 func (s *Server) DidOpen(_ context.Context, params lsp.DidOpenTextDocumentParams) error {
 	doc := params.TextDocument
 	s.workspace.AddDocument(doc.URI, doc.Text, doc.Version)
 	return s.publishDiagnostics(doc.URI)
 }
 
-func (s *Server) DidChange(_ context.Context, params lsp.DidChangeTextDocumentParams) error {
+// DidChange applies edits to a document and schedules its validation.
+func (s *Server) DidChange(ctx context.Context, params lsp.DidChangeTextDocumentParams) error {
 	doc := s.workspace.GetDocument(params.TextDocument.URI)
 	if doc == nil {
 		return nil
@@ -68,26 +137,121 @@ func (s *Server) DidChange(_ context.Context, params lsp.DidChangeTextDocumentPa
 	for _, change := range params.ContentChanges {
 		doc.ApplyChange(change)
 	}
-	return s.publishDiagnostics(params.TextDocument.URI)
+	return s.scheduleDiagnostics(ctx, params.TextDocument.URI)
 }
 
 func (s *Server) DidClose(_ context.Context, params lsp.DidCloseTextDocumentParams) error {
-	s.workspace.RemoveDocument(params.TextDocument.URI)
-	return s.notifyDiagnostics(params.TextDocument.URI, []lsp.Diagnostic{})
+	uri := params.TextDocument.URI
+	s.cancelDiagnostics(string(uri))
+	s.workspace.RemoveDocument(uri)
+	// Files of a workspace root stay indexed with their contents on disk
+	if path := uriToPath(uri); s.workspace.UpdateFile(path) {
+		return s.publishRootDiagnostics(s.workspace.RootOf(path), "")
+	}
+	return s.notifyDiagnostics(uri, []lsp.Diagnostic{})
 }
 
+// Initialized asks the client to watch .sruja and config files, so the workspace
+// index stays current with changes made outside the editor.
+func (s *Server) Initialized(_ context.Context) {
+	if s.conn == nil || !s.watchFiles {
+		return
+	}
+	params := registrationParams{Registrations: []registration{{
+		ID:     "sruja-watched-files",
+		Method: "workspace/didChangeWatchedFiles",
+		RegisterOptions: didChangeWatchedFilesRegistrationOptions{Watchers: []fileSystemWatcher{
+			{GlobPattern: "**/*" + srujaExt},
+			{GlobPattern: "**/" + config.ConfigFileName},
+		}},
+	}}}
+	conn := s.conn
+	// Requests to the client cannot be awaited while handling a message
+	go func() {
+		_ = conn.Call(context.Background(), "client/registerCapability", params, nil)
+	}()
+}
+
+// DidChangeWatchedFiles updates the workspace index for files changed on disk and
+// republishes the diagnostics of the affected roots.
+func (s *Server) DidChangeWatchedFiles(_ context.Context, params lsp.DidChangeWatchedFilesParams) error {
+	changed := make(map[string]bool)
+	for _, change := range params.Changes {
+		path := uriToPath(change.URI)
+		root := s.workspace.RootOf(path)
+		if root == "" {
+			continue
+		}
+		switch {
+		case filepath.Base(path) == config.ConfigFileName:
+			changed[root] = true
+		case change.Type == int(lsp.Deleted):
+			if s.workspace.RemoveFile(path) {
+				changed[root] = true
+				if err := s.notifyDiagnostics(change.URI, []lsp.Diagnostic{}); err != nil {
+					return err
+				}
+			}
+		default:
+			// Open documents are kept current by the editor
+			if s.workspace.UpdateFile(path) {
+				changed[root] = true
+			}
+		}
+	}
+	roots := make([]string, 0, len(changed))
+	for root := range changed {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	for _, root := range roots {
+		if err := s.publishRootDiagnostics(root, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishDiagnostics validates a document, or the workspace root it belongs to,
+// and publishes the diagnostics.
 func (s *Server) publishDiagnostics(uri lsp.DocumentURI) error {
+	key, job := s.diagnosticsJob(uri)
+	if job == nil {
+		return nil
+	}
+	s.cancelDiagnostics(key)
+	return job(context.Background())
+}
+
+// diagnosticsJob snapshots what validating a document needs and returns the
+// validation, with the key it is debounced under: the document's workspace root
+// or its URI. The job publishes nothing once its context is cancelled.
+func (s *Server) diagnosticsJob(uri lsp.DocumentURI) (string, func(context.Context) error) {
 	doc := s.workspace.GetDocument(uri)
 	if doc == nil {
-		return nil
+		return "", nil
 	}
-	p, err := language.NewParser()
-	if err != nil {
-		// Log error but don't fail diagnostics - return empty diagnostics
-		// This allows the LSP to continue functioning even if parser creation fails
-		return s.notifyDiagnostics(uri, []lsp.Diagnostic{})
+	if path := uriToPath(uri); path != "" {
+		if root := s.workspace.RootOf(path); root != "" {
+			docs := s.workspace.snapshotRoot(root)
+			return root, func(ctx context.Context) error {
+				return s.publishRootSnapshot(ctx, root, docs, path)
+			}
+		}
 	}
-	program, diags, parseErr := p.Parse(string(uri), doc.Text)
+	snap := doc.snapshot()
+	return string(uri), func(ctx context.Context) error {
+		diags := s.documentDiagnostics(ctx, snap)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return s.notifyDiagnostics(uri, diags)
+	}
+}
+
+// documentDiagnostics parses and validates a document outside any workspace root.
+func (s *Server) documentDiagnostics(ctx context.Context, doc docSnapshot) []lsp.Diagnostic {
+	program, diags, parseErr := doc.blocks.parse(string(doc.uri), doc.text)
 	_ = parseErr // Ignore parse errors, we still want to show diagnostics
 
 	var lspDiagnostics []lsp.Diagnostic
@@ -96,12 +260,142 @@ func (s *Server) publishDiagnostics(uri lsp.DocumentURI) error {
 	}
 
 	// Run validation even if parse had errors, as partial programs may still be validatable
-	if program != nil {
-		validatorDiags := s.validator.Validate(program)
+	if program != nil && ctx.Err() == nil {
+		validator, cfgErr := s.validatorFor(doc.uri)
+		if cfgErr != nil {
+			lspDiagnostics = append(lspDiagnostics, s.convertDiagnosticsToLSP([]diagnostics.Diagnostic{{
+				Code:     diagnostics.CodeValidationRuleError,
+				Severity: diagnostics.SeverityWarning,
+				Message:  "Invalid sruja.config.json, using default rules: " + cfgErr.Error(),
+				Location: diagnostics.SourceLocation{File: string(doc.uri), Line: 1, Column: 1},
+			}})...)
+		}
+		validatorDiags := validator.ValidateContext(ctx, program)
 		lspDiagnostics = append(lspDiagnostics, s.convertDiagnosticsToLSP(validatorDiags)...)
 	}
+	return lspDiagnostics
+}
+
+// publishRootDiagnostics publishes the diagnostics of each file of a workspace root.
+func (s *Server) publishRootDiagnostics(root, trigger string) error {
+	s.cancelDiagnostics(root)
+	return s.publishRootSnapshot(context.Background(), root, s.workspace.snapshotRoot(root), trigger)
+}
+
+func (s *Server) publishRootSnapshot(ctx context.Context, root string, docs []docSnapshot, trigger string) error {
+	byURI := s.snapshotDiagnostics(ctx, root, docs, trigger)
+	if ctx.Err() != nil {
+		return nil
+	}
+	uris := make([]string, 0, len(byURI))
+	for uri := range byURI {
+		uris = append(uris, string(uri))
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		if err := s.notifyDiagnostics(lsp.DocumentURI(uri), byURI[lsp.DocumentURI(uri)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rootDiagnostics resolves and validates a workspace root as a whole, the way
+// `sruja lint <dir>` does, and returns the diagnostics of each of its files.
+// Diagnostics without a file in the root are reported on trigger, when given.
+func (s *Server) rootDiagnostics(root, trigger string) map[lsp.DocumentURI][]lsp.Diagnostic {
+	return s.snapshotDiagnostics(context.Background(), root, s.workspace.snapshotRoot(root), trigger)
+}
+
+func (s *Server) snapshotDiagnostics(ctx context.Context, root string, docs []docSnapshot, trigger string) map[lsp.DocumentURI][]lsp.Diagnostic {
+	ws, byFile := s.rootWorkspace(ctx, root, docs, trigger)
+	if ws == nil {
+		return nil
+	}
+
+	engine.RunWorkspaceResolution(ws)
+	validator, cfgErr := s.validatorForDir(root)
+	if cfgErr != nil && trigger != "" {
+		byFile[trigger] = append(byFile[trigger], diagnostics.Diagnostic{
+			Code:     diagnostics.CodeValidationRuleError,
+			Severity: diagnostics.SeverityWarning,
+			Message:  "Invalid sruja.config.json, using default rules: " + cfgErr.Error(),
+			Location: diagnostics.SourceLocation{File: trigger, Line: 1, Column: 1},
+		})
+	}
+	for _, d := range validator.ValidateContext(ctx, ws.MergedProgram()) {
+		file := d.Location.File
+		if _, ok := byFile[file]; !ok {
+			// Imported modules report their own diagnostics when opened
+			if _, imported := ws.Programs[file]; imported || trigger == "" {
+				continue
+			}
+			file = trigger
+		}
+		byFile[file] = append(byFile[file], d)
+	}
+
+	byURI := make(map[lsp.DocumentURI][]lsp.Diagnostic, len(docs))
+	for _, d := range docs {
+		byURI[d.uri] = s.convertDiagnosticsToLSP(byFile[d.path])
+	}
+	return byURI
+}
+
+// rootWorkspace builds the workspace of a root from the editor's copies of its
+// files, the way Parser.ParseWorkspace builds it from disk: the root's sruja.mod
+// is loaded, imports are resolved (stdlib, relative paths, required modules and
+// aliases) and their kinds and tags merged into each program. It returns the
+// parse and import diagnostics of each file of the root.
+func (s *Server) rootWorkspace(ctx context.Context, root string, docs []docSnapshot, trigger string) (*language.Workspace, map[string][]diagnostics.Diagnostic) {
+	ws := language.NewWorkspace()
+	byFile := make(map[string][]diagnostics.Diagnostic, len(docs))
+	if mod := language.FindModFile(root); mod != "" {
+		if err := ws.LoadModFile(mod); err != nil && trigger != "" {
+			byFile[trigger] = append(byFile[trigger], diagnostics.Diagnostic{
+				Code:     diagnostics.CodeModuleNotFound,
+				Severity: diagnostics.SeverityError,
+				Message:  "Invalid " + language.ModFileName + ": " + err.Error(),
+				Location: diagnostics.SourceLocation{File: trigger, Line: 1, Column: 1},
+			})
+		}
+	}
+
+	programs := make([]*language.Program, len(docs))
+	for i, d := range docs {
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+		prog, diags, _ := d.blocks.parse(d.path, d.text)
+		programs[i] = prog
+		ws.AddProgram(d.path, prog, nil)
+		byFile[d.path] = append(byFile[d.path], diags...)
+	}
+
+	// Resolve imports once every file of the root is in the workspace, so
+	// imports between them use the editor's text rather than the files on disk
+	if p, err := language.DefaultParser(); err == nil {
+		for i, d := range docs {
+			_ = p.ResolveImports(ws, programs[i], d.path)
+		}
+	}
+	ws.ResolveAndMergeImports()
 
-	return s.notifyDiagnostics(uri, lspDiagnostics)
+	// Imports of modules that cannot be loaded or that conflict, as sruja lint reports them
+	for _, d := range ws.Diags {
+		if !strings.HasPrefix(d.Code, "E5") {
+			continue
+		}
+		file := d.Location.File
+		if _, ok := byFile[file]; !ok {
+			if trigger == "" {
+				continue
+			}
+			file = trigger
+		}
+		byFile[file] = append(byFile[file], d)
+	}
+	return ws, byFile
 }
 
 func (s *Server) notifyDiagnostics(uri lsp.DocumentURI, diagnostics []lsp.Diagnostic) error {
@@ -112,6 +406,26 @@ func (s *Server) notifyDiagnostics(uri lsp.DocumentURI, diagnostics []lsp.Diagno
 	return s.conn.Notify(context.Background(), "textDocument/publishDiagnostics", params)
 }
 
+// registrationParams and the types below model client/registerCapability for
+// file watchers, which go-lsp does not provide.
+type registrationParams struct {
+	Registrations []registration `json:"registrations"`
+}
+
+type registration struct {
+	ID              string      `json:"id"`
+	Method          string      `json:"method"`
+	RegisterOptions interface{} `json:"registerOptions,omitempty"`
+}
+
+type didChangeWatchedFilesRegistrationOptions struct {
+	Watchers []fileSystemWatcher `json:"watchers"`
+}
+
+type fileSystemWatcher struct {
+	GlobPattern string `json:"globPattern"`
+}
+
 type stdioStream struct {
 	in  io.Reader
 	out io.Writer
@@ -142,14 +456,33 @@ func unmarshalParams[T any](params *json.RawMessage) (T, error) {
 func StartServer(in io.Reader, out io.Writer) error {
 	srv := NewServer()
 	handler := jsonrpc2.HandlerWithError(func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
-		srv.conn = conn
+		if srv.conn == nil {
+			srv.conn = conn // set once: scheduled validations notify through it
+		}
 		switch req.Method {
 		case "initialize":
 			params, err := unmarshalParams[lsp.InitializeParams](req.Params)
 			if err != nil {
 				return nil, err
 			}
+			// go-lsp does not model workspace folders
+			folders, err := unmarshalParams[struct {
+				WorkspaceFolders []WorkspaceFolder `json:"workspaceFolders"`
+			}](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			srv.IndexWorkspaceFolders(folders.WorkspaceFolders)
 			return srv.Initialize(ctx, params)
+		case "initialized":
+			srv.Initialized(ctx)
+			return nil, nil
+		case "workspace/didChangeWatchedFiles":
+			params, err := unmarshalParams[lsp.DidChangeWatchedFilesParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			return nil, srv.DidChangeWatchedFiles(ctx, params)
 		case "textDocument/didOpen":
 			params, err := unmarshalParams[lsp.DidOpenTextDocumentParams](req.Params)
 			if err != nil {
@@ -203,6 +536,27 @@ func StartServer(in io.Reader, out io.Writer) error {
 			}
 			refs, err := srv.References(ctx, params)
 			return refs, err
+		case "textDocument/documentHighlight":
+			params, err := unmarshalParams[lsp.TextDocumentPositionParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			highlights, err := srv.DocumentHighlights(ctx, params)
+			return highlights, err
+		case "textDocument/selectionRange":
+			params, err := unmarshalParams[SelectionRangeParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			ranges, err := srv.SelectionRanges(ctx, params)
+			return ranges, err
+		case "textDocument/linkedEditingRange":
+			params, err := unmarshalParams[lsp.TextDocumentPositionParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			ranges, err := srv.LinkedEditingRanges(ctx, params)
+			return ranges, err
 		case "textDocument/documentSymbol":
 			params, err := unmarshalParams[lsp.DocumentSymbolParams](req.Params)
 			if err != nil {
@@ -264,6 +618,62 @@ func StartServer(in io.Reader, out io.Writer) error {
 			}
 			ranges, err := srv.FoldingRanges(ctx, params)
 			return ranges, err
+		case "textDocument/inlayHint":
+			params, err := unmarshalParams[InlayHintParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			hints, err := srv.InlayHints(ctx, params)
+			return hints, err
+		case "textDocument/codeLens":
+			params, err := unmarshalParams[lsp.CodeLensParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			lenses, err := srv.CodeLenses(ctx, params)
+			return lenses, err
+		case "textDocument/prepareCallHierarchy":
+			params, err := unmarshalParams[lsp.TextDocumentPositionParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			items, err := srv.PrepareCallHierarchy(ctx, params)
+			return items, err
+		case "callHierarchy/incomingCalls":
+			params, err := unmarshalParams[HierarchyItemParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			calls, err := srv.IncomingCalls(ctx, params)
+			return calls, err
+		case "callHierarchy/outgoingCalls":
+			params, err := unmarshalParams[HierarchyItemParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			calls, err := srv.OutgoingCalls(ctx, params)
+			return calls, err
+		case "textDocument/prepareTypeHierarchy":
+			params, err := unmarshalParams[lsp.TextDocumentPositionParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			items, err := srv.PrepareTypeHierarchy(ctx, params)
+			return items, err
+		case "typeHierarchy/supertypes":
+			params, err := unmarshalParams[HierarchyItemParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			items, err := srv.Supertypes(ctx, params)
+			return items, err
+		case "typeHierarchy/subtypes":
+			params, err := unmarshalParams[HierarchyItemParams](req.Params)
+			if err != nil {
+				return nil, err
+			}
+			items, err := srv.Subtypes(ctx, params)
+			return items, err
 		default:
 			// Unhandled methods: respond with nil
 			return nil, nil
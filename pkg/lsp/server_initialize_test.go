@@ -15,4 +15,13 @@ func TestServerInitializeCapabilities(t *testing.T) {
 	if res == nil || !res.Capabilities.HoverProvider || !res.Capabilities.DefinitionProvider || !res.Capabilities.DocumentFormattingProvider {
 		t.Fatalf("capabilities missing")
 	}
+	if !res.Capabilities.InlayHintProvider || res.Capabilities.CodeLensProvider == nil {
+		t.Fatalf("inlay hint or code lens capability missing")
+	}
+	if !res.Capabilities.CallHierarchyProvider || !res.Capabilities.TypeHierarchyProvider {
+		t.Fatalf("call or type hierarchy capability missing")
+	}
+	if !res.Capabilities.DocumentHighlightProvider || !res.Capabilities.SelectionRangeProvider || !res.Capabilities.LinkedEditingRangeProvider {
+		t.Fatalf("highlight, selection range or linked editing capability missing")
+	}
 }
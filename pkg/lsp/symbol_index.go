@@ -0,0 +1,458 @@
+package lsp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// symbolIndex maps the elements defined in a set of documents to every place
+// their names appear: definitions, relation endpoints, scenario and flow steps,
+// view expressions and layouts, deployment instances and import lists.
+type symbolIndex struct {
+	defs        map[string]*symbolOccurrence // FQN -> definition
+	suffixes    map[string][]string          // last segment -> FQNs
+	occurrences []*symbolOccurrence
+	refs        []*symbolRef
+}
+
+// symbolRef is a reference as written in a document and the element it
+// resolves to.
+type symbolRef struct {
+	fqn      string
+	doc      *Document
+	token    qualifiedToken
+	endpoint bool // a relation or step endpoint
+}
+
+// symbolOccurrence is one identifier in a document that names an element,
+// either its definition or one segment of a possibly qualified reference.
+type symbolOccurrence struct {
+	fqn    string
+	doc    *Document
+	offset int
+	length int
+	def    bool
+	// bySuffix marks references resolved through a unique last segment, as the
+	// engine resolver does, rather than through their scope or a full path.
+	bySuffix bool
+}
+
+func (o *symbolOccurrence) location() lsp.Location {
+	return lsp.Location{URI: o.doc.URI, Range: offsetToRange(o.doc.Text, o.offset, o.length)}
+}
+
+// qualifiedToken is a dotted identifier in the document text with the offset
+// of each of its parts.
+type qualifiedToken struct {
+	parts   []string
+	offsets []int
+}
+
+func (t qualifiedToken) end() int {
+	last := len(t.parts) - 1
+	return t.offsets[last] + len(t.parts[last])
+}
+
+// pendingRef is a reference located in the text, resolved once all
+// definitions are known.
+type pendingRef struct {
+	doc      *Document
+	scope    string
+	token    qualifiedToken
+	endpoint bool
+}
+
+type indexBuilder struct {
+	idx     *symbolIndex
+	doc     *Document
+	tokens  []qualifiedToken
+	refs    []pendingRef
+	extends []func()
+}
+
+// indexSymbols builds the symbol index of the documents a document is resolved
+// against: its workspace root, or the open documents outside any root.
+func (w *Workspace) indexSymbols(doc *Document) *symbolIndex {
+	var docs []*Document
+	if root := w.RootOf(uriToPath(doc.URI)); root != "" {
+		for _, d := range w.RootDocuments(root) {
+			docs = append(docs, d)
+		}
+	} else {
+		for _, d := range w.AllDocuments() {
+			if w.RootOf(uriToPath(d.URI)) == "" {
+				docs = append(docs, d)
+			}
+		}
+	}
+	return buildSymbolIndex(docs)
+}
+
+func buildSymbolIndex(docs []*Document) *symbolIndex {
+	sort.Slice(docs, func(i, j int) bool { return docs[i].URI < docs[j].URI })
+	b := &indexBuilder{idx: &symbolIndex{
+		defs:     make(map[string]*symbolOccurrence),
+		suffixes: make(map[string][]string),
+	}}
+	for _, d := range docs {
+		// Resolution rewrites programs in place, and references must match the
+		// text as written, so the resolved EnsureParsed program will not do
+		prog, _ := d.parse()
+		if prog == nil {
+			continue
+		}
+		b.doc, b.tokens = d, scanQualifiedIdents(d.Text)
+		b.program(prog)
+	}
+	// Extensions add children to elements that may be defined in later files
+	for _, extend := range b.extends {
+		extend()
+	}
+	for _, ref := range b.refs {
+		b.resolve(ref)
+	}
+	return b.idx
+}
+
+func (b *indexBuilder) program(prog *language.Program) {
+	for _, item := range prog.Items {
+		switch {
+		case item.ElementDef != nil:
+			b.element(item.ElementDef, "")
+		case item.Relation != nil:
+			b.relation(item.Relation, "")
+		case item.ViewDef != nil:
+			b.view(item.ViewDef)
+		case item.Deployment != nil:
+			b.deployment(item.Deployment)
+		case item.Import != nil:
+			cursor := item.Import.Pos.Offset
+			for _, name := range item.Import.Elements {
+				if name != "*" {
+					cursor = b.ref([]string{name}, "", cursor)
+				}
+			}
+		case item.Extend != nil:
+			b.extend(item.Extend)
+		}
+	}
+}
+
+func (b *indexBuilder) element(e *language.ElementDef, parent string) {
+	id := e.GetID()
+	if id == "" {
+		return
+	}
+	fqn := id
+	if parent != "" {
+		fqn = parent + "." + id
+	}
+	if _, exists := b.idx.defs[fqn]; !exists {
+		def := &symbolOccurrence{fqn: fqn, doc: b.doc, offset: e.Pos.Offset, length: len(id), def: true}
+		b.idx.defs[fqn] = def
+		b.idx.suffixes[id] = append(b.idx.suffixes[id], fqn)
+		b.idx.occurrences = append(b.idx.occurrences, def)
+	}
+	b.body(e.GetBody(), fqn)
+}
+
+func (b *indexBuilder) body(body *language.ElementDefBody, scope string) {
+	if body == nil {
+		return
+	}
+	for _, item := range body.Items {
+		switch {
+		case item.Element != nil:
+			b.element(item.Element, scope)
+		case item.Relation != nil:
+			b.relation(item.Relation, scope)
+		case item.Step != nil:
+			b.endpoints(item.Step.FromParts, item.Step.ToParts, scope, item.Step.Pos.Offset)
+		}
+	}
+}
+
+func (b *indexBuilder) extend(ext *language.ExtendElement) {
+	doc, tokens := b.doc, b.tokens
+	b.ref(ext.ID.Parts, "", ext.Pos.Offset)
+	b.extends = append(b.extends, func() {
+		fqn, _ := b.idx.resolve("", ext.ID.Parts)
+		if fqn == "" {
+			return
+		}
+		b.doc, b.tokens = doc, tokens
+		b.body(ext.Body, fqn)
+	})
+}
+
+func (b *indexBuilder) relation(rel *language.Relation, scope string) {
+	b.endpoints(rel.From.Parts, rel.To.Parts, scope, rel.Pos.Offset)
+}
+
+// endpoints records the two sides of a relation or step written at offset.
+// Back arrows are normalized by swapping the sides, so each is searched for
+// from the start of the statement.
+func (b *indexBuilder) endpoints(from, to []string, scope string, offset int) {
+	end := b.locate(from, scope, offset, true)
+	if strings.Join(from, ".") != strings.Join(to, ".") {
+		end = offset
+	}
+	b.locate(to, scope, end, true)
+}
+
+func (b *indexBuilder) view(v *language.ViewDef) {
+	cursor := v.Pos.Offset
+	if v.Of != nil {
+		cursor = b.ref(v.Of.Parts, "", cursor)
+	}
+	if v.Body == nil {
+		return
+	}
+	for _, item := range v.Body.Items {
+		switch {
+		case item.Include != nil:
+			for i := range item.Include.Expressions {
+				cursor = b.viewExpr(&item.Include.Expressions[i], cursor)
+			}
+		case item.Exclude != nil:
+			for i := range item.Exclude.Expressions {
+				cursor = b.viewExpr(&item.Exclude.Expressions[i], cursor)
+			}
+		case item.Layout != nil:
+			for _, el := range item.Layout.Elements {
+				b.ref(el.Element.Parts, "", el.Pos.Offset)
+			}
+		}
+	}
+}
+
+func (b *indexBuilder) viewExpr(expr *language.ViewExpr, cursor int) int {
+	if expr.Selector != nil {
+		parts := []string{*expr.Selector}
+		for sub := expr.Sub; sub != nil && sub.Ident != nil; sub = sub.Next {
+			parts = append(parts, *sub.Ident)
+		}
+		cursor = b.ref(parts, "", cursor)
+	}
+	if expr.Target != nil {
+		cursor = b.viewExpr(expr.Target, cursor)
+	}
+	return cursor
+}
+
+func (b *indexBuilder) deployment(node *language.DeploymentNode) {
+	for _, item := range node.Items {
+		switch {
+		case item.Node != nil:
+			b.deployment(item.Node)
+		case item.ContainerInstance != nil:
+			b.ref([]string{item.ContainerInstance.ContainerID}, "", item.ContainerInstance.Pos.Offset)
+		}
+	}
+}
+
+// ref locates parts in the text at or after offset and records it as a
+// reference from scope. It returns the offset after the reference, or offset
+// when it is not found.
+func (b *indexBuilder) ref(parts []string, scope string, offset int) int {
+	return b.locate(parts, scope, offset, false)
+}
+
+func (b *indexBuilder) locate(parts []string, scope string, offset int, endpoint bool) int {
+	if len(parts) == 0 {
+		return offset
+	}
+	i := sort.Search(len(b.tokens), func(i int) bool { return b.tokens[i].offsets[0] >= offset })
+	for ; i < len(b.tokens); i++ {
+		if equalParts(b.tokens[i].parts, parts) {
+			b.refs = append(b.refs, pendingRef{doc: b.doc, scope: scope, token: b.tokens[i], endpoint: endpoint})
+			return b.tokens[i].end()
+		}
+	}
+	return offset
+}
+
+// resolve records an occurrence for each segment of a reference that names a
+// known element.
+func (b *indexBuilder) resolve(ref pendingRef) {
+	parts := ref.token.parts
+	fqn, bySuffix := b.idx.resolve(ref.scope, parts)
+	if fqn == "" {
+		return
+	}
+	b.idx.refs = append(b.idx.refs, &symbolRef{fqn: fqn, doc: ref.doc, token: ref.token, endpoint: ref.endpoint})
+	if bySuffix {
+		last := len(parts) - 1
+		b.idx.occurrences = append(b.idx.occurrences, &symbolOccurrence{
+			fqn: fqn, doc: ref.doc, offset: ref.token.offsets[last], length: len(parts[last]), bySuffix: true,
+		})
+		return
+	}
+	// The reference is a suffix of the FQN: A.B written in scope S names S.A.B
+	segments := strings.Split(fqn, ".")
+	base := len(segments) - len(parts)
+	for i, part := range parts {
+		b.idx.occurrences = append(b.idx.occurrences, &symbolOccurrence{
+			fqn: strings.Join(segments[:base+i+1], "."), doc: ref.doc, offset: ref.token.offsets[i], length: len(part),
+		})
+	}
+}
+
+// resolve resolves a reference written in scope: relative to the scope and its
+// parents first, then as a full path, then by a unique last segment.
+func (x *symbolIndex) resolve(scope string, parts []string) (fqn string, bySuffix bool) {
+	ref := strings.Join(parts, ".")
+	for s := scope; s != ""; s = parentFQN(s) {
+		if _, ok := x.defs[s+"."+ref]; ok {
+			return s + "." + ref, false
+		}
+	}
+	if _, ok := x.defs[ref]; ok {
+		return ref, false
+	}
+	if matches := x.suffixes[parts[len(parts)-1]]; len(matches) == 1 {
+		return matches[0], true
+	}
+	return "", false
+}
+
+// occurrenceAt returns the occurrence under the cursor, if any.
+func (x *symbolIndex) occurrenceAt(doc *Document, pos lsp.Position) *symbolOccurrence {
+	offset := positionToOffset(doc, pos)
+	for _, o := range x.occurrences {
+		if o.doc == doc && offset >= o.offset && offset <= o.offset+o.length {
+			return o
+		}
+	}
+	return nil
+}
+
+// occurrencesOf returns the occurrences naming fqn in document order.
+func (x *symbolIndex) occurrencesOf(fqn string, includeDeclaration bool) []*symbolOccurrence {
+	var res []*symbolOccurrence
+	seen := make(map[*Document]map[int]bool)
+	for _, o := range x.occurrences {
+		if o.fqn != fqn || (o.def && !includeDeclaration) {
+			continue
+		}
+		if seen[o.doc] == nil {
+			seen[o.doc] = make(map[int]bool)
+		}
+		if seen[o.doc][o.offset] {
+			continue
+		}
+		seen[o.doc][o.offset] = true
+		res = append(res, o)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].doc.URI != res[j].doc.URI {
+			return res[i].doc.URI < res[j].doc.URI
+		}
+		return res[i].offset < res[j].offset
+	})
+	return res
+}
+
+// scanQualifiedIdents returns the dotted identifiers of a document in order,
+// skipping strings, comments and tag references as the parser's lexer does.
+func scanQualifiedIdents(text string) []qualifiedToken {
+	var tokens []qualifiedToken
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			if end := strings.Index(text[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(text)
+			}
+		case c == '"' || c == '\'':
+			i++
+			for i < len(text) && text[i] != c {
+				if text[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case c == '#' || (c >= '0' && c <= '9'):
+			i++
+			for i < len(text) && isIdentChar(text[i]) {
+				i++
+			}
+		case isIdentStart(c):
+			var tok qualifiedToken
+			for {
+				start := i
+				for i < len(text) && isIdentChar(text[i]) {
+					i++
+				}
+				tok.parts = append(tok.parts, text[start:i])
+				tok.offsets = append(tok.offsets, start)
+				if i+1 >= len(text) || text[i] != '.' || !isIdentStart(text[i+1]) {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, tok)
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isValidIdentifier reports whether name can be used as an element ID.
+func isValidIdentifier(name string) bool {
+	if name == "" || !isIdentStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isIdentChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalParts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func parentFQN(fqn string) string {
+	if i := strings.LastIndex(fqn, "."); i >= 0 {
+		return fqn[:i]
+	}
+	return ""
+}
+
+func lastSegment(fqn string) string {
+	return fqn[strings.LastIndex(fqn, ".")+1:]
+}
+
+func positionToOffset(doc *Document, pos lsp.Position) int {
+	offset := 0
+	for i := 0; i < pos.Line && i < len(doc.lines); i++ {
+		offset += len(doc.lines[i]) + 1
+	}
+	return offset + pos.Character
+}
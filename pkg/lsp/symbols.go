@@ -36,34 +36,7 @@ func (s *Server) DocumentSymbols(_ context.Context, params lsp.DocumentSymbolPar
 		kind := elem.GetKind()
 		loc := elem.Location()
 
-		// Map element kind to LSP symbol kind
-		var lspKind lsp.SymbolKind
-		switch kind {
-		case "system", "System":
-			lspKind = lsp.SKClass
-		case "container", "Container":
-			lspKind = lsp.SKModule
-		case "component", "Component":
-			lspKind = lsp.SKFunction
-		case "database", "Database", "datastore", "DataStore":
-			lspKind = lsp.SKStruct
-		case "queue", "Queue":
-			lspKind = lsp.SKEnum
-		case "person", "Person", "actor", "Actor", "user", "User":
-			lspKind = lsp.SKInterface
-		case "adr", "Adr", "ADR":
-			lspKind = lsp.SKString
-		case "requirement", "Requirement":
-			lspKind = lsp.SKProperty
-		case "policy", "Policy":
-			lspKind = lsp.SKConstant
-		case "scenario", "Scenario", "story", "Story":
-			lspKind = lsp.SKEvent
-		case "flow", "Flow":
-			lspKind = lsp.SKMethod
-		default:
-			lspKind = lsp.SKVariable
-		}
+		lspKind := symbolKind(kind)
 
 		// Find range in document
 		sRange := findIDRange(doc, id)
@@ -142,3 +115,33 @@ func findTextRange(doc *Document, text string) lsp.Range {
 	end := lsp.Position{Line: line, Character: col + len(text)}
 	return lsp.Range{Start: start, End: end}
 }
+
+// symbolKind maps an element kind to the LSP symbol kind shown for it.
+func symbolKind(kind string) lsp.SymbolKind {
+	switch kind {
+	case "system", "System":
+		return lsp.SKClass
+	case "container", "Container":
+		return lsp.SKModule
+	case "component", "Component":
+		return lsp.SKFunction
+	case "database", "Database", "datastore", "DataStore":
+		return lsp.SKStruct
+	case "queue", "Queue":
+		return lsp.SKEnum
+	case "person", "Person", "actor", "Actor", "user", "User":
+		return lsp.SKInterface
+	case "adr", "Adr", "ADR":
+		return lsp.SKString
+	case "requirement", "Requirement":
+		return lsp.SKProperty
+	case "policy", "Policy":
+		return lsp.SKConstant
+	case "scenario", "Scenario", "story", "Story":
+		return lsp.SKEvent
+	case "flow", "Flow":
+		return lsp.SKMethod
+	default:
+		return lsp.SKVariable
+	}
+}
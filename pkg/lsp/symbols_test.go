@@ -82,3 +82,29 @@ func TestFindTextRange_NotFound(t *testing.T) {
 		t.Errorf("expected full doc range for missing text, got %+v", r)
 	}
 }
+
+func TestDocumentSymbols_SyntaxErrorKeepsValidParts(t *testing.T) {
+	uri := lsp.DocumentURI("file:///partial.sruja")
+	text := "Shop = system \"Shop\" {\n  API = container \"API\"\n  technology\n}\nUser = person \"User\"\nUser -> \n"
+	srv := NewServer()
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Text: text, Version: 1}})
+
+	syms, err := srv.DocumentSymbols(context.Background(), lsp.DocumentSymbolParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, s := range syms {
+		names[s.Name] = true
+	}
+	for _, name := range []string{"Shop", "API", "User"} {
+		if !names[name] {
+			t.Errorf("expected symbol %s despite syntax errors, got %+v", name, syms)
+		}
+	}
+
+	hover, err := srv.Hover(context.Background(), lsp.TextDocumentPositionParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}, Position: lsp.Position{Line: 4, Character: 1}})
+	if err != nil || hover == nil {
+		t.Errorf("expected hover on User despite syntax errors, got %v %v", hover, err)
+	}
+}
@@ -19,10 +19,16 @@ type Document struct {
 	defKinds      map[string]lsp.SymbolKind
 	defContainers map[string]string
 	program       *language.Program
+
+	// ws is the workspace the document belongs to, used to resolve references
+	// against the other files of its workspace root. Nil for standalone documents.
+	ws *Workspace
+	// blocks keeps the parsed top-level blocks of the document across edits.
+	blocks *parseCache
 }
 
 func NewDocument(uri lsp.DocumentURI, text string, version int) *Document {
-	d := &Document{URI: uri, Text: text, Version: version}
+	d := &Document{URI: uri, Text: text, Version: version, blocks: newParseCache()}
 	d.lines = strings.Split(text, "\n")
 	// Estimate capacity: typical DSL files have ~50-200 definitions
 	estimatedDefs := len(d.lines) / 10
@@ -398,64 +404,102 @@ func (d *Document) EnsureParsed() *language.Program {
 	if d.program != nil {
 		return d.program
 	}
-	p, err := language.NewParser()
+	p, err := language.DefaultParser()
 	if err != nil {
 		return nil
 	}
-	program, _, err := p.Parse(string(d.URI), d.Text)
-	if err != nil {
+	// Syntax errors leave a partial program, so features keep working on the
+	// parts of the document that parse
+	program, _, _ := d.blocks.parse(string(d.URI), d.Text)
+	if program == nil {
 		return nil
 	}
 
-	// Create a workspace to merge imports (including stdlib)
+	// Create a workspace to merge imports (including stdlib). The program is
+	// added under its file path, so relative and module imports resolve from
+	// the document's directory and its nearest sruja.mod
+	name := uriToPath(d.URI)
+	if name == "" {
+		name = string(d.URI)
+	}
 	ws := language.NewWorkspace()
-	ws.AddProgram(string(d.URI), program, nil)
+	ws.AddProgram(name, program, nil)
 
 	// Load stdlib into workspace for import resolution
 	loadStdLibIntoWorkspace(p, ws)
 
-	// Merge imports from stdlib into the program
+	// Load imported modules and merge their kinds and tags into the program
+	_ = p.ResolveImports(ws, program, name)
 	ws.ResolveAndMergeImports()
 
-	// Resolve references so LSP features work on canonical IDs
-	engine.RunResolution(program)
+	// Resolve references so LSP features work on canonical IDs, against the
+	// whole workspace root when the document belongs to one
+	if peers := d.peerModels(); len(peers) > 0 && program.Model != nil {
+		merged := &language.Model{Items: program.Model.Items}
+		for _, m := range peers {
+			merged.Items = append(merged.Items, m.Items...)
+		}
+		engine.NewResolverFromModel(merged).ResolveModel(program.Model)
+	} else {
+		engine.RunResolution(program)
+	}
 
 	d.program = program
 	return program
 }
 
+var (
+	stdlibOnce     sync.Once
+	stdlibPrograms map[string]*language.Program
+)
+
 // loadStdLibIntoWorkspace loads the embedded stdlib files into a workspace.
+// They are parsed once and shared, as imports only read their specifications.
 func loadStdLibIntoWorkspace(p *language.Parser, ws *language.Workspace) {
-	// Load core.sruja from embedded stdlib
-	stdlibFiles := []string{"core.sruja", "styles.sruja"}
-	for _, filename := range stdlibFiles {
-		content, err := stdlib.FS.ReadFile(filename)
-		if err != nil {
-			continue
+	stdlibOnce.Do(func() {
+		stdlibPrograms = make(map[string]*language.Program)
+		for _, filename := range stdlib.Files() {
+			content, err := stdlib.FS.ReadFile(filename)
+			if err != nil {
+				continue
+			}
+			path := language.StdlibModule + "/" + filename
+			if prog, _, err := p.Parse(path, string(content)); err == nil {
+				stdlibPrograms[path] = prog
+			}
 		}
-		path := "sruja.ai/stdlib/" + filename
+	})
+	for path, prog := range stdlibPrograms {
 		if _, exists := ws.Programs[path]; !exists {
-			prog, diags, err := p.Parse(path, string(content))
-			if err == nil {
-				ws.AddProgram(path, prog, diags)
-			}
+			ws.AddProgram(path, prog, nil)
 		}
 	}
 }
 
 type Workspace struct {
-	docs map[lsp.DocumentURI]*Document
-	mu   sync.RWMutex
+	docs map[lsp.DocumentURI]*Document // documents opened by the editor
+
+	// roots are the indexed workspace roots and files the .sruja files found in
+	// them, keyed by filesystem path. Open documents take precedence over files.
+	roots []string
+	files map[string]*Document
+
+	mu sync.RWMutex
 }
 
 func NewWorkspace() *Workspace {
-	return &Workspace{docs: make(map[lsp.DocumentURI]*Document)}
+	return &Workspace{
+		docs:  make(map[lsp.DocumentURI]*Document),
+		files: make(map[string]*Document),
+	}
 }
 
 func (w *Workspace) AddDocument(uri lsp.DocumentURI, text string, version int) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	w.docs[uri] = NewDocument(uri, text, version)
+	doc := NewDocument(uri, text, version)
+	doc.ws = w
+	w.docs[uri] = doc
 }
 
 func (w *Workspace) RemoveDocument(uri lsp.DocumentURI) {
@@ -470,12 +514,26 @@ func (w *Workspace) GetDocument(uri lsp.DocumentURI) *Document {
 	return w.docs[uri]
 }
 
+// AllDocuments returns the open documents and the indexed files that are not open.
 func (w *Workspace) AllDocuments() []*Document {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
-	res := make([]*Document, 0, len(w.docs))
+	return w.allDocumentsLocked()
+}
+
+func (w *Workspace) allDocumentsLocked() []*Document {
+	res := make([]*Document, 0, len(w.docs)+len(w.files))
+	open := make(map[string]bool, len(w.docs))
 	for _, d := range w.docs {
 		res = append(res, d)
+		if path := uriToPath(d.URI); path != "" {
+			open[path] = true
+		}
+	}
+	for path, d := range w.files {
+		if !open[path] {
+			res = append(res, d)
+		}
 	}
 	return res
 }
@@ -484,19 +542,28 @@ func (w *Workspace) FindDefinition(id string) (lsp.DocumentURI, lsp.Range, bool)
 	// Try exact match (including qualified form)
 	w.mu.RLock()
 	defer w.mu.RUnlock()
-	for _, d := range w.docs {
+	docs := w.allDocumentsLocked()
+	for _, d := range docs {
 		if r, ok := d.defs[id]; ok {
 			return d.URI, r, true
 		}
 	}
-	// Try by last segment for qualified tokens
+	// Try by last segment for qualified tokens, unless it names elements in
+	// several documents
 	if idx := strings.LastIndex(id, "."); idx > 0 {
 		last := id[idx+1:]
-		for _, d := range w.docs {
+		var uri lsp.DocumentURI
+		var rng lsp.Range
+		matches := 0
+		for _, d := range docs {
 			if r, ok := d.defs[last]; ok {
-				return d.URI, r, true
+				uri, rng = d.URI, r
+				matches++
 			}
 		}
+		if matches == 1 {
+			return uri, rng, true
+		}
 	}
 	return "", lsp.Range{}, false
 }
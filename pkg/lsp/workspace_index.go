@@ -0,0 +1,231 @@
+package lsp
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+	"github.com/sruja-ai/sruja/pkg/language"
+)
+
+// IndexRoot parses the .sruja files of a workspace root, so definitions,
+// references and diagnostics cover files the editor has not opened.
+func (w *Workspace) IndexRoot(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	p, err := language.NewParser()
+	if err != nil {
+		return err
+	}
+	parsed, err := p.ParseWorkspace(root)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]*Document, len(parsed.Programs))
+	for path := range parsed.Programs {
+		if !withinRoot(path, root) {
+			continue // stdlib and imports from outside the root
+		}
+		if doc := w.readFile(path); doc != nil {
+			files[path] = doc
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for path := range w.files {
+		if withinRoot(path, root) {
+			delete(w.files, path)
+		}
+	}
+	for path, doc := range files {
+		w.files[path] = doc
+	}
+	for _, r := range w.roots {
+		if r == root {
+			return nil
+		}
+	}
+	w.roots = append(w.roots, root)
+	return nil
+}
+
+// Roots returns the indexed workspace roots.
+func (w *Workspace) Roots() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]string(nil), w.roots...)
+}
+
+// UpdateFile re-reads an indexed file from disk, or adds it when it is a new
+// .sruja file inside a root. It reports whether the file belongs to a root.
+func (w *Workspace) UpdateFile(path string) bool {
+	if filepath.Ext(path) != srujaExt || w.RootOf(path) == "" {
+		return false
+	}
+	doc := w.readFile(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if doc == nil {
+		delete(w.files, path)
+	} else {
+		w.files[path] = doc
+	}
+	return true
+}
+
+// RemoveFile drops a deleted file from the index. It reports whether the file
+// belonged to a root.
+func (w *Workspace) RemoveFile(path string) bool {
+	if w.RootOf(path) == "" {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.files, path)
+	return true
+}
+
+// RootOf returns the innermost indexed root containing path, or "".
+func (w *Workspace) RootOf(path string) string {
+	if path == "" {
+		return ""
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	best := ""
+	for _, root := range w.roots {
+		if withinRoot(path, root) && len(root) > len(best) {
+			best = root
+		}
+	}
+	return best
+}
+
+// RootDocuments returns the documents of a root keyed by filesystem path, with
+// open documents in place of their files on disk.
+func (w *Workspace) RootDocuments(root string) map[string]*Document {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	docs := make(map[string]*Document)
+	for path, d := range w.files {
+		if withinRoot(path, root) {
+			docs[path] = d
+		}
+	}
+	for _, d := range w.docs {
+		if path := uriToPath(d.URI); path != "" && withinRoot(path, root) {
+			docs[path] = d
+		}
+	}
+	return docs
+}
+
+// documentAt returns the open document at uri or, for a file of an indexed
+// root, its indexed copy.
+func (w *Workspace) documentAt(uri lsp.DocumentURI) *Document {
+	if doc := w.GetDocument(uri); doc != nil {
+		return doc
+	}
+	path := uriToPath(uri)
+	if path == "" {
+		return nil
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.files[path]
+}
+
+// docSnapshot is the text of a document at the time its diagnostics were
+// scheduled, so they can be computed while the editor keeps changing it.
+type docSnapshot struct {
+	uri    lsp.DocumentURI
+	path   string
+	text   string
+	blocks *parseCache
+}
+
+func (d *Document) snapshot() docSnapshot {
+	return docSnapshot{uri: d.URI, path: uriToPath(d.URI), text: d.Text, blocks: d.blocks}
+}
+
+// snapshotRoot snapshots the documents of a root, ordered by path.
+func (w *Workspace) snapshotRoot(root string) []docSnapshot {
+	docs := w.RootDocuments(root)
+	snaps := make([]docSnapshot, 0, len(docs))
+	for path, d := range docs {
+		snap := d.snapshot()
+		snap.path = path
+		snaps = append(snaps, snap)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].path < snaps[j].path })
+	return snaps
+}
+
+func (w *Workspace) readFile(path string) *Document {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+	doc := NewDocument(pathToURI(path), string(content), 0)
+	doc.ws = w
+	return doc
+}
+
+// parse parses the document under its filesystem path without resolving it.
+// Each call returns a new program, so callers may resolve it in place.
+func (d *Document) parse() (*language.Program, []diagnostics.Diagnostic) {
+	filename := uriToPath(d.URI)
+	if filename == "" {
+		filename = string(d.URI)
+	}
+	prog, diags, _ := d.blocks.parse(filename, d.Text)
+	return prog, diags
+}
+
+// peerModels returns the models of the other documents in the document's root.
+func (d *Document) peerModels() []*language.Model {
+	if d.ws == nil {
+		return nil
+	}
+	self := uriToPath(d.URI)
+	root := d.ws.RootOf(self)
+	if root == "" {
+		return nil
+	}
+	docs := d.ws.RootDocuments(root)
+	paths := make([]string, 0, len(docs))
+	for path := range docs {
+		if path != self {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	models := make([]*language.Model, 0, len(paths))
+	for _, path := range paths {
+		if prog, _ := docs[path].parse(); prog != nil && prog.Model != nil {
+			models = append(models, prog.Model)
+		}
+	}
+	return models
+}
+
+const srujaExt = ".sruja"
+
+func withinRoot(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// pathToURI converts a filesystem path to a file:// document URI.
+func pathToURI(path string) lsp.DocumentURI {
+	return lsp.DocumentURI((&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String())
+}
@@ -0,0 +1,264 @@
+package lsp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+const (
+	indexSystemsText = "Shop = system \"Shop\" {\n  API = container \"API\"\n}\n"
+	indexPeopleText  = "User = person \"User\"\nUser -> Shop.API \"uses\"\n"
+)
+
+// indexedServer initializes a server on a root holding systems.sruja and
+// people.sruja, and opens only people.sruja.
+func indexedServer(t *testing.T) (srv *Server, root string, people lsp.DocumentURI) {
+	t.Helper()
+	root = t.TempDir()
+	writeIndexFile(t, filepath.Join(root, "systems.sruja"), indexSystemsText)
+	writeIndexFile(t, filepath.Join(root, "nested", "people.sruja"), indexPeopleText)
+
+	srv = NewServer()
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(root)}); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	people = pathToURI(filepath.Join(root, "nested", "people.sruja"))
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: people, Text: indexPeopleText, Version: 1}})
+	return srv, root, people
+}
+
+func writeIndexFile(t *testing.T, path, text string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkspace_IndexRoot(t *testing.T) {
+	srv, root, _ := indexedServer(t)
+
+	if roots := srv.workspace.Roots(); len(roots) != 1 || roots[0] != root {
+		t.Fatalf("expected root %s, got %v", root, roots)
+	}
+	// Both files are known, the unopened one from disk
+	if docs := srv.workspace.AllDocuments(); len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(docs))
+	}
+	if srv.workspace.RootOf(filepath.Join(root, "nested", "people.sruja")) != root {
+		t.Error("expected nested file to belong to the root")
+	}
+	if srv.workspace.RootOf(filepath.Join(filepath.Dir(root), "other.sruja")) != "" {
+		t.Error("expected file outside the root not to belong to it")
+	}
+}
+
+func TestServer_DefinitionAndReferencesInUnopenedFile(t *testing.T) {
+	srv, root, people := indexedServer(t)
+	systems := pathToURI(filepath.Join(root, "systems.sruja"))
+
+	// "Shop" in "User -> Shop.API"
+	pos := lsp.TextDocumentPositionParams{TextDocument: lsp.TextDocumentIdentifier{URI: people}, Position: lsp.Position{Line: 1, Character: 9}}
+	locs, err := srv.Definition(context.Background(), pos)
+	if err != nil {
+		t.Fatalf("definition error: %v", err)
+	}
+	if len(locs) != 1 || locs[0].URI != systems || locs[0].Range.Start.Line != 0 {
+		t.Fatalf("expected definition in unopened systems.sruja, got %+v", locs)
+	}
+
+	refs, err := srv.References(context.Background(), lsp.ReferenceParams{TextDocumentPositionParams: pos, Context: lsp.ReferenceContext{IncludeDeclaration: true}})
+	if err != nil {
+		t.Fatalf("references error: %v", err)
+	}
+	found := map[lsp.DocumentURI]bool{}
+	for _, r := range refs {
+		found[r.URI] = true
+	}
+	if !found[systems] || !found[people] {
+		t.Errorf("expected references in both files, got %+v", refs)
+	}
+
+	edit, err := srv.Rename(context.Background(), lsp.RenameParams{TextDocument: pos.TextDocument, Position: pos.Position, NewName: "Store"})
+	if err != nil || edit == nil {
+		t.Fatalf("rename error: %v", err)
+	}
+	if len(edit.Changes[string(systems)]) == 0 {
+		t.Errorf("expected rename edits in unopened systems.sruja, got %+v", edit.Changes)
+	}
+}
+
+func TestDocument_EnsureParsedResolvesAcrossRoot(t *testing.T) {
+	root := t.TempDir()
+	writeIndexFile(t, filepath.Join(root, "systems.sruja"), indexSystemsText)
+	ws := NewWorkspace()
+	if err := ws.IndexRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	uri := pathToURI(filepath.Join(root, "people.sruja"))
+	ws.AddDocument(uri, "User = person \"User\"\nUser -> API \"uses\"\n", 1)
+
+	program := ws.GetDocument(uri).EnsureParsed()
+	if program == nil || program.Model == nil {
+		t.Fatal("expected a parsed program")
+	}
+	for _, item := range program.Model.Items {
+		if item.Relation != nil && item.Relation.To.String() != "Shop.API" {
+			t.Errorf("expected API resolved to Shop.API, got %s", item.Relation.To.String())
+		}
+	}
+}
+
+func TestServer_RootDiagnostics(t *testing.T) {
+	srv, root, people := indexedServer(t)
+	systems := pathToURI(filepath.Join(root, "systems.sruja"))
+
+	byURI := srv.rootDiagnostics(root, "")
+	if _, ok := byURI[systems]; !ok {
+		t.Fatalf("expected diagnostics entry for unopened systems.sruja, got %v", byURI)
+	}
+	for _, d := range byURI[people] {
+		if d.Code == diagnostics.CodeReferenceNotFound {
+			t.Errorf("expected cross-file reference to resolve, got %q", d.Message)
+		}
+	}
+
+	// An unknown reference is reported on the open document
+	doc := srv.workspace.GetDocument(people)
+	doc.SetText(indexPeopleText + "User -> Shop.Missing \"uses\"\n")
+	found := false
+	for _, d := range srv.rootDiagnostics(root, "")[people] {
+		found = found || (d.Code == diagnostics.CodeReferenceNotFound && strings.Contains(d.Message, "Missing"))
+	}
+	if !found {
+		t.Errorf("expected a missing reference diagnostic, got %+v", srv.rootDiagnostics(root, "")[people])
+	}
+}
+
+func TestServer_DidChangeWatchedFiles(t *testing.T) {
+	srv, root, _ := indexedServer(t)
+	billing := filepath.Join(root, "billing.sruja")
+	writeIndexFile(t, billing, "Billing = system \"Billing\"\n")
+
+	err := srv.DidChangeWatchedFiles(context.Background(), lsp.DidChangeWatchedFilesParams{Changes: []lsp.FileEvent{
+		{URI: pathToURI(billing), Type: int(lsp.Created)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri, _, ok := srv.workspace.FindDefinition("Billing"); !ok || uri != pathToURI(billing) {
+		t.Fatalf("expected Billing defined in the new file, got %q", uri)
+	}
+
+	writeIndexFile(t, billing, "Payments = system \"Payments\"\n")
+	_ = srv.DidChangeWatchedFiles(context.Background(), lsp.DidChangeWatchedFilesParams{Changes: []lsp.FileEvent{
+		{URI: pathToURI(billing), Type: int(lsp.Changed)},
+	}})
+	if _, _, ok := srv.workspace.FindDefinition("Payments"); !ok {
+		t.Error("expected the changed file to be re-read")
+	}
+
+	if err := os.Remove(billing); err != nil {
+		t.Fatal(err)
+	}
+	_ = srv.DidChangeWatchedFiles(context.Background(), lsp.DidChangeWatchedFilesParams{Changes: []lsp.FileEvent{
+		{URI: pathToURI(billing), Type: int(lsp.Deleted)},
+	}})
+	if _, _, ok := srv.workspace.FindDefinition("Payments"); ok {
+		t.Error("expected the deleted file to be dropped from the index")
+	}
+}
+
+func TestServer_DidCloseKeepsIndexedFile(t *testing.T) {
+	srv, _, people := indexedServer(t)
+	_ = srv.DidClose(context.Background(), lsp.DidCloseTextDocumentParams{TextDocument: lsp.TextDocumentIdentifier{URI: people}})
+	if srv.workspace.GetDocument(people) != nil {
+		t.Fatal("expected the document to be closed")
+	}
+	if _, _, ok := srv.workspace.FindDefinition("User"); !ok {
+		t.Error("expected the closed file to stay indexed")
+	}
+}
+
+func TestServer_IndexWorkspaceFolders(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	writeIndexFile(t, filepath.Join(a, "a.sruja"), "A = system \"A\"\n")
+	writeIndexFile(t, filepath.Join(b, "b.sruja"), "B = system \"B\"\n")
+
+	srv := NewServer()
+	srv.IndexWorkspaceFolders([]WorkspaceFolder{{URI: pathToURI(a)}, {URI: pathToURI(b)}})
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(a)}); err != nil {
+		t.Fatal(err)
+	}
+	if roots := srv.workspace.Roots(); len(roots) != 2 {
+		t.Fatalf("expected 2 roots, got %v", roots)
+	}
+	for _, id := range []string{"A", "B"} {
+		if _, _, ok := srv.workspace.FindDefinition(id); !ok {
+			t.Errorf("expected %s to be indexed", id)
+		}
+	}
+}
+
+func TestEnsureParsed_ModuleImports(t *testing.T) {
+	dir := t.TempDir()
+	writeIndexFile(t, filepath.Join(dir, "app", "sruja.mod"), "module acme.com/shop\nalias acme.com/platform => ../vendor/platform\n")
+	writeIndexFile(t, filepath.Join(dir, "vendor", "platform", "kinds.sruja"), "service = kind \"Service\"\n")
+
+	uri := pathToURI(filepath.Join(dir, "app", "main.sruja"))
+	doc := NewDocument(uri, "import { service } from 'acme.com/platform'\nimport { * } from 'sruja.ai/stdlib'\nShop = system \"Shop\"\n", 1)
+	prog := doc.EnsureParsed()
+	if prog == nil || prog.Specification == nil {
+		t.Fatal("expected imported specification")
+	}
+	kinds := map[string]bool{}
+	for _, item := range prog.Specification.Items {
+		if item.Element != nil {
+			kinds[item.Element.Name] = true
+		}
+	}
+	// The vendored kind through sruja.mod, and the embedded stdlib kinds
+	if !kinds["service"] || !kinds["person"] || !kinds["container"] {
+		t.Errorf("imported kinds = %v", kinds)
+	}
+}
+
+func TestServer_RootDiagnosticsResolveImports(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "arch")
+	writeIndexFile(t, filepath.Join(dir, "lib", "kinds.sruja"), "publishes = relationship \"Publishes\" {\n  to [queue]\n}\n")
+	writeIndexFile(t, filepath.Join(root, "sruja.config.json"), `{"validation": {"rules": ["relation-tags"]}}`)
+	mainText := "import { publishes } from '../lib/kinds.sruja'\nOrders = system \"Orders\"\nEvents = queue \"Events\"\nOrders -> Events [publishes]\n"
+	writeIndexFile(t, filepath.Join(root, "main.sruja"), mainText)
+
+	srv := NewServer()
+	if _, err := srv.Initialize(context.Background(), lsp.InitializeParams{RootURI: pathToURI(root)}); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	main := pathToURI(filepath.Join(root, "main.sruja"))
+	_ = srv.DidOpen(context.Background(), lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: main, Text: mainText, Version: 1}})
+
+	// The imported relationship kind extends the relation vocabulary
+	if diags := srv.rootDiagnostics(root, "")[main]; len(diags) != 0 {
+		t.Errorf("expected no diagnostics with the imported kind, got %+v", diags)
+	}
+
+	// Unresolvable module imports are reported like sruja lint reports them
+	doc := srv.workspace.GetDocument(main)
+	doc.SetText("import { service } from 'acme.com/missing@v1.0.0'\n" + mainText)
+	found := false
+	for _, d := range srv.rootDiagnostics(root, "")[main] {
+		found = found || d.Code == diagnostics.CodeModuleNotFound
+	}
+	if !found {
+		t.Errorf("expected a %s diagnostic, got %+v", diagnostics.CodeModuleNotFound, srv.rootDiagnostics(root, "")[main])
+	}
+}
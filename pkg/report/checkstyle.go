@@ -0,0 +1,78 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// Checkstyle is the root of a checkstyle XML report.
+type Checkstyle struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []CheckstyleFile `xml:"file"`
+}
+
+// CheckstyleFile lists the findings in one file.
+type CheckstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
+}
+
+// CheckstyleError is a single finding.
+type CheckstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// ToCheckstyle converts a report to checkstyle XML, grouping findings by file.
+// The source of a finding is the tool followed by its diagnostic code.
+func ToCheckstyle(r *Report) *Checkstyle {
+	cs := &Checkstyle{Version: "4.3"}
+	diags := r.sorted()
+	for i := range diags {
+		d := &diags[i]
+		file := r.file(d)
+		if len(cs.Files) == 0 || cs.Files[len(cs.Files)-1].Name != file {
+			cs.Files = append(cs.Files, CheckstyleFile{Name: file})
+		}
+		f := &cs.Files[len(cs.Files)-1]
+		f.Errors = append(f.Errors, CheckstyleError{
+			Line:     d.Location.Line,
+			Column:   d.Location.Column,
+			Severity: checkstyleSeverity(d.Severity),
+			Message:  d.Message,
+			Source:   r.tool() + "." + d.Code,
+		})
+	}
+	return cs
+}
+
+// WriteCheckstyle writes a report as checkstyle XML.
+func WriteCheckstyle(w io.Writer, r *Report) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(ToCheckstyle(r)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func checkstyleSeverity(s diagnostics.Severity) string {
+	switch s {
+	case diagnostics.SeverityError:
+		return "error"
+	case diagnostics.SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
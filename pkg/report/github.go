@@ -0,0 +1,62 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// WriteGitHub writes a report as GitHub Actions workflow commands, one
+// annotation line per diagnostic:
+//
+//	::error file=arch.sruja,line=3,col=5,title=E202 Valid References::Undefined reference 'DB'
+func WriteGitHub(w io.Writer, r *Report) error {
+	diags := r.sorted()
+	for i := range diags {
+		d := &diags[i]
+		var props []string
+		if file := r.file(d); file != "" {
+			props = append(props, "file="+escapeGitHubProperty(filepath.ToSlash(file)))
+		}
+		if d.Location.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", d.Location.Line))
+			if d.Location.Column > 0 {
+				props = append(props, fmt.Sprintf("col=%d", d.Location.Column))
+			}
+		}
+		title := d.Code
+		if d.Rule != "" {
+			title += " " + d.Rule
+		}
+		props = append(props, "title="+escapeGitHubProperty(title))
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubLevel(d.Severity), strings.Join(props, ","), escapeGitHubData(d.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func githubLevel(s diagnostics.Severity) string {
+	switch s {
+	case diagnostics.SeverityError:
+		return "error"
+	case diagnostics.SeverityWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeGitHubData escapes the message of a workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// JUnitSuites is the root of a JUnit XML report.
+type JUnitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite groups the test cases of one run.
+type JUnitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is a rule. It fails when the rule reported errors.
+type JUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure lists the errors reported by a rule.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ToJUnit converts a report to JUnit XML with one test case per rule. Errors
+// fail the test case; warnings and infos are listed in its output.
+func ToJUnit(r *Report) *JUnitSuites {
+	byRule := make(map[string][]diagnostics.Diagnostic)
+	names := append([]string(nil), r.Rules...)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+	diags := r.sorted()
+	for i := range diags {
+		name := ruleOf(&diags[i])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		byRule[name] = append(byRule[name], diags[i])
+	}
+	sort.Strings(names)
+
+	tool := r.tool()
+	suite := JUnitSuite{Name: tool + " lint"}
+	if r.Target != "" {
+		suite.Name += " " + r.Target
+	}
+	for _, name := range names {
+		tc := JUnitCase{Name: name, ClassName: tool + "." + className(name)}
+		var failures, output []string
+		var codes []string
+		seenCode := make(map[string]bool)
+		for i := range byRule[name] {
+			d := &byRule[name][i]
+			line := fmt.Sprintf("%s:%d:%d: [%s] %s", r.file(d), d.Location.Line, d.Location.Column, d.Code, d.Message)
+			if d.Severity == diagnostics.SeverityError {
+				failures = append(failures, line)
+				if !seenCode[d.Code] {
+					seenCode[d.Code] = true
+					codes = append(codes, d.Code)
+				}
+			} else {
+				output = append(output, string(d.Severity)+": "+line)
+			}
+		}
+		if len(failures) > 0 {
+			tc.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%d error(s)", len(failures)),
+				Type:    strings.Join(codes, ","),
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		tc.SystemOut = strings.Join(output, "\n")
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	return &JUnitSuites{
+		Name:     tool,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []JUnitSuite{suite},
+	}
+}
+
+// WriteJUnit writes a report as JUnit XML.
+func WriteJUnit(w io.Writer, r *Report) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(ToJUnit(r)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// className turns a rule name into an identifier for the classname attribute,
+// which JUnit viewers split on dots.
+func className(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '.' {
+			return -1
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,118 @@
+// Package report writes diagnostics in the formats read by CI systems and code
+// review tools: SARIF 2.1.0, JUnit XML, checkstyle XML and GitHub Actions
+// workflow commands.
+//
+// The writers only depend on the diagnostics they are given, so the CLI, the
+// language server and the WebAssembly build share them.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+// Format is a machine-readable output format.
+type Format string
+
+const (
+	FormatSARIF      Format = "sarif"
+	FormatJUnit      Format = "junit"
+	FormatCheckstyle Format = "checkstyle"
+	FormatGitHub     Format = "github"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatSARIF, FormatJUnit, FormatCheckstyle, FormatGitHub}
+
+// ParseFormat returns the format named by s.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown format %q (expected %s)", s, strings.Join(names, ", "))
+}
+
+// Report is the outcome of validating an architecture.
+type Report struct {
+	// Tool and Version identify the producer, e.g. "sruja" and "v1.2.0".
+	Tool    string
+	Version string
+	// Target is the file or directory validated. Diagnostics without a file
+	// are reported against it.
+	Target string
+	// Rules are the names of the rules that ran, so that formats listing
+	// checks report rules without findings as passing.
+	Rules       []string
+	Diagnostics []diagnostics.Diagnostic
+}
+
+// Write writes the report in the given format.
+func Write(w io.Writer, format Format, r *Report) error {
+	switch format {
+	case FormatSARIF:
+		return WriteSARIF(w, r)
+	case FormatJUnit:
+		return WriteJUnit(w, r)
+	case FormatCheckstyle:
+		return WriteCheckstyle(w, r)
+	case FormatGitHub:
+		return WriteGitHub(w, r)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// tool returns the name of the producer.
+func (r *Report) tool() string {
+	if r.Tool == "" {
+		return "sruja"
+	}
+	return r.Tool
+}
+
+// file returns the file a diagnostic is reported against.
+func (r *Report) file(d *diagnostics.Diagnostic) string {
+	if d.Location.File != "" {
+		return d.Location.File
+	}
+	return r.Target
+}
+
+// sorted returns the diagnostics ordered by file, position and code, since
+// rules run concurrently and report in no particular order.
+func (r *Report) sorted() []diagnostics.Diagnostic {
+	diags := append([]diagnostics.Diagnostic(nil), r.Diagnostics...)
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := &diags[i], &diags[j]
+		if fa, fb := r.file(a), r.file(b); fa != fb {
+			return fa < fb
+		}
+		if a.Location.Line != b.Location.Line {
+			return a.Location.Line < b.Location.Line
+		}
+		if a.Location.Column != b.Location.Column {
+			return a.Location.Column < b.Location.Column
+		}
+		return a.Code < b.Code
+	})
+	return diags
+}
+
+// ruleOf returns the name of the rule that reported a diagnostic, or its code
+// when it did not come from a rule (e.g. a syntax or import error).
+func ruleOf(d *diagnostics.Diagnostic) string {
+	if d.Rule != "" {
+		return d.Rule
+	}
+	return d.Code
+}
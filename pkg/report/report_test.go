@@ -0,0 +1,170 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+func testReport() *Report {
+	return &Report{
+		Tool:    "sruja",
+		Version: "v1.0.0",
+		Target:  "arch.sruja",
+		Rules:   []string{"Valid References", "OrphanDetection", "Unique IDs"},
+		Diagnostics: []diagnostics.Diagnostic{
+			{
+				Code:     diagnostics.CodeOrphanElement,
+				Severity: diagnostics.SeverityWarning,
+				Message:  "Element 'Cache' is not used",
+				Location: diagnostics.SourceLocation{File: "arch.sruja", Line: 7, Column: 3},
+				Rule:     "OrphanDetection",
+			},
+			{
+				Code:     diagnostics.CodeUndefinedRef,
+				Severity: diagnostics.SeverityError,
+				Message:  "Undefined reference 'DB'",
+				Location: diagnostics.SourceLocation{File: "arch.sruja", Line: 3, Column: 5},
+				Rule:     "Valid References",
+			},
+			{
+				Code:     diagnostics.CodeModuleNotFound,
+				Severity: diagnostics.SeverityError,
+				Message:  "module acme.com/platform: not found",
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats {
+		got, err := ParseFormat(strings.ToUpper(string(f)))
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %q, %v", f, got, err)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+	var log SARIF
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version = %q, runs = %d", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "sruja" || run.Tool.Driver.Version != "v1.0.0" {
+		t.Errorf("driver = %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("results = %d, rules = %d", len(run.Results), len(run.Tool.Driver.Rules))
+	}
+
+	// Sorted by file then line; the module error is reported against the target
+	first := run.Results[0]
+	if first.RuleID != "E501" || first.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("first result = %+v", first)
+	}
+	second := run.Results[1]
+	rule := run.Tool.Driver.Rules[second.RuleIndex]
+	if second.RuleID != "E202" || second.Level != "error" || rule.ID != "E202" || rule.Name != "Valid References" {
+		t.Errorf("second result = %+v, rule = %+v", second, rule)
+	}
+	if rule.ShortDescription.Text != "Undefined reference" {
+		t.Errorf("shortDescription = %q", rule.ShortDescription.Text)
+	}
+	if region := second.Locations[0].PhysicalLocation.Region; region == nil || region.StartLine != 3 || region.StartColumn != 5 {
+		t.Errorf("region = %+v", region)
+	}
+	if run.Results[2].Level != "warning" {
+		t.Errorf("orphan level = %q", run.Results[2].Level)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+	var suites JUnitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	// One test case per rule that ran, plus the module error without a rule
+	if suites.Tests != 4 || suites.Failures != 2 {
+		t.Fatalf("tests = %d, failures = %d\n%s", suites.Tests, suites.Failures, buf.String())
+	}
+	cases := make(map[string]JUnitCase)
+	for _, tc := range suites.Suites[0].Cases {
+		cases[tc.Name] = tc
+	}
+	if tc := cases["Unique IDs"]; tc.Failure != nil || tc.ClassName != "sruja.UniqueIDs" {
+		t.Errorf("passing rule = %+v", tc)
+	}
+	if tc := cases["Valid References"]; tc.Failure == nil || tc.Failure.Type != "E202" ||
+		!strings.Contains(tc.Failure.Text, "arch.sruja:3:5: [E202] Undefined reference 'DB'") {
+		t.Errorf("failing rule = %+v", tc)
+	}
+	if tc := cases["OrphanDetection"]; tc.Failure != nil || !strings.Contains(tc.SystemOut, "Warning: arch.sruja:7:3") {
+		t.Errorf("warning rule = %+v", tc)
+	}
+	if tc := cases["E501"]; tc.Failure == nil {
+		t.Errorf("module error = %+v", tc)
+	}
+}
+
+func TestWriteCheckstyle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCheckstyle(&buf, testReport()); err != nil {
+		t.Fatal(err)
+	}
+	var cs Checkstyle
+	if err := xml.Unmarshal(buf.Bytes(), &cs); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if len(cs.Files) != 1 || cs.Files[0].Name != "arch.sruja" || len(cs.Files[0].Errors) != 3 {
+		t.Fatalf("files = %+v", cs.Files)
+	}
+	e := cs.Files[0].Errors[1]
+	if e.Line != 3 || e.Column != 5 || e.Severity != "error" || e.Source != "sruja.E202" {
+		t.Errorf("error = %+v", e)
+	}
+	if cs.Files[0].Errors[2].Severity != "warning" {
+		t.Errorf("warning = %+v", cs.Files[0].Errors[2])
+	}
+}
+
+func TestWriteGitHub(t *testing.T) {
+	r := testReport()
+	r.Diagnostics[1].Message = "Undefined reference 'DB'\n100% sure"
+	var buf bytes.Buffer
+	if err := WriteGitHub(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		"::error file=arch.sruja,title=E501::module acme.com/platform: not found",
+		"::error file=arch.sruja,line=3,col=5,title=E202 Valid References::Undefined reference 'DB'%0A100%25 sure",
+		"::warning file=arch.sruja,line=7,col=3,title=E205 OrphanDetection::Element 'Cache' is not used",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
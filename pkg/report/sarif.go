@@ -0,0 +1,165 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/sruja-ai/sruja/pkg/diagnostics"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://sruja.ai"
+)
+
+// SARIF is a SARIF 2.1.0 log. Only the properties Sruja fills in are modelled.
+type SARIF struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of a tool.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool and the rules it checks.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is the metadata of a rule, identified by its diagnostic code.
+type SARIFRule struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name,omitempty"`
+	ShortDescription SARIFMessage      `json:"shortDescription"`
+	DefaultConfig    *SARIFRuleConfig  `json:"defaultConfiguration,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFRuleConfig is the default configuration of a rule.
+type SARIFRuleConfig struct {
+	Level string `json:"level"`
+}
+
+// SARIFResult is a single finding.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+// SARIFMessage is a plain text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation is where a result was found.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a region of a file.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is the URI of a file, relative to the working directory.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a 1-based position in a file.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// ToSARIF converts a report to a SARIF log. Rules are identified by diagnostic
+// code and named after the engine rule that reported them.
+func ToSARIF(r *Report) *SARIF {
+	driver := SARIFDriver{
+		Name:           r.tool(),
+		Version:        r.Version,
+		InformationURI: sarifToolURI,
+		Rules:          []SARIFRule{},
+	}
+	results := []SARIFResult{}
+	ruleIndex := make(map[string]int)
+
+	diags := r.sorted()
+	for i := range diags {
+		d := &diags[i]
+		idx, ok := ruleIndex[d.Code]
+		if !ok {
+			idx = len(driver.Rules)
+			ruleIndex[d.Code] = idx
+			rule := SARIFRule{
+				ID:               d.Code,
+				ShortDescription: SARIFMessage{Text: diagnostics.CodeTitle(d.Code)},
+				DefaultConfig:    &SARIFRuleConfig{Level: sarifLevel(d.Severity)},
+			}
+			if d.Rule != "" {
+				rule.Name = d.Rule
+				rule.Properties = map[string]string{"rule": d.Rule}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		result := SARIFResult{
+			RuleID:    d.Code,
+			RuleIndex: idx,
+			Level:     sarifLevel(d.Severity),
+			Message:   SARIFMessage{Text: d.Message},
+		}
+		if file := r.file(d); file != "" {
+			loc := SARIFLocation{PhysicalLocation: SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: filepath.ToSlash(file)},
+			}}
+			if d.Location.Line > 0 {
+				loc.PhysicalLocation.Region = &SARIFRegion{StartLine: d.Location.Line, StartColumn: d.Location.Column}
+			}
+			result.Locations = []SARIFLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	return &SARIF{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
+
+// WriteSARIF writes a report as an indented SARIF 2.1.0 log.
+func WriteSARIF(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ToSARIF(r))
+}
+
+func sarifLevel(s diagnostics.Severity) string {
+	switch s {
+	case diagnostics.SeverityError:
+		return "error"
+	case diagnostics.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
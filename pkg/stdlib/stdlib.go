@@ -2,9 +2,16 @@ package stdlib
 
 import (
 	"embed"
+	"io/fs"
 )
 
-// FS contains the standard library files.
+// FS contains the standard library files and its sruja.mod.
 //
-//go:embed *.sruja
+//go:embed *.sruja sruja.mod
 var FS embed.FS
+
+// Files returns the names of the standard library's .sruja files in FS.
+func Files() []string {
+	names, _ := fs.Glob(FS, "*.sruja")
+	return names
+}